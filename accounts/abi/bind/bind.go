@@ -82,6 +82,12 @@ func isKeyWord(arg string) bool {
 // enforces compile time type safety and naming convention as opposed to having to
 // manually maintain hard coded strings that break on runtime.
 func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]string, pkg string, lang Lang, libs map[string]string, aliases map[string]string) (string, error) {
+	return bind(types, abis, bytecodes, fsigs, pkg, lang, libs, aliases, new(bindConfig)) // libevm
+}
+
+// bind is the original implementation of [Bind], modified to accept a
+// [bindConfig] of libevm-specific options.
+func bind(types []string, abis []string, bytecodes []string, fsigs []map[string]string, pkg string, lang Lang, libs map[string]string, aliases map[string]string, cfg *bindConfig) (string, error) {
 	var (
 		// contracts is the map of each individual contract requested binding
 		contracts = make(map[string]*tmplContract)
@@ -250,6 +256,13 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 		if len(fsigs) > i {
 			contracts[types[i]].FuncSigs = fsigs[i]
 		}
+		//libevm:start
+		if cfg.precompileCallers {
+			if err := checkPrecompileIdentifiers(contracts[types[i]]); err != nil {
+				return "", err
+			}
+		}
+		//libevm:end
 		// Parse library references.
 		for pattern, name := range libs {
 			matched, err := regexp.Match("__\\$"+pattern+"\\$__", []byte(contracts[types[i]].InputBin))
@@ -272,10 +285,11 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 	}
 	// Generate the contract template data content and render it
 	data := &tmplData{
-		Package:   pkg,
-		Contracts: contracts,
-		Libraries: libs,
-		Structs:   structs,
+		Package:           pkg,
+		Contracts:         contracts,
+		Libraries:         libs,
+		Structs:           structs,
+		PrecompileCallers: cfg.precompileCallers, // libevm
 	}
 	buffer := new(bytes.Buffer)
 
@@ -287,6 +301,7 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 		"decapitalise":  decapitalise,
 	}
 	tmpl := template.Must(template.New("").Funcs(funcs).Parse(tmplSource[lang]))
+	tmpl = template.Must(tmpl.Funcs(libevmTemplateFuncs(structs)).Parse(tmplPrecompileCallerGo)) // libevm
 	if err := tmpl.Execute(buffer, data); err != nil {
 		return "", err
 	}
@@ -0,0 +1,127 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/accounts/abi"
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/options"
+)
+
+type bindConfig struct {
+	precompileCallers bool
+}
+
+// A BindOption configures [BindWithOptions].
+type BindOption = options.Option[bindConfig]
+
+// WithPrecompileCallers generates, in addition to the regular bindings, a
+// <Type>Precompile binding for calling each contract from within a stateful
+// precompile; see [CallFromPrecompile]. The regular bindings remain usable by
+// external clients, including for calling precompiles over RPC.
+func WithPrecompileCallers() BindOption {
+	return options.Func[bindConfig](func(c *bindConfig) {
+		c.precompileCallers = true
+	})
+}
+
+// BindWithOptions is equivalent to [Bind] but accepts libevm-specific options.
+func BindWithOptions(types []string, abis []string, bytecodes []string, fsigs []map[string]string, pkg string, lang Lang, libs map[string]string, aliases map[string]string, opts ...BindOption) (string, error) {
+	return bind(types, abis, bytecodes, fsigs, pkg, lang, libs, aliases, options.As(opts...))
+}
+
+// checkPrecompileIdentifiers returns an error if calls and transactions of the
+// contract share a normalised name, which is allowed by regular bindings as
+// they are methods on different types, but not by the precompile binding.
+func checkPrecompileIdentifiers(c *tmplContract) error {
+	for _, call := range c.Calls {
+		for _, tx := range c.Transacts {
+			if n := call.Normalized.Name; n == tx.Normalized.Name {
+				return fmt.Errorf("duplicated identifier %q in precompile binding of %s, use --alias for renaming", n, c.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// A PrecompileCaller can call contracts from within a stateful precompile. It
+// is satisfied by [vm.PrecompileEnvironment].
+type PrecompileCaller interface {
+	Gas() uint64
+	Call(addr common.Address, input []byte, gas uint64, value *uint256.Int, _ ...vm.CallOption) ([]byte, error)
+}
+
+var _ PrecompileCaller = vm.PrecompileEnvironment(nil)
+
+// PrecompileCallOpts are the options for calls made via [CallFromPrecompile].
+// A nil pointer is equivalent to the zero value.
+type PrecompileCallOpts struct {
+	Gas     uint64       // Gas to make available; if zero, all remaining gas
+	Value   *uint256.Int // Value to send; nil is treated as zero
+	Options []vm.CallOption
+}
+
+// CallFromPrecompile packs the method and parameters according to the ABI,
+// calls the contract at `addr` via the [PrecompileCaller], and unpacks the
+// returned data into `results`, in the same manner as [BoundContract.Call].
+//
+// If the call reverts with a reason string then it is included in the returned
+// error, which otherwise wraps the error returned by the PrecompileCaller.
+func CallFromPrecompile(caller PrecompileCaller, opts *PrecompileCallOpts, parsed *abi.ABI, addr common.Address, results *[]interface{}, method string, params ...interface{}) error {
+	if opts == nil {
+		opts = new(PrecompileCallOpts)
+	}
+	if results == nil {
+		results = new([]interface{})
+	}
+	input, err := parsed.Pack(method, params...)
+	if err != nil {
+		return err
+	}
+
+	gas := opts.Gas
+	if gas == 0 {
+		gas = caller.Gas()
+	}
+	value := opts.Value
+	if value == nil {
+		value = new(uint256.Int)
+	}
+	output, err := caller.Call(addr, input, gas, value, opts.Options...)
+	if err != nil {
+		if errors.Is(err, vm.ErrExecutionReverted) {
+			if reason, errUnpack := abi.UnpackRevert(output); errUnpack == nil {
+				return fmt.Errorf("%w: %s", err, reason)
+			}
+		}
+		return err
+	}
+
+	if len(*results) == 0 {
+		res, err := parsed.Unpack(method, output)
+		*results = res
+		return err
+	}
+	res := *results
+	return parsed.UnpackIntoInterface(res[0], method, output)
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package bind_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/accounts/abi"
+	"github.com/ava-labs/libevm/accounts/abi/bind"
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+)
+
+const precompileCallerABI = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"who","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+func TestBindWithPrecompileCallers(t *testing.T) {
+	bindToken := func(t *testing.T, opts ...bind.BindOption) string {
+		t.Helper()
+		code, err := bind.BindWithOptions([]string{"Token"}, []string{precompileCallerABI}, []string{""}, nil, "token", bind.LangGo, nil, nil, opts...)
+		require.NoError(t, err, "bind.BindWithOptions()")
+		return code
+	}
+
+	t.Run("without option", func(t *testing.T) {
+		code := bindToken(t)
+		want, err := bind.Bind([]string{"Token"}, []string{precompileCallerABI}, []string{""}, nil, "token", bind.LangGo, nil, nil)
+		require.NoError(t, err, "bind.Bind()")
+		assert.Equal(t, want, code, "bind.BindWithOptions() without options is equivalent to bind.Bind()")
+		assert.NotContains(t, code, "TokenPrecompile")
+	})
+
+	t.Run("with option", func(t *testing.T) {
+		code := bindToken(t, bind.WithPrecompileCallers())
+		for _, want := range []string{
+			"type TokenPrecompile struct",
+			"func NewTokenPrecompile(address common.Address) (*TokenPrecompile, error)",
+			"func (_Token *TokenPrecompile) BalanceOf(env bind.PrecompileCaller, opts *bind.PrecompileCallOpts, who common.Address) (*big.Int, error)",
+			"func (_Token *TokenPrecompile) Transfer(env bind.PrecompileCaller, opts *bind.PrecompileCallOpts, to common.Address, amount *big.Int) (bool, error)",
+		} {
+			assert.Contains(t, code, want)
+		}
+		assert.Less(t, strings.Index(code, ") BalanceOf(env"), strings.Index(code, ") Transfer(env"), "methods sorted by name")
+	})
+}
+
+// precompileCallerStub records the last call and returns canned output.
+type precompileCallerStub struct {
+	gas    uint64
+	output []byte
+	err    error
+
+	gotAddr  common.Address
+	gotInput []byte
+	gotGas   uint64
+	gotValue *uint256.Int
+}
+
+func (s *precompileCallerStub) Gas() uint64 { return s.gas }
+
+func (s *precompileCallerStub) Call(addr common.Address, input []byte, gas uint64, value *uint256.Int, _ ...vm.CallOption) ([]byte, error) {
+	s.gotAddr = addr
+	s.gotInput = input
+	s.gotGas = gas
+	s.gotValue = value
+	return s.output, s.err
+}
+
+func TestCallFromPrecompile(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(precompileCallerABI))
+	require.NoError(t, err, "abi.JSON()")
+
+	var (
+		addr = common.Address{'t', 'o', 'k', 'e', 'n'}
+		who  = common.Address{'w', 'h', 'o'}
+		bal  = big.NewInt(314159)
+	)
+	output, err := parsed.Methods["balanceOf"].Outputs.Pack(bal)
+	require.NoError(t, err, "Pack([balance])")
+
+	t.Run("success", func(t *testing.T) {
+		stub := &precompileCallerStub{gas: 1e6, output: output}
+		var out []interface{}
+		require.NoError(t, bind.CallFromPrecompile(stub, nil, &parsed, addr, &out, "balanceOf", who), "bind.CallFromPrecompile()")
+
+		wantInput, err := parsed.Pack("balanceOf", who)
+		require.NoError(t, err, "Pack(balanceOf)")
+		assert.Equal(t, addr, stub.gotAddr, "address")
+		assert.Equal(t, wantInput, stub.gotInput, "input")
+		assert.Equal(t, stub.gas, stub.gotGas, "gas defaults to all remaining")
+		assert.True(t, stub.gotValue.IsZero(), "value defaults to zero")
+		require.Len(t, out, 1, "results")
+		assert.Equal(t, bal, out[0], "result")
+	})
+
+	t.Run("options", func(t *testing.T) {
+		stub := &precompileCallerStub{gas: 1e6, output: output}
+		opts := &bind.PrecompileCallOpts{
+			Gas:   1000,
+			Value: uint256.NewInt(42),
+		}
+		require.NoError(t, bind.CallFromPrecompile(stub, opts, &parsed, addr, nil, "balanceOf", who), "bind.CallFromPrecompile()")
+		assert.Equal(t, opts.Gas, stub.gotGas, "gas")
+		assert.Equal(t, opts.Value, stub.gotValue, "value")
+	})
+
+	t.Run("revert reason", func(t *testing.T) {
+		// Error(string) with reason "nope"
+		revert := common.FromHex("0x08c379a0" +
+			"0000000000000000000000000000000000000000000000000000000000000020" +
+			"0000000000000000000000000000000000000000000000000000000000000004" +
+			"6e6f706500000000000000000000000000000000000000000000000000000000")
+		stub := &precompileCallerStub{gas: 1e6, output: revert, err: vm.ErrExecutionReverted}
+		err := bind.CallFromPrecompile(stub, nil, &parsed, addr, nil, "balanceOf", who)
+		require.ErrorIs(t, err, vm.ErrExecutionReverted, "bind.CallFromPrecompile()")
+		assert.Contains(t, err.Error(), "nope", "revert reason")
+	})
+}
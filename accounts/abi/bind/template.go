@@ -24,6 +24,8 @@ type tmplData struct {
 	Contracts map[string]*tmplContract // List of contracts to generate into this file
 	Libraries map[string]string        // Map the bytecode's link pattern to the library name
 	Structs   map[string]*tmplStruct   // Contract struct type definitions
+
+	PrecompileCallers bool // libevm: whether to generate bindings for calling from precompiles
 }
 
 // tmplContract contains the data needed to generate an individual contract binding.
@@ -567,5 +569,6 @@ var (
 		}
 
  	{{end}}
+	{{if $.PrecompileCallers}}{{template "precompileCaller" $contract}}{{end}}
 {{end}}
 `
@@ -0,0 +1,101 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// libevmTemplateFuncs returns functions used by libevm-specific templates.
+func libevmTemplateFuncs(structs map[string]*tmplStruct) template.FuncMap {
+	return template.FuncMap{
+		"structs": func() map[string]*tmplStruct { return structs },
+		// allMethods returns the contract's calls and transactions, sorted by
+		// name for deterministic output.
+		"allMethods": func(c *tmplContract) []*tmplMethod {
+			var ms []*tmplMethod
+			for _, m := range c.Calls {
+				ms = append(ms, m)
+			}
+			for _, m := range c.Transacts {
+				ms = append(ms, m)
+			}
+			slices.SortFunc(ms, func(a, b *tmplMethod) int {
+				return strings.Compare(a.Original.Name, b.Original.Name)
+			})
+			return ms
+		},
+	}
+}
+
+// tmplPrecompileCallerGo defines the "precompileCaller" template, executed for
+// each contract iff [WithPrecompileCallers] is used.
+const tmplPrecompileCallerGo = `
+{{define "precompileCaller"}}
+	{{$contract := .}}
+	{{$structs := structs}}
+	// {{.Type}}Precompile is an auto generated Go binding for calling an Ethereum
+	// contract from within a stateful precompile.
+	type {{.Type}}Precompile struct {
+		address common.Address // Address of the contract, which MAY itself be a precompile
+		abi     *abi.ABI        // Parsed ABI of the contract
+	}
+
+	// New{{.Type}}Precompile creates a new instance of {{.Type}} for calling from
+	// within a stateful precompile, bound to a specific address.
+	func New{{.Type}}Precompile(address common.Address) (*{{.Type}}Precompile, error) {
+		parsed, err := {{.Type}}MetaData.GetAbi()
+		if err != nil {
+			return nil, err
+		}
+		if parsed == nil {
+			return nil, errors.New("GetABI returned nil")
+		}
+		return &{{.Type}}Precompile{address: address, abi: parsed}, nil
+	}
+
+	{{range allMethods .}}
+		// {{.Normalized.Name}} calls the contract method 0x{{printf "%x" .Original.ID}} from within a precompile.
+		//
+		// Solidity: {{.Original.String}}
+		func (_{{$contract.Type}} *{{$contract.Type}}Precompile) {{.Normalized.Name}}(env bind.PrecompileCaller, opts *bind.PrecompileCallOpts {{range .Normalized.Inputs}}, {{.Name}} {{bindtype .Type $structs}} {{end}}) ({{if .Structured}}struct{ {{range .Normalized.Outputs}}{{.Name}} {{bindtype .Type $structs}};{{end}} },{{else}}{{range .Normalized.Outputs}}{{bindtype .Type $structs}},{{end}}{{end}} error) {
+			var out []interface{}
+			err := bind.CallFromPrecompile(env, opts, _{{$contract.Type}}.abi, _{{$contract.Type}}.address, &out, "{{.Original.Name}}" {{range .Normalized.Inputs}}, {{.Name}}{{end}})
+			{{if .Structured}}
+			outstruct := new(struct{ {{range .Normalized.Outputs}} {{.Name}} {{bindtype .Type $structs}}; {{end}} })
+			if err != nil {
+				return *outstruct, err
+			}
+			{{range $i, $t := .Normalized.Outputs}}
+			outstruct.{{.Name}} = *abi.ConvertType(out[{{$i}}], new({{bindtype .Type $structs}})).(*{{bindtype .Type $structs}}){{end}}
+
+			return *outstruct, err
+			{{else}}
+			if err != nil {
+				return {{range $i, $_ := .Normalized.Outputs}}*new({{bindtype .Type $structs}}), {{end}} err
+			}
+			{{range $i, $t := .Normalized.Outputs}}
+			out{{$i}} := *abi.ConvertType(out[{{$i}}], new({{bindtype .Type $structs}})).(*{{bindtype .Type $structs}}){{end}}
+
+			return {{range $i, $t := .Normalized.Outputs}}out{{$i}}, {{end}} err
+			{{end}}
+		}
+	{{end}}
+{{end}}
+`
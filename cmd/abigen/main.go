@@ -72,6 +72,11 @@ var (
 		Name:  "alias",
 		Usage: "Comma separated aliases for function and event renaming, e.g. original1=alias1, original2=alias2",
 	}
+	// libevm
+	precompileFlag = &cli.BoolFlag{
+		Name:  "precompile-callers",
+		Usage: "Additionally generate bindings for calling contracts from within stateful precompiles",
+	}
 )
 
 var app = flags.NewApp("Ethereum ABI wrapper code generator")
@@ -88,6 +93,7 @@ func init() {
 		outFlag,
 		langFlag,
 		aliasFlag,
+		precompileFlag, // libevm
 	}
 	app.Action = abigen
 }
@@ -216,7 +222,13 @@ func abigen(c *cli.Context) error {
 		}
 	}
 	// Generate the contract binding
-	code, err := bind.Bind(types, abis, bins, sigs, c.String(pkgFlag.Name), lang, libs, aliases)
+	//libevm:start
+	var opts []bind.BindOption
+	if c.Bool(precompileFlag.Name) {
+		opts = append(opts, bind.WithPrecompileCallers())
+	}
+	code, err := bind.BindWithOptions(types, abis, bins, sigs, c.String(pkgFlag.Name), lang, libs, aliases, opts...)
+	//libevm:end
 	if err != nil {
 		utils.Fatalf("Failed to generate ABI binding: %v", err)
 	}
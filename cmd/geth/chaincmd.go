@@ -54,6 +54,7 @@ var (
 			utils.CachePreimagesFlag,
 			utils.OverrideCancun,
 			utils.OverrideVerkle,
+			utils.OverrideConfigOverlay, // libevm
 		}, utils.DatabaseFlags),
 		Description: `
 The init command initializes a new genesis block and definition for the network.
@@ -220,6 +221,15 @@ func initGenesis(ctx *cli.Context) error {
 		v := ctx.Uint64(utils.OverrideVerkle.Name)
 		overrides.OverrideVerkle = &v
 	}
+	//libevm:start
+	if ctx.IsSet(utils.OverrideConfigOverlay.Name) {
+		o, err := params.LoadConfigOverlay(ctx.String(utils.OverrideConfigOverlay.Name))
+		if err != nil {
+			utils.Fatalf("invalid config overlay: %v", err)
+		}
+		overrides.ConfigOverlay = o
+	}
+	//libevm:end
 	for _, name := range []string{"chaindata", "lightchaindata"} {
 		chaindb, err := stack.OpenDatabaseWithFreezer(name, 0, 0, ctx.String(utils.AncientFlag.Name), "", false)
 		if err != nil {
@@ -252,6 +252,12 @@ var (
 		Usage:    "Manually specify the Verkle fork timestamp, overriding the bundled setting",
 		Category: flags.EthCategory,
 	}
+	// libevm
+	OverrideConfigOverlay = &cli.StringFlag{
+		Name:     "override.config",
+		Usage:    "Path to a JSON or TOML chain-config overlay, merged into the bundled or stored config",
+		Category: flags.EthCategory,
+	}
 	SyncModeFlag = &flags.TextMarshalerFlag{
 		Name:     "syncmode",
 		Usage:    `Blockchain sync mode ("snap" or "full")`,
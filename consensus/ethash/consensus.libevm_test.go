@@ -0,0 +1,93 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/libevm/consensus"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/params"
+)
+
+type forkGatedHeaderExtra struct {
+	types.NOOPHeaderHooks
+	X uint64
+}
+
+const forkGatedActivation = 100
+
+func (*forkGatedHeaderExtra) HeaderExtrasActive(h *types.Header) bool {
+	return h.Time >= forkGatedActivation
+}
+
+type configOnlyChain struct {
+	consensus.ChainHeaderReader
+	config *params.ChainConfig
+}
+
+func (c configOnlyChain) Config() *params.ChainConfig { return c.config }
+
+func TestVerifyHeaderForkGatedExtras(t *testing.T) {
+	types.TestOnlyClearRegisteredExtras()
+	t.Cleanup(types.TestOnlyClearRegisteredExtras)
+
+	extras := types.RegisterExtras[
+		forkGatedHeaderExtra, *forkGatedHeaderExtra,
+		types.NOOPBlockBodyHooks, *types.NOOPBlockBodyHooks,
+		struct{},
+	]()
+
+	chain := configOnlyChain{config: &params.ChainConfig{ChainID: big.NewInt(1)}}
+	parent := &types.Header{
+		Number:     big.NewInt(1),
+		Difficulty: big.NewInt(131072),
+		GasLimit:   params.GenesisGasLimit,
+		Time:       forkGatedActivation - 2,
+	}
+
+	tests := []struct {
+		time, x uint64
+		want    error
+	}{
+		{time: forkGatedActivation - 1, x: 0, want: nil},
+		{time: forkGatedActivation - 1, x: 42, want: types.ErrHeaderExtrasBeforeActivation},
+		{time: forkGatedActivation, x: 0, want: types.ErrHeaderExtrasMissingAfterActivation},
+		{time: forkGatedActivation, x: 42, want: nil},
+	}
+
+	engine := NewFaker()
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("time_%d_x_%d", tt.time, tt.x), func(t *testing.T) {
+			header := &types.Header{
+				ParentHash: parent.Hash(),
+				Number:     big.NewInt(2),
+				Difficulty: engine.CalcDifficulty(chain, tt.time, parent),
+				GasLimit:   parent.GasLimit,
+				Time:       tt.time,
+			}
+			extras.Header.Get(header).X = tt.x
+
+			err := engine.verifyHeader(chain, header, parent, false, int64(tt.time))
+			assert.ErrorIs(t, err, tt.want, "verifyHeader()")
+		})
+	}
+}
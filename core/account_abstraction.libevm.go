@@ -0,0 +1,111 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+)
+
+// AccountAbstractionHooks are optional extensions of [params.RulesHooks] that
+// allow account-abstraction (e.g. ERC-4337-style or native) execution flows to
+// be orchestrated by chain rules, without maintaining a divergent
+// [ApplyMessage]. They are only used if the [params.RulesHooks] returned by
+// the registered [params.Extras] also implement this interface, and only for
+// messages sent to a designated entrypoint, which is typically a stateful
+// precompile that executes the operation itself.
+//
+// For such messages, the [StateTransition] is modified as follows:
+//
+//  1. After nonce and fee-cap checks, but before gas is purchased,
+//     ValidateAAOperation is called. A non-nil error invalidates the message.
+//  2. Gas is purchased from, and remaining gas refunded to, the payer returned
+//     by validation instead of the sender. The sender remains responsible for
+//     any value transferred.
+//  3. After execution of the message, but before refunds, PostAAOperation is
+//     called with the outcome, which it MAY modify.
+//
+// Calls made via the [vm.EVM] by ValidateAAOperation occur before tracing of
+// the transaction has started, and any gas that they consume is not accounted
+// for by the [StateTransition].
+type AccountAbstractionHooks interface {
+	// IsAAEntrypoint reports whether messages to the address are to be
+	// processed as account-abstraction operations.
+	IsAAEntrypoint(common.Address) bool
+	// ValidateAAOperation performs the validation phase of an operation,
+	// returning the account to pay for its gas.
+	ValidateAAOperation(*vm.EVM, *Message) (payer common.Address, _ error)
+	// PostAAOperation receives the outcome of an operation's execution and
+	// returns the (possibly modified) outcome to be used instead. The returned
+	// gas MUST NOT exceed that received. A non-nil error returned by the hook
+	// is a VM error, not a consensus error, and is therefore included in the
+	// [ExecutionResult].
+	PostAAOperation(_ *vm.EVM, _ *Message, payer common.Address, gasRemaining uint64, ret []byte, vmErr error) (_ uint64, _ []byte, _ error)
+}
+
+// aaHooks returns the registered [AccountAbstractionHooks] i.f.f. the message
+// is sent to an entrypoint.
+func (st *StateTransition) aaHooks() (AccountAbstractionHooks, bool) {
+	if st.msg.To == nil {
+		return nil, false
+	}
+	h, ok := st.rulesHooks().(AccountAbstractionHooks)
+	if !ok || !h.IsAAEntrypoint(*st.msg.To) {
+		return nil, false
+	}
+	return h, true
+}
+
+// validateAAOperation calls [AccountAbstractionHooks.ValidateAAOperation] if
+// applicable, recording the payer.
+func (st *StateTransition) validateAAOperation() error {
+	h, ok := st.aaHooks()
+	if !ok {
+		return nil
+	}
+	payer, err := h.ValidateAAOperation(st.evm, st.msg)
+	if err != nil {
+		return fmt.Errorf("account-abstraction validation: %w", err)
+	}
+	st.aaPayer = &payer
+	return nil
+}
+
+// gasPayer returns the account that pays for, and is refunded, gas.
+func (st *StateTransition) gasPayer() common.Address {
+	if p := st.aaPayer; p != nil {
+		return *p
+	}
+	return st.msg.From
+}
+
+// postAAOperation calls [AccountAbstractionHooks.PostAAOperation] if
+// applicable, updating the gas remaining.
+func (st *StateTransition) postAAOperation(ret []byte, vmErr error) ([]byte, error) {
+	if st.aaPayer == nil {
+		return ret, vmErr
+	}
+	h, ok := st.aaHooks()
+	if !ok {
+		return ret, vmErr
+	}
+	gas, ret, vmErr := h.PostAAOperation(st.evm, st.msg, *st.aaPayer, st.gasRemaining, ret, vmErr)
+	st.gasRemaining = min(gas, st.gasRemaining)
+	return ret, vmErr
+}
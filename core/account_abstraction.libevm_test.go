@@ -0,0 +1,133 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+type aaHooks struct {
+	hookstest.Stub
+	entrypoint, payer common.Address
+	validationErr     error
+	postOpGas         uint64
+}
+
+var _ core.AccountAbstractionHooks = (*aaHooks)(nil)
+
+func (h *aaHooks) IsAAEntrypoint(addr common.Address) bool {
+	return addr == h.entrypoint
+}
+
+func (h *aaHooks) ValidateAAOperation(*vm.EVM, *core.Message) (common.Address, error) {
+	return h.payer, h.validationErr
+}
+
+func (h *aaHooks) PostAAOperation(_ *vm.EVM, _ *core.Message, payer common.Address, gas uint64, ret []byte, vmErr error) (uint64, []byte, error) {
+	if payer != h.payer {
+		return 0, nil, errors.New("unexpected payer")
+	}
+	return gas - h.postOpGas, append(ret, []byte("+post")...), vmErr
+}
+
+func TestAccountAbstractionHooks(t *testing.T) {
+	const (
+		gasPrice     = 2
+		entryGas     = 1000
+		postOpGas    = 500
+		startBalance = 1e9
+	)
+
+	rng := ethtest.NewPseudoRand(748)
+	hooks := &aaHooks{
+		entrypoint: rng.Address(),
+		payer:      rng.Address(),
+		postOpGas:  postOpGas,
+	}
+	hooks.PrecompileOverrides = map[common.Address]libevm.PrecompiledContract{
+		hooks.entrypoint: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+			if !env.UseGas(entryGas) {
+				return nil, vm.ErrOutOfGas
+			}
+			return []byte("op"), nil
+		}),
+	}
+	hookstest.Register(t, params.Extras[*aaHooks, *aaHooks]{
+		NewRules: func(*params.ChainConfig, *params.Rules, *aaHooks, *big.Int, bool, uint64) *aaHooks {
+			return hooks
+		},
+	})
+
+	sender := rng.Address()
+	newMsg := func(to common.Address) *core.Message {
+		return &core.Message{
+			From:              sender,
+			To:                &to,
+			GasLimit:          1e5,
+			GasPrice:          big.NewInt(gasPrice),
+			Value:             big.NewInt(0),
+			SkipAccountChecks: true,
+		}
+	}
+
+	t.Run("entrypoint", func(t *testing.T) {
+		sdb, evm := ethtest.NewZeroEVM(t)
+		sdb.SetBalance(hooks.payer, uint256.NewInt(startBalance))
+
+		res, err := core.ApplyMessage(evm, newMsg(hooks.entrypoint), new(core.GasPool).AddGas(30e6))
+		require.NoError(t, err, "core.ApplyMessage()")
+		require.NoError(t, res.Err, "execution error")
+		assert.Equal(t, []byte("op+post"), res.ReturnData, "return data modified by post-op hook")
+
+		wantUsed := params.TxGas + entryGas + postOpGas
+		assert.Equal(t, wantUsed, res.UsedGas, "gas used")
+		assert.Equal(t, uint256.NewInt(startBalance-wantUsed*gasPrice), sdb.GetBalance(hooks.payer), "payer balance")
+		assert.True(t, sdb.GetBalance(sender).IsZero(), "sender balance")
+	})
+
+	t.Run("validation_error", func(t *testing.T) {
+		errInvalid := errors.New("invalid operation")
+		hooks.validationErr = errInvalid
+		t.Cleanup(func() { hooks.validationErr = nil })
+
+		_, evm := ethtest.NewZeroEVM(t)
+		_, err := core.ApplyMessage(evm, newMsg(hooks.entrypoint), new(core.GasPool).AddGas(30e6))
+		require.ErrorIs(t, err, errInvalid, "core.ApplyMessage()")
+	})
+
+	t.Run("not_entrypoint", func(t *testing.T) {
+		sdb, evm := ethtest.NewZeroEVM(t)
+		sdb.SetBalance(hooks.payer, uint256.NewInt(startBalance))
+
+		_, err := core.ApplyMessage(evm, newMsg(rng.Address()), new(core.GasPool).AddGas(30e6))
+		require.ErrorIs(t, err, core.ErrInsufficientFunds, "core.ApplyMessage() without balance in sender")
+	})
+}
@@ -0,0 +1,27 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import "github.com/ava-labs/libevm/core/types"
+
+// SetBlockExtra sets a function to attach an extra payload to the generated
+// block, immediately after it is assembled and before it is returned or used as
+// the parent of the next block. The function is typically a closure over
+// [types.ExtraPayloads.BlockWithExtra].
+func (b *BlockGen) SetBlockExtra(attach func(*types.Block) *types.Block) {
+	b.withBlockExtra = attach
+}
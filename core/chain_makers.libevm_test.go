@@ -0,0 +1,58 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/params"
+)
+
+type blockGenPayload struct {
+	types.NOOPBlockBodyHooks
+	x int
+}
+
+func (p *blockGenPayload) Copy() *blockGenPayload {
+	return &blockGenPayload{x: p.x}
+}
+
+func TestBlockGenSetBlockExtra(t *testing.T) {
+	types.TestOnlyClearRegisteredExtras()
+	t.Cleanup(types.TestOnlyClearRegisteredExtras)
+	extras := types.RegisterExtras[
+		types.NOOPHeaderHooks, *types.NOOPHeaderHooks,
+		blockGenPayload, *blockGenPayload,
+		struct{},
+	]()
+
+	gspec := &core.Genesis{Config: params.TestChainConfig}
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 3, func(i int, b *core.BlockGen) {
+		b.SetBlockExtra(func(blk *types.Block) *types.Block {
+			return extras.BlockWithExtra(blk, &blockGenPayload{x: i})
+		})
+	})
+
+	for i, b := range blocks {
+		require.Equalf(t, i, extras.Block.Get(b).x, "payload of block %d", i)
+	}
+}
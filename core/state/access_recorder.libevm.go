@@ -0,0 +1,87 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"slices"
+	"sort"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+)
+
+// An accessRecorder is a set of accounts, each with a set of storage slots.
+type accessRecorder struct {
+	accessed map[common.Address]map[common.Hash]struct{}
+}
+
+// RecordAccesses starts recording every account and storage slot accessed via
+// the StateDB, be it a read or a write. The returned function stops recording
+// and returns the accesses as an EIP-2930 access list, sorted by address and
+// then by storage key. Storage keys are recorded as passed to the StateDB;
+// i.e. before any [StateDBHooks.TransformStateKey]. Recordings MAY overlap.
+func (s *StateDB) RecordAccesses() (stop func() types.AccessList) {
+	rec := &accessRecorder{
+		accessed: make(map[common.Address]map[common.Hash]struct{}),
+	}
+	s.accessRecorders = append(s.accessRecorders, rec)
+
+	return func() types.AccessList {
+		if i := slices.Index(s.accessRecorders, rec); i != -1 {
+			s.accessRecorders = slices.Delete(s.accessRecorders, i, i+1)
+		}
+		return rec.accessList()
+	}
+}
+
+// recordAccess records the account and, if non-nil, the storage slot with all
+// active recorders, including as a read by any [ReadWriteSet] recorders.
+func (s *StateDB) recordAccess(addr common.Address, slot *common.Hash) {
+	s.recordRead(addr, slot)
+	for _, r := range s.accessRecorders {
+		slots, ok := r.accessed[addr]
+		if !ok {
+			slots = make(map[common.Hash]struct{})
+			r.accessed[addr] = slots
+		}
+		if slot != nil {
+			slots[*slot] = struct{}{}
+		}
+	}
+}
+
+func (r *accessRecorder) accessList() types.AccessList {
+	al := make(types.AccessList, 0, len(r.accessed))
+	for addr, slots := range r.accessed {
+		t := types.AccessTuple{
+			Address:     addr,
+			StorageKeys: make([]common.Hash, 0, len(slots)),
+		}
+		for k := range slots {
+			t.StorageKeys = append(t.StorageKeys, k)
+		}
+		sort.Slice(t.StorageKeys, func(i, j int) bool {
+			return bytes.Compare(t.StorageKeys[i][:], t.StorageKeys[j][:]) < 0
+		})
+		al = append(al, t)
+	}
+	sort.Slice(al, func(i, j int) bool {
+		return bytes.Compare(al[i].Address[:], al[j].Address[:]) < 0
+	})
+	return al
+}
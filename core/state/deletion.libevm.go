@@ -0,0 +1,110 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/libevm/common"
+)
+
+// An AccountDeletionReason describes why [StateDB.Finalise] deleted an account.
+type AccountDeletionReason uint8
+
+// Reasons for account deletion.
+const (
+	// SelfDestructed accounts executed SELFDESTRUCT; under EIP-6780 this is
+	// only the case if they were created in the same transaction.
+	SelfDestructed AccountDeletionReason = iota + 1
+	// EmptyAccountCleared accounts were touched while empty, and deleted in
+	// accordance with EIP-158.
+	EmptyAccountCleared
+)
+
+// String returns a human-readable representation of the reason.
+func (r AccountDeletionReason) String() string {
+	switch r {
+	case SelfDestructed:
+		return "self-destructed"
+	case EmptyAccountCleared:
+		return "empty account cleared"
+	default:
+		return fmt.Sprintf("%T(%d)", r, r)
+	}
+}
+
+// AccountDeletionHooks MAY be implemented by the [StateDBHooks] passed to
+// [RegisterExtras] to be notified of every account deleted by
+// [StateDB.Finalise], allowing derived indexes to remain consistent with the
+// account lifecycle. Notifications are delivered after all deletions of a
+// single call to Finalise, in ascending order of address, and only then is the
+// transaction's journal cleared. OnAccountDeleted MUST NOT modify the StateDB
+// and MUST be safe for concurrent use by different StateDB instances.
+type AccountDeletionHooks interface {
+	OnAccountDeleted(common.Address, AccountDeletionReason)
+}
+
+// A deletionNotifier collects account deletions for delivery to the
+// registered [AccountDeletionHooks]. A nil deletionNotifier is valid and
+// discards all deletions, avoiding allocations if there is no listener.
+type deletionNotifier struct {
+	hooks   AccountDeletionHooks
+	deleted []accountDeletion
+}
+
+type accountDeletion struct {
+	addr   common.Address
+	reason AccountDeletionReason
+}
+
+func newDeletionNotifier() *deletionNotifier {
+	h, ok := RegisteredExtras()
+	if !ok {
+		return nil
+	}
+	if h, ok := h.(AccountDeletionHooks); ok {
+		return &deletionNotifier{hooks: h}
+	}
+	return nil
+}
+
+func (n *deletionNotifier) add(obj *stateObject) {
+	if n == nil {
+		return
+	}
+	reason := EmptyAccountCleared
+	if obj.selfDestructed {
+		reason = SelfDestructed
+	}
+	n.deleted = append(n.deleted, accountDeletion{obj.address, reason})
+}
+
+// send delivers all deletions in a deterministic order, which isn't that of
+// the journal's dirty accounts.
+func (n *deletionNotifier) send() {
+	if n == nil {
+		return
+	}
+	slices.SortFunc(n.deleted, func(a, b accountDeletion) int {
+		return bytes.Compare(a.addr[:], b.addr[:])
+	})
+	for _, d := range n.deleted {
+		n.hooks.OnAccountDeleted(d.addr, d.reason)
+	}
+}
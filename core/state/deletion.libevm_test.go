@@ -0,0 +1,70 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+)
+
+type deletionRecorder struct {
+	noopHooks
+	got []accountDeletion
+}
+
+func (r *deletionRecorder) OnAccountDeleted(addr common.Address, reason AccountDeletionReason) {
+	r.got = append(r.got, accountDeletion{addr, reason})
+}
+
+func TestAccountDeletionHooks(t *testing.T) {
+	rec := new(deletionRecorder)
+	TestOnlyClearRegisteredExtras()
+	RegisterExtras(rec)
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+
+	sdb, err := New(types.EmptyRootHash, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err, "New()")
+
+	var (
+		destructed = common.Address{3}
+		empty      = common.Address{1}
+		funded     = common.Address{2}
+	)
+	sdb.AddBalance(destructed, uint256.NewInt(1))
+	sdb.AddBalance(funded, uint256.NewInt(1))
+	sdb.AddBalance(empty, new(uint256.Int)) // touch
+	sdb.Finalise(false)
+	assert.Empty(t, rec.got, "deletions without self-destruct or EIP-158 clearing")
+
+	sdb.SelfDestruct(destructed)
+	sdb.AddBalance(empty, new(uint256.Int))
+	sdb.AddBalance(funded, uint256.NewInt(1))
+	sdb.Finalise(true)
+
+	want := []accountDeletion{
+		{empty, EmptyAccountCleared},
+		{destructed, SelfDestructed},
+	}
+	assert.Equal(t, want, rec.got, "deletions, in order of address")
+}
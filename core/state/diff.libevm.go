@@ -0,0 +1,259 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+)
+
+// A StateDiff describes changes to accounts between two points in the
+// execution of a transaction. Only accounts with at least one change are
+// present.
+type StateDiff map[common.Address]*AccountDiff
+
+// An AccountDiff describes changes to a single account. Fields are nil if the
+// respective value is unchanged. Storage keys are those stored in the trie; i.e.
+// after any transformation by [StateDBHooks.TransformStateKey].
+type AccountDiff struct {
+	Balance  *Change[*uint256.Int]               `json:"balance,omitempty"`
+	Nonce    *Change[uint64]                     `json:"nonce,omitempty"`
+	CodeHash *Change[common.Hash]                `json:"codeHash,omitempty"`
+	Storage  map[common.Hash]Change[common.Hash] `json:"storage,omitempty"`
+}
+
+// A Change describes a value before and after a modification.
+type Change[T any] struct {
+	Before T `json:"before"`
+	After  T `json:"after"`
+}
+
+// DiffSinceSnapshot returns the changes made since the snapshot with the
+// specified ID, as returned by [StateDB.Snapshot], was taken.
+func (s *StateDB) DiffSinceSnapshot(id int) (StateDiff, error) {
+	from, err := s.snapshotJournalIndex(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.diffJournal(from, s.journal.length()), nil
+}
+
+// DiffBetweenSnapshots returns the changes made between the two snapshots,
+// which MUST have been taken in the order of the arguments.
+func (s *StateDB) DiffBetweenSnapshots(from, to int) (StateDiff, error) {
+	start, err := s.snapshotJournalIndex(from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := s.snapshotJournalIndex(to)
+	if err != nil {
+		return nil, err
+	}
+	if start > end {
+		return nil, fmt.Errorf("snapshot %d taken after snapshot %d", from, to)
+	}
+	return s.diffJournal(start, end), nil
+}
+
+// TxDiff returns the changes made since the start of the current transaction;
+// i.e. since the last call to [StateDB.Finalise] or [StateDB.IntermediateRoot].
+func (s *StateDB) TxDiff() StateDiff {
+	return s.diffJournal(0, s.journal.length())
+}
+
+func (s *StateDB) snapshotJournalIndex(id int) (int, error) {
+	idx := sort.Search(len(s.validRevisions), func(i int) bool {
+		return s.validRevisions[i].id >= id
+	})
+	if idx == len(s.validRevisions) || s.validRevisions[idx].id != id {
+		return 0, fmt.Errorf("invalid snapshot ID %d", id)
+	}
+	return s.validRevisions[idx].journalIndex, nil
+}
+
+// diffJournal computes the diff between the states at the journal indices
+// `from` and `to`. The state at an index is reconstructed from the previous
+// values recorded by the first subsequent journal entry to modify each value,
+// falling back to the current state if there is no such entry.
+func (s *StateDB) diffJournal(from, to int) StateDiff {
+	before := s.viewAt(from)
+	after := s.viewAt(to)
+
+	diff := make(StateDiff)
+	for _, entry := range s.journal.entries[from:to] {
+		addr := entry.dirtied()
+		if addr == nil {
+			continue
+		}
+		b, a := before.account(*addr), after.account(*addr)
+
+		d, ok := diff[*addr]
+		if !ok {
+			d = new(AccountDiff)
+		}
+		if x, y := b.balance(), a.balance(); !x.Eq(y) {
+			d.Balance = &Change[*uint256.Int]{x, y}
+		}
+		if x, y := b.nonce(), a.nonce(); x != y {
+			d.Nonce = &Change[uint64]{x, y}
+		}
+		if x, y := b.codeHash(), a.codeHash(); x != y {
+			d.CodeHash = &Change[common.Hash]{x, y}
+		}
+		if ch, ok := entry.(storageChange); ok {
+			if x, y := b.state(ch.key), a.state(ch.key); x != y {
+				if d.Storage == nil {
+					d.Storage = make(map[common.Hash]Change[common.Hash])
+				}
+				d.Storage[ch.key] = Change[common.Hash]{x, y}
+			}
+		}
+
+		if d.Balance != nil || d.Nonce != nil || d.CodeHash != nil || len(d.Storage) > 0 {
+			diff[*addr] = d
+		}
+	}
+	return diff
+}
+
+// A stateView reconstructs account values at a point in the journal.
+type stateView struct {
+	db       *StateDB
+	accounts map[common.Address]*accountView
+}
+
+type accountView struct {
+	// Values recorded by journal entries take precedence.
+	bal      *uint256.Int
+	non      *uint64
+	hash     *common.Hash
+	storage  map[common.Hash]common.Hash
+	fallback *stateObject
+}
+
+func (s *StateDB) viewAt(idx int) *stateView {
+	v := &stateView{
+		db:       s,
+		accounts: make(map[common.Address]*accountView),
+	}
+	for _, entry := range s.journal.entries[idx:] {
+		addr := entry.dirtied()
+		if addr == nil {
+			continue
+		}
+		_, seen := v.accounts[*addr]
+		a := v.account(*addr)
+
+		switch ch := entry.(type) {
+		case createObjectChange:
+			if !seen {
+				a.fallback = nil
+			}
+		case resetObjectChange:
+			if !seen {
+				a.fallback = ch.prev
+			}
+		case balanceChange:
+			if a.bal == nil {
+				a.bal = ch.prev
+			}
+		case selfDestructChange:
+			if a.bal == nil {
+				a.bal = ch.prevbalance
+			}
+		case nonceChange:
+			if a.non == nil {
+				n := ch.prev
+				a.non = &n
+			}
+		case codeChange:
+			if a.hash == nil {
+				h := common.BytesToHash(ch.prevhash)
+				a.hash = &h
+			}
+		case storageChange:
+			if _, ok := a.storage[ch.key]; !ok {
+				a.storage[ch.key] = ch.prevalue
+			}
+		}
+	}
+	return v
+}
+
+func (v *stateView) account(addr common.Address) *accountView {
+	if a, ok := v.accounts[addr]; ok {
+		return a
+	}
+	a := &accountView{
+		storage:  make(map[common.Hash]common.Hash),
+		fallback: v.db.getStateObject(addr),
+	}
+	v.accounts[addr] = a
+	return a
+}
+
+func (a *accountView) live() *stateObject {
+	if o := a.fallback; o != nil && !o.deleted {
+		return o
+	}
+	return nil
+}
+
+func (a *accountView) balance() *uint256.Int {
+	if a.bal != nil {
+		return a.bal
+	}
+	if o := a.live(); o != nil {
+		return o.Balance()
+	}
+	return new(uint256.Int)
+}
+
+func (a *accountView) nonce() uint64 {
+	if a.non != nil {
+		return *a.non
+	}
+	if o := a.live(); o != nil {
+		return o.Nonce()
+	}
+	return 0
+}
+
+func (a *accountView) codeHash() common.Hash {
+	if a.hash != nil {
+		return *a.hash
+	}
+	if o := a.live(); o != nil {
+		return common.BytesToHash(o.CodeHash())
+	}
+	return types.EmptyCodeHash
+}
+
+func (a *accountView) state(key common.Hash) common.Hash {
+	if val, ok := a.storage[key]; ok {
+		return val
+	}
+	if o := a.live(); o != nil {
+		return o.GetState(key)
+	}
+	return common.Hash{}
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm"
+)
+
+// ProofOfStorage returns Merkle proofs of the account and of the specified
+// storage slots against the pre-state root; i.e. the root at which the StateDB
+// was opened, or that of the last [StateDB.Commit]. Proven values therefore
+// exclude all uncommitted changes, which makes them deterministic across
+// nodes when executing the same block.
+//
+// Slots are transformed by [StateDBHooks.TransformStateKey], as with
+// [StateDB.GetState], and the returned [libevm.SlotProof] keys are those that
+// are proven.
+func (s *StateDB) ProofOfStorage(addr common.Address, slots []common.Hash) (*libevm.StorageProof, error) {
+	tr, err := s.db.OpenTrie(s.originalRoot)
+	if err != nil {
+		return nil, err
+	}
+	proof := &libevm.StorageProof{
+		StateRoot:   s.originalRoot,
+		Address:     addr,
+		StorageRoot: types.EmptyRootHash,
+		Slots:       make([]libevm.SlotProof, len(slots)),
+	}
+
+	var accountProof proofNodes
+	if err := tr.Prove(crypto.Keccak256(addr.Bytes()), &accountProof); err != nil {
+		return nil, err
+	}
+	proof.AccountProof = accountProof
+
+	acc, err := tr.GetAccount(addr)
+	if err != nil {
+		return nil, err
+	}
+	if acc != nil {
+		proof.StorageRoot = acc.Root
+	}
+	st, err := s.db.OpenStorageTrie(s.originalRoot, addr, proof.StorageRoot, tr)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, slot := range slots {
+		key := transformStateKey(addr, slot)
+		val, err := st.GetStorage(addr, key.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		var nodes proofNodes
+		if err := st.Prove(crypto.Keccak256(key.Bytes()), &nodes); err != nil {
+			return nil, err
+		}
+		proof.Slots[i] = libevm.SlotProof{
+			Key:   key,
+			Value: common.BytesToHash(val),
+			Proof: nodes,
+		}
+	}
+	return proof, nil
+}
+
+// proofNodes implements [ethdb.KeyValueWriter] to collect proof nodes, in the
+// order in which they are written.
+type proofNodes [][]byte
+
+func (n *proofNodes) Put(_, value []byte) error {
+	*n = append(*n, common.CopyBytes(value))
+	return nil
+}
+
+func (*proofNodes) Delete([]byte) error {
+	return errors.New("deletion from proof not supported")
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"slices"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/libevm/set"
+)
+
+// A ReadWriteSet records the state read and written by a transaction, allowing
+// a parallel executor to detect conflicts between transactions run
+// concurrently, typically falling back to serial execution of those that
+// conflict. See [StateDB.RecordReadWriteSet].
+type ReadWriteSet struct {
+	Reads, Writes AccessSet
+	// Declared is not populated by the [StateDB] but MAY be set to the keys
+	// returned by vm.EVM.DeclaredConflicts(), which are then considered by
+	// [ReadWriteSet.ConflictsWith].
+	Declared set.Set[common.Hash]
+}
+
+// An AccessSet is a set of accessed accounts.
+type AccessSet map[common.Address]*AccountAccess
+
+// An AccountAccess describes the access of a single account. Account is true
+// if any non-storage field (e.g. balance, nonce, or code) or the existence of
+// the account was accessed. Storage slots are recorded as passed to the
+// StateDB; i.e. before any [StateDBHooks.TransformStateKey].
+type AccountAccess struct {
+	Account bool
+	Slots   set.Set[common.Hash]
+}
+
+func (s AccessSet) add(addr common.Address, slot *common.Hash) {
+	a, ok := s[addr]
+	if !ok {
+		a = &AccountAccess{Slots: make(set.Set[common.Hash])}
+		s[addr] = a
+	}
+	if slot == nil {
+		a.Account = true
+	} else {
+		a.Slots[*slot] = struct{}{}
+	}
+}
+
+// RecordReadWriteSet starts recording every account and storage slot read or
+// written via the StateDB. The returned function stops recording and returns
+// the set. Recordings MAY overlap.
+//
+// Recording is conservative: writes are recorded even if later reverted, every
+// write is also recorded as a read, and every storage read is also recorded as
+// a read of the account. Transaction fees are paid to the coinbase account so
+// a write to it is recorded by every transaction; executors SHOULD account for
+// fees themselves and remove the coinbase from the set before checking for
+// conflicts.
+func (s *StateDB) RecordReadWriteSet() (stop func() *ReadWriteSet) {
+	rw := &ReadWriteSet{
+		Reads:  make(AccessSet),
+		Writes: make(AccessSet),
+	}
+	s.readWriteRecorders = append(s.readWriteRecorders, rw)
+
+	return func() *ReadWriteSet {
+		if i := slices.Index(s.readWriteRecorders, rw); i != -1 {
+			s.readWriteRecorders = slices.Delete(s.readWriteRecorders, i, i+1)
+		}
+		return rw
+	}
+}
+
+// recordRead records the read of the account or, if non-nil, the storage slot
+// with all active [ReadWriteSet] recorders.
+func (s *StateDB) recordRead(addr common.Address, slot *common.Hash) {
+	for _, rw := range s.readWriteRecorders {
+		rw.Reads.add(addr, slot)
+	}
+}
+
+// recordWrite is the write equivalent of [StateDB.recordRead], also recording
+// a read.
+func (s *StateDB) recordWrite(addr common.Address, slot *common.Hash) {
+	for _, rw := range s.readWriteRecorders {
+		rw.Reads.add(addr, slot)
+		rw.Writes.add(addr, slot)
+	}
+}
+
+// ConflictsWith returns whether the transactions that produced the respective
+// sets MUST NOT be executed concurrently; i.e. if either wrote state accessed
+// by the other or if they declared a common conflict key.
+//
+// Writes to an account's non-storage fields conflict with all reads of the
+// account, including of its storage, whereas writes to storage slots only
+// conflict with accesses of the same slots.
+func (rw *ReadWriteSet) ConflictsWith(other *ReadWriteSet) bool {
+	return rw.Writes.overlaps(other.Reads) ||
+		rw.Writes.overlaps(other.Writes) ||
+		other.Writes.overlaps(rw.Reads) ||
+		len(rw.Declared.Intersect(other.Declared)) > 0
+}
+
+func (s AccessSet) overlaps(t AccessSet) bool {
+	for addr, a := range s {
+		b, ok := t[addr]
+		if !ok {
+			continue
+		}
+		if a.Account && b.Account || len(a.Slots.Intersect(b.Slots)) > 0 {
+			return true
+		}
+	}
+	return false
+}
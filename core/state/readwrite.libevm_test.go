@@ -0,0 +1,146 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/libevm/set"
+)
+
+func TestRecordReadWriteSet(t *testing.T) {
+	state, err := New(types.EmptyRootHash, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err, "New()")
+
+	var (
+		a = common.Address{'a'}
+		b = common.Address{'b'}
+		c = common.Address{'c'}
+	)
+	state.SetNonce(a, 1) // not recorded
+
+	stop := state.RecordReadWriteSet()
+	_ = state.GetBalance(b)
+	_ = state.GetState(c, common.Hash{1})
+	state.SetState(c, common.Hash{2}, common.Hash{42})
+	state.AddBalance(a, uint256.NewInt(1))
+	got := stop()
+
+	_ = state.GetState(b, common.Hash{3}) // not recorded
+
+	want := &ReadWriteSet{
+		Reads: AccessSet{
+			a: {Account: true, Slots: set.From[common.Hash]()},
+			b: {Account: true, Slots: set.From[common.Hash]()},
+			c: {Account: true, Slots: set.From(common.Hash{1}, common.Hash{2})},
+		},
+		Writes: AccessSet{
+			a: {Account: true, Slots: set.From[common.Hash]()},
+			c: {Slots: set.From(common.Hash{2})},
+		},
+	}
+	assert.Equal(t, want, got, "recording")
+	assert.Empty(t, state.readWriteRecorders, "recorders after stopping")
+}
+
+func TestReadWriteSetConflicts(t *testing.T) {
+	addr := common.Address{'a'}
+	slot := func(s byte) *common.Hash {
+		return &common.Hash{s}
+	}
+
+	type access struct {
+		write bool
+		slot  *common.Hash // nil for the account
+	}
+	rwSet := func(accesses ...access) *ReadWriteSet {
+		rw := &ReadWriteSet{
+			Reads:  make(AccessSet),
+			Writes: make(AccessSet),
+		}
+		for _, a := range accesses {
+			rw.Reads.add(addr, a.slot)
+			if a.write {
+				rw.Writes.add(addr, a.slot)
+			}
+		}
+		return rw
+	}
+
+	tests := []struct {
+		name string
+		a, b *ReadWriteSet
+		want bool
+	}{
+		{
+			name: "both_read_account",
+			a:    rwSet(access{}),
+			b:    rwSet(access{}),
+			want: false,
+		},
+		{
+			name: "write_read_account",
+			a:    rwSet(access{write: true}),
+			b:    rwSet(access{}),
+			want: true,
+		},
+		{
+			name: "write_same_slot",
+			a:    rwSet(access{write: true, slot: slot(1)}),
+			b:    rwSet(access{write: true, slot: slot(1)}),
+			want: true,
+		},
+		{
+			name: "write_read_same_slot",
+			a:    rwSet(access{write: true, slot: slot(1)}),
+			b:    rwSet(access{slot: slot(1)}),
+			want: true,
+		},
+		{
+			name: "write_different_slots",
+			a:    rwSet(access{}, access{write: true, slot: slot(1)}),
+			b:    rwSet(access{}, access{write: true, slot: slot(2)}),
+			want: false,
+		},
+		{
+			name: "declared",
+			a:    &ReadWriteSet{Declared: set.From(common.Hash{1}, common.Hash{2})},
+			b:    &ReadWriteSet{Declared: set.From(common.Hash{2})},
+			want: true,
+		},
+		{
+			name: "declared_different",
+			a:    &ReadWriteSet{Declared: set.From(common.Hash{1})},
+			b:    &ReadWriteSet{Declared: set.From(common.Hash{2})},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.a.ConflictsWith(tt.b), "a.ConflictsWith(b)")
+			assert.Equal(t, tt.want, tt.b.ConflictsWith(tt.a), "b.ConflictsWith(a)")
+		})
+	}
+}
@@ -18,6 +18,7 @@
 package state
 
 import (
+	"io"
 	"fmt"
 	golog "log"
 	"maps"
@@ -147,12 +148,17 @@ type StateDB struct {
 
 	// op log
 	opLogger *golog.Logger
+
+	subTries           map[subTrieID]*SubTrie // libevm: see [StateDB.SubTrie]
+	accessRecorders    []*accessRecorder      // libevm: see [StateDB.RecordAccesses]
+	readWriteRecorders []*ReadWriteSet        // libevm: see [StateDB.RecordReadWriteSet]
 }
 
 // New creates a new state from a given trie.
 func New(root common.Hash, db Database, snaps SnapshotTree) (*StateDB, error) {
-	fmt.Println("StateDB without logger ====================")
-	debug.PrintStack()
+	_ = fmt.Println
+	_ = debug.PrintStack
+	return NewWithLogger(root, db, snaps, golog.New(io.Discard, "", 0))
 	snaps = clearTypedNilPointer(snaps)
 	tr, err := db.OpenTrie(root)
 	if err != nil {
@@ -394,6 +400,7 @@ func (s *StateDB) GetCodeHash(addr common.Address) common.Hash {
 // GetState retrieves a value from the given account's storage trie.
 func (s *StateDB) GetState(addr common.Address, hash common.Hash, opts ...stateconf.StateDBStateOption) common.Hash {
 	s.opLogger.Printf("%x,GetState,%x,%x", s.txIndex, addr, hash)
+	s.recordAccess(addr, &hash) // libevm
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		hash = transformStateKey(addr, hash, opts...)
@@ -405,6 +412,7 @@ func (s *StateDB) GetState(addr common.Address, hash common.Hash, opts ...statec
 // GetCommittedState retrieves a value from the given account's committed storage trie.
 func (s *StateDB) GetCommittedState(addr common.Address, hash common.Hash, opts ...stateconf.StateDBStateOption) common.Hash {
 	s.opLogger.Printf("%x,GetCommittedState,%x,%x", s.txIndex, addr, hash)
+	s.recordAccess(addr, &hash) // libevm
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		hash = transformStateKey(addr, hash, opts...)
@@ -434,6 +442,7 @@ func (s *StateDB) HasSelfDestructed(addr common.Address) bool {
 // AddBalance adds amount to the account associated with addr.
 func (s *StateDB) AddBalance(addr common.Address, amount *uint256.Int) {
 	s.opLogger.Printf("%x,AddBalance,%x", s.txIndex, addr)
+	s.recordWrite(addr, nil) // libevm
 	stateObject := s.getOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.AddBalance(amount)
@@ -443,6 +452,7 @@ func (s *StateDB) AddBalance(addr common.Address, amount *uint256.Int) {
 // SubBalance subtracts amount from the account associated with addr.
 func (s *StateDB) SubBalance(addr common.Address, amount *uint256.Int) {
 	s.opLogger.Printf("%x,SubBalance,%x", s.txIndex, addr)
+	s.recordWrite(addr, nil) // libevm
 	stateObject := s.getOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SubBalance(amount)
@@ -451,6 +461,7 @@ func (s *StateDB) SubBalance(addr common.Address, amount *uint256.Int) {
 
 func (s *StateDB) SetBalance(addr common.Address, amount *uint256.Int) {
 	s.opLogger.Printf("%x,SetBalance,%x", s.txIndex, addr)
+	s.recordWrite(addr, nil) // libevm
 	stateObject := s.getOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetBalance(amount)
@@ -459,6 +470,7 @@ func (s *StateDB) SetBalance(addr common.Address, amount *uint256.Int) {
 
 func (s *StateDB) SetNonce(addr common.Address, nonce uint64) {
 	s.opLogger.Printf("%x,SetNonce,%x", s.txIndex, addr)
+	s.recordWrite(addr, nil) // libevm
 	stateObject := s.getOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetNonce(nonce)
@@ -467,6 +479,7 @@ func (s *StateDB) SetNonce(addr common.Address, nonce uint64) {
 
 func (s *StateDB) SetCode(addr common.Address, code []byte) {
 	s.opLogger.Printf("%x,SetCode,%x", s.txIndex, addr)
+	s.recordWrite(addr, nil) // libevm
 	stateObject := s.getOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.SetCode(crypto.Keccak256Hash(code), code)
@@ -475,6 +488,8 @@ func (s *StateDB) SetCode(addr common.Address, code []byte) {
 
 func (s *StateDB) SetState(addr common.Address, key, value common.Hash, opts ...stateconf.StateDBStateOption) {
 	s.opLogger.Printf("%x,SetState,%x,%x", s.txIndex, addr, key)
+	s.recordWrite(addr, &key)  // libevm
+	s.recordAccess(addr, &key) // libevm
 	stateObject := s.getOrNewStateObject(addr)
 	if stateObject != nil {
 		key = transformStateKey(addr, key, opts...)
@@ -495,6 +510,7 @@ func (s *StateDB) SetStorage(addr common.Address, storage map[common.Hash]common
 	// TODO(rjl493456442) this function should only be supported by 'unwritable'
 	// state and all mutations made should all be discarded afterwards.
 	s.opLogger.Printf("%x,SetStorage,%x", s.txIndex, addr)
+	s.recordWrite(addr, nil) // libevm
 	if _, ok := s.stateObjectsDestruct[addr]; !ok {
 		s.stateObjectsDestruct[addr] = nil
 	}
@@ -511,6 +527,7 @@ func (s *StateDB) SetStorage(addr common.Address, storage map[common.Hash]common
 // getStateObject will return a non-nil account after SelfDestruct.
 func (s *StateDB) SelfDestruct(addr common.Address) {
 	s.opLogger.Printf("%x,SelfDestruct,%x", s.txIndex, addr)
+	s.recordWrite(addr, nil) // libevm
 	stateObject := s.getStateObject(addr)
 	if stateObject == nil {
 		return
@@ -616,6 +633,7 @@ func (s *StateDB) deleteStateObject(obj *stateObject) {
 // the object is not found or was deleted in this execution context. If you need
 // to differentiate between non-existent/just-deleted, use getDeletedStateObject.
 func (s *StateDB) getStateObject(addr common.Address) *stateObject {
+	s.recordAccess(addr, nil) // libevm
 	if obj := s.getDeletedStateObject(addr); obj != nil && !obj.deleted {
 		return obj
 	}
@@ -747,6 +765,7 @@ func (s *StateDB) createObject(addr common.Address) (newobj, prev *stateObject)
 // Carrying over the balance ensures that Ether doesn't disappear.
 func (s *StateDB) CreateAccount(addr common.Address) {
 	s.opLogger.Printf("%x,CreateAccount,%x", s.txIndex, addr)
+	s.recordWrite(addr, nil) // libevm
 	newObj, prev := s.createObject(addr)
 	if prev != nil {
 		newObj.setBalance(prev.data.Balance)
@@ -758,6 +777,7 @@ func (s *StateDB) CreateAccount(addr common.Address) {
 func (s *StateDB) Copy() *StateDB {
 	// Copy all the basic fields, initialize the memory ones
 	state := &StateDB{
+		opLogger:             s.opLogger,
 		db:                   s.db,
 		trie:                 s.db.CopyTrie(s.trie),
 		originalRoot:         s.originalRoot,
@@ -834,6 +854,7 @@ func (s *StateDB) Copy() *StateDB {
 	// in the middle of a transaction.
 	state.accessList = s.accessList.Copy()
 	state.transientStorage = s.transientStorage.Copy()
+	state.subTries = s.copySubTries(state) // libevm
 
 	// If there's a prefetcher running, make an inactive copy of it that can
 	// only access data but does not actively preload (since the user will not
@@ -878,6 +899,7 @@ func (s *StateDB) GetRefund() uint64 {
 // into the tries just yet. Only IntermediateRoot or Commit will do that.
 func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 	addressesToPrefetch := make([][]byte, 0, len(s.journal.dirties))
+	deletions := newDeletionNotifier() // libevm
 	for addr := range s.journal.dirties {
 		obj, exist := s.stateObjects[addr]
 		if !exist {
@@ -891,6 +913,7 @@ func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 		}
 		if obj.selfDestructed || (deleteEmptyObjects && obj.empty()) {
 			obj.deleted = true
+			deletions.add(obj) // libevm
 
 			// We need to maintain account deletions explicitly (will remain
 			// set indefinitely). Note only the first occurred self-destruct
@@ -920,6 +943,7 @@ func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 	if s.prefetcher != nil && len(addressesToPrefetch) > 0 {
 		s.prefetcher.prefetch(common.Hash{}, s.originalRoot, common.Address{}, addressesToPrefetch)
 	}
+	deletions.send() // libevm
 	// Invalidate journal because reverting across transactions is not allowed.
 	s.clearJournalAndRefund()
 }
@@ -1274,6 +1298,11 @@ func (s *StateDB) Commit(block uint64, deleteEmptyObjects bool, opts ...statecon
 			log.Crit("Failed to commit dirty codes", "error", err)
 		}
 	}
+	// libevm: sub-tries are committed after the storage tries that reference
+	// their roots.
+	if err := s.commitSubTries(nodes); err != nil {
+		return common.Hash{}, err
+	}
 	// Write the account trie changes, measuring the amount of wasted time
 	var start time.Time
 	if metrics.EnabledExpensive {
@@ -1370,7 +1399,7 @@ func (s *StateDB) Commit(block uint64, deleteEmptyObjects bool, opts ...statecon
 // - Add coinbase to access list (EIP-3651)
 // - Reset transient storage (EIP-1153)
 func (s *StateDB) Prepare(rules params.Rules, sender, coinbase common.Address, dst *common.Address, precompiles []common.Address, list types.AccessList) {
-	if rules.IsBerlin {
+	if rules.IsEIP2929() { // libevm: was IsBerlin
 		// Clear out any leftover from previous executions
 		al := newAccessList()
 		s.accessList = al
@@ -1,4 +1,4 @@
-// Copyright 2024 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -21,6 +21,8 @@ import (
 
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/core/state/snapshot"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/libevm"
 	"github.com/ava-labs/libevm/libevm/register"
 	"github.com/ava-labs/libevm/libevm/stateconf"
 )
@@ -30,6 +32,59 @@ func (s *StateDB) TxHash() common.Hash {
 	return s.thash
 }
 
+// PrefetchAccessList requests that the trie prefetcher, if running, load the
+// accounts and storage slots in the list. Storage keys are transformed as for
+// [StateDB.GetState]. Loading the root of each storage trie requires reading
+// the respective account, which is performed synchronously.
+func (s *StateDB) PrefetchAccessList(al types.AccessList) {
+	if s.prefetcher == nil || len(al) == 0 {
+		return
+	}
+
+	addrs := make([][]byte, len(al))
+	for i, t := range al {
+		addrs[i] = common.CopyBytes(t.Address[:])
+	}
+	s.prefetcher.prefetch(common.Hash{}, s.originalRoot, common.Address{}, addrs)
+
+	for _, t := range al {
+		if len(t.StorageKeys) == 0 {
+			continue
+		}
+		obj := s.getStateObject(t.Address)
+		if obj == nil {
+			continue
+		}
+		keys := make([][]byte, len(t.StorageKeys))
+		for i, k := range t.StorageKeys {
+			keys[i] = common.CopyBytes(transformStateKey(t.Address, k).Bytes())
+		}
+		s.prefetcher.prefetch(obj.addrHash, obj.data.Root, t.Address, keys)
+	}
+}
+
+// AppendJournalEntry applies the entry and adds it to the journal such that it
+// is reverted along with regular state changes by [StateDB.RevertToSnapshot].
+// Entries are discarded, without being reverted, once the transaction is
+// finalised.
+func (s *StateDB) AppendJournalEntry(e libevm.JournalEntry) {
+	e.Apply()
+	s.journal.append(customChange{e})
+}
+
+// customChange adapts a [libevm.JournalEntry] to the internal journal.
+type customChange struct {
+	entry libevm.JournalEntry
+}
+
+func (ch customChange) revert(*StateDB) {
+	ch.entry.Revert()
+}
+
+func (ch customChange) dirtied() *common.Address {
+	return nil
+}
+
 // SnapshotTree mirrors the functionality of a [snapshot.Tree], allowing for
 // drop-in replacements. This is intended as a temporary feature as a workaround
 // until a standard Tree can be used.
@@ -105,6 +160,16 @@ func TestOnlyClearRegisteredExtras() {
 	registeredExtras.TestOnlyClear()
 }
 
+// RegisteredExtras returns the [StateDBHooks] registered with
+// [RegisterExtras] and whether any have been registered at all.
+func RegisteredExtras() (StateDBHooks, bool) {
+	r := registeredExtras
+	if !r.Registered() {
+		return nil, false
+	}
+	return r.Get(), true
+}
+
 var registeredExtras register.AtMostOnce[StateDBHooks]
 
 func transformStateKey(addr common.Address, key common.Hash, opts ...stateconf.StateDBStateOption) common.Hash {
@@ -1,4 +1,4 @@
-// Copyright 2024-2025 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -17,8 +17,10 @@
 package state
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -26,8 +28,12 @@ import (
 	"github.com/ava-labs/libevm/core/rawdb"
 	"github.com/ava-labs/libevm/core/state/snapshot"
 	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
 	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/ethdb/memorydb"
+	"github.com/ava-labs/libevm/libevm"
 	"github.com/ava-labs/libevm/libevm/stateconf"
+	"github.com/ava-labs/libevm/rlp"
 	"github.com/ava-labs/libevm/trie"
 	"github.com/ava-labs/libevm/trie/trienode"
 	"github.com/ava-labs/libevm/trie/triestate"
@@ -232,3 +238,370 @@ func TestTransformStateKey(t *testing.T) {
 	assertCommittedEq(t, regularKey, flippedVal)
 	assertCommittedEq(t, flippedKey, flippedVal, noTransform)
 }
+
+type recordingJournalEntry struct {
+	id  int
+	log *[]string
+}
+
+func (e recordingJournalEntry) Apply()  { *e.log = append(*e.log, fmt.Sprintf("apply %d", e.id)) }
+func (e recordingJournalEntry) Revert() { *e.log = append(*e.log, fmt.Sprintf("revert %d", e.id)) }
+
+func TestAppendJournalEntry(t *testing.T) {
+	state, err := New(types.EmptyRootHash, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err, "New()")
+
+	var got []string
+	add := func(id int) {
+		state.AppendJournalEntry(recordingJournalEntry{id, &got})
+	}
+
+	add(0)
+	snap := state.Snapshot()
+	add(1)
+	add(2)
+	state.RevertToSnapshot(snap)
+	add(3)
+
+	want := []string{
+		"apply 0",
+		"apply 1",
+		"apply 2",
+		"revert 2",
+		"revert 1",
+		"apply 3",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestStateDiff(t *testing.T) {
+	state, err := New(types.EmptyRootHash, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err, "New()")
+
+	var (
+		alice = common.Address{'a'}
+		bob   = common.Address{'b'}
+		slot  = common.Hash{'s'}
+		val   = common.Hash{'v'}
+		code  = []byte{0x60, 0x00}
+	)
+
+	state.AddBalance(alice, uint256.NewInt(100))
+	start := state.Snapshot()
+
+	state.SetNonce(alice, 1)
+	state.SetState(alice, slot, val)
+	mid := state.Snapshot()
+
+	state.SubBalance(alice, uint256.NewInt(40))
+	state.AddBalance(bob, uint256.NewInt(40))
+	state.SetCode(bob, code)
+	state.SetState(alice, slot, common.Hash{}) // restored to its original value
+
+	t.Run("between_snapshots", func(t *testing.T) {
+		got, err := state.DiffBetweenSnapshots(start, mid)
+		require.NoError(t, err)
+		want := StateDiff{
+			alice: {
+				Nonce: &Change[uint64]{0, 1},
+				Storage: map[common.Hash]Change[common.Hash]{
+					slot: {common.Hash{}, val},
+				},
+			},
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("since_snapshot", func(t *testing.T) {
+		got, err := state.DiffSinceSnapshot(mid)
+		require.NoError(t, err)
+		want := StateDiff{
+			alice: {
+				Balance: &Change[*uint256.Int]{uint256.NewInt(100), uint256.NewInt(60)},
+				Storage: map[common.Hash]Change[common.Hash]{
+					slot: {val, common.Hash{}},
+				},
+			},
+			bob: {
+				Balance:  &Change[*uint256.Int]{uint256.NewInt(0), uint256.NewInt(40)},
+				CodeHash: &Change[common.Hash]{types.EmptyCodeHash, crypto.Keccak256Hash(code)},
+			},
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("tx", func(t *testing.T) {
+		got := state.TxDiff()
+		want := StateDiff{
+			alice: {
+				Balance: &Change[*uint256.Int]{uint256.NewInt(0), uint256.NewInt(60)},
+				Nonce:   &Change[uint64]{0, 1},
+			},
+			bob: {
+				Balance:  &Change[*uint256.Int]{uint256.NewInt(0), uint256.NewInt(40)},
+				CodeHash: &Change[common.Hash]{types.EmptyCodeHash, crypto.Keccak256Hash(code)},
+			},
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("invalid_snapshots", func(t *testing.T) {
+		_, err := state.DiffSinceSnapshot(mid + 100)
+		assert.Error(t, err, "DiffSinceSnapshot(unknown ID)")
+		_, err = state.DiffBetweenSnapshots(mid, start)
+		assert.Error(t, err, "DiffBetweenSnapshots() out of order")
+	})
+}
+
+func TestSubTrie(t *testing.T) {
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	state, err := New(types.EmptyRootHash, db, nil)
+	require.NoError(t, err, "New()")
+
+	owner := common.Address{'p', 'r', 'e'}
+	const name = "registry"
+	state.SetNonce(owner, 1) // avoid deletion as an empty account
+
+	sub, err := state.SubTrie(owner, name)
+	require.NoErrorf(t, err, "%T.SubTrie()", state)
+
+	kv := map[string]string{
+		"apple":  "red",
+		"banana": "yellow",
+		"cherry": "dark red",
+	}
+	for k, v := range kv {
+		require.NoErrorf(t, sub.Update([]byte(k), []byte(v)), "%T.Update(%q)", sub, k)
+	}
+	rootBefore := sub.Root()
+	assert.Equal(t, rootBefore, state.GetState(owner, SubTrieRootSlot(name)), "root stored in owner's storage")
+
+	snap := state.Snapshot()
+	require.NoError(t, sub.Update([]byte("durian"), []byte("green")))
+	require.NoError(t, sub.Delete([]byte("apple")))
+	state.RevertToSnapshot(snap)
+	assert.Equal(t, rootBefore, sub.Root(), "root after RevertToSnapshot()")
+	assert.Equal(t, rootBefore, state.GetState(owner, SubTrieRootSlot(name)), "stored root after RevertToSnapshot()")
+
+	it, err := sub.Iterator([]byte("b"))
+	require.NoErrorf(t, err, "%T.Iterator()", sub)
+	var keys []string
+	for it.Next() {
+		keys = append(keys, string(it.Key))
+	}
+	require.NoError(t, it.Err, "iteration")
+	assert.Equal(t, []string{"banana", "cherry"}, keys, "keys iterated in order from start")
+
+	stateRoot, err := state.Commit(1, true)
+	require.NoErrorf(t, err, "%T.Commit()", state)
+	require.NoError(t, db.TrieDB().Commit(stateRoot, false), "TrieDB().Commit()")
+
+	reopened, err := New(stateRoot, db, nil)
+	require.NoError(t, err, "New() at committed root")
+	sub, err = reopened.SubTrie(owner, name)
+	require.NoErrorf(t, err, "%T.SubTrie() after commit", reopened)
+	assert.Equal(t, rootBefore, sub.Root(), "root after commit")
+
+	for k, v := range kv {
+		got, err := sub.Get([]byte(k))
+		require.NoErrorf(t, err, "%T.Get(%q)", sub, k)
+		assert.Equalf(t, v, string(got), "%T.Get(%q) after commit", sub, k)
+
+		proof := memorydb.New()
+		require.NoErrorf(t, sub.Prove([]byte(k), proof), "%T.Prove(%q)", sub, k)
+		got, err = trie.VerifyProof(sub.Root(), []byte(k), proof)
+		require.NoErrorf(t, err, "trie.VerifyProof(%q)", k)
+		assert.Equalf(t, v, string(got), "trie.VerifyProof(%q)", k)
+	}
+}
+
+func TestStorageRange(t *testing.T) {
+	db := NewDatabaseWithConfig(rawdb.NewMemoryDatabase(), &triedb.Config{Preimages: true})
+	state, err := New(types.EmptyRootHash, db, nil)
+	require.NoError(t, err, "New()")
+
+	addr := common.Address{'r', 'e', 'g'}
+	state.SetNonce(addr, 1)
+	for i := byte(1); i <= 4; i++ {
+		state.SetState(addr, common.Hash{i}, common.Hash{i})
+	}
+	root, err := state.Commit(1, true)
+	require.NoErrorf(t, err, "%T.Commit()", state)
+
+	state, err = New(root, db, nil)
+	require.NoError(t, err, "New() at committed root")
+	// Uncommitted changes: one modification, one deletion, and one addition.
+	state.SetState(addr, common.Hash{1}, common.Hash{42})
+	state.SetState(addr, common.Hash{2}, common.Hash{})
+	state.SetState(addr, common.Hash{5}, common.Hash{5})
+
+	want := map[common.Hash]common.Hash{
+		{1}: {42},
+		{3}: {3},
+		{4}: {4},
+		{5}: {5},
+	}
+
+	var (
+		got   = make(map[common.Hash]common.Hash)
+		start common.Hash
+		last  common.Hash
+		pages int
+	)
+	for {
+		res, err := state.StorageRange(addr, start, 3)
+		require.NoErrorf(t, err, "%T.StorageRange(..., %v, ...)", state, start)
+		pages++
+
+		for _, slot := range res.Slots {
+			assert.Truef(t, slot.HashedKey.Cmp(last) > 0, "slots in ascending order of hashed key")
+			last = slot.HashedKey
+			require.NotNilf(t, slot.Key, "preimage of %v", slot.HashedKey)
+			assert.Equal(t, crypto.Keccak256Hash(slot.Key.Bytes()), slot.HashedKey, "preimage")
+			got[*slot.Key] = slot.Value
+		}
+		if res.NextKey == nil {
+			break
+		}
+		start = *res.NextKey
+	}
+	assert.Equal(t, want, got, "all slots across pages")
+	assert.Equal(t, 2, pages, "number of pages")
+
+	res, err := state.StorageRange(common.Address{'n', 'o', 'n', 'e'}, common.Hash{}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, res.Slots, "slots of non-existent account")
+	assert.Nil(t, res.NextKey, "next key of non-existent account")
+}
+
+func TestGetStates(t *testing.T) {
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	state, err := New(types.EmptyRootHash, db, nil)
+	require.NoError(t, err, "New()")
+
+	addr := common.Address{'b', 'a', 't', 'c', 'h'}
+	state.SetNonce(addr, 1)
+	for i := byte(1); i <= 8; i++ {
+		state.SetState(addr, common.Hash{i}, common.Hash{i, i})
+	}
+	root, err := state.Commit(1, true)
+	require.NoErrorf(t, err, "%T.Commit()", state)
+
+	state, err = New(root, db, nil)
+	require.NoError(t, err, "New() at committed root")
+	state.SetState(addr, common.Hash{2}, common.Hash{42})
+	state.SetState(addr, common.Hash{3}, common.Hash{})
+	_ = state.GetState(addr, common.Hash{4}) // cached as origin
+
+	keys := []common.Hash{{8}, {2}, {3}, {4}, {99}, {1}, {8}}
+	want := make([]common.Hash, len(keys))
+	for i, k := range keys {
+		want[i] = state.Copy().GetState(addr, k)
+	}
+	assert.Equal(t, want, state.GetStates(addr, keys), "GetStates() vs GetState() per key")
+	assert.Equal(t, want, state.GetStates(addr, keys), "GetStates() after caching")
+	// A wrapper hides the optional method, exercising the fallback.
+	wrapped := struct{ libevm.StateReader }{state}
+	assert.Equal(t, want, libevm.GetStates(wrapped, addr, keys), "libevm.GetStates() without MultiStateReader")
+	require.NoError(t, state.Error(), "StateDB.Error()")
+
+	assert.Equal(t, make([]common.Hash, 2), state.GetStates(common.Address{'n', 'o', 'n', 'e'}, keys[:2]), "non-existent account")
+}
+
+func TestProofOfStorage(t *testing.T) {
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	state, err := New(types.EmptyRootHash, db, nil)
+	require.NoError(t, err, "New()")
+
+	var (
+		addr    = common.Address{'p', 'r', 'o', 'o', 'f'}
+		present = common.Hash{1}
+		absent  = common.Hash{2}
+		val     = common.Hash{42}
+	)
+	state.SetNonce(addr, 1)
+	state.SetState(addr, present, val)
+	root, err := state.Commit(1, true)
+	require.NoErrorf(t, err, "%T.Commit()", state)
+
+	state, err = New(root, db, nil)
+	require.NoError(t, err, "New() at committed root")
+	// Uncommitted changes MUST NOT be reflected in the proof.
+	state.SetState(addr, present, common.Hash{})
+	state.SetState(addr, absent, val)
+
+	proof, err := state.ProofOfStorage(addr, []common.Hash{present, absent})
+	require.NoErrorf(t, err, "%T.ProofOfStorage()", state)
+	assert.Equal(t, root, proof.StateRoot, "state root")
+
+	verify := func(t *testing.T, root common.Hash, key []byte, nodes [][]byte) []byte {
+		t.Helper()
+		proofDB := memorydb.New()
+		for _, n := range nodes {
+			require.NoError(t, proofDB.Put(crypto.Keccak256(n), n))
+		}
+		got, err := trie.VerifyProof(root, crypto.Keccak256(key), proofDB)
+		require.NoError(t, err, "trie.VerifyProof()")
+		return got
+	}
+
+	accRLP := verify(t, proof.StateRoot, addr.Bytes(), proof.AccountProof)
+	acc, err := types.FullAccount(accRLP)
+	require.NoError(t, err, "types.FullAccount(proven account)")
+	assert.Equal(t, proof.StorageRoot, acc.Root, "proven storage root")
+
+	want := []common.Hash{val, {}}
+	for i, slot := range proof.Slots {
+		assert.Equalf(t, want[i], slot.Value, "slot %d value", i)
+
+		enc := verify(t, proof.StorageRoot, slot.Key.Bytes(), slot.Proof)
+		var got common.Hash
+		if len(enc) > 0 {
+			_, content, _, err := rlp.Split(enc)
+			require.NoError(t, err, "rlp.Split(proven slot value)")
+			got = common.BytesToHash(content)
+		}
+		assert.Equalf(t, slot.Value, got, "slot %d proven value", i)
+	}
+}
+
+func TestRecordAccesses(t *testing.T) {
+	state, err := New(types.EmptyRootHash, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err, "New()")
+
+	var (
+		a = common.Address{'a'}
+		b = common.Address{'b'}
+		c = common.Address{'c'}
+	)
+	state.SetNonce(a, 1) // not recorded
+
+	stopOuter := state.RecordAccesses()
+	_ = state.GetBalance(b)
+	state.SetState(a, common.Hash{2}, common.Hash{42})
+
+	stopInner := state.RecordAccesses()
+	_ = state.GetCommittedState(c, common.Hash{3})
+	_ = state.GetStates(a, []common.Hash{{1}, {2}})
+	inner := stopInner()
+
+	_ = state.GetState(b, common.Hash{4})
+	outer := stopOuter()
+
+	_ = state.GetState(c, common.Hash{5}) // not recorded
+
+	wantInner := types.AccessList{
+		{Address: a, StorageKeys: []common.Hash{{1}, {2}}},
+		{Address: c, StorageKeys: []common.Hash{{3}}},
+	}
+	assert.Equal(t, wantInner, inner, "inner recording")
+
+	wantOuter := types.AccessList{
+		{Address: a, StorageKeys: []common.Hash{{1}, {2}}},
+		{Address: b, StorageKeys: []common.Hash{{4}}},
+		{Address: c, StorageKeys: []common.Hash{{3}}},
+	}
+	assert.Equal(t, wantOuter, outer, "outer recording")
+
+	assert.Empty(t, state.accessRecorders, "recorders after stopping")
+}
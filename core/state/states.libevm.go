@@ -0,0 +1,151 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/stateconf"
+	"github.com/ava-labs/libevm/metrics"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+var _ libevm.MultiStateReader = (*StateDB)(nil)
+
+// GetStates is equivalent to calling [StateDB.GetState] for each of the keys,
+// returning the values in the same order, but it resolves the account only
+// once and reads all uncached slots in a single pass, ordered by hashed key,
+// over the snapshot or storage trie.
+func (s *StateDB) GetStates(addr common.Address, keys []common.Hash, opts ...stateconf.StateDBStateOption) []common.Hash {
+	s.opLogger.Printf("%x,GetStates,%x,%d", s.txIndex, addr, len(keys))
+	for i := range keys {
+		s.recordAccess(addr, &keys[i])
+	}
+	vals := make([]common.Hash, len(keys))
+	obj := s.getStateObject(addr)
+	if obj == nil {
+		return vals
+	}
+	transformed := make([]common.Hash, len(keys))
+	for i, k := range keys {
+		transformed[i] = transformStateKey(addr, k, opts...)
+	}
+	obj.getStates(transformed, vals)
+	return vals
+}
+
+// getStates populates `vals` with [stateObject.GetState] of the respective
+// `keys`, which MUST be of equal length.
+func (s *stateObject) getStates(keys, vals []common.Hash) {
+	var misses []int
+	for i, k := range keys {
+		if v, ok := s.dirtyStorage[k]; ok {
+			vals[i] = v
+			continue
+		}
+		if v, ok := s.pendingStorage[k]; ok {
+			vals[i] = v
+			continue
+		}
+		if v, ok := s.originStorage[k]; ok {
+			vals[i] = v
+			continue
+		}
+		misses = append(misses, i)
+	}
+	if len(misses) == 0 {
+		return
+	}
+	// See rationale in [stateObject.GetCommittedState].
+	if _, destructed := s.db.stateObjectsDestruct[s.address]; destructed {
+		return
+	}
+
+	hashed := make(map[int]common.Hash, len(misses))
+	for _, i := range misses {
+		hashed[i] = crypto.Keccak256Hash(keys[i].Bytes())
+	}
+	// Reading in order of hashed key means that consecutive trie lookups share
+	// the longest possible path prefix, the nodes of which have already been
+	// resolved by previous lookups.
+	sort.SliceStable(misses, func(a, b int) bool {
+		ha, hb := hashed[misses[a]], hashed[misses[b]]
+		return bytes.Compare(ha[:], hb[:]) < 0
+	})
+
+	if s.db.snap != nil && s.getStatesFromSnapshot(keys, vals, misses, hashed) {
+		return
+	}
+	s.getStatesFromTrie(keys, vals, misses)
+}
+
+// getStatesFromSnapshot reports whether all `misses` were read from the
+// snapshot. If it returns false then no values were cached and the caller MUST
+// fall back to the trie.
+func (s *stateObject) getStatesFromSnapshot(keys, vals []common.Hash, misses []int, hashed map[int]common.Hash) bool {
+	start := time.Now()
+	if metrics.EnabledExpensive {
+		defer func() { s.db.SnapshotStorageReads += time.Since(start) }()
+	}
+
+	read := make([]common.Hash, len(misses))
+	for j, i := range misses {
+		enc, err := s.db.snap.Storage(s.addrHash, hashed[i])
+		if err != nil {
+			return false
+		}
+		if len(enc) > 0 {
+			_, content, _, err := rlp.Split(enc)
+			if err != nil {
+				s.db.setError(err)
+			}
+			read[j].SetBytes(content)
+		}
+	}
+	for j, i := range misses {
+		vals[i] = read[j]
+		s.originStorage[keys[i]] = read[j]
+	}
+	return true
+}
+
+func (s *stateObject) getStatesFromTrie(keys, vals []common.Hash, misses []int) {
+	start := time.Now()
+	if metrics.EnabledExpensive {
+		defer func() { s.db.StorageReads += time.Since(start) }()
+	}
+
+	tr, err := s.getTrie()
+	if err != nil {
+		s.db.setError(err)
+		return
+	}
+	for _, i := range misses {
+		val, err := tr.GetStorage(s.address, keys[i].Bytes())
+		if err != nil {
+			s.db.setError(err)
+			return
+		}
+		vals[i].SetBytes(val)
+		s.originStorage[keys[i]] = vals[i]
+	}
+}
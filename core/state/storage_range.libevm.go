@@ -0,0 +1,166 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"sort"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/rlp"
+	"github.com/ava-labs/libevm/trie"
+)
+
+var _ libevm.StorageRanger = (*StateDB)(nil)
+
+// StorageRange returns up to `limit` non-zero storage slots of the account, in
+// order of hashed key, starting at the first hashed key greater than or equal
+// to `start`. Uncommitted changes are included. Slot keys are those stored in
+// the trie; i.e. after any transformation by [StateDBHooks.TransformStateKey],
+// and their preimages are only available if recorded by the trie database.
+//
+// Committed storage is read from the snapshot if available and consistent with
+// the account, otherwise from the storage trie, in both cases by iteration
+// instead of individual slot reads.
+func (s *StateDB) StorageRange(addr common.Address, start common.Hash, limit int) (*libevm.StorageRange, error) {
+	res := new(libevm.StorageRange)
+	obj := s.getStateObject(addr)
+	if obj == nil {
+		return res, nil
+	}
+
+	base, err := s.committedStorageIterator(obj, start)
+	if err != nil {
+		return nil, err
+	}
+	defer base.Release()
+	overlay := uncommittedStorage(obj, start)
+
+	baseOK := base.Next()
+	for {
+		var slot libevm.StorageSlot
+		switch {
+		case baseOK && (len(overlay) == 0 || base.Hash().Cmp(overlay[0].HashedKey) < 0):
+			slot.HashedKey = base.Hash()
+			if enc := base.Slot(); len(enc) > 0 { // empty if deleted from a snapshot
+				_, content, _, err := rlp.Split(enc)
+				if err != nil {
+					return nil, err
+				}
+				slot.Value = common.BytesToHash(content)
+			}
+			baseOK = base.Next()
+
+		case len(overlay) > 0:
+			slot = overlay[0]
+			overlay = overlay[1:]
+			if baseOK && base.Hash() == slot.HashedKey {
+				baseOK = base.Next() // overridden
+			}
+
+		default:
+			return res, base.Error()
+		}
+
+		if slot.Value == (common.Hash{}) {
+			continue
+		}
+		if len(res.Slots) == limit {
+			res.NextKey = &slot.HashedKey
+			return res, base.Error()
+		}
+		if slot.Key == nil {
+			if pre := s.db.TrieDB().Preimage(slot.HashedKey); pre != nil {
+				k := common.BytesToHash(pre)
+				slot.Key = &k
+			}
+		}
+		res.Slots = append(res.Slots, slot)
+	}
+}
+
+// A storageIterator is the intersection of [snapshot.StorageIterator] and a
+// wrapped [trie.Iterator].
+type storageIterator interface {
+	Next() bool
+	Error() error
+	Hash() common.Hash
+	Slot() []byte
+	Release()
+}
+
+func (s *StateDB) committedStorageIterator(obj *stateObject, start common.Hash) (storageIterator, error) {
+	if _, destructed := s.stateObjectsDestruct[obj.address]; !destructed && s.snaps != nil && obj.origin != nil && obj.data.Root == obj.origin.Root {
+		if it, err := s.snaps.StorageIterator(s.originalRoot, obj.addrHash, start); err == nil {
+			return it, nil
+		}
+		// Fall back to the trie, as for individual reads.
+	}
+
+	// The object's trie reflects all changes already flushed from pending
+	// storage, and is empty if the account was destructed.
+	tr, err := obj.getTrie()
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := s.db.CopyTrie(tr).NodeIterator(start.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return &trieStorageIterator{trie.NewIterator(nodes)}, nil
+}
+
+type trieStorageIterator struct {
+	*trie.Iterator
+}
+
+func (it *trieStorageIterator) Error() error      { return it.Err }
+func (it *trieStorageIterator) Hash() common.Hash { return common.BytesToHash(it.Key) }
+func (it *trieStorageIterator) Slot() []byte      { return it.Value }
+func (it *trieStorageIterator) Release()          {}
+
+// uncommittedStorage returns the pending and dirty storage of the object, with
+// hashed keys greater than or equal to `start`, sorted by hashed key. Zero
+// values are included as they override committed values.
+func uncommittedStorage(obj *stateObject, start common.Hash) []libevm.StorageSlot {
+	merged := make(map[common.Hash]common.Hash, len(obj.pendingStorage)+len(obj.dirtyStorage))
+	for k, v := range obj.pendingStorage {
+		merged[k] = v
+	}
+	for k, v := range obj.dirtyStorage {
+		merged[k] = v
+	}
+
+	slots := make([]libevm.StorageSlot, 0, len(merged))
+	for k, v := range merged {
+		h := crypto.Keccak256Hash(k.Bytes())
+		if h.Cmp(start) < 0 {
+			continue
+		}
+		k := k
+		slots = append(slots, libevm.StorageSlot{
+			HashedKey: h,
+			Key:       &k,
+			Value:     v,
+		})
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].HashedKey.Cmp(slots[j].HashedKey) < 0
+	})
+	return slots
+}
@@ -0,0 +1,234 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/ethdb"
+	"github.com/ava-labs/libevm/libevm/stateconf"
+	"github.com/ava-labs/libevm/trie"
+	"github.com/ava-labs/libevm/trie/trienode"
+)
+
+// A SubTrie is a key-value store owned by a single account, typically that of a
+// precompile, which is backed by its own trie instead of being packed into
+// 32-byte storage slots. Unlike storage tries, keys are of arbitrary length and
+// are not hashed, so iteration is in key order and supports range queries.
+//
+// The root of the SubTrie is stored in a storage slot of the owning account,
+// derived from the SubTrie's name, so the SubTrie is committed under the
+// account's storage root and thus the state root. Modifications are journalled
+// and therefore reverted along with the regular state.
+//
+// Although trie nodes are persisted by both hash- and path-based databases,
+// the latter's state history doesn't record SubTrie contents so rolling back a
+// path-based database is unsupported if SubTries are in use.
+type SubTrie struct {
+	db       *StateDB
+	addr     common.Address
+	rootSlot common.Hash
+	trie     *trie.Trie
+	dirty    bool
+}
+
+type subTrieID struct {
+	addr common.Address
+	name string
+}
+
+// SubTrieRootSlot returns the storage slot, of the owning account, in which the
+// root of the named [SubTrie] is stored. The slot is accessed without
+// [StateDBHooks.TransformStateKey] transformation.
+func SubTrieRootSlot(name string) common.Hash {
+	return crypto.Keccak256Hash([]byte("libevm/state.SubTrie"), []byte(name))
+}
+
+// subTrieOwner returns the owner used to distinguish the SubTrie's nodes from
+// those of all other tries, as required by path-based databases.
+func subTrieOwner(addr common.Address, name string) common.Hash {
+	return crypto.Keccak256Hash(addr.Bytes(), SubTrieRootSlot(name).Bytes())
+}
+
+// SubTrie opens the named [SubTrie] owned by the account. Repeated calls with
+// the same arguments return the same instance until [StateDB.Commit] is called,
+// after which it MUST NOT be used. Precompiles can access this method by type
+// assertion on the StateDB provided by their environment.
+func (s *StateDB) SubTrie(addr common.Address, name string) (*SubTrie, error) {
+	id := subTrieID{addr, name}
+	if t, ok := s.subTries[id]; ok {
+		return t, nil
+	}
+
+	slot := SubTrieRootSlot(name)
+	root := s.GetState(addr, slot, stateconf.SkipStateKeyTransformation())
+	if root == (common.Hash{}) {
+		root = types.EmptyRootHash
+	}
+	tr, err := trie.New(trie.StorageTrieID(s.originalRoot, subTrieOwner(addr, name), root), s.db.TrieDB())
+	if err != nil {
+		return nil, fmt.Errorf("opening sub-trie %q of %v: %w", name, addr, err)
+	}
+
+	t := &SubTrie{
+		db:       s,
+		addr:     addr,
+		rootSlot: slot,
+		trie:     tr,
+	}
+	if s.subTries == nil {
+		s.subTries = make(map[subTrieID]*SubTrie)
+	}
+	s.subTries[id] = t
+	return t, nil
+}
+
+// Get returns the value stored under the key, which is nil if absent.
+func (t *SubTrie) Get(key []byte) ([]byte, error) {
+	return t.trie.Get(key)
+}
+
+// Update stores the value under the key. An empty value is equivalent to
+// calling [SubTrie.Delete].
+func (t *SubTrie) Update(key, value []byte) error {
+	prev, err := t.trie.Get(key)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(prev, value) {
+		return nil
+	}
+	if err := t.trie.Update(key, value); err != nil {
+		return err
+	}
+	t.db.journal.append(subTrieChange{
+		account: &t.addr,
+		trie:    t,
+		key:     common.CopyBytes(key),
+		prev:    prev,
+	})
+	t.updateRoot()
+	return nil
+}
+
+// Delete removes the key, if present.
+func (t *SubTrie) Delete(key []byte) error {
+	return t.Update(key, nil)
+}
+
+// Root returns the current root of the SubTrie.
+func (t *SubTrie) Root() common.Hash {
+	return t.trie.Hash()
+}
+
+// Iterator returns an iterator over key-value pairs, in key order, starting at
+// the first key greater than or equal to `start`.
+func (t *SubTrie) Iterator(start []byte) (*trie.Iterator, error) {
+	it, err := t.trie.NodeIterator(start)
+	if err != nil {
+		return nil, err
+	}
+	return trie.NewIterator(it), nil
+}
+
+// Prove writes a Merkle proof of the key's value, or of its absence, against
+// [SubTrie.Root]. See [trie.VerifyProof].
+func (t *SubTrie) Prove(key []byte, proofDB ethdb.KeyValueWriter) error {
+	return t.trie.Prove(key, proofDB)
+}
+
+// updateRoot stores the trie root in the owning account's storage, which is
+// journalled as a regular storage change.
+func (t *SubTrie) updateRoot() {
+	t.dirty = true
+	root := t.trie.Hash()
+	if root == types.EmptyRootHash {
+		root = common.Hash{}
+	}
+	t.db.SetState(t.addr, t.rootSlot, root, stateconf.SkipStateKeyTransformation())
+}
+
+// subTrieChange is the journal entry for a [SubTrie] modification. The root
+// slot is journalled separately, by [StateDB.SetState].
+type subTrieChange struct {
+	account   *common.Address
+	trie      *SubTrie
+	key, prev []byte
+}
+
+func (ch subTrieChange) revert(*StateDB) {
+	// Errors can only occur when resolving nodes, which were necessarily
+	// resolved by the modification being reverted.
+	if len(ch.prev) == 0 {
+		_ = ch.trie.trie.Delete(ch.key)
+	} else {
+		_ = ch.trie.trie.Update(ch.key, ch.prev)
+	}
+}
+
+func (ch subTrieChange) dirtied() *common.Address {
+	return ch.account
+}
+
+// copySubTries returns deep copies of all [SubTrie] instances, for use by the
+// [StateDB] copy `to`.
+func (s *StateDB) copySubTries(to *StateDB) map[subTrieID]*SubTrie {
+	if s.subTries == nil {
+		return nil
+	}
+	cp := make(map[subTrieID]*SubTrie, len(s.subTries))
+	for id, t := range s.subTries {
+		cp[id] = &SubTrie{
+			db:       to,
+			addr:     t.addr,
+			rootSlot: t.rootSlot,
+			trie:     t.trie.Copy(),
+			dirty:    t.dirty,
+		}
+	}
+	return cp
+}
+
+// commitSubTries commits all modified [SubTrie] instances, merging their nodes
+// into the set. It MUST be called after the storage tries of their owning
+// accounts have been committed.
+func (s *StateDB) commitSubTries(nodes *trienode.MergedNodeSet) error {
+	for id, t := range s.subTries {
+		if !t.dirty {
+			continue
+		}
+		root, set, err := t.trie.Commit(false)
+		if err != nil {
+			return fmt.Errorf("committing sub-trie %q of %v: %w", id.name, id.addr, err)
+		}
+		if set == nil {
+			continue
+		}
+		if err := nodes.Merge(set); err != nil {
+			return err
+		}
+		if obj := s.getStateObject(id.addr); obj != nil && root != types.EmptyRootHash {
+			nodes.AddExternalReference(root, obj.data.Root)
+		}
+	}
+	s.subTries = nil
+	return nil
+}
@@ -1,4 +1,4 @@
-// Copyright 2024 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -21,8 +21,13 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state/snapshot"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/triedb"
 )
 
 type synchronisingWorkerPool struct {
@@ -78,3 +83,39 @@ func TestStopPrefetcherWaitsOnWorkers(t *testing.T) {
 	// called.
 	assert.Equalf(t, 2, pool.preconditionsToStopPrefetcher, "%T.StopPrefetcher() returned early", db)
 }
+
+func TestPrefetchAccessList(t *testing.T) {
+	// The prefetcher is only started if there is a snapshot tree.
+	var (
+		disk = rawdb.NewMemoryDatabase()
+		tdb  = triedb.NewDatabase(disk, nil)
+		db   = NewDatabaseWithNodeDB(disk, tdb)
+	)
+	snaps, err := snapshot.New(snapshot.Config{CacheSize: 10}, disk, tdb, types.EmptyRootHash)
+	require.NoError(t, err, "snapshot.New()")
+
+	addr := common.HexToAddress("0xaffe")
+	sdb, err := New(types.EmptyRootHash, db, snaps)
+	require.NoError(t, err, "New()")
+	sdb.SetState(addr, common.Hash{}, common.Hash{1})
+	root, err := sdb.Commit(1, false)
+	require.NoError(t, err, "%T.Commit()", sdb)
+
+	sdb, err = New(root, db, snaps)
+	require.NoError(t, err, "New(<committed root>)")
+	sdb.PrefetchAccessList(types.AccessList{{Address: addr}}) // MUST NOT panic without prefetcher
+
+	sdb.StartPrefetcher("")
+	defer sdb.StopPrefetcher()
+	require.NotNil(t, sdb.prefetcher, "%T.StartPrefetcher() with snapshots", sdb)
+
+	sdb.PrefetchAccessList(types.AccessList{{
+		Address:     addr,
+		StorageKeys: []common.Hash{{}},
+	}})
+
+	assert.NotNil(t, sdb.prefetcher.trie(common.Hash{}, root), "account trie prefetched")
+	obj := sdb.getStateObject(addr)
+	require.NotNil(t, obj, "%T.getStateObject()", sdb)
+	assert.NotNil(t, sdb.prefetcher.trie(obj.addrHash, obj.data.Root), "storage trie prefetched")
+}
@@ -27,7 +27,7 @@ import (
 	"github.com/ava-labs/libevm/core/state"
 	"github.com/ava-labs/libevm/core/types"
 	"github.com/ava-labs/libevm/core/vm"
-	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm/migration"
 	"github.com/ava-labs/libevm/params"
 )
 
@@ -71,14 +71,20 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 	if p.config.DAOForkSupport && p.config.DAOForkBlock != nil && p.config.DAOForkBlock.Cmp(block.Number()) == 0 {
 		misc.ApplyDAOHardFork(statedb)
 	}
+	if err := migration.Run(p.config, header, statedb); err != nil { // libevm
+		return nil, nil, 0, err
+	}
 	var (
 		context = NewEVMBlockContext(header, p.bc, nil)
 		vmenv   = vm.NewEVM(context, vm.TxContext{}, statedb, p.config, cfg)
 		signer  = types.MakeSigner(p.config, header.Number, header.Time)
 	)
+	defer vmenv.Finish()        // libevm
+	vmenv.DisableCancellation() // libevm
 	if beaconRoot := block.BeaconRoot(); beaconRoot != nil {
 		ProcessBeaconBlockRoot(*beaconRoot, vmenv, statedb)
 	}
+	prefetchPrecompileHints(p.config.Rules(blockNumber, context.Random != nil, header.Time), statedb, block.Transactions()) // libevm
 	// Iterate over and process the individual transactions
 	for i, tx := range block.Transactions() {
 		msg, err := TransactionToMessage(tx, signer, header.BaseFee)
@@ -132,6 +138,7 @@ func applyTransaction(msg *Message, config *params.ChainConfig, gp *GasPool, sta
 	} else {
 		receipt.Status = types.ReceiptStatusSuccessful
 	}
+	receipt.Failure = ReceiptFailure(result, evm) // libevm
 	receipt.TxHash = tx.Hash()
 	receipt.GasUsed = result.UsedGas
 
@@ -142,7 +149,7 @@ func applyTransaction(msg *Message, config *params.ChainConfig, gp *GasPool, sta
 
 	// If the transaction created a contract, store the creation address in the receipt.
 	if msg.To == nil {
-		receipt.ContractAddress = crypto.CreateAddress(evm.TxContext.Origin, tx.Nonce())
+		receipt.ContractAddress = evm.CreateAddress(evm.TxContext.Origin, tx.Nonce(), msg.Data) // libevm: modified
 	}
 
 	// Set the receipt logs and create the bloom filter.
@@ -159,14 +166,23 @@ func applyTransaction(msg *Message, config *params.ChainConfig, gp *GasPool, sta
 // for the transaction, gas used and an error if the transaction failed,
 // indicating the block was invalid.
 func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config) (*types.Receipt, error) {
+	return applyTransactionInMode(vm.HeaderCommitted, config, bc, author, gp, statedb, header, tx, usedGas, cfg) // libevm
+}
+
+// applyTransactionInMode is the body of [ApplyTransaction], modified by libevm
+// to set the [vm.HeaderMode] of the block context.
+func applyTransactionInMode(mode vm.HeaderMode, config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config) (*types.Receipt, error) {
 	msg, err := TransactionToMessage(tx, types.MakeSigner(config, header.Number, header.Time), header.BaseFee)
 	if err != nil {
 		return nil, err
 	}
 	// Create a new context to be used in the EVM environment
 	blockContext := NewEVMBlockContext(header, bc, author)
+	blockContext.HeaderMode = mode // libevm
 	txContext := NewEVMTxContext(msg)
 	vmenv := vm.NewEVM(blockContext, txContext, statedb, config, cfg)
+	defer vmenv.Finish()        // libevm
+	vmenv.DisableCancellation() // libevm
 	return applyTransaction(msg, config, gp, statedb, header.Number, header.Hash(), tx, usedGas, vmenv)
 }
 
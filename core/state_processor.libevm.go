@@ -0,0 +1,66 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/params"
+)
+
+// ApplyTransactionToBuildingBlock is equivalent to [ApplyTransaction] except
+// that the header is treated as that of a block under construction, which is
+// reported to precompiles as [vm.HeaderBuilding]. It MUST be used by block
+// builders instead of [ApplyTransaction].
+func ApplyTransactionToBuildingBlock(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, tx *types.Transaction, usedGas *uint64, cfg vm.Config) (*types.Receipt, error) {
+	return applyTransactionInMode(vm.HeaderBuilding, config, bc, author, gp, statedb, header, tx, usedGas, cfg)
+}
+
+// ReceiptFailure classifies the reason, if any, for the failure of the
+// transaction most recently executed by the EVM.
+func ReceiptFailure(res *ExecutionResult, evm *vm.EVM) types.ReceiptFailure {
+	err := res.Err
+	switch blocked := evm.ContractCreationBlocked(); {
+	case err == nil:
+		return types.ReceiptFailureNone
+	case errors.Is(err, ErrExecutionInvalidated):
+		return types.ReceiptFailureInvalidated
+	case errors.Is(err, vm.ErrExecutionReverted):
+		return types.ReceiptFailureReverted
+	case blocked != nil && errors.Is(err, blocked):
+		return types.ReceiptFailurePolicyRejected
+	default:
+		return types.ReceiptFailureOther
+	}
+}
+
+// prefetchPrecompileHints passes the hints of all [vm.PrefetchHinter]
+// precompiles called directly by the transactions to the StateDB's trie
+// prefetcher.
+func prefetchPrecompileHints(rules params.Rules, statedb *state.StateDB, txs types.Transactions) {
+	for _, tx := range txs {
+		to := tx.To()
+		if to == nil {
+			continue
+		}
+		statedb.PrefetchAccessList(vm.PrefetchHints(rules, *to, tx.Data()))
+	}
+}
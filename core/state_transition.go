@@ -212,6 +212,7 @@ type StateTransition struct {
 	initialGas   uint64
 	state        vm.StateDB
 	evm          *vm.EVM
+	aaPayer      *common.Address // libevm: see [AccountAbstractionHooks]
 }
 
 // NewStateTransition initialises and returns a new state transition object.
@@ -233,13 +234,20 @@ func (st *StateTransition) to() common.Address {
 }
 
 func (st *StateTransition) buyGas() error {
+	if err := st.validateAAOperation(); err != nil { // libevm
+		return err
+	}
+	payer := st.gasPayer() // libevm
+
 	mgval := new(big.Int).SetUint64(st.msg.GasLimit)
 	mgval = mgval.Mul(mgval, st.msg.GasPrice)
 	balanceCheck := new(big.Int).Set(mgval)
 	if st.msg.GasFeeCap != nil {
 		balanceCheck.SetUint64(st.msg.GasLimit)
 		balanceCheck = balanceCheck.Mul(balanceCheck, st.msg.GasFeeCap)
-		balanceCheck.Add(balanceCheck, st.msg.Value)
+		if payer == st.msg.From { // libevm: otherwise the value isn't the payer's responsibility
+			balanceCheck.Add(balanceCheck, st.msg.Value)
+		}
 	}
 	if st.evm.ChainConfig().IsCancun(st.evm.Context.BlockNumber, st.evm.Context.Time) {
 		if blobGas := st.blobGasUsed(); blobGas > 0 {
@@ -255,10 +263,10 @@ func (st *StateTransition) buyGas() error {
 	}
 	balanceCheckU256, overflow := uint256.FromBig(balanceCheck)
 	if overflow {
-		return fmt.Errorf("%w: address %v required balance exceeds 256 bits", ErrInsufficientFunds, st.msg.From.Hex())
+		return fmt.Errorf("%w: address %v required balance exceeds 256 bits", ErrInsufficientFunds, payer.Hex()) // libevm
 	}
-	if have, want := st.state.GetBalance(st.msg.From), balanceCheckU256; have.Cmp(want) < 0 {
-		return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, st.msg.From.Hex(), have, want)
+	if have, want := st.state.GetBalance(payer), balanceCheckU256; have.Cmp(want) < 0 { // libevm
+		return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, payer.Hex(), have, want) // libevm
 	}
 	if err := st.gp.SubGas(st.msg.GasLimit); err != nil {
 		return err
@@ -267,7 +275,7 @@ func (st *StateTransition) buyGas() error {
 
 	st.initialGas = st.msg.GasLimit
 	mgvalU256, _ := uint256.FromBig(mgval)
-	st.state.SubBalance(st.msg.From, mgvalU256)
+	st.state.SubBalance(payer, mgvalU256) // libevm
 	return nil
 }
 
@@ -395,7 +403,7 @@ func (st *StateTransition) transitionDb() (*ExecutionResult, error) {
 	)
 
 	// Check clauses 4-5, subtract intrinsic gas if everything is correct
-	gas, err := IntrinsicGas(msg.Data, msg.AccessList, contractCreation, rules.IsHomestead, rules.IsIstanbul, rules.IsShanghai)
+	gas, err := IntrinsicGas(msg.Data, msg.AccessList, contractCreation, rules.IsHomestead, rules.IsIstanbul, rules.IsEIP3860()) // libevm: was IsShanghai
 	if err != nil {
 		return nil, err
 	}
@@ -414,7 +422,7 @@ func (st *StateTransition) transitionDb() (*ExecutionResult, error) {
 	}
 
 	// Check whether the init code size has been exceeded.
-	if rules.IsShanghai && contractCreation && len(msg.Data) > params.MaxInitCodeSize {
+	if rules.IsEIP3860() && contractCreation && len(msg.Data) > params.MaxInitCodeSize { // libevm: was IsShanghai
 		return nil, fmt.Errorf("%w: code size %v limit %v", ErrMaxInitCodeSizeExceeded, len(msg.Data), params.MaxInitCodeSize)
 	}
 
@@ -427,6 +435,7 @@ func (st *StateTransition) transitionDb() (*ExecutionResult, error) {
 		ret   []byte
 		vmerr error // vm errors do not effect consensus and are therefore not assigned to err
 	)
+	invalidationSnap := st.state.Snapshot() // libevm
 	if contractCreation {
 		ret, _, st.gasRemaining, vmerr = st.evm.Create(sender, msg.Data, st.gasRemaining, value)
 	} else {
@@ -434,6 +443,8 @@ func (st *StateTransition) transitionDb() (*ExecutionResult, error) {
 		st.state.SetNonce(msg.From, st.state.GetNonce(sender.Address())+1)
 		ret, st.gasRemaining, vmerr = st.evm.Call(sender, st.to(), msg.Data, st.gasRemaining, value)
 	}
+	ret, vmerr = st.revertIfInvalidated(invalidationSnap, ret, vmerr) // libevm
+	ret, vmerr = st.postAAOperation(ret, vmerr)                       // libevm
 
 	var gasRefund uint64
 	if !rules.IsLondon {
@@ -480,7 +491,7 @@ func (st *StateTransition) refundGas(refundQuotient uint64) uint64 {
 	// Return ETH for remaining gas, exchanged at the original rate.
 	remaining := uint256.NewInt(st.gasRemaining)
 	remaining = remaining.Mul(remaining, uint256.MustFromBig(st.msg.GasPrice))
-	st.state.AddBalance(st.msg.From, remaining)
+	st.state.AddBalance(st.gasPayer(), remaining) // libevm
 
 	// Also return remaining gas to the block gas counter so it is
 	// available for the next transaction.
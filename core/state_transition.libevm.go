@@ -1,4 +1,4 @@
-// Copyright 2024-2025 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -17,6 +17,7 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/ava-labs/libevm/core/vm"
@@ -51,9 +52,23 @@ var _ = (*vm.EVM)(nil)
 // nil evm execution result.
 //
 // libevm-specific behaviour: if, during execution, [vm.EVM.InvalidateExecution]
-// is called with a non-nil error then said error will be returned, wrapped. All
-// state transitions (e.g. nonce incrementing) will be reverted to a snapshot
-// taken before execution.
+// is called with a non-nil error then the transaction is treated according to
+// the [params.InvalidationDisposition] returned by [params.Rules]:
+//
+//   - [params.RejectInvalidatedTx]: all state transitions (e.g. nonce
+//     incrementing) are reverted to a snapshot taken before execution and the
+//     error is returned, wrapped with [ErrExecutionInvalidated].
+//   - [params.RevertInvalidatedTx]: execution is reverted and all gas is
+//     consumed, but the nonce is incremented and fees are paid; the error is
+//     reported, wrapped with [ErrExecutionInvalidated], as the [ExecutionResult]
+//     Err and a nil error is returned.
+//   - [params.FailInvalidatedBlock]: as for rejection, but the error is
+//     wrapped with [ErrBlockInvalidated].
+//
+// Functions scheduled with [vm.PrecompileEnvironment.OnTxEnd] are run, via
+// [vm.EVM.RunTxEndActions], iff execution wasn't invalidated. Functions
+// scheduled with [vm.PrecompileEnvironment.OnCommit] are run, via
+// [vm.EVM.RunCommitActions], iff execution succeeds.
 func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	if err := st.canExecuteTransaction(); err != nil {
 		return nil, err
@@ -67,13 +82,69 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	// a defensive measure, we don't return early on non-nil `err` in case an
 	// upstream update breaks this invariant.
 
+	if err == nil && st.evm.ExecutionInvalidated() == nil {
+		st.evm.RunTxEndActions()
+	} else {
+		st.evm.DiscardTxEndActions()
+	}
+
 	if invalid := st.evm.ExecutionInvalidated(); invalid != nil {
-		st.state.RevertToSnapshot(snap)
-		err = fmt.Errorf("execution invalidated: %w", invalid)
+		switch st.invalidationDisposition(invalid) {
+		case params.RevertInvalidatedTx:
+			// Already handled by [StateTransition.revertIfInvalidated].
+		case params.FailInvalidatedBlock:
+			st.state.RevertToSnapshot(snap)
+			err = fmt.Errorf("%w: %w", ErrBlockInvalidated, invalid)
+		default:
+			st.state.RevertToSnapshot(snap)
+			err = fmt.Errorf("%w: %w", ErrExecutionInvalidated, invalid)
+		}
+	}
+
+	if err == nil && !res.Failed() {
+		st.evm.RunCommitActions()
+	} else {
+		st.evm.DiscardCommitActions()
 	}
 	return res, err
 }
 
+var (
+	// ErrExecutionInvalidated wraps errors passed to
+	// [vm.EVM.InvalidateExecution] when the transaction is either rejected or
+	// reverted.
+	ErrExecutionInvalidated = errors.New("execution invalidated")
+	// ErrBlockInvalidated wraps errors passed to [vm.EVM.InvalidateExecution]
+	// when [params.FailInvalidatedBlock] is in effect. It is a consensus error
+	// that renders the entire block invalid.
+	ErrBlockInvalidated = errors.New("block invalidated by execution")
+)
+
+// invalidationDisposition is a convenience wrapper for calling
+// [params.Rules.InvalidationDisposition].
+func (st *StateTransition) invalidationDisposition(invalid error) params.InvalidationDisposition {
+	bCtx := st.evm.Context
+	rules := st.evm.ChainConfig().Rules(bCtx.BlockNumber, bCtx.Random != nil, bCtx.Time)
+	return rules.InvalidationDisposition(invalid)
+}
+
+// revertIfInvalidated reverts execution to the snapshot, consuming all
+// remaining gas, iff execution was invalidated and the disposition is
+// [params.RevertInvalidatedTx]. As the snapshot MUST be taken before the
+// sender's nonce is incremented, it is incremented again after reverting.
+// Otherwise the arguments are returned unchanged.
+func (st *StateTransition) revertIfInvalidated(snap int, ret []byte, vmErr error) ([]byte, error) {
+	invalid := st.evm.ExecutionInvalidated()
+	if invalid == nil || st.invalidationDisposition(invalid) != params.RevertInvalidatedTx {
+		return ret, vmErr
+	}
+	st.state.RevertToSnapshot(snap)
+	from := st.msg.From
+	st.state.SetNonce(from, st.state.GetNonce(from)+1)
+	st.gasRemaining = 0
+	return nil, fmt.Errorf("%w: %w", ErrExecutionInvalidated, invalid)
+}
+
 // canExecuteTransaction is a convenience wrapper for calling the
 // [params.RulesHooks.CanExecuteTransaction] hook.
 func (st *StateTransition) canExecuteTransaction() error {
@@ -1,4 +1,4 @@
-// Copyright 2024-2025 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -16,15 +16,18 @@
 package core_test
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
 
 	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/state"
 	"github.com/ava-labs/libevm/core/types"
 	"github.com/ava-labs/libevm/core/vm"
 	"github.com/ava-labs/libevm/crypto"
@@ -200,3 +203,175 @@ func TestMinimumGasConsumption(t *testing.T) {
 		})
 	}
 }
+
+func TestInvalidationDisposition(t *testing.T) {
+	rng := ethtest.NewPseudoRand(0)
+	precompile := rng.Address()
+	slot := rng.Hash()
+	errInvalid := errors.New("invalid")
+
+	var disposition params.InvalidationDisposition
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				env.StateDB().SetState(precompile, slot, common.Hash{1})
+				env.InvalidateExecution(errInvalid)
+				return []byte("ok"), nil
+			}),
+		},
+		InvalidationDispositionFn: func(err error) params.InvalidationDisposition {
+			var invalid *vm.InvalidationError
+			if !errors.As(err, &invalid) || invalid.Precompile != precompile {
+				t.Errorf("InvalidationDisposition(%v) got unexpected argument", err)
+			}
+			return disposition
+		},
+	}
+	hooks.Register(t)
+
+	const (
+		gasLimit        = 1e5
+		gasPrice        = 3
+		startingBalance = 10 * params.Ether
+	)
+	sender := rng.Address()
+
+	tests := []struct {
+		disposition    params.InvalidationDisposition
+		wantErrIs      error
+		wantResErrIs   error
+		wantNonce      uint64
+		wantGasUsed    uint64
+		wantReturnData []byte
+	}{
+		{
+			disposition: params.RejectInvalidatedTx,
+			wantErrIs:   core.ErrExecutionInvalidated,
+			wantNonce:   0,
+		},
+		{
+			disposition:  params.RevertInvalidatedTx,
+			wantResErrIs: core.ErrExecutionInvalidated,
+			wantNonce:    1,
+			wantGasUsed:  gasLimit,
+		},
+		{
+			disposition: params.FailInvalidatedBlock,
+			wantErrIs:   core.ErrBlockInvalidated,
+			wantNonce:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.disposition.String(), func(t *testing.T) {
+			disposition = tt.disposition
+
+			sdb, evm := ethtest.NewZeroEVM(t)
+			sdb.SetBalance(sender, uint256.NewInt(startingBalance))
+
+			msg := &core.Message{
+				From:     sender,
+				To:       &precompile,
+				GasLimit: gasLimit,
+				GasPrice: big.NewInt(gasPrice),
+				Value:    big.NewInt(0),
+			}
+			res, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(30e6))
+			require.ErrorIs(t, err, tt.wantErrIs, "core.ApplyMessage()")
+			if tt.wantErrIs != nil {
+				require.ErrorIs(t, err, errInvalid, "core.ApplyMessage()")
+			} else {
+				require.ErrorIs(t, res.Err, tt.wantResErrIs, "%T.Err", res)
+				require.ErrorIs(t, res.Err, errInvalid, "%T.Err", res)
+				assert.Equal(t, tt.wantGasUsed, res.UsedGas, "%T.UsedGas", res)
+				assert.Equal(t, tt.wantReturnData, res.ReturnData, "%T.ReturnData", res)
+				assert.Equal(t, types.ReceiptFailureInvalidated, core.ReceiptFailure(res, evm), "core.ReceiptFailure()")
+			}
+
+			assert.Equal(t, tt.wantNonce, sdb.GetNonce(sender), "sender nonce")
+			assert.Equal(t, common.Hash{}, sdb.GetState(precompile, slot), "state set by precompile")
+			wantBalance := uint256.NewInt(startingBalance - tt.wantGasUsed*gasPrice)
+			assert.Equal(t, wantBalance, sdb.GetBalance(sender), "sender balance")
+		})
+	}
+}
+
+func TestActiveEIPsBeyondJumpTable(t *testing.T) {
+	// Both EIPs are otherwise inactive under the zero-value chain config used
+	// by [ethtest.NewZeroEVM].
+	const (
+		eip2929 = 2929
+		eip3860 = 3860
+	)
+	rng := ethtest.NewPseudoRand(710)
+	sender := rng.Address()
+
+	tests := []struct {
+		name            string
+		eips            []int
+		wantAccessList  bool
+		wantInitCodeErr bool
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:           "eip_2929",
+			eips:           []int{eip2929},
+			wantAccessList: true,
+		},
+		{
+			name:            "eip_3860",
+			eips:            []int{eip3860},
+			wantInitCodeErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks := &hookstest.Stub{
+				ActiveEIPsFn: func() []int { return tt.eips },
+			}
+			hooks.Register(t)
+
+			newEVM := func(t *testing.T) (*state.StateDB, *vm.EVM) {
+				t.Helper()
+				sdb, evm := ethtest.NewZeroEVM(t)
+				sdb.SetBalance(sender, uint256.NewInt(params.Ether))
+				return sdb, evm
+			}
+
+			t.Run("access_list", func(t *testing.T) {
+				sdb, evm := newEVM(t)
+				to := rng.Address()
+				msg := &core.Message{
+					From:     sender,
+					To:       &to,
+					GasLimit: 1e6,
+					GasPrice: big.NewInt(0),
+					Value:    big.NewInt(0),
+				}
+				_, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(30e6))
+				require.NoError(t, err, "core.ApplyMessage()")
+				assert.Equal(t, tt.wantAccessList, sdb.AddressInAccessList(to), "recipient in access list")
+			})
+
+			t.Run("init_code_size", func(t *testing.T) {
+				_, evm := newEVM(t)
+				msg := &core.Message{
+					From:     sender,
+					Data:     make([]byte, params.MaxInitCodeSize+1),
+					GasLimit: 1e6,
+					GasPrice: big.NewInt(0),
+					Value:    big.NewInt(0),
+				}
+				_, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(30e6))
+				if tt.wantInitCodeErr {
+					require.ErrorIs(t, err, core.ErrMaxInitCodeSizeExceeded, "core.ApplyMessage()")
+					return
+				}
+				require.NoError(t, err, "core.ApplyMessage()")
+			})
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright 2024-2025 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -17,8 +17,11 @@
 package types
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
+	"reflect"
 
 	"github.com/ava-labs/libevm/libevm/pseudo"
 	"github.com/ava-labs/libevm/rlp"
@@ -53,12 +56,107 @@ func (h *Header) UnmarshalJSON(b []byte) error {
 
 // EncodeRLP implements the [rlp.Encoder] interface.
 func (h *Header) EncodeRLP(w io.Writer) error {
-	return h.hooks().EncodeRLP(h, w)
+	hooks := h.hooks()
+	if g, ok := hooks.(ForkGatedHeaderHooks); ok && !g.HeaderExtrasActive(h) {
+		hooks = new(NOOPHeaderHooks)
+	}
+	return hooks.EncodeRLP(h, w)
 }
 
 // DecodeRLP implements the [rlp.Decoder] interface.
 func (h *Header) DecodeRLP(s *rlp.Stream) error {
-	return h.hooks().DecodeRLP(h, s)
+	hooks := h.hooks()
+	if g, ok := hooks.(ForkGatedHeaderHooks); ok {
+		return h.decodeForkGatedRLP(g, s)
+	}
+	return hooks.DecodeRLP(h, s)
+}
+
+// ForkGatedHeaderHooks MAY be implemented by a type registered for [Header]
+// payloads to signal that the payload is only included in the RLP encoding,
+// and therefore in [Header.Hash], once a fork is active. This allows hashed
+// fields to be introduced mid-chain.
+//
+// HeaderExtrasActive MUST only depend on geth-defined fields of the [Header],
+// typically its Time, as it is also called on headers decoded without the
+// payload. Before activation, the [HeaderHooks] RLP methods are bypassed and
+// the [Header] is {en,de}coded as if no payload were registered; decoding of
+// headers that carry a payload before activation fails.
+type ForkGatedHeaderHooks interface {
+	HeaderHooks
+	HeaderExtrasActive(*Header) bool
+}
+
+// Errors returned when a [Header] payload is inconsistent with whether
+// [ForkGatedHeaderHooks] are active.
+var (
+	// ErrHeaderExtrasBeforeActivation is returned when decoding the RLP
+	// encoding of a [Header] that includes a payload before activation, and by
+	// [Header.VerifyForkGatedExtras] if the payload is non-zero.
+	ErrHeaderExtrasBeforeActivation = errors.New("header extras before activation")
+	// ErrHeaderExtrasMissingAfterActivation is returned by
+	// [Header.VerifyForkGatedExtras] if the payload is zero after activation.
+	ErrHeaderExtrasMissingAfterActivation = errors.New("header extras missing after activation")
+)
+
+// VerifyForkGatedExtras returns an error if the [Header] payload is
+// inconsistent with whether its registered [ForkGatedHeaderHooks] are active:
+// before activation the payload MUST be its zero value, as it isn't committed
+// to by [Header.Hash], and after activation it MUST NOT be. It returns nil if
+// the registered type doesn't implement [ForkGatedHeaderHooks].
+//
+// Headers aren't only constructed by RLP decoding, and whether a post-activation
+// encoding without a payload is rejected by [Header.DecodeRLP] depends on the
+// registered type, so consensus engines call VerifyForkGatedExtras during
+// header verification.
+func (h *Header) VerifyForkGatedExtras() error {
+	g, ok := h.hooks().(ForkGatedHeaderHooks)
+	if !ok {
+		return nil
+	}
+	// The registered payload is always a non-nil pointer, which is never zero.
+	zero := reflect.ValueOf(g).Elem().IsZero()
+
+	switch active := g.HeaderExtrasActive(h); {
+	case !active && !zero:
+		return ErrHeaderExtrasBeforeActivation
+	case active && zero:
+		return ErrHeaderExtrasMissingAfterActivation
+	default:
+		return nil
+	}
+}
+
+// decodeForkGatedRLP decodes an RLP-encoded header without knowing, a priori,
+// whether the fork gating the payload is active; i.e. it is the
+// [ForkGatedHeaderHooks]-aware equivalent of [HeaderHooks.DecodeRLP].
+func (h *Header) decodeForkGatedRLP(hooks ForkGatedHeaderHooks, s *rlp.Stream) error {
+	raw, err := s.Raw()
+	if err != nil {
+		return err
+	}
+	stream := func() *rlp.Stream {
+		return rlp.NewStream(bytes.NewReader(raw), uint64(len(raw)))
+	}
+
+	// A pre-activation header has no payload so will be successfully decoded
+	// as if none were registered. If decoding fails then it's assumed that a
+	// payload is present.
+	var noExtra Header
+	if err := new(NOOPHeaderHooks).DecodeRLP(&noExtra, stream()); err == nil && !hooks.HeaderExtrasActive(&noExtra) {
+		// Leaving the payload unset, rather than carrying over that of `h`,
+		// results in a zero value, as verified by [Header.VerifyForkGatedExtras].
+		*h = noExtra
+		return nil
+	}
+
+	if err := hooks.DecodeRLP(h, stream()); err != nil {
+		return err
+	}
+	if !hooks.HeaderExtrasActive(h) {
+		return ErrHeaderExtrasBeforeActivation
+	}
+	return nil
 }
 
 // NOOPHeaderHooks implements [HeaderHooks] such that they are equivalent to
@@ -179,3 +277,34 @@ func (NOOPBlockBodyHooks) BodyRLPFieldPointersForDecoding(b *Body) *rlp.Fields {
 		Optional: []any{&b.Withdrawals},
 	}
 }
+
+// NewBlockWithExtra is equivalent to [NewBlockWithWithdrawals] except that the
+// returned [Block] carries `extra` as its payload from the outset, instead of it
+// being set after construction.
+func (e ExtraPayloads[HPtr, BPtr, SA]) NewBlockWithExtra(
+	header *Header,
+	txs []*Transaction,
+	uncles []*Header,
+	receipts []*Receipt,
+	withdrawals []*Withdrawal,
+	hasher TrieHasher,
+	extra BPtr,
+) *Block {
+	b := NewBlockWithWithdrawals(header, txs, uncles, receipts, withdrawals, hasher)
+	e.Block.Set(b, extra)
+	return b
+}
+
+// BlockWithExtra returns a shallow copy of the [Block], equivalent to those
+// returned by the Block's With*() methods, but carrying `extra` as its payload.
+// The original Block is unmodified.
+func (e ExtraPayloads[HPtr, BPtr, SA]) BlockWithExtra(b *Block, extra BPtr) *Block {
+	cp := &Block{
+		header:       b.header,
+		transactions: b.transactions,
+		uncles:       b.uncles,
+		withdrawals:  b.withdrawals,
+	}
+	e.Block.Set(cp, extra)
+	return cp
+}
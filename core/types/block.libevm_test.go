@@ -1,4 +1,4 @@
-// Copyright 2024-2025 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -17,10 +17,12 @@
 package types_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"reflect"
 	"strings"
 	"testing"
@@ -270,3 +272,160 @@ func TestBlockWithX(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockExtraConstructors(t *testing.T) {
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+
+	extras := RegisterExtras[
+		NOOPHeaderHooks, *NOOPHeaderHooks,
+		blockPayload, *blockPayload,
+		struct{},
+	]()
+
+	hdr := &Header{Number: big.NewInt(1)}
+
+	t.Run("NewBlockWithExtra", func(t *testing.T) {
+		b := extras.NewBlockWithExtra(hdr, nil, nil, nil, nil, nil, &blockPayload{x: 42})
+		assert.Equal(t, 42, extras.Block.Get(b).x, "payload of new block")
+		assert.Equal(t, NewBlockWithWithdrawals(hdr, nil, nil, nil, nil, nil).Hash(), b.Hash(), "block hash unaffected by payload")
+	})
+
+	t.Run("BlockWithExtra", func(t *testing.T) {
+		orig := extras.NewBlockWithExtra(hdr, nil, nil, nil, nil, nil, &blockPayload{x: 1})
+		b := extras.BlockWithExtra(orig, &blockPayload{x: 2})
+		assert.Equal(t, 2, extras.Block.Get(b).x, "payload of copy")
+		assert.Equal(t, 1, extras.Block.Get(orig).x, "payload of original")
+		assert.Equal(t, orig.Hash(), b.Hash(), "block hash")
+	})
+}
+
+// forkGatedHeaderHooks appends `X` to the RLP encoding of a [Header] at and
+// after `forkGatedActivation`.
+type forkGatedHeaderHooks struct {
+	NOOPHeaderHooks
+	X uint64
+}
+
+const forkGatedActivation = 100
+
+func (*forkGatedHeaderHooks) HeaderExtrasActive(h *Header) bool {
+	return h.Time >= forkGatedActivation
+}
+
+type forkGatedHeaderRLP struct {
+	Geth rlp.RawValue
+	X    uint64
+}
+
+func (hh *forkGatedHeaderHooks) EncodeRLP(h *Header, w io.Writer) error {
+	var geth bytes.Buffer
+	if err := new(NOOPHeaderHooks).EncodeRLP(h, &geth); err != nil {
+		return err
+	}
+	return rlp.Encode(w, &forkGatedHeaderRLP{geth.Bytes(), hh.X})
+}
+
+func (hh *forkGatedHeaderHooks) DecodeRLP(h *Header, s *rlp.Stream) error {
+	var enc forkGatedHeaderRLP
+	if err := s.Decode(&enc); err != nil {
+		return err
+	}
+	hh.X = enc.X
+	return new(NOOPHeaderHooks).DecodeRLP(h, rlp.NewStream(bytes.NewReader(enc.Geth), 0))
+}
+
+func TestForkGatedHeaderHooks(t *testing.T) {
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+
+	extras := RegisterExtras[
+		forkGatedHeaderHooks, *forkGatedHeaderHooks,
+		NOOPBlockBodyHooks, *NOOPBlockBodyHooks,
+		struct{},
+	]()
+
+	newHeader := func(time, x uint64) *Header {
+		h := &Header{
+			Number:     big.NewInt(1),
+			Difficulty: big.NewInt(0),
+			Time:       time,
+		}
+		extras.Header.Get(h).X = x
+		return h
+	}
+
+	for _, time := range []uint64{forkGatedActivation - 1, forkGatedActivation} {
+		active := time >= forkGatedActivation
+
+		t.Run(fmt.Sprintf("time_%d", time), func(t *testing.T) {
+			withX := newHeader(time, 42)
+			withoutX := newHeader(time, 0)
+			if active {
+				assert.NotEqual(t, withoutX.Hash(), withX.Hash(), "Header.Hash() includes extras after activation")
+			} else {
+				assert.Equal(t, withoutX.Hash(), withX.Hash(), "Header.Hash() excludes extras before activation")
+			}
+
+			buf, err := rlp.EncodeToBytes(withX)
+			require.NoErrorf(t, err, "rlp.EncodeToBytes(%T)", withX)
+			got := new(Header)
+			require.NoErrorf(t, rlp.DecodeBytes(buf, got), "rlp.DecodeBytes(..., %T)", got)
+
+			wantX := uint64(42)
+			if !active {
+				wantX = 0
+			}
+			assert.Equal(t, wantX, extras.Header.Get(got).X, "decoded extra")
+			assert.Equal(t, withX.Hash(), got.Hash(), "Header.Hash() after RLP round trip")
+		})
+	}
+
+	t.Run("extras_before_activation", func(t *testing.T) {
+		h := newHeader(forkGatedActivation-1, 42)
+		var geth bytes.Buffer
+		require.NoError(t, new(NOOPHeaderHooks).EncodeRLP(h, &geth), "encoding geth fields")
+		buf, err := rlp.EncodeToBytes(&forkGatedHeaderRLP{geth.Bytes(), 42})
+		require.NoError(t, err, "rlp.EncodeToBytes(...)")
+
+		err = rlp.DecodeBytes(buf, new(Header))
+		require.ErrorIs(t, err, ErrHeaderExtrasBeforeActivation, "rlp.DecodeBytes() of header with extras before activation")
+	})
+
+	t.Run("decode_before_activation_into_used_header", func(t *testing.T) {
+		buf, err := rlp.EncodeToBytes(newHeader(forkGatedActivation-1, 0))
+		require.NoError(t, err, "rlp.EncodeToBytes(...)")
+		got := newHeader(forkGatedActivation, 42)
+		require.NoErrorf(t, rlp.DecodeBytes(buf, got), "rlp.DecodeBytes(..., %T)", got)
+		assert.Zero(t, extras.Header.Get(got).X, "decoded extra")
+		assert.NoError(t, got.VerifyForkGatedExtras(), "VerifyForkGatedExtras()")
+	})
+
+	t.Run("VerifyForkGatedExtras", func(t *testing.T) {
+		tests := []struct {
+			time, x uint64
+			want    error
+		}{
+			{time: forkGatedActivation - 1, x: 0, want: nil},
+			{time: forkGatedActivation - 1, x: 42, want: ErrHeaderExtrasBeforeActivation},
+			{time: forkGatedActivation, x: 0, want: ErrHeaderExtrasMissingAfterActivation},
+			{time: forkGatedActivation, x: 42, want: nil},
+		}
+		for _, tt := range tests {
+			h := newHeader(tt.time, tt.x)
+			assert.ErrorIsf(t, h.VerifyForkGatedExtras(), tt.want, "VerifyForkGatedExtras() with Time = %d and X = %d", tt.time, tt.x)
+		}
+	})
+}
+
+func TestVerifyForkGatedExtrasNotGated(t *testing.T) {
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+
+	RegisterExtras[
+		NOOPHeaderHooks, *NOOPHeaderHooks,
+		NOOPBlockBodyHooks, *NOOPBlockBodyHooks,
+		struct{},
+	]()
+	assert.NoError(t, new(Header).VerifyForkGatedExtras())
+}
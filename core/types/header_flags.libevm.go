@@ -0,0 +1,72 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/libevm/params"
+)
+
+// HeaderFlags are boolean flags carried in a [Header]'s registered payload,
+// allowing the block proposer to switch precompile behaviour; e.g. to pause a
+// precompile in an emergency. The meaning of each bit is defined by the
+// registering package, typically as a set of constants.
+type HeaderFlags uint64
+
+// Has reports whether all bits set in `flag` are also set in `f`.
+func (f HeaderFlags) Has(flag HeaderFlags) bool {
+	return f&flag == flag
+}
+
+// HeaderFlagsCarrier MAY be implemented by a type registered for [Header]
+// payloads to expose [HeaderFlags] to precompiles, via
+// [vm.PrecompileEnvironment], and to have them validated during header
+// verification.
+type HeaderFlagsCarrier interface {
+	HeaderHooks
+	HeaderFlags() HeaderFlags
+	// VerifyHeaderFlags is called by consensus engines when verifying the
+	// header carrying the payload, and SHOULD return an error if the flags
+	// weren't permitted to be set; e.g. under governance rules limiting which
+	// proposers may pause a precompile.
+	VerifyHeaderFlags(config *params.ChainConfig, parent, header *Header) error
+}
+
+// Flags returns the flags carried by the [Header]'s registered payload. It
+// returns zero if no payload is registered or if the payload doesn't implement
+// [HeaderFlagsCarrier].
+func (h *Header) Flags() HeaderFlags {
+	if c, ok := h.hooks().(HeaderFlagsCarrier); ok {
+		return c.HeaderFlags()
+	}
+	return 0
+}
+
+// VerifyHeaderFlags verifies the flags carried by the header, if its registered
+// payload implements [HeaderFlagsCarrier]. It is called by all consensus
+// engines alongside other parent-dependent header checks.
+func VerifyHeaderFlags(config *params.ChainConfig, parent, header *Header) error {
+	c, ok := header.hooks().(HeaderFlagsCarrier)
+	if !ok {
+		return nil
+	}
+	if err := c.VerifyHeaderFlags(config, parent, header); err != nil {
+		return fmt.Errorf("invalid header flags %#x: %w", c.HeaderFlags(), err)
+	}
+	return nil
+}
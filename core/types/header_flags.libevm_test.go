@@ -0,0 +1,101 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package types_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	. "github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/params"
+)
+
+const flagPause HeaderFlags = 1 << 0
+
+var (
+	governor      = common.Address{'g', 'o', 'v'}
+	errNotAllowed = errors.New("proposer not permitted to set flags")
+)
+
+type flagHeaderHooks struct {
+	NOOPHeaderHooks
+	Flags HeaderFlags
+}
+
+func (hh *flagHeaderHooks) HeaderFlags() HeaderFlags {
+	return hh.Flags
+}
+
+func (hh *flagHeaderHooks) VerifyHeaderFlags(_ *params.ChainConfig, _, header *Header) error {
+	if hh.Flags != 0 && header.Coinbase != governor {
+		return errNotAllowed
+	}
+	return nil
+}
+
+func TestHeaderFlags(t *testing.T) {
+	t.Run("unregistered", func(t *testing.T) {
+		TestOnlyClearRegisteredExtras()
+		assert.Zero(t, new(Header).Flags(), "Header.Flags()")
+		require.NoError(t, VerifyHeaderFlags(params.TestChainConfig, new(Header), new(Header)), "VerifyHeaderFlags()")
+	})
+
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+	extras := RegisterExtras[
+		flagHeaderHooks, *flagHeaderHooks,
+		NOOPBlockBodyHooks, *NOOPBlockBodyHooks,
+		struct{},
+	]()
+
+	tests := []struct {
+		name     string
+		coinbase common.Address
+		flags    HeaderFlags
+		wantErr  error
+	}{
+		{
+			name: "no_flags",
+		},
+		{
+			name:     "governor_sets_flag",
+			coinbase: governor,
+			flags:    flagPause,
+		},
+		{
+			name:    "other_proposer_sets_flag",
+			flags:   flagPause,
+			wantErr: errNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hdr := &Header{Coinbase: tt.coinbase}
+			extras.Header.Get(hdr).Flags = tt.flags
+
+			assert.Equal(t, tt.flags, hdr.Flags(), "Header.Flags()")
+			assert.Equal(t, tt.flags != 0, hdr.Flags().Has(flagPause), "Header.Flags().Has(flagPause)")
+			err := VerifyHeaderFlags(params.TestChainConfig, new(Header), hdr)
+			require.ErrorIs(t, err, tt.wantErr, "VerifyHeaderFlags()")
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package types
+
+// SubSecondTimeCarrier MAY be implemented by a type registered for [Header]
+// payloads to expose block time at a finer, chain-defined granularity than the
+// Time field, via [vm.PrecompileEnvironment.Clock].
+type SubSecondTimeCarrier interface {
+	HeaderHooks
+	// SubSecondTime returns the number of ticks, each 1/perSecond of a second,
+	// elapsed since the header's Time. It MUST be the case that ticks <
+	// perSecond.
+	SubSecondTime() (ticks, perSecond uint64)
+}
+
+// SubSecondTime returns the values reported by the [Header]'s registered
+// payload if it implements [SubSecondTimeCarrier]. The returned boolean is
+// false, and the other values zero, if no payload is registered, if the payload
+// doesn't implement said interface, or if the reported values are invalid.
+func (h *Header) SubSecondTime() (ticks, perSecond uint64, ok bool) {
+	c, ok := h.hooks().(SubSecondTimeCarrier)
+	if !ok {
+		return 0, 0, false
+	}
+	ticks, perSecond = c.SubSecondTime()
+	if ticks >= perSecond {
+		return 0, 0, false
+	}
+	return ticks, perSecond, true
+}
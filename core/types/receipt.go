@@ -71,6 +71,10 @@ type Receipt struct {
 	BlockHash        common.Hash `json:"blockHash,omitempty"`
 	BlockNumber      *big.Int    `json:"blockNumber,omitempty"`
 	TransactionIndex uint        `json:"transactionIndex"`
+
+	// libevm: neither part of the consensus encoding nor persisted, so only
+	// set in memory on receipts returned by transaction execution.
+	Failure ReceiptFailure `json:"-"`
 }
 
 type receiptMarshaling struct {
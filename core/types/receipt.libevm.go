@@ -0,0 +1,69 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package types
+
+import "fmt"
+
+// A ReceiptFailure classifies the reason for a [Receipt] having a
+// [ReceiptStatusFailed] status. It is neither part of the consensus encoding
+// of a Receipt nor persisted with receipts in storage, and therefore only
+// exists in memory, on receipts returned by the execution of the respective
+// transaction; e.g. by the state processor or block builder. In particular, it
+// is always [ReceiptFailureNone] on receipts read from the database, and is
+// not exposed by RPC methods such as `eth_getTransactionReceipt`.
+//
+// Transactions rejected by [params.RulesAllowlistHooks.CanExecuteTransaction]
+// are not included in blocks and therefore have no receipts, nor do those
+// invalidated by a precompile (see vm.PrecompileEnvironment) unless the
+// disposition of the invalidation is [params.RevertInvalidatedTx].
+type ReceiptFailure uint8
+
+// Reasons for transaction failure.
+const (
+	// ReceiptFailureNone is used for successful transactions.
+	ReceiptFailureNone ReceiptFailure = iota
+	// ReceiptFailureReverted is used when the top-level call reverted.
+	ReceiptFailureReverted
+	// ReceiptFailurePolicyRejected is used when a contract creation by the
+	// transaction itself was blocked by
+	// [params.RulesAllowlistHooks.CanCreateContract].
+	ReceiptFailurePolicyRejected
+	// ReceiptFailureOther is used for all other failures; e.g. exhaustion of
+	// gas.
+	ReceiptFailureOther
+	// ReceiptFailureInvalidated is used when execution was invalidated by a
+	// precompile and reverted, as per [params.RevertInvalidatedTx].
+	ReceiptFailureInvalidated
+)
+
+// String returns a human-readable representation of the failure reason.
+func (f ReceiptFailure) String() string {
+	switch f {
+	case ReceiptFailureNone:
+		return "none"
+	case ReceiptFailureReverted:
+		return "reverted"
+	case ReceiptFailurePolicyRejected:
+		return "policyRejected"
+	case ReceiptFailureOther:
+		return "other"
+	case ReceiptFailureInvalidated:
+		return "invalidated"
+	default:
+		return fmt.Sprintf("ReceiptFailure(%d)", f)
+	}
+}
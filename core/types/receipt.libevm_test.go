@@ -0,0 +1,57 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/rlp"
+)
+
+func TestReceiptFailureNotStored(t *testing.T) {
+	base := func() *Receipt {
+		return &Receipt{
+			Status:            ReceiptStatusFailed,
+			CumulativeGasUsed: 42,
+			Logs:              []*Log{},
+		}
+	}
+	want, err := rlp.EncodeToBytes((*ReceiptForStorage)(base()))
+	require.NoError(t, err, "rlp.EncodeToBytes(ReceiptForStorage)")
+
+	for _, f := range []ReceiptFailure{
+		ReceiptFailureReverted,
+		ReceiptFailurePolicyRejected,
+		ReceiptFailureOther,
+		ReceiptFailureInvalidated,
+	} {
+		t.Run(f.String(), func(t *testing.T) {
+			r := base()
+			r.Failure = f
+			buf, err := rlp.EncodeToBytes((*ReceiptForStorage)(r))
+			require.NoError(t, err, "rlp.EncodeToBytes(ReceiptForStorage)")
+			assert.Equal(t, want, buf, "storage encoding with failure reason")
+
+			got := new(ReceiptForStorage)
+			require.NoError(t, rlp.DecodeBytes(buf, got), "rlp.DecodeBytes(..., ReceiptForStorage)")
+			assert.Equal(t, ReceiptFailureNone, got.Failure, "Failure after storage round trip")
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright 2024-2025 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -151,6 +151,29 @@ func TestOnlyClearRegisteredExtras() {
 	registeredExtras.TestOnlyClear()
 }
 
+// RegisteredExtrasInfo describes the types registered with [RegisterExtras].
+// Types are represented as reported by the `%T` verb of the [fmt] package.
+type RegisteredExtrasInfo struct {
+	Header       string `json:"header"`
+	BlockBody    string `json:"blockBody"`
+	StateAccount string `json:"stateAccount"`
+}
+
+// RegisteredExtras returns information about the types registered with
+// [RegisterExtras] and whether any have been registered at all.
+func RegisteredExtras() (RegisteredExtrasInfo, bool) {
+	r := registeredExtras
+	if !r.Registered() {
+		return RegisteredExtrasInfo{}, false
+	}
+	e := r.Get()
+	return RegisteredExtrasInfo{
+		Header:       fmt.Sprintf("%T", e.newHeader().Interface()),
+		BlockBody:    fmt.Sprintf("%T", e.newBlockOrBody().Interface()),
+		StateAccount: e.stateAccountType,
+	}, true
+}
+
 var registeredExtras register.AtMostOnce[*extraConstructors]
 
 type extraConstructors struct {
@@ -0,0 +1,83 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/binary"
+	"math/big"
+	"reflect"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/params"
+)
+
+// ReplayDomainHooks MAY be implemented by the [params.ChainConfigHooks]
+// registered as [params.Extras], allowing chains that share a chain ID to
+// enforce distinct replay-protection domains without modifying signers.
+type ReplayDomainHooks interface {
+	// SigningChainID receives [params.ChainConfig.ChainID] and returns the
+	// chain ID to be used, in its place, by the signers returned by
+	// [MakeSigner] and [LatestSigner], and by everything else that relies on
+	// [SigningChainID]. It MAY use [DeriveReplayDomain] to
+	// include extra domain fields carried by the chain-config extras. A nil
+	// return value disables EIP-155 replay protection.
+	SigningChainID(chainID *big.Int) *big.Int
+}
+
+// SigningChainID returns the chain ID to be used when signing transactions for
+// the config, honouring any registered [ReplayDomainHooks]. It MUST be used in
+// place of [params.ChainConfig.ChainID] wherever a transaction's chain ID is
+// set or checked, including when reporting the chain ID to clients, otherwise
+// the transactions are rejected by the config's signers.
+func SigningChainID(c *params.ChainConfig) *big.Int {
+	h, ok := c.Hooks().(ReplayDomainHooks)
+	if !ok {
+		return c.ChainID
+	}
+	// Configs without extras carry nil hooks if the registered type is a
+	// pointer, which are equivalent to there being no hooks.
+	if v := reflect.ValueOf(h); v.Kind() == reflect.Pointer && v.IsNil() {
+		return c.ChainID
+	}
+	return h.SigningChainID(c.ChainID)
+}
+
+// DeriveReplayDomain deterministically derives a chain ID from another one and
+// any number of extra domain fields, for use as the return value of
+// [ReplayDomainHooks.SigningChainID]. Distinct inputs result in distinct IDs
+// with overwhelming probability. The derived ID is non-zero and less than
+// 2^52, so it can be represented exactly by JavaScript clients.
+func DeriveReplayDomain(chainID *big.Int, fields ...[]byte) *big.Int {
+	var id common.Hash
+	if chainID != nil {
+		chainID.FillBytes(id[:])
+	}
+	preimage := [][]byte{[]byte("libevm.replay-domain"), id[:]}
+	for _, f := range fields {
+		// Length prefixes stop fields from being ambiguously concatenated.
+		preimage = append(preimage, binary.BigEndian.AppendUint64(nil, uint64(len(f))), f)
+	}
+	h := crypto.Keccak256(preimage...)
+
+	const bits = 52
+	derived := binary.BigEndian.Uint64(h[:8]) >> (64 - bits)
+	if derived == 0 {
+		derived = 1
+	}
+	return new(big.Int).SetUint64(derived)
+}
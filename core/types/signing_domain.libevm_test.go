@@ -0,0 +1,124 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/params"
+)
+
+type replayDomainConfig struct {
+	params.NOOPHooks
+	domain []byte
+}
+
+func (c *replayDomainConfig) SigningChainID(chainID *big.Int) *big.Int {
+	if c.domain == nil {
+		return chainID
+	}
+	return DeriveReplayDomain(chainID, c.domain)
+}
+
+func TestReplayDomainHooks(t *testing.T) {
+	params.TestOnlyClearRegisteredExtras()
+	t.Cleanup(params.TestOnlyClearRegisteredExtras)
+	extras := params.RegisterExtras(params.Extras[*replayDomainConfig, params.NOOPHooks]{})
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err, "crypto.GenerateKey()")
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	newConfig := func(domain []byte) *params.ChainConfig {
+		c := *params.MergedTestChainConfig
+		extras.ChainConfig.Set(&c, &replayDomainConfig{domain: domain})
+		return &c
+	}
+	var (
+		plain = newConfig(nil)
+		appA  = newConfig([]byte("A"))
+		appB  = newConfig([]byte("B"))
+	)
+
+	wantA := DeriveReplayDomain(plain.ChainID, []byte("A"))
+	assert.Equal(t, wantA, MakeSigner(appA, big.NewInt(0), 0).ChainID(), "MakeSigner().ChainID()")
+	assert.Equal(t, wantA, LatestSigner(appA).ChainID(), "LatestSigner().ChainID()")
+	assert.Equal(t, plain.ChainID, LatestSigner(plain).ChainID(), "LatestSigner().ChainID() without domain")
+
+	noExtras := *params.MergedTestChainConfig
+	assert.Equal(t, noExtras.ChainID, SigningChainID(&noExtras), "SigningChainID() with nil-pointer hooks")
+
+	txs := map[string]TxData{
+		"legacy": &LegacyTx{Gas: 21_000, GasPrice: big.NewInt(1)},
+		"dynamic_fee": &DynamicFeeTx{
+			ChainID:   wantA,
+			Gas:       21_000,
+			GasFeeCap: big.NewInt(1),
+		},
+		"blob": &BlobTx{
+			ChainID:    uint256.MustFromBig(wantA),
+			Gas:        21_000,
+			GasFeeCap:  uint256.NewInt(1),
+			BlobFeeCap: uint256.NewInt(1),
+			BlobHashes: []common.Hash{{1}},
+		},
+	}
+
+	for name, data := range txs {
+		t.Run(name, func(t *testing.T) {
+			tx, err := SignNewTx(key, LatestSigner(appA), data)
+			require.NoError(t, err, "SignNewTx()")
+			assert.Equal(t, wantA, tx.ChainId(), "signed tx's ChainId()")
+
+			got, err := Sender(LatestSigner(appA), tx)
+			require.NoError(t, err, "Sender() in same domain")
+			assert.Equal(t, from, got, "Sender() in same domain")
+
+			for _, other := range []*params.ChainConfig{plain, appB} {
+				got, err := Sender(LatestSigner(other), tx)
+				if err == nil {
+					assert.NotEqual(t, from, got, "Sender() in different domain")
+				}
+			}
+		})
+	}
+}
+
+func TestDeriveReplayDomain(t *testing.T) {
+	id := big.NewInt(43114)
+	base := DeriveReplayDomain(id, []byte("x"))
+
+	assert.Equal(t, base, DeriveReplayDomain(id, []byte("x")), "deterministic")
+	for _, other := range []*big.Int{
+		DeriveReplayDomain(id),
+		DeriveReplayDomain(id, []byte("y")),
+		DeriveReplayDomain(big.NewInt(43113), []byte("x")),
+		DeriveReplayDomain(id, []byte("x"), nil),
+		DeriveReplayDomain(id, nil, []byte("x")),
+	} {
+		assert.NotEqual(t, base, other, "different inputs")
+	}
+	assert.Less(t, base.BitLen(), 53, "BitLen()")
+	assert.Positive(t, base.Sign(), "Sign()")
+}
@@ -38,16 +38,17 @@ type sigCache struct {
 
 // MakeSigner returns a Signer based on the given chain config and block number.
 func MakeSigner(config *params.ChainConfig, blockNumber *big.Int, blockTime uint64) Signer {
+	chainID := SigningChainID(config) // libevm
 	var signer Signer
 	switch {
 	case config.IsCancun(blockNumber, blockTime):
-		signer = NewCancunSigner(config.ChainID)
+		signer = NewCancunSigner(chainID)
 	case config.IsLondon(blockNumber):
-		signer = NewLondonSigner(config.ChainID)
+		signer = NewLondonSigner(chainID)
 	case config.IsBerlin(blockNumber):
-		signer = NewEIP2930Signer(config.ChainID)
+		signer = NewEIP2930Signer(chainID)
 	case config.IsEIP155(blockNumber):
-		signer = NewEIP155Signer(config.ChainID)
+		signer = NewEIP155Signer(chainID)
 	case config.IsHomestead(blockNumber):
 		signer = HomesteadSigner{}
 	default:
@@ -64,18 +65,18 @@ func MakeSigner(config *params.ChainConfig, blockNumber *big.Int, blockTime uint
 // Use this in transaction-handling code where the current block number is unknown. If you
 // have the current block number available, use MakeSigner instead.
 func LatestSigner(config *params.ChainConfig) Signer {
-	if config.ChainID != nil {
+	if chainID := SigningChainID(config); chainID != nil { // libevm
 		if config.CancunTime != nil {
-			return NewCancunSigner(config.ChainID)
+			return NewCancunSigner(chainID)
 		}
 		if config.LondonBlock != nil {
-			return NewLondonSigner(config.ChainID)
+			return NewLondonSigner(chainID)
 		}
 		if config.BerlinBlock != nil {
-			return NewEIP2930Signer(config.ChainID)
+			return NewEIP2930Signer(chainID)
 		}
 		if config.EIP155Block != nil {
-			return NewEIP155Signer(config.ChainID)
+			return NewEIP155Signer(chainID)
 		}
 	}
 	return HomesteadSigner{}
@@ -0,0 +1,109 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/params"
+)
+
+// An AccessRecordingStateDB is a [StateDB] that can record all accounts and
+// storage slots accessed through it. It is implemented by
+// [github.com/ava-labs/libevm/core/state.StateDB].
+type AccessRecordingStateDB interface {
+	StateDB
+	RecordAccesses() (stop func() types.AccessList)
+}
+
+// A PrecompileAccessListLogger is an optional extension of [EVMLogger]. If
+// the [Config.Tracer] implements it, and the [EVM.StateDB] is an
+// [AccessRecordingStateDB], then all accounts and storage slots accessed
+// during the execution of a stateful precompile, including via
+// [PrecompileEnvironment.Call], are reported upon the precompile returning.
+//
+// This allows access-list generation (e.g. eth_createAccessList) to account
+// for state that isn't visible to opcode-level tracing.
+type PrecompileAccessListLogger interface {
+	CapturePrecompileAccessList(precompile common.Address, accessed types.AccessList)
+}
+
+// recordPrecompileAccesses starts recording state accesses if the conditions
+// described by [PrecompileAccessListLogger] are met. The returned function
+// MUST be called when the precompile returns.
+//
+// It is a method on the EVM, not on [evmCallArgs], to avoid the returned
+// closure causing the latter to escape to the heap.
+func (evm *EVM) recordPrecompileAccesses(precompile common.Address) (report func()) {
+	noop := func() {}
+	if evm == nil { // see [RunPrecompiledContract] in tests
+		return noop
+	}
+	l, ok := evm.Config.Tracer.(PrecompileAccessListLogger)
+	if !ok {
+		return noop
+	}
+	db, ok := evm.StateDB.(AccessRecordingStateDB)
+	if !ok {
+		return noop
+	}
+	stop := db.RecordAccesses()
+	return func() {
+		l.CapturePrecompileAccessList(precompile, stop())
+	}
+}
+
+func (e *environment) AddressInAccessList(addr common.Address) bool {
+	return e.evm.StateDB.AddressInAccessList(addr)
+}
+
+func (e *environment) SlotInAccessList(addr common.Address, slot common.Hash) (addressOk, slotOk bool) {
+	return e.evm.StateDB.SlotInAccessList(addr, slot)
+}
+
+// Additions to the access list are permitted in read-only mode, as they are
+// for the interpreter's handling of, for example, SLOAD during STATICCALL.
+
+func (e *environment) AddAddressToAccessList(addr common.Address) {
+	e.evm.StateDB.AddAddressToAccessList(addr)
+}
+
+func (e *environment) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	e.evm.StateDB.AddSlotToAccessList(addr, slot)
+}
+
+func (e *environment) AccountAccessGas(addr common.Address) uint64 {
+	if !e.evm.chainRules.IsEIP2929() {
+		return 0
+	}
+	if e.evm.StateDB.AddressInAccessList(addr) {
+		return params.WarmStorageReadCostEIP2929
+	}
+	e.evm.StateDB.AddAddressToAccessList(addr)
+	return params.ColdAccountAccessCostEIP2929
+}
+
+func (e *environment) SlotAccessGas(addr common.Address, slot common.Hash) uint64 {
+	if !e.evm.chainRules.IsEIP2929() {
+		return 0
+	}
+	if _, ok := e.evm.StateDB.SlotInAccessList(addr, slot); ok {
+		return params.WarmStorageReadCostEIP2929
+	}
+	e.evm.StateDB.AddSlotToAccessList(addr, slot)
+	return params.ColdSloadCostEIP2929
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/eth/tracers/logger"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestPrecompileAccessList(t *testing.T) {
+	rng := ethtest.NewPseudoRand(743)
+	var (
+		caller     = rng.Address()
+		precompile = rng.Address()
+		read       = rng.Address()
+		touched    = rng.Address()
+		slot       = rng.Hash()
+	)
+
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				sdb := env.StateDB()
+				_ = sdb.GetState(read, slot)
+				_ = sdb.GetBalance(touched)
+				_ = sdb.GetBalance(env.Addresses().EVMSemantic.Caller) // excluded by the tracer
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	_, evm := ethtest.NewZeroEVM(t)
+	tracer := logger.NewAccessListTracer(nil, caller, precompile, nil)
+	evm.Config.Tracer = tracer
+
+	_, _, err := evm.Call(vm.AccountRef(caller), precompile, nil, 1e6, uint256.NewInt(0))
+	require.NoError(t, err, "evm.Call()")
+
+	want := types.AccessList{
+		{Address: read, StorageKeys: []common.Hash{slot}},
+		{Address: touched, StorageKeys: []common.Hash{}},
+	}
+	assert.ElementsMatch(t, want, tracer.AccessList(), "%T.AccessList()", tracer)
+}
+
+func TestPrecompileAccessListGas(t *testing.T) {
+	rng := ethtest.NewPseudoRand(759)
+	var (
+		precompile = rng.Address()
+		account    = rng.Address()
+		slot       = rng.Hash()
+	)
+
+	type result struct {
+		accountWarmBefore, slotWarmBefore bool
+		accountGas, slotGas               []uint64
+		accountWarmAfter, slotWarmAfter   bool
+	}
+	var got result
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				got = result{}
+				got.accountWarmBefore = env.AddressInAccessList(account)
+				_, got.slotWarmBefore = env.SlotInAccessList(account, slot)
+				for range 2 {
+					got.accountGas = append(got.accountGas, env.AccountAccessGas(account))
+					got.slotGas = append(got.slotGas, env.SlotAccessGas(account, slot))
+				}
+				got.accountWarmAfter = env.AddressInAccessList(account)
+				_, got.slotWarmAfter = env.SlotInAccessList(account, slot)
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	tests := []struct {
+		name   string
+		config *params.ChainConfig
+		want   result
+	}{
+		{
+			name:   "berlin",
+			config: params.TestChainConfig,
+			want: result{
+				accountGas:       []uint64{params.ColdAccountAccessCostEIP2929, params.WarmStorageReadCostEIP2929},
+				slotGas:          []uint64{params.ColdSloadCostEIP2929, params.WarmStorageReadCostEIP2929},
+				accountWarmAfter: true,
+				slotWarmAfter:    true,
+			},
+		},
+		{
+			name:   "pre_berlin",
+			config: &params.ChainConfig{ChainID: big.NewInt(1)},
+			want: result{
+				accountGas: []uint64{0, 0},
+				slotGas:    []uint64{0, 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, evm := ethtest.NewZeroEVM(t,
+				ethtest.WithChainConfig(tt.config),
+				ethtest.WithBlockContext(vm.BlockContext{
+					CanTransfer: core.CanTransfer,
+					Transfer:    core.Transfer,
+					BlockNumber: big.NewInt(0),
+				}),
+			)
+			_, _, err := evm.StaticCall(vm.AccountRef(rng.Address()), precompile, nil, 1e6)
+			require.NoError(t, err, "evm.StaticCall()")
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+)
+
+// CallerAliasingHooks MAY be implemented by a [params.RulesHooks] to rewrite
+// the caller of calls originating from designated senders; e.g. the address
+// aliasing of cross-domain messages, as performed by optimistic rollups, to
+// allow ported L2 contracts to behave correctly.
+//
+// Aliasing is applied by [EVM.Call] after any value is transferred from the
+// original caller. The aliased address is then observed by the callee as
+// CALLER, by tracers, and by precompiles via [PrecompileEnvironment.Addresses].
+// ORIGIN is unchanged.
+type CallerAliasingHooks interface {
+	// AliasCaller returns the address to be used as the caller of a call from
+	// `caller` to `callee`, and whether aliasing applies.
+	AliasCaller(caller, callee common.Address) (_ common.Address, alias bool)
+}
+
+func (evm *EVM) aliasCaller(caller ContractRef, callee common.Address) ContractRef {
+	ah, ok := evm.chainRules.Hooks().(CallerAliasingHooks)
+	if !ok {
+		return caller
+	}
+	if alias, ok := ah.AliasCaller(caller.Address(), callee); ok {
+		return AccountRef(alias)
+	}
+	return caller
+}
+
+// RollupAliasOffset is the offset added to addresses by optimistic rollups to
+// alias the senders of cross-domain messages.
+var RollupAliasOffset = common.HexToAddress("0x1111000000000000000000000000000000001111")
+
+// ApplyRollupAlias returns `addr` + [RollupAliasOffset], modulo 2^160.
+func ApplyRollupAlias(addr common.Address) common.Address {
+	return addAddresses(addr, RollupAliasOffset, false)
+}
+
+// UndoRollupAlias is the inverse of [ApplyRollupAlias].
+func UndoRollupAlias(addr common.Address) common.Address {
+	return addAddresses(addr, RollupAliasOffset, true)
+}
+
+func addAddresses(a, b common.Address, subtract bool) common.Address {
+	x := new(uint256.Int).SetBytes20(a.Bytes())
+	y := new(uint256.Int).SetBytes20(b.Bytes())
+	if subtract {
+		x.Sub(x, y)
+	} else {
+		x.Add(x, y)
+	}
+	return x.Bytes20()
+}
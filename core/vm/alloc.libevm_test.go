@@ -0,0 +1,95 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"os/exec"
+	"regexp"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/params"
+)
+
+// noopPrecompile is a stateless precompile that doesn't allocate.
+type noopPrecompile struct{}
+
+func (noopPrecompile) RequiredGas([]byte) uint64     { return 100 }
+func (noopPrecompile) Run(in []byte) ([]byte, error) { return in, nil }
+
+// runStatelessPrecompile mirrors the precompile path of [EVM.Call].
+func runStatelessPrecompile(evm *EVM, caller ContractRef, p PrecompiledContract, input []byte, value *uint256.Int) {
+	args := &evmCallArgs{evm, Call, caller, common.Address{1}, input, 1e6, value}
+	_, _, _ = args.RunPrecompiledContract(p, input, 1e6)
+}
+
+func TestStatelessPrecompileAllocations(t *testing.T) {
+	evm := NewEVM(BlockContext{}, TxContext{}, nil, &params.ChainConfig{}, Config{})
+	var (
+		caller ContractRef         = AccountRef{'c', 'a', 'l', 'l', 'e', 'r'}
+		p      PrecompiledContract = noopPrecompile{}
+		input                      = []byte("input")
+		value                      = uint256.NewInt(0)
+	)
+
+	got := testing.AllocsPerRun(100, func() {
+		runStatelessPrecompile(evm, caller, p, input, value)
+	})
+	assert.Zero(t, got, "heap allocations per stateless precompile call")
+}
+
+// TestEVMCallArgsDoNotEscape uses the compiler's escape analysis to confirm
+// that the [evmCallArgs] constructed by [EVM] methods remain on the stack.
+func TestEVMCallArgsDoNotEscape(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles the package")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skipf("go toolchain unavailable: %v", err)
+	}
+
+	// Inlining would result in [evmCallArgs] being reported from the
+	// perspective of the callers of the [EVM] methods too.
+	out, err := exec.Command(goBin, "build", "-gcflags=-m -l", ".").CombinedOutput()
+	require.NoErrorf(t, err, "go build -gcflags=-m; output:\n%s", out)
+
+	escapes := regexp.MustCompile(`evm\.go:\d+:\d+: &evmCallArgs\{...\} escapes to heap`)
+	assert.Empty(t, escapes.FindAll(out, -1), "evmCallArgs escaping to heap")
+
+	constructed := regexp.MustCompile(`evm\.go:\d+:\d+: &evmCallArgs\{...\} does not escape`)
+	assert.Len(t, constructed.FindAll(out, -1), 4, "evmCallArgs constructed by Call(), CallCode(), DelegateCall() and StaticCall()")
+}
+
+func BenchmarkStatelessPrecompile(b *testing.B) {
+	evm := NewEVM(BlockContext{}, TxContext{}, nil, &params.ChainConfig{}, Config{})
+	var (
+		caller ContractRef         = AccountRef{'c', 'a', 'l', 'l', 'e', 'r'}
+		p      PrecompiledContract = noopPrecompile{}
+		input                      = []byte("input")
+		value                      = uint256.NewInt(0)
+	)
+
+	b.ReportAllocs()
+	for range b.N {
+		runStatelessPrecompile(evm, caller, p, input, value)
+	}
+}
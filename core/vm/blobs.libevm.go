@@ -0,0 +1,80 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+)
+
+// BlobOpCodeHooks MAY be implemented by a [params.RulesHooks] to allow chains
+// that don't carry blobs to define deterministic behaviour for the BLOBHASH and
+// BLOBBASEFEE opcodes, instead of inheriting mainnet assumptions. This keeps
+// bytecode compiled for mainnet portable. They are only used once the
+// respective opcodes are active.
+//
+// A non-nil error returned by either method halts the calling frame as if
+// returned by the opcode. All remaining gas is consumed unless the error is
+// [ErrExecutionReverted] itself, which behaves as REVERT with empty return
+// data. [ErrBlobsUnsupported] is provided for chains that reject blob opcodes
+// outright.
+type BlobOpCodeHooks interface {
+	// BlobHash returns the value to be pushed by BLOBHASH, which would
+	// otherwise be the versioned hash at `index` in the transaction's blob
+	// hashes, or zero if out of range.
+	BlobHash(txBlobHashes []common.Hash, index *uint256.Int) (common.Hash, error)
+	// BlobBaseFee returns the value to be pushed by BLOBBASEFEE. The header is
+	// that of the [BlockContext], which MAY be nil.
+	BlobBaseFee(*types.Header) (*uint256.Int, error)
+}
+
+// ErrBlobsUnsupported MAY be returned by [BlobOpCodeHooks] to signal that a
+// chain doesn't support blobs.
+var ErrBlobsUnsupported = errors.New("blobs unsupported")
+
+func (evm *EVM) blobOpCodeHooks() (BlobOpCodeHooks, bool) {
+	bh, ok := evm.chainRules.Hooks().(BlobOpCodeHooks)
+	return bh, ok
+}
+
+func opBlobHashHook(h BlobOpCodeHooks, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	index := scope.Stack.peek()
+	evm := interpreter.evm
+	blobHash, err := h.BlobHash(evm.TxContext.BlobHashes, index)
+	if err != nil {
+		return nil, err
+	}
+	index.SetBytes32(blobHash[:])
+	return nil, nil
+}
+
+func opBlobBaseFeeHook(h BlobOpCodeHooks, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	evm := interpreter.evm
+	fee, err := h.BlobBaseFee(evm.Context.Header)
+	if err != nil {
+		return nil, err
+	}
+	if fee == nil {
+		fee = new(uint256.Int)
+	}
+	scope.Stack.push(fee)
+	return nil, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/ava-labs/libevm/common"
+)
+
+// WithBlockCache returns a stateful precompile that caches the successful
+// results of `fn`, keyed by input, for the duration of a block. It is an
+// opt-in optimisation for precompiles whose output depends only on their input
+// and on state that changes at block boundaries (e.g. validator-set queries),
+// such that repeated calls within the same block need not repeat the work.
+//
+// The cache is invalidated whenever a call is made in the context of a
+// different block, identified by its number and parent hash; a call without an
+// available [PrecompileEnvironment.BlockHeader] bypasses the cache entirely. A
+// cache hit consumes the same amount of gas as the original call, but `fn` MUST
+// NOT have any other side effects (e.g. state changes or logs) as they won't be
+// replayed. At most `maxEntries` results are cached per block, beyond which
+// calls are passed through to `fn`.
+func WithBlockCache(fn PrecompiledStatefulContract, maxEntries int) PrecompiledContract {
+	c := &blockCache{
+		fn:         fn,
+		maxEntries: maxEntries,
+	}
+	return NewStatefulPrecompile(c.run)
+}
+
+type blockCache struct {
+	fn         PrecompiledStatefulContract
+	maxEntries int
+
+	mu      sync.Mutex
+	epoch   blockEpoch
+	entries map[string]blockCacheEntry
+}
+
+type blockEpoch struct {
+	number     uint64
+	parentHash common.Hash
+}
+
+type blockCacheEntry struct {
+	ret     []byte
+	gasUsed uint64
+}
+
+func (c *blockCache) run(env PrecompileEnvironment, input []byte) ([]byte, error) {
+	hdr, err := env.BlockHeader()
+	if err != nil || hdr.Number == nil || !hdr.Number.IsUint64() {
+		return c.fn(env, input)
+	}
+	epoch := blockEpoch{hdr.Number.Uint64(), hdr.ParentHash}
+
+	if e, ok := c.get(epoch, input); ok {
+		if !env.UseGas(e.gasUsed) {
+			return nil, ErrOutOfGas
+		}
+		return bytes.Clone(e.ret), nil
+	}
+
+	before := env.Gas()
+	ret, err := c.fn(env, input)
+	if err != nil {
+		return ret, err
+	}
+	c.set(epoch, input, blockCacheEntry{
+		ret:     bytes.Clone(ret),
+		gasUsed: before - env.Gas(),
+	})
+	return ret, nil
+}
+
+func (c *blockCache) get(epoch blockEpoch, input []byte) (blockCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if epoch != c.epoch {
+		return blockCacheEntry{}, false
+	}
+	e, ok := c.entries[string(input)]
+	return e, ok
+}
+
+func (c *blockCache) set(epoch blockEpoch, input []byte, e blockCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if epoch != c.epoch || c.entries == nil {
+		c.epoch = epoch
+		c.entries = make(map[string]blockCacheEntry)
+	}
+	if len(c.entries) >= c.maxEntries {
+		return
+	}
+	c.entries[string(input)] = e
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestWithBlockCache(t *testing.T) {
+	rng := ethtest.NewPseudoRand(755)
+	precompile := rng.Address()
+
+	const (
+		gasCost  = 1000
+		gasLimit = 1e6
+	)
+	var runs int
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.WithBlockCache(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				runs++
+				if !env.UseGas(gasCost) {
+					return nil, vm.ErrOutOfGas
+				}
+				return append([]byte{byte(runs)}, input...), nil
+			}, 2),
+		},
+	}
+	hooks.Register(t)
+
+	caller := vm.AccountRef(rng.Address())
+	parent := rng.Hash()
+
+	type step struct {
+		name     string
+		header   *types.Header
+		input    byte
+		wantRet  []byte
+		wantRuns int
+	}
+	steps := []step{
+		{
+			name:     "miss",
+			header:   &types.Header{Number: big.NewInt(1), ParentHash: parent},
+			input:    'a',
+			wantRet:  []byte{1, 'a'},
+			wantRuns: 1,
+		},
+		{
+			name:     "hit",
+			header:   &types.Header{Number: big.NewInt(1), ParentHash: parent},
+			input:    'a',
+			wantRet:  []byte{1, 'a'},
+			wantRuns: 1,
+		},
+		{
+			name:     "different_input",
+			header:   &types.Header{Number: big.NewInt(1), ParentHash: parent},
+			input:    'b',
+			wantRet:  []byte{2, 'b'},
+			wantRuns: 2,
+		},
+		{
+			name:     "beyond_max_entries",
+			header:   &types.Header{Number: big.NewInt(1), ParentHash: parent},
+			input:    'c',
+			wantRet:  []byte{3, 'c'},
+			wantRuns: 3,
+		},
+		{
+			name:     "beyond_max_entries_not_cached",
+			header:   &types.Header{Number: big.NewInt(1), ParentHash: parent},
+			input:    'c',
+			wantRet:  []byte{4, 'c'},
+			wantRuns: 4,
+		},
+		{
+			name:     "different_parent",
+			header:   &types.Header{Number: big.NewInt(1), ParentHash: rng.Hash()},
+			input:    'a',
+			wantRet:  []byte{5, 'a'},
+			wantRuns: 5,
+		},
+		{
+			name:     "next_block",
+			header:   &types.Header{Number: big.NewInt(2)},
+			input:    'a',
+			wantRet:  []byte{6, 'a'},
+			wantRuns: 6,
+		},
+		{
+			name:     "hit_in_next_block",
+			header:   &types.Header{Number: big.NewInt(2)},
+			input:    'a',
+			wantRet:  []byte{6, 'a'},
+			wantRuns: 6,
+		},
+		{
+			name:     "no_header_bypasses",
+			input:    'a',
+			wantRet:  []byte{7, 'a'},
+			wantRuns: 7,
+		},
+	}
+
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			_, evm := ethtest.NewZeroEVM(t, ethtest.WithBlockContext(vm.BlockContext{
+				CanTransfer: core.CanTransfer,
+				Transfer:    core.Transfer,
+				BlockNumber: big.NewInt(0),
+				Header:      s.header,
+			}))
+			got, gasLeft, err := evm.Call(caller, precompile, []byte{s.input}, gasLimit, uint256.NewInt(0))
+			require.NoError(t, err, "evm.Call()")
+			assert.Equal(t, s.wantRet, got, "evm.Call() output")
+			assert.Equal(t, s.wantRuns, runs, "number of runs of wrapped function")
+			assert.Equal(t, uint64(gasLimit-gasCost), gasLeft, "gas left")
+		})
+	}
+
+	t.Run("insufficient_gas_on_hit", func(t *testing.T) {
+		_, evm := ethtest.NewZeroEVM(t, ethtest.WithBlockContext(vm.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			BlockNumber: big.NewInt(0),
+			Header:      &types.Header{Number: big.NewInt(2)},
+		}))
+		_, _, err := evm.Call(caller, precompile, []byte{'a'}, gasCost-1, uint256.NewInt(0))
+		require.ErrorIs(t, err, vm.ErrOutOfGas, "evm.Call() with insufficient gas")
+	})
+}
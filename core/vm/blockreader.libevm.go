@@ -0,0 +1,99 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/libevm"
+)
+
+// A BlockReader provides access to canonical blocks, attached to an [EVM] via
+// [BlockContext.BlockReader].
+type BlockReader = libevm.BlockReader[*types.Header, *types.Receipt]
+
+// MaxAncestorDepth is the maximum number of ancestors accessible via
+// [PrecompileEnvironment.Ancestors], mirroring the BLOCKHASH op code.
+const MaxAncestorDepth = 256
+
+var (
+	// ErrNoBlockReader is returned by [PrecompileEnvironment.Ancestors] methods
+	// if the [BlockContext] has no BlockReader.
+	ErrNoBlockReader = errors.New("no block reader in block context")
+	// ErrNotAncestor is returned by [PrecompileEnvironment.Ancestors] methods
+	// if the requested block is not one of the last [MaxAncestorDepth]
+	// ancestors of the current block.
+	ErrNotAncestor = errors.New("block is not an accessible ancestor")
+)
+
+// ancestorReader bounds a [BlockReader] to the ancestors of the current block
+// such that results are deterministic regardless of the chain's head.
+type ancestorReader struct {
+	ctx *BlockContext
+}
+
+var _ BlockReader = ancestorReader{}
+
+func (e *environment) Ancestors() BlockReader {
+	return ancestorReader{&e.evm.Context}
+}
+
+// BlockHash mirrors the BLOCKHASH op code, including its window of accessible
+// blocks, but distinguishes between an unavailable hash and a zero one.
+func (e *environment) BlockHash(num uint64) (common.Hash, bool) {
+	ctx := &e.evm.Context
+	if ctx.GetHash == nil || ctx.BlockNumber == nil || !ctx.BlockNumber.IsUint64() {
+		return common.Hash{}, false
+	}
+	curr := ctx.BlockNumber.Uint64()
+	if num >= curr || curr-num > MaxAncestorDepth {
+		return common.Hash{}, false
+	}
+	return ctx.GetHash(num), true
+}
+
+func (r ancestorReader) check(num uint64) error {
+	if r.ctx.BlockReader == nil {
+		return ErrNoBlockReader
+	}
+	curr := r.ctx.BlockNumber
+	if curr == nil || !curr.IsUint64() || num >= curr.Uint64() || curr.Uint64()-num > MaxAncestorDepth {
+		return fmt.Errorf("%w: %d from block %v", ErrNotAncestor, num, curr)
+	}
+	return nil
+}
+
+func (r ancestorReader) HeaderByNumber(num uint64) (*types.Header, error) {
+	if err := r.check(num); err != nil {
+		return nil, err
+	}
+	hdr, err := r.ctx.BlockReader.HeaderByNumber(num)
+	if err != nil {
+		return nil, err
+	}
+	return types.CopyHeader(hdr), nil
+}
+
+func (r ancestorReader) ReceiptsByNumber(num uint64) ([]*types.Receipt, error) {
+	if err := r.check(num); err != nil {
+		return nil, err
+	}
+	return r.ctx.BlockReader.ReceiptsByNumber(num)
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+// chainStub is a [vm.BlockReader] that fabricates every requested block.
+type chainStub struct{}
+
+func (chainStub) HeaderByNumber(n uint64) (*types.Header, error) {
+	return &types.Header{Number: new(big.Int).SetUint64(n)}, nil
+}
+
+func (chainStub) ReceiptsByNumber(n uint64) ([]*types.Receipt, error) {
+	return []*types.Receipt{{BlockNumber: new(big.Int).SetUint64(n)}}, nil
+}
+
+func TestPrecompileAncestors(t *testing.T) {
+	rng := ethtest.NewPseudoRand(753)
+	precompile := rng.Address()
+
+	var (
+		request uint64
+		gotHdr  *types.Header
+		gotRcpt []*types.Receipt
+		errs    [2]error
+	)
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				gotHdr, errs[0] = env.Ancestors().HeaderByNumber(request)
+				gotRcpt, errs[1] = env.Ancestors().ReceiptsByNumber(request)
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	const current = 1000
+	tests := []struct {
+		reader  vm.BlockReader
+		request uint64
+		wantErr error
+	}{
+		{
+			reader:  nil,
+			request: current - 1,
+			wantErr: vm.ErrNoBlockReader,
+		},
+		{
+			reader:  chainStub{},
+			request: current - 1,
+		},
+		{
+			reader:  chainStub{},
+			request: current - vm.MaxAncestorDepth,
+		},
+		{
+			reader:  chainStub{},
+			request: current - vm.MaxAncestorDepth - 1,
+			wantErr: vm.ErrNotAncestor,
+		},
+		{
+			reader:  chainStub{},
+			request: current,
+			wantErr: vm.ErrNotAncestor,
+		},
+		{
+			reader:  chainStub{},
+			request: current + 1,
+			wantErr: vm.ErrNotAncestor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("reader_%T_block_%d", tt.reader, tt.request), func(t *testing.T) {
+			_, evm := ethtest.NewZeroEVM(t, ethtest.WithBlockContext(vm.BlockContext{
+				CanTransfer: core.CanTransfer,
+				Transfer:    core.Transfer,
+				BlockNumber: big.NewInt(current),
+				BlockReader: tt.reader,
+			}))
+
+			request = tt.request
+			_, _, err := evm.Call(vm.AccountRef(rng.Address()), precompile, nil, 1e6, uint256.NewInt(0))
+			require.NoError(t, err, "evm.Call()")
+
+			for i, err := range errs {
+				require.ErrorIsf(t, err, tt.wantErr, "error %d", i)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			assert.Equal(t, tt.request, gotHdr.Number.Uint64(), "header number")
+			require.Len(t, gotRcpt, 1, "receipts")
+			assert.Equal(t, tt.request, gotRcpt[0].BlockNumber.Uint64(), "receipt block number")
+		})
+	}
+}
+
+func TestPrecompileBlockHash(t *testing.T) {
+	rng := ethtest.NewPseudoRand(761)
+	var (
+		precompile = rng.Address()
+		contract   = rng.Address()
+		caller     = vm.AccountRef(rng.Address())
+	)
+
+	var (
+		request uint64
+		gotOK   bool
+	)
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				h, ok := env.BlockHash(request)
+				gotOK = ok
+				return h.Bytes(), nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	// Offset by one so that no hash is zero, which BLOCKHASH only returns for
+	// blocks out of range.
+	getHash := func(n uint64) common.Hash {
+		return common.BigToHash(new(big.Int).SetUint64(n + 1))
+	}
+
+	for _, current := range []uint64{100, vm.MaxAncestorDepth, vm.MaxAncestorDepth + 1, 1000} {
+		sdb, evm := ethtest.NewZeroEVM(t, ethtest.WithBlockContext(vm.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			BlockNumber: new(big.Int).SetUint64(current),
+			GetHash:     getHash,
+		}))
+
+		requests := []uint64{0, current - 1, current, current + 1}
+		if current > vm.MaxAncestorDepth {
+			requests = append(requests, current-vm.MaxAncestorDepth, current-vm.MaxAncestorDepth-1)
+		}
+		for _, req := range requests {
+			t.Run(fmt.Sprintf("block_%d_from_%d", req, current), func(t *testing.T) {
+				// PUSH8 <req>; BLOCKHASH; PUSH1 0; MSTORE; PUSH1 32; PUSH1 0; RETURN
+				code := []byte{byte(vm.PUSH8)}
+				code = append(code, new(uint256.Int).SetUint64(req).PaddedBytes(8)...)
+				code = append(code,
+					byte(vm.BLOCKHASH), byte(vm.PUSH1), 0, byte(vm.MSTORE),
+					byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN),
+				)
+				sdb.SetCode(contract, code)
+
+				want, _, err := evm.Call(caller, contract, nil, 1e6, uint256.NewInt(0))
+				require.NoError(t, err, "evm.Call([BLOCKHASH contract])")
+
+				request = req
+				got, _, err := evm.Call(caller, precompile, nil, 1e6, uint256.NewInt(0))
+				require.NoError(t, err, "evm.Call([precompile])")
+
+				assert.Equal(t, want, got, "env.BlockHash() vs BLOCKHASH op code")
+				assert.Equal(t, common.BytesToHash(want) != (common.Hash{}), gotOK, "env.BlockHash() ok iff BLOCKHASH non-zero")
+			})
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"slices"
+
+	"github.com/ava-labs/libevm/common"
+)
+
+// A CallFrame is a single entry in the stack returned by
+// [PrecompileEnvironment.CallStack]. Its addresses are raw, as defined by
+// [libevm.AddressContext]; i.e. Self is the address that was called, even for
+// DELEGATECALL and CALLCODE, and Caller is the account that called it.
+type CallFrame struct {
+	// Type is one of CALL, CALLCODE, DELEGATECALL, STATICCALL, CREATE, or
+	// CREATE2.
+	Type   OpCode
+	Caller common.Address
+	Self   common.Address
+}
+
+// pushCallFrame records entry into a call or contract creation, and MUST be
+// paired with a deferred call to popCallFrame().
+func (evm *EVM) pushCallFrame(typ OpCode, caller ContractRef, self common.Address) {
+	evm.callStack = append(evm.callStack, CallFrame{
+		Type:   typ,
+		Caller: caller.Address(),
+		Self:   self,
+	})
+}
+
+func (evm *EVM) popCallFrame() {
+	evm.callStack = evm.callStack[:len(evm.callStack)-1]
+}
+
+// CallStack returns a copy because the backing array is reused by the EVM. A
+// standalone environment has no EVM-recorded frames so its only frame is
+// synthesised from its own addresses.
+func (e *environment) CallStack() []CallFrame {
+	if len(e.evm.callStack) == 0 {
+		return []CallFrame{{
+			Type:   OpCode(e.callType),
+			Caller: e.rawCaller,
+			Self:   e.rawSelf,
+		}}
+	}
+	return slices.Clone(e.evm.callStack)
+}
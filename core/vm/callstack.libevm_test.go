@@ -0,0 +1,117 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestPrecompileCallStack(t *testing.T) {
+	rng := ethtest.NewPseudoRand(7632)
+	var (
+		precompile = rng.Address()
+		proxy      = rng.Address()
+		eoa        = rng.Address()
+	)
+
+	var got []vm.CallFrame
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				got = env.CallStack()
+				return []byte{1}, nil
+			}),
+		},
+	}
+	hookstest.Register(t, params.Extras[*hookstest.Stub, *hookstest.Stub]{
+		NewRules: func(_ *params.ChainConfig, r *params.Rules, _ *hookstest.Stub, _ *big.Int, _ bool, _ uint64) *hookstest.Stub {
+			r.IsCancun = true // enable PUSH0
+			return hooks
+		},
+	})
+
+	t.Run("direct", func(t *testing.T) {
+		_, evm := ethtest.NewZeroEVM(t)
+		_, _, err := evm.Call(vm.AccountRef(eoa), precompile, nil, 1e6, uint256.NewInt(0))
+		require.NoError(t, err, "evm.Call([precompile])")
+
+		want := []vm.CallFrame{
+			{Type: vm.CALL, Caller: eoa, Self: precompile},
+		}
+		assert.Equal(t, want, got, "CallStack()")
+	})
+
+	for _, op := range []vm.OpCode{vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL} {
+		t.Run("via_"+op.String(), func(t *testing.T) {
+			sdb, evm := ethtest.NewZeroEVM(t)
+			sdb.SetCode(proxy, convertBytes[vm.OpCode, byte](makeReturnProxy(t, precompile, op)...))
+
+			// The same EVM is used twice to demonstrate that frames are
+			// popped upon return.
+			for i := 0; i < 2; i++ {
+				_, _, err := evm.Call(vm.AccountRef(eoa), proxy, []byte{0}, 1e6, uint256.NewInt(0))
+				require.NoError(t, err, "evm.Call([proxy])")
+
+				want := []vm.CallFrame{
+					{Type: vm.CALL, Caller: eoa, Self: proxy},
+					{Type: op, Caller: proxy, Self: precompile},
+				}
+				assert.Equalf(t, want, got, "CallStack() on call %d", i)
+			}
+		})
+	}
+
+	t.Run("via_constructor", func(t *testing.T) {
+		_, evm := ethtest.NewZeroEVM(t)
+		initCode := convertBytes[vm.OpCode, byte](makeReturnProxy(t, precompile, vm.CALL)...)
+		_, created, _, err := evm.Create(vm.AccountRef(eoa), initCode, 1e6, uint256.NewInt(0))
+		require.NoError(t, err, "evm.Create()")
+
+		want := []vm.CallFrame{
+			{Type: vm.CREATE, Caller: eoa, Self: created},
+			{Type: vm.CALL, Caller: created, Self: precompile},
+		}
+		assert.Equal(t, want, got, "CallStack()")
+	})
+
+	t.Run("standalone", func(t *testing.T) {
+		sdb, _ := ethtest.NewZeroEVM(t)
+		call := vm.StandaloneCall{
+			Origin:     eoa,
+			Caller:     eoa,
+			Precompile: precompile,
+		}
+		env := vm.NewStandaloneEnvironment(sdb, &params.ChainConfig{ChainID: big.NewInt(1)}, vm.BlockContext{BlockNumber: big.NewInt(0)}, call)
+
+		want := []vm.CallFrame{
+			{Type: vm.STATICCALL, Caller: eoa, Self: precompile},
+		}
+		assert.Equal(t, want, env.CallStack(), "CallStack() of standalone environment")
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrCancelled is returned by calls made after the context passed to
+// [EVM.CancelOnDone] is done. It is checked at call boundaries, including calls
+// to precompiles, which SHOULD respect [PrecompileEnvironment.Context] if they
+// are long running. Calling [EVM.Cancel] directly retains geth semantics,
+// stopping execution without an error.
+var ErrCancelled = errors.New("EVM execution cancelled")
+
+// CancelOnDone results in [EVM.Cancel] being called when `ctx` is done, and
+// makes `ctx` available to precompiles via [PrecompileEnvironment.Context]. It
+// is intended for simulations and RPC calls, and the returned function SHOULD
+// be called to release resources once execution is complete; it reports
+// whether it prevented cancellation.
+//
+// CancelOnDone MUST NOT be used on consensus paths, where execution has to be
+// deterministic; see [EVM.DisableCancellation].
+func (evm *EVM) CancelOnDone(ctx context.Context) (stop func() bool) {
+	evm.ctx = ctx
+	return context.AfterFunc(ctx, evm.cancelWithError)
+}
+
+// cancelWithError is equivalent to [EVM.Cancel] but also results in calls
+// returning [ErrCancelled].
+func (evm *EVM) cancelWithError() {
+	if evm.cancellationDisabled.Load() {
+		return
+	}
+	evm.ctxDone.Store(true)
+	evm.Cancel()
+}
+
+// cancelledWithError reports whether calls MUST return [ErrCancelled].
+func (evm *EVM) cancelledWithError() bool {
+	return evm.ctxDone.Load()
+}
+
+// DisableCancellation results in all future calls to [EVM.Cancel] being
+// ignored, and [PrecompileEnvironment.Context] always returning a background
+// context. It is called by [core.StateProcessor] and [core.ApplyTransaction] to
+// guarantee determinism when processing blocks, and MUST be called before the
+// EVM is shared with any goroutine that might cancel it.
+func (evm *EVM) DisableCancellation() {
+	evm.cancellationDisabled.Store(true)
+}
+
+func (e *environment) Context() context.Context {
+	if ctx := e.evm.ctx; ctx != nil && !e.evm.cancellationDisabled.Load() {
+		return ctx
+	}
+	return context.Background()
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestCancelOnDone(t *testing.T) {
+	rng := ethtest.NewPseudoRand(7572)
+	var (
+		precompile = rng.Address()
+		contract   = rng.Address()
+		caller     = vm.AccountRef(rng.Address())
+	)
+
+	var gotCtx context.Context
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				gotCtx = env.Context()
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	newEVM := func(t *testing.T) *vm.EVM {
+		t.Helper()
+		sdb, evm := ethtest.NewZeroEVM(t)
+		sdb.SetCode(contract, []byte{byte(vm.STOP)})
+		return evm
+	}
+	call := func(evm *vm.EVM, addr common.Address) error {
+		_, _, err := evm.Call(caller, addr, nil, 1e6, uint256.NewInt(0))
+		return err
+	}
+
+	t.Run("cancelled", func(t *testing.T) {
+		evm := newEVM(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stop := evm.CancelOnDone(ctx)
+		defer stop()
+
+		require.NoError(t, call(evm, precompile), "call to precompile before cancellation")
+		assert.Equal(t, ctx, gotCtx, "PrecompileEnvironment.Context()")
+
+		cancel()
+		require.Eventually(t, evm.Cancelled, time.Second, time.Millisecond, "EVM.Cancelled() after context cancelled")
+		for _, addr := range []common.Address{precompile, contract} {
+			require.ErrorIsf(t, call(evm, addr), vm.ErrCancelled, "call to %v after cancellation", addr)
+		}
+	})
+
+	t.Run("cancel_without_context", func(t *testing.T) {
+		evm := newEVM(t)
+		evm.Cancel()
+		require.True(t, evm.Cancelled(), "EVM.Cancelled() after EVM.Cancel()")
+		for _, addr := range []common.Address{precompile, contract} {
+			require.NoErrorf(t, call(evm, addr), "call to %v after EVM.Cancel()", addr)
+		}
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		evm := newEVM(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		stop := evm.CancelOnDone(ctx)
+		assert.True(t, stop(), "stop() before cancellation")
+		cancel()
+		assert.False(t, evm.Cancelled(), "EVM.Cancelled() after context cancelled but stopped")
+	})
+
+	t.Run("cancellation_disabled", func(t *testing.T) {
+		evm := newEVM(t)
+		evm.DisableCancellation()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stop := evm.CancelOnDone(ctx)
+		defer stop()
+
+		evm.Cancel()
+		cancel()
+		assert.False(t, evm.Cancelled(), "EVM.Cancelled() after EVM.Cancel()")
+		require.NoError(t, call(evm, precompile), "call to precompile")
+		assert.Equal(t, context.Background(), gotCtx, "PrecompileEnvironment.Context()")
+	})
+}
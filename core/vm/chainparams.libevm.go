@@ -0,0 +1,162 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm"
+)
+
+// ChainParameters are per-block parameters (e.g. price multipliers) stored by
+// a designated system precompile, which sets them with
+// [ChainParameters.Set]. A value set in one block takes effect from the next
+// one, so all reads within a block, including those by opcodes and hooks via
+// [ChainParameters.Get], observe the same value regardless of the order of
+// transactions.
+//
+// As effective values depend only on the parent block, they are cached for
+// the duration of a block, identified by its number and parent hash. Reads
+// without an available block header, i.e. [BlockContext.Header] or
+// [PrecompileEnvironment.BlockHeader], bypass the cache. Cache hits don't read
+// from the [StateDB] and are therefore not recorded as state accesses.
+//
+// For a parameter key k, the precompile's storage holds the value effective
+// before the most recent change at slot(k, 0), the most recently set value at
+// slot(k, 1), and the block number from which the latter is effective at
+// slot(k, 2), where slot(k, i) is keccak256(k ++ i) with i as a big-endian
+// uint64.
+type ChainParameters struct {
+	precompile common.Address
+
+	mu     sync.Mutex
+	epoch  blockEpoch
+	values map[common.Hash]common.Hash
+}
+
+// NewChainParameters returns parameters stored by the precompile at the
+// address.
+func NewChainParameters(precompile common.Address) *ChainParameters {
+	return &ChainParameters{precompile: precompile}
+}
+
+// Precompile returns the address of the precompile storing the parameters.
+func (p *ChainParameters) Precompile() common.Address {
+	return p.precompile
+}
+
+// Get returns the value of the parameter in effect in the EVM's current block.
+func (p *ChainParameters) Get(evm *EVM, key common.Hash) common.Hash {
+	return p.get(evm.StateDB, evm.Context.BlockNumber, evm.Context.Header, key)
+}
+
+// GetFromPrecompile is equivalent to [ChainParameters.Get], for use by any
+// precompile.
+func (p *ChainParameters) GetFromPrecompile(env PrecompileEnvironment, key common.Hash) common.Hash {
+	var hdr *types.Header
+	if h, err := env.BlockHeader(); err == nil {
+		hdr = &h
+	}
+	return p.get(env.ReadOnlyState(), env.BlockNumber(), hdr, key)
+}
+
+// Set sets the value of the parameter, effective from the block after the
+// current one. It MUST be called by the precompile storing the parameters, but
+// doesn't consume gas, which is the responsibility of said precompile.
+func (p *ChainParameters) Set(env PrecompileEnvironment, key, value common.Hash) error {
+	if self := env.Addresses().EVMSemantic.Self; self != p.precompile {
+		return fmt.Errorf("chain parameters of %v set by %v", p.precompile, self)
+	}
+	if env.ReadOnly() {
+		return ErrWriteProtection
+	}
+	num := env.BlockNumber()
+	if !num.IsUint64() {
+		return fmt.Errorf("block number %v out of range", num)
+	}
+	n := num.Uint64()
+
+	sdb := env.StateDB()
+	if from, pending := p.pending(sdb, key); from != 0 && from <= n {
+		sdb.SetState(p.precompile, chainParamSlot(key, chainParamPrevious), pending)
+	}
+	sdb.SetState(p.precompile, chainParamSlot(key, chainParamLatest), value)
+	sdb.SetState(p.precompile, chainParamSlot(key, chainParamEffectiveFrom), common.BigToHash(new(big.Int).SetUint64(n+1)))
+	return nil
+}
+
+func (p *ChainParameters) get(sdb libevm.StateReader, num *big.Int, hdr *types.Header, key common.Hash) common.Hash {
+	if num == nil || !num.IsUint64() {
+		return p.read(sdb, 0, key)
+	}
+	n := num.Uint64()
+	if hdr == nil {
+		return p.read(sdb, n, key)
+	}
+
+	epoch := blockEpoch{n, hdr.ParentHash}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if epoch != p.epoch || p.values == nil {
+		p.epoch = epoch
+		p.values = make(map[common.Hash]common.Hash)
+	}
+	if v, ok := p.values[key]; ok {
+		return v
+	}
+	v := p.read(sdb, n, key)
+	p.values[key] = v
+	return v
+}
+
+// read returns the value of the parameter in effect in block `n`.
+func (p *ChainParameters) read(sdb libevm.StateReader, n uint64, key common.Hash) common.Hash {
+	if from, pending := p.pending(sdb, key); from != 0 && from <= n {
+		return pending
+	}
+	return sdb.GetState(p.precompile, chainParamSlot(key, chainParamPrevious))
+}
+
+// pending returns the most recently set value of the parameter and the block
+// number from which it is effective, which is zero if it was never set.
+func (p *ChainParameters) pending(sdb libevm.StateReader, key common.Hash) (effectiveFrom uint64, _ common.Hash) {
+	vals := libevm.GetStates(sdb, p.precompile, []common.Hash{
+		chainParamSlot(key, chainParamEffectiveFrom),
+		chainParamSlot(key, chainParamLatest),
+	})
+	from := vals[0].Big()
+	if !from.IsUint64() {
+		return 0, common.Hash{}
+	}
+	return from.Uint64(), vals[1]
+}
+
+const (
+	chainParamPrevious uint64 = iota
+	chainParamLatest
+	chainParamEffectiveFrom
+)
+
+func chainParamSlot(key common.Hash, field uint64) common.Hash {
+	return crypto.Keccak256Hash(key[:], binary.BigEndian.AppendUint64(nil, field))
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestChainParameters(t *testing.T) {
+	var (
+		system = common.Address{'s', 'y', 's'}
+		reader = common.Address{'r', 'e', 'a', 'd'}
+		key    = common.Hash{'k', 'e', 'y'}
+	)
+	chainParams := vm.NewChainParameters(system)
+
+	// Both precompiles attempt to set the parameter to their input, if any,
+	// and return its current value.
+	setAndGet := vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+		if len(input) > 0 {
+			if err := chainParams.Set(env, key, common.BytesToHash(input)); err != nil {
+				return nil, err
+			}
+		}
+		v := chainParams.GetFromPrecompile(env, key)
+		return v[:], nil
+	})
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			system: setAndGet,
+			reader: setAndGet,
+		},
+	}
+	hooks.Register(t)
+
+	_, evm := ethtest.NewZeroEVM(t)
+	atBlock := func(n uint64, withHeader bool) {
+		evm.Context.BlockNumber = new(big.Int).SetUint64(n)
+		evm.Context.Header = nil
+		if withHeader {
+			evm.Context.Header = &types.Header{
+				Number:     new(big.Int).SetUint64(n),
+				ParentHash: common.Hash{byte(n - 1)},
+			}
+		}
+	}
+	call := func(t *testing.T, addr common.Address, set byte) (common.Hash, error) {
+		t.Helper()
+		var input []byte
+		if set != 0 {
+			input = []byte{set}
+		}
+		ret, _, err := evm.Call(vm.AccountRef{}, addr, input, 1e6, uint256.NewInt(0))
+		return common.BytesToHash(ret), err
+	}
+	h := func(b byte) common.Hash {
+		return common.BytesToHash([]byte{b})
+	}
+
+	steps := []struct {
+		name       string
+		block      uint64
+		withHeader bool
+		set        byte
+		want       common.Hash
+	}{
+		{"unset", 1, true, 0, common.Hash{}},
+		{"set_first", 1, true, 1, common.Hash{}},
+		{"same_block_after_set", 1, true, 0, common.Hash{}},
+		{"next_block", 2, true, 0, h(1)},
+		{"set_second", 2, true, 2, h(1)},
+		{"overwrite_in_same_block", 2, true, 3, h(1)},
+		{"next_block_again", 3, true, 0, h(3)},
+		{"without_header", 3, false, 0, h(3)},
+		{"set_without_header", 3, false, 4, h(3)},
+		{"skipped_blocks", 10, true, 0, h(4)},
+	}
+
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			atBlock(s.block, s.withHeader)
+			got, err := call(t, system, s.set)
+			require.NoError(t, err, "%T.Call(<system precompile>)", evm)
+			assert.Equal(t, s.want, got, "value returned by precompile")
+			assert.Equal(t, s.want, chainParams.Get(evm, key), "%T.Get()", chainParams)
+		})
+	}
+
+	t.Run("set_by_other_precompile", func(t *testing.T) {
+		_, err := call(t, reader, 42)
+		require.Error(t, err, "%T.Set() from other precompile", chainParams)
+		assert.Equal(t, h(4), chainParams.Get(evm, key), "%T.Get() after failed Set()", chainParams)
+	})
+
+	t.Run("static_call", func(t *testing.T) {
+		_, _, err := evm.StaticCall(vm.AccountRef{}, system, []byte{42}, 1e6)
+		require.ErrorIs(t, err, vm.ErrWriteProtection, "%T.Set() in static call", chainParams)
+	})
+
+	t.Run("isolated_by_precompile", func(t *testing.T) {
+		other := vm.NewChainParameters(reader)
+		assert.Equal(t, common.Hash{}, other.Get(evm, key), "%T.Get() for different precompile", other)
+	})
+}
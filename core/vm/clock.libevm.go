@@ -0,0 +1,116 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+	"time"
+)
+
+// A Clock is a deterministic time source derived from the current block,
+// available to precompiles via [PrecompileEnvironment.Clock]. Precompiles MUST
+// use it instead of wall-clock time, which differs between nodes.
+//
+// All durations are in seconds, as for the TIMESTAMP op code, and all methods
+// saturate instead of overflowing.
+type Clock struct {
+	seconds          uint64
+	ticks, perSecond uint64
+}
+
+// Clock returns the current block's time, including the sub-second component
+// carried by the header if it is available and its registered payload
+// implements [types.SubSecondTimeCarrier].
+func (e *environment) Clock() Clock {
+	c := Clock{seconds: e.evm.Context.Time}
+	if hdr := e.evm.Context.Header; hdr != nil {
+		c.ticks, c.perSecond, _ = hdr.SubSecondTime()
+	}
+	return c
+}
+
+// Unix returns the block time in seconds since the Unix epoch.
+func (c Clock) Unix() uint64 { return c.seconds }
+
+// SubSecond returns the number of ticks, each 1/perSecond of a second, elapsed
+// since Unix(). Both values are zero if the chain doesn't define sub-second
+// block times.
+func (c Clock) SubSecond() (ticks, perSecond uint64) { return c.ticks, c.perSecond }
+
+// Time returns the block time, including any sub-second component, in UTC. If
+// Unix() is beyond the range of [time.Time] then the maximum is returned.
+func (c Clock) Time() time.Time {
+	var nanos int64
+	if c.perSecond > 0 {
+		n := new(big.Int).SetUint64(c.ticks)
+		n.Mul(n, big.NewInt(int64(time.Second)))
+		n.Quo(n, new(big.Int).SetUint64(c.perSecond))
+		nanos = n.Int64() // < 1e9 because ticks < perSecond
+	}
+	secs := c.seconds
+	if secs > maxUnixSeconds {
+		secs, nanos = maxUnixSeconds, 0
+	}
+	return time.Unix(int64(secs), nanos).UTC()
+}
+
+// maxUnixSeconds is the largest number of seconds that can be converted to a
+// [time.Time] without overflowing.
+const maxUnixSeconds = 1<<63 - 1 - unixToInternal
+
+// unixToInternal mirrors the unexported constant of the same name in the
+// [time] package: the number of seconds between year 1 and 1970.
+const unixToInternal = (1969*365 + 1969/4 - 1969/100 + 1969/400) * 24 * 60 * 60
+
+// Reached reports whether the block time is at or after the deadline.
+func (c Clock) Reached(deadline uint64) bool { return c.seconds >= deadline }
+
+// Since returns the number of seconds elapsed since start, or zero if start is
+// in the future.
+func (c Clock) Since(start uint64) uint64 {
+	if start >= c.seconds {
+		return 0
+	}
+	return c.seconds - start
+}
+
+// Until returns the number of seconds remaining until the deadline, or zero if
+// it has been reached.
+func (c Clock) Until(deadline uint64) uint64 {
+	if c.Reached(deadline) {
+		return 0
+	}
+	return deadline - c.seconds
+}
+
+// After returns the time `d` seconds after the block time, saturating at the
+// maximum uint64.
+func (c Clock) After(d uint64) uint64 {
+	t := c.seconds + d
+	if t < c.seconds {
+		return ^uint64(0)
+	}
+	return t
+}
+
+// ElapsedWithin returns the number of seconds of the period [start,
+// start+duration] that have elapsed, clamped to [0, duration]. This is
+// typically used for linear vesting, with the vested amount being
+// total*ElapsedWithin(start, duration)/duration.
+func (c Clock) ElapsedWithin(start, duration uint64) uint64 {
+	return min(c.Since(start), duration)
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+type millisHeaderHooks struct {
+	types.NOOPHeaderHooks
+	Millis uint64
+}
+
+func (hh *millisHeaderHooks) SubSecondTime() (uint64, uint64) {
+	return hh.Millis, 1000
+}
+
+func TestPrecompileClock(t *testing.T) {
+	types.TestOnlyClearRegisteredExtras()
+	t.Cleanup(types.TestOnlyClearRegisteredExtras)
+	extras := types.RegisterExtras[
+		millisHeaderHooks, *millisHeaderHooks,
+		types.NOOPBlockBodyHooks, *types.NOOPBlockBodyHooks,
+		struct{},
+	]()
+
+	rng := ethtest.NewPseudoRand(754)
+	precompile := rng.Address()
+	var got vm.Clock
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				got = env.Clock()
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	const now = 1_700_000_000
+	tests := []struct {
+		name          string
+		header        *types.Header
+		millis        uint64
+		wantTicks     uint64
+		wantPerSecond uint64
+		wantTime      time.Time
+	}{
+		{
+			name:     "no_header",
+			wantTime: time.Unix(now, 0).UTC(),
+		},
+		{
+			name:          "sub_second_ticks",
+			header:        &types.Header{Time: now},
+			millis:        250,
+			wantTicks:     250,
+			wantPerSecond: 1000,
+			wantTime:      time.Unix(now, 250*int64(time.Millisecond)).UTC(),
+		},
+		{
+			name:     "invalid_ticks_ignored",
+			header:   &types.Header{Time: now},
+			millis:   1000,
+			wantTime: time.Unix(now, 0).UTC(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.header != nil {
+				extras.Header.Get(tt.header).Millis = tt.millis
+			}
+			_, evm := ethtest.NewZeroEVM(t, ethtest.WithBlockContext(vm.BlockContext{
+				CanTransfer: core.CanTransfer,
+				Transfer:    core.Transfer,
+				BlockNumber: big.NewInt(0),
+				Time:        now,
+				Header:      tt.header,
+			}))
+			_, _, err := evm.Call(vm.AccountRef(rng.Address()), precompile, nil, 1e6, uint256.NewInt(0))
+			require.NoError(t, err, "evm.Call()")
+
+			assert.Equal(t, uint64(now), got.Unix(), "Unix()")
+			ticks, perSecond := got.SubSecond()
+			assert.Equal(t, tt.wantTicks, ticks, "SubSecond() ticks")
+			assert.Equal(t, tt.wantPerSecond, perSecond, "SubSecond() per second")
+			assert.Equal(t, tt.wantTime, got.Time(), "Time()")
+		})
+	}
+
+	t.Run("durations", func(t *testing.T) {
+		c := got
+		assert.True(t, c.Reached(now), "Reached(now)")
+		assert.False(t, c.Reached(now+1), "Reached(now+1)")
+		assert.Equal(t, uint64(10), c.Since(now-10), "Since(past)")
+		assert.Zero(t, c.Since(now+10), "Since(future)")
+		assert.Equal(t, uint64(10), c.Until(now+10), "Until(future)")
+		assert.Zero(t, c.Until(now-10), "Until(past)")
+		assert.Equal(t, uint64(now+10), c.After(10), "After(10)")
+		assert.Equal(t, uint64(math.MaxUint64), c.After(math.MaxUint64), "After(overflow)")
+		assert.Equal(t, uint64(10), c.ElapsedWithin(now-10, 100), "ElapsedWithin(during)")
+		assert.Equal(t, uint64(100), c.ElapsedWithin(now-1000, 100), "ElapsedWithin(after)")
+		assert.Zero(t, c.ElapsedWithin(now+1, 100), "ElapsedWithin(before)")
+	})
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/params"
+)
+
+// CodeMetadataHooks MAY be implemented by a [params.RulesHooks] to maintain
+// metadata about deployed code; e.g. a compiler-version tag or a flag marking
+// the code hash as audited.
+//
+// Metadata is typically stored as part of the account's extra payload (see
+// [types.StateAccountExtra] and the state.SetExtra() function) so that it is
+// journalled, committed and reverted alongside the code itself.
+type CodeMetadataHooks interface {
+	// OnCodeDeployed is called immediately after the code of a newly created
+	// contract is stored. State changes made by the hook are reverted if the
+	// creation, or any surrounding call, reverts.
+	OnCodeDeployed(_ StateDB, _ *libevm.AddressContext, code []byte)
+	// CodeMetadata returns the metadata of the code at the address, or nil if
+	// there is none.
+	CodeMetadata(_ StateDB, _ common.Address) any
+}
+
+// CodeMetadata returns the metadata of the code at `addr`, as reported by the
+// [CodeMetadataHooks] implemented by the rules' hooks. It returns nil if the
+// hooks don't implement said interface.
+func CodeMetadata(rules params.Rules, sdb StateDB, addr common.Address) any {
+	h, ok := rules.Hooks().(CodeMetadataHooks)
+	if !ok {
+		return nil
+	}
+	return h.CodeMetadata(sdb, addr)
+}
+
+func (evm *EVM) onCodeDeployed(caller ContractRef, addr common.Address, code []byte) {
+	h, ok := evm.chainRules.Hooks().(CodeMetadataHooks)
+	if !ok {
+		return
+	}
+	self := libevm.CallerAndSelf{
+		Caller: caller.Address(),
+		Self:   addr,
+	}
+	h.OnCodeDeployed(evm.StateDB, &libevm.AddressContext{
+		Origin:      evm.Origin,
+		EVMSemantic: self,
+		Raw:         &self,
+	}, code)
+}
+
+func (e *environment) CodeMetadata(addr common.Address) any {
+	return CodeMetadata(e.evm.chainRules, e.evm.StateDB, addr)
+}
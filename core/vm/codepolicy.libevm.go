@@ -0,0 +1,161 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+)
+
+// A PrecompileCodePolicy determines the precedence of a precompile, installed
+// via [params.RulesHooks.PrecompileOverride], at an address that also holds
+// account code; e.g. a contract deployed before the precompile was activated.
+// See [WithCodePolicy].
+type PrecompileCodePolicy uint8
+
+const (
+	// DefaultCodePolicy runs the precompile while EXTCODESIZE, EXTCODEHASH,
+	// and EXTCODECOPY report the account code. It is the behaviour of
+	// precompiles without an explicit policy.
+	DefaultCodePolicy PrecompileCodePolicy = iota
+	// PrecompileShadowsCode runs the precompile and hides the account code
+	// from EXTCODESIZE, EXTCODEHASH, and EXTCODECOPY, which behave as if the
+	// account had no code.
+	PrecompileShadowsCode
+	// CodeShadowsPrecompile runs the account code instead of the precompile,
+	// which is inactive at the address while it holds code.
+	CodeShadowsPrecompile
+	// RejectCallsWithCode fails all calls to the address with
+	// [ErrPrecompileHasCode], consuming all gas, while it holds code.
+	RejectCallsWithCode
+)
+
+// String returns a human-readable name for the policy.
+func (p PrecompileCodePolicy) String() string {
+	switch p {
+	case DefaultCodePolicy:
+		return "default"
+	case PrecompileShadowsCode:
+		return "precompile_shadows_code"
+	case CodeShadowsPrecompile:
+		return "code_shadows_precompile"
+	case RejectCallsWithCode:
+		return "reject_calls_with_code"
+	default:
+		return fmt.Sprintf("PrecompileCodePolicy(%d)", uint8(p))
+	}
+}
+
+// ErrPrecompileHasCode is returned by calls to a precompile with the
+// [RejectCallsWithCode] policy if its address holds account code.
+var ErrPrecompileHasCode = errors.New("precompile address holds account code")
+
+// WithCodePolicy returns a [PrecompiledContract] that otherwise behaves
+// identically to `p`, which MAY be a stateful precompile, but with the policy
+// applied if it is installed at an address that holds account code.
+func WithCodePolicy(p PrecompiledContract, policy PrecompileCodePolicy) PrecompiledContract {
+	return &codePolicyPrecompile{p, policy}
+}
+
+type codePolicyPrecompile struct {
+	PrecompiledContract
+	policy PrecompileCodePolicy
+}
+
+func (p *codePolicyPrecompile) codePolicy() PrecompileCodePolicy {
+	return p.policy
+}
+
+func (p *codePolicyPrecompile) unwrap() PrecompiledContract {
+	return p.PrecompiledContract
+}
+
+func codePolicyOf(p PrecompiledContract) PrecompileCodePolicy {
+	d, ok := precompileAs[interface{ codePolicy() PrecompileCodePolicy }](p)
+	if !ok {
+		return DefaultCodePolicy
+	}
+	return d.codePolicy()
+}
+
+// applyPrecompileCodePolicy returns the precompile, if any, to be run at the
+// address in place of the overriding precompile `p`. The policy can't be
+// applied without a [StateDB] so `p` is returned unchanged if there is none.
+func (evm *EVM) applyPrecompileCodePolicy(addr common.Address, p PrecompiledContract) (PrecompiledContract, bool) {
+	policy := codePolicyOf(p)
+	if policy != CodeShadowsPrecompile && policy != RejectCallsWithCode {
+		return p, true
+	}
+	if evm.StateDB == nil || evm.StateDB.GetCodeSize(addr) == 0 {
+		return p, true
+	}
+	if policy == CodeShadowsPrecompile {
+		return nil, false
+	}
+	return rejectedPrecompile{}, true
+}
+
+// A rejectedPrecompile is run in place of a precompile with the
+// [RejectCallsWithCode] policy.
+type rejectedPrecompile struct{}
+
+func (rejectedPrecompile) RequiredGas([]byte) uint64 { return 0 }
+
+func (rejectedPrecompile) Run([]byte) ([]byte, error) {
+	return nil, ErrPrecompileHasCode
+}
+
+// precompileHidesCode reports whether the address holds a precompile with the
+// [PrecompileShadowsCode] policy.
+func (evm *EVM) precompileHidesCode(addr common.Address) bool {
+	p, override := evm.chainRules.Hooks().PrecompileOverride(addr)
+	return override && p != nil && codePolicyOf(p) == PrecompileShadowsCode
+}
+
+// extCodeSize, extCode, and extCodeHash return the values to be used by the
+// respective EXTCODE* opcodes, honouring any [PrecompileCodePolicy]. As
+// opExtCodeHash handles empty accounts, extCodeHash MUST only be called for
+// non-empty ones.
+
+func (evm *EVM) extCodeSize(addr common.Address) int {
+	if evm.precompileHidesCode(addr) {
+		return 0
+	}
+	return evm.StateDB.GetCodeSize(addr)
+}
+
+func (evm *EVM) extCode(addr common.Address) []byte {
+	if evm.precompileHidesCode(addr) {
+		return nil
+	}
+	return evm.StateDB.GetCode(addr)
+}
+
+func (evm *EVM) extCodeHash(addr common.Address) common.Hash {
+	if !evm.precompileHidesCode(addr) {
+		return evm.StateDB.GetCodeHash(addr)
+	}
+	// An account without code is empty if it also has a zero nonce and
+	// balance, in which case EXTCODEHASH returns zero.
+	if evm.StateDB.GetNonce(addr) == 0 && evm.StateDB.GetBalance(addr).IsZero() {
+		return common.Hash{}
+	}
+	return types.EmptyCodeHash
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestPrecompileCodePolicy(t *testing.T) {
+	var (
+		precompile = common.Address{'p'}
+		observer   = common.Address{'o'}
+		caller     = vm.AccountRef(common.Address{'c'})
+	)
+	// Returns the single byte 0xc0.
+	code := []byte{
+		byte(vm.PUSH1), 0xc0, byte(vm.PUSH0), byte(vm.MSTORE8),
+		byte(vm.PUSH1), 1, byte(vm.PUSH0), byte(vm.RETURN),
+	}
+	const codeCopyLen = 4
+	// Returns EXTCODESIZE, EXTCODEHASH, and the first [codeCopyLen] bytes of
+	// EXTCODECOPY of the precompile address.
+	observerCode := append([]byte{byte(vm.PUSH20)}, precompile[:]...)
+	observerCode = append(observerCode, byte(vm.EXTCODESIZE), byte(vm.PUSH0), byte(vm.MSTORE), byte(vm.PUSH20))
+	observerCode = append(observerCode, precompile[:]...)
+	observerCode = append(observerCode, byte(vm.EXTCODEHASH), byte(vm.PUSH1), 32, byte(vm.MSTORE))
+	observerCode = append(observerCode, byte(vm.PUSH1), codeCopyLen, byte(vm.PUSH0), byte(vm.PUSH1), 64, byte(vm.PUSH20))
+	observerCode = append(observerCode, precompile[:]...)
+	observerCode = append(observerCode,
+		byte(vm.EXTCODECOPY),
+		byte(vm.PUSH1), 64+codeCopyLen, byte(vm.PUSH0), byte(vm.RETURN),
+	)
+
+	type extCode struct {
+		size     uint64
+		hash     common.Hash
+		copyHead []byte
+	}
+	var (
+		visible = extCode{
+			size:     uint64(len(code)),
+			hash:     crypto.Keccak256Hash(code),
+			copyHead: code[:codeCopyLen],
+		}
+		hidden = extCode{
+			hash:     types.EmptyCodeHash,
+			copyHead: make([]byte, codeCopyLen),
+		}
+	)
+
+	tests := []struct {
+		policy  vm.PrecompileCodePolicy
+		hasCode bool
+		wantRet []byte
+		wantErr error
+		wantExt extCode
+	}{
+		{
+			policy:  vm.DefaultCodePolicy,
+			hasCode: true,
+			wantRet: []byte("precompile"),
+			wantExt: visible,
+		},
+		{
+			policy:  vm.PrecompileShadowsCode,
+			hasCode: true,
+			wantRet: []byte("precompile"),
+			wantExt: hidden,
+		},
+		{
+			policy:  vm.CodeShadowsPrecompile,
+			hasCode: true,
+			wantRet: []byte{0xc0},
+			wantExt: visible,
+		},
+		{
+			policy:  vm.CodeShadowsPrecompile,
+			hasCode: false,
+			wantRet: []byte("precompile"),
+			wantExt: hidden,
+		},
+		{
+			policy:  vm.RejectCallsWithCode,
+			hasCode: true,
+			wantErr: vm.ErrPrecompileHasCode,
+			wantExt: visible,
+		},
+		{
+			policy:  vm.RejectCallsWithCode,
+			hasCode: false,
+			wantRet: []byte("precompile"),
+			wantExt: hidden,
+		},
+	}
+
+	for _, tt := range tests {
+		name := tt.policy.String()
+		if !tt.hasCode {
+			name += "_without_code"
+		}
+		t.Run(name, func(t *testing.T) {
+			p := vm.WithCodePolicy(
+				vm.NewStatefulPrecompile(func(vm.PrecompileEnvironment, []byte) ([]byte, error) {
+					return []byte("precompile"), nil
+				}),
+				tt.policy,
+			)
+			hooks := &hookstest.Stub{
+				PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+					precompile: p,
+				},
+			}
+			hookstest.Register(t, params.Extras[*hookstest.Stub, *hookstest.Stub]{
+				NewRules: func(_ *params.ChainConfig, r *params.Rules, _ *hookstest.Stub, _ *big.Int, _ bool, _ uint64) *hookstest.Stub {
+					r.IsCancun = true // enable PUSH0
+					return hooks
+				},
+			})
+
+			sdb, evm := ethtest.NewZeroEVM(t)
+			sdb.SetNonce(precompile, 1)
+			if tt.hasCode {
+				sdb.SetCode(precompile, code)
+			}
+			sdb.SetCode(observer, observerCode)
+
+			got, _, err := evm.Call(caller, precompile, nil, 1e6, uint256.NewInt(0))
+			require.ErrorIs(t, err, tt.wantErr, "Call(precompile)")
+			assert.Equal(t, tt.wantRet, got, "Call(precompile) return data")
+			assert.Equal(t, tt.policy != vm.CodeShadowsPrecompile || !tt.hasCode, evm.IsPrecompile(precompile), "IsPrecompile()")
+
+			ret, _, err := evm.Call(caller, observer, nil, 1e6, uint256.NewInt(0))
+			require.NoError(t, err, "Call(observer)")
+			require.Len(t, ret, 64+codeCopyLen, "observer return data")
+			gotExt := extCode{
+				size:     new(uint256.Int).SetBytes(ret[:32]).Uint64(),
+				hash:     common.BytesToHash(ret[32:64]),
+				copyHead: ret[64:],
+			}
+			assert.Equal(t, tt.wantExt, gotExt, "EXTCODE{SIZE,HASH,COPY}")
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"slices"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/libevm/set"
+)
+
+func (e *environment) DeclareConflict(key common.Hash) {
+	if e.evm.declaredConflicts == nil {
+		e.evm.declaredConflicts = make(map[common.Hash]struct{})
+	}
+	e.evm.declaredConflicts[key] = struct{}{}
+}
+
+// DeclaredConflicts returns the keys passed to
+// [PrecompileEnvironment.DeclareConflict] since the EVM was created or last
+// [EVM.Reset], in ascending order. Transactions that declare a common key
+// MUST NOT be executed concurrently by a parallel executor, which SHOULD fall
+// back to serial execution; see the core/state.ReadWriteSet for detection of
+// conflicts via state accesses.
+func (evm *EVM) DeclaredConflicts() []common.Hash {
+	keys := set.Set[common.Hash](evm.declaredConflicts).Slice()
+	slices.SortFunc(keys, func(a, b common.Hash) int {
+		return a.Cmp(b)
+	})
+	return keys
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestDeclaredConflicts(t *testing.T) {
+	precompile := common.Address{'p'}
+	errRevert := errors.New("revert")
+
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				env.DeclareConflict(common.BytesToHash(input))
+				if len(input) > 1 {
+					return nil, errRevert
+				}
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	_, evm := ethtest.NewZeroEVM(t)
+	assert.Empty(t, evm.DeclaredConflicts(), "DeclaredConflicts() before any calls")
+
+	caller := vm.AccountRef(common.Address{'c'})
+	_, _, err := evm.Call(caller, precompile, []byte{2}, 1e6, uint256.NewInt(0))
+	require.NoError(t, err, "Call()")
+	_, _, err = evm.StaticCall(caller, precompile, []byte{1}, 1e6)
+	require.NoError(t, err, "StaticCall()")
+	_, _, err = evm.Call(caller, precompile, []byte{1, 0}, 1e6, uint256.NewInt(0))
+	require.ErrorIs(t, err, errRevert, "Call() with reverting precompile")
+	_, _, err = evm.Call(caller, precompile, []byte{1}, 1e6, uint256.NewInt(0))
+	require.NoError(t, err, "Call() with duplicate key")
+
+	want := []common.Hash{
+		common.BytesToHash([]byte{1}),
+		common.BytesToHash([]byte{2}),
+		common.BytesToHash([]byte{1, 0}),
+	}
+	assert.Equal(t, want, evm.DeclaredConflicts(), "DeclaredConflicts() includes read-only and reverted calls")
+
+	evm.Reset(vm.TxContext{}, evm.StateDB)
+	assert.Empty(t, evm.DeclaredConflicts(), "DeclaredConflicts() after Reset()")
+}
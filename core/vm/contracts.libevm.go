@@ -1,4 +1,4 @@
-// Copyright 2024-2025 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -17,12 +17,16 @@
 package vm
 
 import (
+	"context"
 	"fmt"
 	"math/big"
+	"slices"
+	"time"
 
 	"github.com/holiman/uint256"
 	"golang.org/x/exp/slog"
 
+	"github.com/ava-labs/libevm/accounts/abi"
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/core/types"
 	"github.com/ava-labs/libevm/libevm"
@@ -39,14 +43,30 @@ type P256Verify struct {
 	p256Verify
 }
 
-// ActivePrecompiles returns the precompiles enabled with the current configuration.
+// LogModule is the module name used by high-frequency logging in this package,
+// for use with [log.SetModuleLevel].
+const LogModule = "vm"
+
+// activePrecompilesLog is called for every EVM call so is rate limited.
+var activePrecompilesLog = log.NewLimiter(LogModule, log.LimiterConfig{
+	Burst:    10,
+	Interval: time.Second,
+})
+
+// ActivePrecompiles returns the precompiles enabled with the current
+// configuration, in ascending order and without duplicates, irrespective of the
+// order returned by the upstream implementation or by
+// [params.RulesHooks.ActivePrecompiles]. Canonicalisation guarantees that all
+// nodes iterate over the precompiles in the same order.
 func ActivePrecompiles(rules params.Rules) []common.Address {
 	orig := activePrecompiles(rules) // original, upstream implementation
-	active := rules.Hooks().ActivePrecompiles(append([]common.Address{}, orig...))
+	in := slices.Clone(orig)
+	slices.SortFunc(in, common.Address.Cmp)
+	active := canonicalAddresses(rules.Hooks().ActivePrecompiles(in))
 
 	// As all set computation is done lazily and only when debugging, there is
 	// some duplication in favour of simplified code.
-	log.Debug(
+	activePrecompilesLog.Debug(
 		"Overriding active precompiles",
 		"added", log.Lazy(func() slog.Value {
 			diff := set.From(active...).Sub(set.From(orig...))
@@ -65,6 +85,39 @@ func ActivePrecompiles(rules params.Rules) []common.Address {
 	return active
 }
 
+// canonicalAddresses returns the addresses in ascending order and without
+// duplicates. The argument is returned unchanged if already canonical,
+// otherwise it is copied so a slice retained by the caller isn't modified.
+func canonicalAddresses(addrs []common.Address) []common.Address {
+	canonical := true
+	for i := 1; i < len(addrs) && canonical; i++ {
+		canonical = addrs[i-1].Cmp(addrs[i]) < 0
+	}
+	if canonical {
+		return addrs
+	}
+	addrs = slices.Clone(addrs)
+	slices.SortFunc(addrs, common.Address.Cmp)
+	return slices.Compact(addrs)
+}
+
+// PrecompileAt returns the precompiled contract that an [EVM] would run at the
+// address under the given rules, honouring any
+// [params.RulesHooks.PrecompileOverride], and whether one exists.
+func PrecompileAt(rules params.Rules, addr common.Address) (PrecompiledContract, bool) {
+	evm := &EVM{chainRules: rules}
+	return evm.precompile(addr)
+}
+
+// UpstreamPrecompile returns the geth precompiled contract at the address
+// under the given rules, ignoring any [params.RulesHooks.PrecompileOverride],
+// and whether one exists. It allows overrides to decorate upstream
+// implementations (e.g. with [WrapWithEnvironment]) instead of replacing them.
+func UpstreamPrecompile(rules params.Rules, addr common.Address) (PrecompiledContract, bool) {
+	evm := &EVM{chainRules: rules}
+	return evm.upstreamPrecompile(addr)
+}
+
 // evmCallArgs mirrors the parameters of the [EVM] methods Call(), CallCode(),
 // DelegateCall() and StaticCall(). Its fields are identical to those of the
 // parameters, prepended with the receiver name and call type. As
@@ -134,11 +187,35 @@ func (t CallType) OpCode() OpCode {
 // run runs the [PrecompiledContract], differentiating between stateful and
 // regular types, updating `args.gasRemaining` in the stateful case.
 func (args *evmCallArgs) run(p PrecompiledContract, input []byte) (ret []byte, err error) {
-	sp, ok := p.(statefulPrecompile)
+	if args.evm != nil { // see [RunPrecompiledContract] in tests
+		if args.evm.cancelledWithError() {
+			return nil, ErrCancelled
+		}
+		defer func() {
+			ret, err = args.evm.limitReturnData(ret, err)
+		}()
+	}
+	if c := args.slowPrecompileConfig(); c != nil {
+		start := time.Now()
+		defer func() {
+			c.maybeReport(args, p, input, time.Since(start), err)
+		}()
+	}
+	if r := args.precompileMetrics(); r != nil {
+		start := time.Now()
+		gasBefore := args.gasRemaining
+		defer func() {
+			args.recordPrecompileMetrics(r, p, input, gasBefore, time.Since(start), err)
+		}()
+	}
+
+	sp, ok := unwrapPrecompile(p).(statefulPrecompile)
 	if !ok {
 		return p.Run(input)
 	}
 
+	defer args.evm.recordPrecompileAccesses(args.addr)()
+
 	env := args.env()
 	// Depth and read-only setting are handled by [EVMInterpreter.Run],
 	// which isn't used for precompiles, so we need to do it ourselves to
@@ -153,7 +230,7 @@ func (args *evmCallArgs) run(p PrecompiledContract, input []byte) (ret []byte, e
 		defer func() { in.readOnly = false }()
 	}
 
-	ret, err = sp(env, input)
+	ret, err = convertRevertError(sp(env, input))
 	args.gasRemaining = env.Gas()
 	return ret, err
 }
@@ -163,7 +240,9 @@ func (args *evmCallArgs) run(p PrecompiledContract, input []byte) (ret []byte, e
 //
 // Instead of receiving and returning gas arguments, stateful precompiles use
 // the respective methods on [PrecompileEnvironment]. If a call to UseGas()
-// returns false, a stateful precompile SHOULD return [ErrOutOfGas].
+// returns false, a stateful precompile SHOULD return [ErrOutOfGas]. To revert
+// with data, exactly as a contract would, it SHOULD return a [RevertError];
+// e.g. via [RevertWithReason] or [RevertWithCustomError].
 type PrecompiledStatefulContract func(env PrecompileEnvironment, input []byte) (ret []byte, err error)
 
 // NewStatefulPrecompile constructs a new PrecompiledContract that can be used
@@ -204,18 +283,170 @@ type PrecompileEnvironment interface {
 
 	IncomingCallType() CallType
 	Addresses() *libevm.AddressContext
+	// CallFraming classifies how the precompile was reached, including the
+	// address of the code that called it, which allows access-control schemes
+	// to differentiate between direct calls and those via proxies.
+	CallFraming() CallFraming
 	ReadOnly() bool
+	// CallStack returns the frames of all calls and contract creations leading
+	// to, and including, the call to the precompile, outermost first. The
+	// first frame is therefore that of the transaction and the last frame
+	// corresponds to Addresses().Raw. A stack of length one signals that the
+	// precompile was called directly by the transaction's sender.
+	CallStack() []CallFrame
+	// TxContext returns the context of the transaction that invoked the
+	// precompile; i.e. its origin, effective gas price, blob hashes, and blob
+	// fee cap.
+	TxContext() TxContext
 	// Equivalent to respective methods on [Contract].
 	Gas() uint64
 	UseGas(uint64) (hasEnoughGas bool)
 	Value() *uint256.Int
 
+	// BlockHeader returns the header of the current block, which MAY be
+	// incomplete, as reported by BlockHeaderMode().
 	BlockHeader() (types.Header, error)
+	BlockHeaderMode() HeaderMode
+	// HeaderFlags returns the flags carried by BlockHeader(), or zero if its
+	// registered payload doesn't implement [types.HeaderFlagsCarrier].
+	HeaderFlags() (types.HeaderFlags, error)
+	// BlockNumber, BlockTime, Coinbase, BlockGasLimit, BaseFee, and
+	// BlobBaseFee return the values pushed by the respective op codes, read
+	// directly from the [BlockContext] and therefore available even if
+	// BlockHeader() isn't. The fees are zero if not set in the context. See
+	// BlockRandomness() for the equivalent of DIFFICULTY and PREVRANDAO.
 	BlockNumber() *big.Int
 	BlockTime() uint64
-
-	// Invalidate invalidates the transaction calling this precompile.
+	Coinbase() common.Address
+	BlockGasLimit() uint64
+	BaseFee() *big.Int
+	BlobBaseFee() *big.Int
+	// Clock returns a deterministic time source derived from the block, which
+	// MUST be used instead of wall-clock time.
+	Clock() Clock
+	// BlockRandomness returns the value pushed by the DIFFICULTY opcode or,
+	// after the merge, PREVRANDAO, including any override by
+	// [BlockRandomnessHooks]. It SHOULD be used in preference to the respective
+	// BlockHeader() fields, which aren't overridden.
+	BlockRandomness() common.Hash
+	// Ancestors returns a reader of the last [MaxAncestorDepth] ancestors of
+	// the current block, backed by [BlockContext.BlockReader]. Its methods
+	// return [ErrNotAncestor] for any other block, and [ErrNoBlockReader] if
+	// there is no backing reader. Returned receipts MUST NOT be modified.
+	Ancestors() BlockReader
+	// BlockHash returns the hash that the BLOCKHASH op code would push for the
+	// block number, backed by [BlockContext.GetHash]. The returned boolean is
+	// false, instead of the hash being zero, if the block isn't one of the last
+	// [MaxAncestorDepth] ancestors of the current block.
+	BlockHash(number uint64) (common.Hash, bool)
+
+	// Services returns the container set by [Config.Services], which MAY be
+	// nil; services are retrieved from it with [Service].
+	Services() *Services
+
+	// Context returns the context passed to [EVM.CancelOnDone], or a background
+	// context if there was none or cancellation is disabled. Long-running
+	// precompiles SHOULD abort, returning [ErrCancelled], once it is done.
+	Context() context.Context
+
+	// InvalidateExecution invalidates the transaction calling this
+	// precompile. A non-nil error is wrapped in an [InvalidationError] before
+	// being passed to [EVM.InvalidateExecution]; see
+	// [params.ExecutionInvalidationHooks] for its treatment.
 	InvalidateExecution(error)
+	// AppendJournalEntry applies the entry and journals it alongside state
+	// changes such that it is reverted if the precompile call, or any
+	// surrounding call, reverts. It returns [ErrWriteProtection] if ReadOnly()
+	// and an error if the StateDB doesn't implement [JournalingStateDB].
+	AppendJournalEntry(libevm.JournalEntry) error
+	// OnCommit schedules the function to be run after the transaction, iff
+	// neither the precompile call, any surrounding call, nor the transaction
+	// itself reverts. OnRevert schedules the function to be run if the
+	// precompile call or any surrounding call reverts. Both are implemented
+	// with AppendJournalEntry() and return errors under the same conditions.
+	OnCommit(func()) error
+	OnRevert(func()) error
+	// OnTxEnd schedules the function to be run once all call frames of the
+	// transaction have returned, but before the receipt is created, iff
+	// neither the precompile call nor any surrounding call reverts, and
+	// execution isn't invalidated. The function receives the final [StateDB]
+	// and any changes it makes are included in the transaction, allowing
+	// effects of multiple calls to be aggregated (e.g. batched settlement).
+	// It is implemented with AppendJournalEntry() and returns errors under the
+	// same conditions.
+	OnTxEnd(func(StateDB)) error
+	// DeclareConflict declares that the transaction accesses a logical
+	// resource identified by the key, such that it MUST NOT be executed
+	// concurrently with any other transaction declaring the same key; see
+	// [EVM.DeclaredConflicts]. This is intended for resources not captured
+	// by state accesses alone. Declarations are permitted even if ReadOnly()
+	// and are retained if the call reverts.
+	DeclareConflict(key common.Hash)
+	// Snapshot and RevertToSnapshot are equivalent to the respective [StateDB]
+	// methods, allowing atomic, multi-step operations; the precompile call
+	// itself does NOT need to fail after a revert. Only IDs returned by the
+	// same environment's Snapshot(), and not invalidated by an earlier revert,
+	// are accepted, otherwise [ErrUnknownSnapshot] is returned. Both return
+	// [ErrWriteProtection] if ReadOnly().
+	Snapshot() (int, error)
+	RevertToSnapshot(int) error
+	// AddressInAccessList, SlotInAccessList, AddAddressToAccessList, and
+	// AddSlotToAccessList are equivalent to the respective [StateDB] methods,
+	// operating on the EIP-2929 access list. Additions are journalled and
+	// permitted even if ReadOnly(), as with the interpreter.
+	AddressInAccessList(common.Address) bool
+	SlotInAccessList(common.Address, common.Hash) (addressOk, slotOk bool)
+	AddAddressToAccessList(common.Address)
+	AddSlotToAccessList(common.Address, common.Hash)
+	// AccountAccessGas and SlotAccessGas add the account or storage slot to
+	// the access list and return the gas that the interpreter would charge
+	// for accessing it (e.g. via BALANCE or SLOAD respectively) under
+	// EIP-2929; i.e. the warm cost if it was already in the access list,
+	// otherwise the cold cost. If EIP-2929 isn't active, the access list is
+	// unchanged and zero is returned. Neither consumes the gas.
+	AccountAccessGas(common.Address) uint64
+	SlotAccessGas(common.Address, common.Hash) uint64
+	// AddRefund and SubRefund are equivalent to the respective [StateDB]
+	// methods, modifying the transaction's gas-refund counter as SSTORE does
+	// when clearing storage. The counter is journalled and the total refund is
+	// capped at the end of the transaction (e.g. by EIP-3529). SubRefund
+	// returns [ErrRefundUnderflow] instead of panicking if the amount exceeds
+	// the counter. Both return [ErrWriteProtection] if ReadOnly().
+	AddRefund(uint64) error
+	SubRefund(uint64) error
+
+	// ProofOfStorage returns Merkle proofs of the account and storage slots
+	// against the state root from before the current block; i.e. excluding
+	// all changes made by the block so far, which makes it deterministic. It
+	// returns an error if the StateDB doesn't implement [ProvingStateDB].
+	ProofOfStorage(addr common.Address, slots []common.Hash) (*libevm.StorageProof, error)
+	// CodeMetadata returns the metadata of the code at the address, as
+	// reported by any [CodeMetadataHooks], or nil if there are none.
+	CodeMetadata(common.Address) any
+	// ScratchLoad and ScratchStore provide a key-value store private to the
+	// precompile, available regardless of EIP-1153 activation. Like transient
+	// storage, values are cleared between transactions and writes are reverted
+	// along with the precompile call or any surrounding call. ScratchStore
+	// returns [ErrWriteProtection] if ReadOnly().
+	ScratchLoad(key common.Hash) common.Hash
+	ScratchStore(key, value common.Hash) error
+	// GetTransientState and SetTransientState are equivalent to the TLOAD and
+	// TSTORE op codes, respectively, operating on the transient storage of
+	// Addresses().EVMSemantic.Self and journalled such that writes are reverted
+	// along with the precompile call or any surrounding call. Unlike scratch
+	// space, values are shared with contract code that executes at the same
+	// address (e.g. via DELEGATECALL). SetTransientState returns
+	// [ErrWriteProtection] if ReadOnly().
+	GetTransientState(key common.Hash) common.Hash
+	SetTransientState(key, value common.Hash) error
+	// AddLog emits a log, equivalent to a LOG* op code, filling in the address
+	// and block number; the transaction hash and index are filled in by the
+	// StateDB. It returns [ErrWriteProtection] if ReadOnly() and
+	// [ErrTooManyTopics] if there are more than 4 topics. EmitEvent is
+	// equivalent to AddLog() with the values returned by [PackEvent]. Neither
+	// consumes gas; see [LogGas].
+	AddLog(topics []common.Hash, data []byte) error
+	EmitEvent(ev abi.Event, args ...any) error
 
 	// Call is equivalent to [EVM.Call] except that the `caller` argument is
 	// removed and automatically determined according to the type of call that
@@ -226,6 +457,24 @@ type PrecompileEnvironment interface {
 	// pattern, libevm's `reentrancy` package, or some other protection MUST be
 	// used in conjunction with `Call()`.
 	Call(addr common.Address, input []byte, gas uint64, value *uint256.Int, _ ...CallOption) (ret []byte, _ error)
+	// StaticCall and DelegateCall are equivalent to [EVM.StaticCall] and
+	// [EVM.DelegateCall], respectively, in the same manner as Call(). The
+	// former guarantees that the callee can't modify state while the latter
+	// runs the callee's code in the context of the precompile; i.e. with its
+	// storage, caller, and value. The same WARNING applies to both.
+	StaticCall(addr common.Address, input []byte, gas uint64, _ ...CallOption) (ret []byte, _ error)
+	DelegateCall(addr common.Address, input []byte, gas uint64, _ ...CallOption) (ret []byte, _ error)
+	// Create and Create2 are equivalent to [EVM.Create] and [EVM.Create2]
+	// except that the `caller` argument is removed and the precompile itself
+	// (or its caller, if invoked with DELEGATECALL) is the creator, the nonce of
+	// which is incremented. The supplied gas is deducted from Gas() and any
+	// left over is returned. A nil value is treated as zero. Both return
+	// [ErrWriteProtection] if ReadOnly().
+	//
+	// The same reentrancy WARNING as for Call() applies because the init code
+	// is arbitrary.
+	Create(code []byte, gas uint64, value *uint256.Int) (ret []byte, contractAddr common.Address, _ error)
+	Create2(code []byte, gas uint64, value *uint256.Int, salt *uint256.Int) (ret []byte, contractAddr common.Address, _ error)
 }
 
 func (args *evmCallArgs) env() *environment {
@@ -247,20 +496,20 @@ func (args *evmCallArgs) env() *environment {
 		self = args.caller.Address()
 	}
 
+	env := &environment{
+		evm:           args.evm,
+		callType:      args.callType,
+		rawCaller:     args.caller.Address(),
+		rawCallerCode: callerCodeAddress(args.caller),
+		rawSelf:       args.addr,
+	}
 	// This is equivalent to the `contract` variables created by evm.*Call*()
 	// methods, for non precompiles, to pass to [EVMInterpreter.Run].
-	contract := NewContract(args.caller, AccountRef(self), value, args.gasRemaining)
+	env.self = *NewContract(args.caller, AccountRef(self), value, args.gasRemaining)
 	if args.callType == DelegateCall {
-		contract = contract.AsDelegate()
-	}
-
-	return &environment{
-		evm:       args.evm,
-		self:      contract,
-		callType:  args.callType,
-		rawCaller: args.caller.Address(),
-		rawSelf:   args.addr,
+		env.self.AsDelegate()
 	}
+	return env
 }
 
 var (
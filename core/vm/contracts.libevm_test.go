@@ -1,4 +1,4 @@
-// Copyright 2024 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -23,6 +23,7 @@ import (
 	"math"
 	"math/big"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
@@ -639,7 +640,83 @@ func TestActivePrecompilesOverride(t *testing.T) {
 	}
 	hooks.Register(t)
 
-	require.Equal(t, precompiles, vm.ActivePrecompiles(newRules()), "vm.ActivePrecompiles() returns overridden addresses")
+	want := slices.Clone(precompiles)
+	slices.SortFunc(want, common.Address.Cmp)
+	require.Equal(t, want, vm.ActivePrecompiles(newRules()), "vm.ActivePrecompiles() returns sorted, overridden addresses")
+}
+
+func TestActivePrecompilesCanonical(t *testing.T) {
+	// Rules MUST be derived after hooks are registered for their extras to be
+	// populated.
+	newRules := func() params.Rules {
+		return new(params.ChainConfig).Rules(big.NewInt(0), false, 0)
+	}
+	isCanonical := func(addrs []common.Address) bool {
+		for i := 1; i < len(addrs); i++ {
+			if addrs[i-1].Cmp(addrs[i]) >= 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	t.Run("upstream", func(t *testing.T) {
+		// Upstream addresses are populated from maps so are in random order.
+		got := vm.ActivePrecompiles(newRules())
+		assert.True(t, isCanonical(got), "vm.ActivePrecompiles() without hooks is sorted and deduplicated")
+		assert.Len(t, got, len(vm.PrecompiledAddressesHomestead), "vm.ActivePrecompiles() length")
+	})
+
+	var (
+		a = common.Address{1}
+		b = common.Address{2}
+		c = common.Address{3}
+	)
+	tests := []struct {
+		name     string
+		fromHook []common.Address
+		want     []common.Address
+	}{
+		{
+			name:     "already_canonical",
+			fromHook: []common.Address{a, b, c},
+			want:     []common.Address{a, b, c},
+		},
+		{
+			name:     "unsorted",
+			fromHook: []common.Address{c, a, b},
+			want:     []common.Address{a, b, c},
+		},
+		{
+			name:     "duplicates",
+			fromHook: []common.Address{b, a, b, c, a, a},
+			want:     []common.Address{a, b, c},
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received []common.Address
+			hooks := &hookstest.Stub{
+				ActivePrecompilesFn: func(active []common.Address) []common.Address {
+					received = active
+					return tt.fromHook
+				},
+			}
+			hooks.Register(t)
+
+			rules := newRules()
+			original := slices.Clone(tt.fromHook)
+			for i := 0; i < 2; i++ {
+				assert.Equalf(t, tt.want, vm.ActivePrecompiles(rules), "vm.ActivePrecompiles() call %d", i)
+			}
+			assert.True(t, isCanonical(received), "hook receives canonical addresses")
+			assert.Equal(t, original, tt.fromHook, "slice returned by hook is not modified")
+		})
+	}
 }
 
 func TestPrecompileMakeCall(t *testing.T) {
@@ -878,3 +955,1364 @@ func TestPrecompileCallWithTracer(t *testing.T) {
 	require.NoErrorf(t, json.Unmarshal(gotJSON, &got), "json.Unmarshal(%T.GetResult(), %T)", tracer, &got)
 	require.Equal(t, value, got[contract].Storage[zeroHash], "value loaded with SLOAD")
 }
+
+type counterJournalEntry struct {
+	counter *int
+}
+
+func (e counterJournalEntry) Apply()  { *e.counter++ }
+func (e counterJournalEntry) Revert() { *e.counter-- }
+
+func TestPrecompileAppendJournalEntry(t *testing.T) {
+	var counter int
+	errRevert := errors.New("revert")
+	inputToRevert := []byte("revert")
+
+	rng := ethtest.NewPseudoRand(142857)
+	precompile := rng.Address()
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				if err := env.AppendJournalEntry(counterJournalEntry{&counter}); err != nil {
+					return nil, err
+				}
+				if bytes.Equal(input, inputToRevert) {
+					return nil, errRevert
+				}
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	_, evm := ethtest.NewZeroEVM(t)
+	caller := vm.AccountRef(rng.Address())
+
+	tests := []struct {
+		name        string
+		input       []byte
+		static      bool
+		wantErr     error
+		wantCounter int
+	}{
+		{
+			name:        "applied",
+			wantCounter: 1,
+		},
+		{
+			name:        "reverted",
+			input:       inputToRevert,
+			wantErr:     errRevert,
+			wantCounter: 1,
+		},
+		{
+			name:        "static_call",
+			static:      true,
+			wantErr:     vm.ErrWriteProtection,
+			wantCounter: 1,
+		},
+		{
+			name:        "applied_again",
+			wantCounter: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.static {
+				_, _, err = evm.StaticCall(caller, precompile, tt.input, 1e6)
+			} else {
+				_, _, err = evm.Call(caller, precompile, tt.input, 1e6, uint256.NewInt(0))
+			}
+			require.ErrorIs(t, err, tt.wantErr, "evm.Call([precompile appending journal entry])")
+			assert.Equal(t, tt.wantCounter, counter, "counter modified by journal entries")
+		})
+	}
+}
+
+func TestPrecompileDeferredActions(t *testing.T) {
+	var got []string
+	errRevert := errors.New("revert")
+
+	precompile := common.HexToAddress("DEFE22ED")
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				in := string(input)
+				if err := env.OnCommit(func() { got = append(got, "commit "+in) }); err != nil {
+					return nil, err
+				}
+				if err := env.OnRevert(func() { got = append(got, "revert "+in) }); err != nil {
+					return nil, err
+				}
+				if err := env.OnTxEnd(func(sdb vm.StateDB) {
+					got = append(got, "tx end "+in)
+					sdb.AddBalance(precompile, uint256.NewInt(1))
+				}); err != nil {
+					return nil, err
+				}
+
+				switch in {
+				case "revert":
+					return nil, errRevert
+				case "invalidate":
+					env.InvalidateExecution(errRevert)
+				}
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	stateDB, evm := ethtest.NewZeroEVM(t)
+
+	tests := []struct {
+		input       string
+		nonce       uint64
+		want        []string
+		wantBalance uint64
+	}{
+		{
+			input:       "succeed",
+			nonce:       0,
+			want:        []string{"tx end succeed", "commit succeed"},
+			wantBalance: 1,
+		},
+		{
+			input:       "revert",
+			nonce:       1,
+			want:        []string{"revert revert"},
+			wantBalance: 1,
+		},
+		{
+			input:       "invalidate",
+			nonce:       2,
+			want:        []string{"revert invalidate"},
+			wantBalance: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got = nil
+
+			msg := &core.Message{
+				Nonce:    tt.nonce,
+				Data:     []byte(tt.input),
+				To:       &precompile,
+				GasLimit: 1e6,
+				GasPrice: big.NewInt(0),
+				Value:    big.NewInt(0),
+			}
+			evm.Reset(core.NewEVMTxContext(msg), stateDB)
+
+			gas := core.GasPool(math.MaxUint64)
+			_, _ = core.ApplyMessage(evm, msg, &gas)
+			assert.Equal(t, tt.want, got, "deferred actions run")
+			assert.Equal(t, uint256.NewInt(tt.wantBalance), stateDB.GetBalance(precompile), "balance modified by tx-end actions")
+		})
+	}
+}
+
+func TestPrefetchHints(t *testing.T) {
+	rng := ethtest.NewPseudoRand(314159)
+	var (
+		hinted   = rng.Address()
+		unhinted = rng.Address()
+		slot     = rng.Hash()
+	)
+
+	hints := func(input []byte) types.AccessList {
+		return types.AccessList{{
+			Address:     common.BytesToAddress(input),
+			StorageKeys: []common.Hash{slot},
+		}}
+	}
+	stateful := vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+		return env.Addresses().EVMSemantic.Self.Bytes(), nil
+	})
+
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			hinted:   vm.WithPrefetchHints(stateful, hints),
+			unhinted: stateful,
+		},
+	}
+	hooks.Register(t)
+
+	rules := new(params.ChainConfig).Rules(big.NewInt(0), false, 0)
+	input := rng.Address().Bytes()
+	assert.Equal(t, hints(input), vm.PrefetchHints(rules, hinted, input), "hints of wrapped precompile")
+	assert.Nil(t, vm.PrefetchHints(rules, unhinted, input), "hints of unwrapped precompile")
+	assert.Nil(t, vm.PrefetchHints(rules, rng.Address(), input), "hints of non-precompile")
+
+	_, evm := ethtest.NewZeroEVM(t)
+	got, _, err := evm.Call(vm.AccountRef(rng.Address()), hinted, input, 1e6, uint256.NewInt(0))
+	require.NoError(t, err, "evm.Call([hinted stateful precompile])")
+	assert.Equal(t, hinted.Bytes(), got, "stateful precompile run via hint wrapper")
+}
+
+// newMergedChainConfig returns a new equivalent of
+// [params.MergedTestChainConfig]. Package-level configs retain an extra payload
+// of the type registered when they are first used so can't be shared by tests
+// that register different types.
+func newMergedChainConfig() *params.ChainConfig {
+	zero := uint64(0)
+	return &params.ChainConfig{
+		ChainID:                       big.NewInt(1),
+		HomesteadBlock:                big.NewInt(0),
+		EIP150Block:                   big.NewInt(0),
+		EIP155Block:                   big.NewInt(0),
+		EIP158Block:                   big.NewInt(0),
+		ByzantiumBlock:                big.NewInt(0),
+		ConstantinopleBlock:           big.NewInt(0),
+		PetersburgBlock:               big.NewInt(0),
+		IstanbulBlock:                 big.NewInt(0),
+		MuirGlacierBlock:              big.NewInt(0),
+		BerlinBlock:                   big.NewInt(0),
+		LondonBlock:                   big.NewInt(0),
+		ArrowGlacierBlock:             big.NewInt(0),
+		GrayGlacierBlock:              big.NewInt(0),
+		MergeNetsplitBlock:            big.NewInt(0),
+		ShanghaiTime:                  &zero,
+		CancunTime:                    &zero,
+		TerminalTotalDifficulty:       big.NewInt(0),
+		TerminalTotalDifficultyPassed: true,
+		Ethash:                        new(params.EthashConfig),
+	}
+}
+
+// codeMetadataHooks record the Keccak256 hash of deployed code in a storage
+// slot of the new contract.
+type codeMetadataHooks struct {
+	hookstest.Stub
+	slot common.Hash
+}
+
+var _ vm.CodeMetadataHooks = (*codeMetadataHooks)(nil)
+
+func (h *codeMetadataHooks) OnCodeDeployed(sdb vm.StateDB, addrs *libevm.AddressContext, code []byte) {
+	sdb.SetState(addrs.EVMSemantic.Self, h.slot, crypto.Keccak256Hash(code))
+}
+
+func (h *codeMetadataHooks) CodeMetadata(sdb vm.StateDB, addr common.Address) any {
+	if md := sdb.GetState(addr, h.slot); md != (common.Hash{}) {
+		return md
+	}
+	return nil
+}
+
+func TestCodeMetadataHooks(t *testing.T) {
+	rng := ethtest.NewPseudoRand(27182)
+	precompile := rng.Address()
+	hooks := &codeMetadataHooks{
+		Stub: hookstest.Stub{
+			PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+				precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+					md, ok := env.CodeMetadata(common.BytesToAddress(input)).(common.Hash)
+					if !ok {
+						return nil, nil
+					}
+					return md.Bytes(), nil
+				}),
+			},
+		},
+		slot: rng.Hash(),
+	}
+	hookstest.Register(t, params.Extras[*codeMetadataHooks, *codeMetadataHooks]{
+		NewRules: func(*params.ChainConfig, *params.Rules, *codeMetadataHooks, *big.Int, bool, uint64) *codeMetadataHooks {
+			return hooks
+		},
+	})
+
+	// REVERT requires Byzantium.
+	config := newMergedChainConfig()
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0)}
+	rules := config.Rules(header.Number, true, header.Time)
+	sdb, evm := ethtest.NewZeroEVM(
+		t,
+		ethtest.WithChainConfig(config),
+		ethtest.WithBlockContext(core.NewEVMBlockContext(header, nil, &common.Address{})),
+	)
+	caller := vm.AccountRef(rng.Address())
+
+	// PUSH1 1 PUSH1 0 RETURN, which deploys a single zero byte.
+	initCode := []byte{byte(vm.PUSH1), 1, byte(vm.PUSH1), 0, byte(vm.RETURN)}
+	_, deployed, _, err := evm.Create(caller, initCode, 1e6, uint256.NewInt(0))
+	require.NoError(t, err, "evm.Create()")
+	require.Equal(t, []byte{0}, sdb.GetCode(deployed), "deployed code")
+
+	want := crypto.Keccak256Hash([]byte{0})
+	assert.Equal(t, want, vm.CodeMetadata(rules, sdb, deployed), "vm.CodeMetadata([deployed])")
+
+	got, _, err := evm.Call(caller, precompile, deployed.Bytes(), 1e6, uint256.NewInt(0))
+	require.NoError(t, err, "evm.Call([precompile reading metadata])")
+	assert.Equal(t, want.Bytes(), got, "metadata via PrecompileEnvironment.CodeMetadata()")
+
+	// PUSH1 0 PUSH1 0 REVERT
+	revertCode := []byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.REVERT)}
+	_, reverted, _, err := evm.Create(caller, revertCode, 1e6, uint256.NewInt(0))
+	require.ErrorIs(t, err, vm.ErrExecutionReverted, "evm.Create([reverting init code])")
+	assert.Nil(t, vm.CodeMetadata(rules, sdb, reverted), "vm.CodeMetadata([reverted deployment])")
+}
+
+func TestPrecompileScratchSpace(t *testing.T) {
+	rng := ethtest.NewPseudoRand(1153)
+	var (
+		precompile = rng.Address()
+		key        = rng.Hash()
+		val        = rng.Hash()
+	)
+	errRevert := errors.New("revert")
+
+	// Input is a single byte selecting the behaviour: 's'tore, 'r'evert after
+	// storing, or anything else to load.
+	stub := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				switch input[0] {
+				case 's':
+					return nil, env.ScratchStore(key, val)
+				case 'r':
+					if err := env.ScratchStore(key, rng.Hash()); err != nil {
+						return nil, err
+					}
+					return nil, errRevert
+				default:
+					return env.ScratchLoad(key).Bytes(), nil
+				}
+			}),
+		},
+	}
+	stub.Register(t)
+
+	sdb, evm := ethtest.NewZeroEVM(t)
+	require.False(t, evm.ChainConfig().IsCancun(evm.Context.BlockNumber, evm.Context.Time), "EIP-1153 active")
+	caller := vm.AccountRef(rng.Address())
+
+	call := func(t *testing.T, op byte) []byte {
+		t.Helper()
+		got, _, err := evm.Call(caller, precompile, []byte{op}, 1e6, uint256.NewInt(0))
+		if op == 'r' {
+			require.ErrorIs(t, err, errRevert)
+			return nil
+		}
+		require.NoError(t, err)
+		return got
+	}
+
+	assert.Equal(t, common.Hash{}.Bytes(), call(t, 'l'), "initial value")
+	call(t, 's')
+	assert.Equal(t, val.Bytes(), call(t, 'l'), "after store")
+	call(t, 'r')
+	assert.Equal(t, val.Bytes(), call(t, 'l'), "after reverted store")
+
+	assert.Zero(t, sdb.GetTransientState(precompile, key), "scratch space collides with transient storage")
+
+	_, _, err := evm.StaticCall(caller, precompile, []byte{'s'}, 1e6)
+	require.ErrorIs(t, err, vm.ErrWriteProtection, "store via StaticCall()")
+
+	sdb.Prepare(evm.ChainConfig().Rules(evm.Context.BlockNumber, false, evm.Context.Time), caller.Address(), common.Address{}, nil, nil, nil)
+	assert.Equal(t, common.Hash{}.Bytes(), call(t, 'l'), "after new transaction prepared")
+}
+
+func TestPrecompileTransientState(t *testing.T) {
+	rng := ethtest.NewPseudoRand(11532)
+	var (
+		precompile = rng.Address()
+		key        = rng.Hash()
+		val        = rng.Hash()
+	)
+	errRevert := errors.New("revert")
+
+	// Input is a single byte selecting the behaviour, as for
+	// TestPrecompileScratchSpace.
+	stub := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				switch input[0] {
+				case 's':
+					return nil, env.SetTransientState(key, val)
+				case 'r':
+					if err := env.SetTransientState(key, rng.Hash()); err != nil {
+						return nil, err
+					}
+					return nil, errRevert
+				default:
+					return env.GetTransientState(key).Bytes(), nil
+				}
+			}),
+		},
+	}
+	stub.Register(t)
+
+	sdb, evm := ethtest.NewZeroEVM(t)
+	caller := vm.AccountRef(rng.Address())
+
+	call := func(t *testing.T, op byte) []byte {
+		t.Helper()
+		got, _, err := evm.Call(caller, precompile, []byte{op}, 1e6, uint256.NewInt(0))
+		if op == 'r' {
+			require.ErrorIs(t, err, errRevert)
+			return nil
+		}
+		require.NoError(t, err)
+		return got
+	}
+
+	assert.Equal(t, common.Hash{}.Bytes(), call(t, 'l'), "initial value")
+	call(t, 's')
+	assert.Equal(t, val.Bytes(), call(t, 'l'), "after store")
+	call(t, 'r')
+	assert.Equal(t, val.Bytes(), call(t, 'l'), "after reverted store")
+	assert.Equal(t, val, sdb.GetTransientState(precompile, key), "StateDB.GetTransientState()")
+
+	other := rng.Hash()
+	sdb.SetTransientState(precompile, key, other)
+	assert.Equal(t, other.Bytes(), call(t, 'l'), "after StateDB.SetTransientState()")
+
+	_, _, err := evm.StaticCall(caller, precompile, []byte{'s'}, 1e6)
+	require.ErrorIs(t, err, vm.ErrWriteProtection, "store via StaticCall()")
+}
+
+func TestPrecompileABI(t *testing.T) {
+	rng := ethtest.NewPseudoRand(732)
+	var (
+		declared = rng.Address()
+		plain    = rng.Address()
+		abiJSON  = []byte(`[]`)
+	)
+	hints := types.AccessList{{Address: rng.Address()}}
+
+	stateful := vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+		return env.Addresses().Raw.Self.Bytes(), nil
+	})
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			declared: vm.WithABI(
+				vm.WithPrefetchHints(stateful, func([]byte) types.AccessList { return hints }),
+				"Declared", abiJSON,
+			),
+			plain: stateful,
+		},
+	}
+	hooks.Register(t)
+	rules := new(params.ChainConfig).Rules(big.NewInt(0), false, 0)
+
+	name, gotABI, ok := vm.PrecompileABI(rules, declared)
+	require.True(t, ok, "vm.PrecompileABI([declared]) ok")
+	assert.Equal(t, "Declared", name, "name")
+	assert.Equal(t, abiJSON, gotABI, "ABI")
+
+	_, _, ok = vm.PrecompileABI(rules, plain)
+	assert.False(t, ok, "vm.PrecompileABI([undeclared]) ok")
+	_, _, ok = vm.PrecompileABI(rules, rng.Address())
+	assert.False(t, ok, "vm.PrecompileABI([non-precompile]) ok")
+
+	assert.Equal(t, hints, vm.PrefetchHints(rules, declared, nil), "hints of precompile nested in ABI wrapper")
+
+	_, evm := ethtest.NewZeroEVM(t)
+	got, _, err := evm.Call(vm.AccountRef(rng.Address()), declared, nil, 1e6, uint256.NewInt(0))
+	require.NoError(t, err, "evm.Call([stateful precompile with nested wrappers])")
+	assert.Equal(t, declared.Bytes(), got, "stateful precompile run via nested wrappers")
+}
+
+func TestWrapWithEnvironment(t *testing.T) {
+	const preRunGas = 100
+	identity := vm.PrecompiledContractsCancun[common.BytesToAddress([]byte{4})]
+
+	rng := ethtest.NewPseudoRand(747)
+	var (
+		plain   = rng.Address()
+		wrapped = rng.Address()
+		caller  = rng.Address()
+	)
+
+	var (
+		preRunCaller common.Address
+		postRunErr   error
+	)
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			plain: vm.WrapWithEnvironment(identity),
+			wrapped: vm.WrapWithEnvironment(
+				identity,
+				vm.WithPreRunHook(func(env vm.PrecompileEnvironment, _ []byte) error {
+					preRunCaller = env.Addresses().EVMSemantic.Caller
+					if !env.UseGas(preRunGas) {
+						return vm.ErrOutOfGas
+					}
+					return nil
+				}),
+				vm.WithPostRunHook(func(_ vm.PrecompileEnvironment, _, ret []byte, err error) ([]byte, error) {
+					postRunErr = err
+					return append(ret, 'x'), err
+				}),
+			),
+		},
+	}
+	hooks.Register(t)
+
+	input := []byte("hello")
+	required := identity.RequiredGas(input)
+	_, evm := ethtest.NewZeroEVM(t)
+
+	t.Run("without hooks", func(t *testing.T) {
+		got, gasLeft, err := evm.Call(vm.AccountRef(caller), plain, input, 1e6, uint256.NewInt(0))
+		require.NoError(t, err, "evm.Call()")
+		assert.Equal(t, input, got, "output")
+		assert.Equal(t, required, 1e6-gasLeft, "gas used")
+
+		_, _, err = evm.Call(vm.AccountRef(caller), plain, input, required-1, uint256.NewInt(0))
+		assert.ErrorIs(t, err, vm.ErrOutOfGas, "evm.Call() with insufficient gas")
+	})
+
+	t.Run("with hooks", func(t *testing.T) {
+		got, gasLeft, err := evm.Call(vm.AccountRef(caller), wrapped, input, 1e6, uint256.NewInt(0))
+		require.NoError(t, err, "evm.Call()")
+		assert.Equal(t, append(input, 'x'), got, "output modified by post-run hook")
+		assert.Equal(t, required+preRunGas, 1e6-gasLeft, "gas used")
+		assert.Equal(t, caller, preRunCaller, "caller observed by pre-run hook")
+		assert.NoError(t, postRunErr, "error observed by post-run hook")
+
+		_, _, err = evm.Call(vm.AccountRef(caller), wrapped, input, preRunGas+required-1, uint256.NewInt(0))
+		assert.ErrorIs(t, err, vm.ErrOutOfGas, "evm.Call() with insufficient gas")
+		assert.ErrorIs(t, postRunErr, vm.ErrOutOfGas, "error observed by post-run hook")
+	})
+}
+
+func TestWrapUpstreamPrecompile(t *testing.T) {
+	identityAddr := common.BytesToAddress([]byte{4})
+	rules := new(params.ChainConfig).Rules(big.NewInt(0), false, 0)
+	identity, ok := vm.UpstreamPrecompile(rules, identityAddr)
+	require.True(t, ok, "vm.UpstreamPrecompile(..., [identity])")
+
+	errEmptyInput := errors.New("empty input")
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			identityAddr: vm.WrapWithEnvironment(
+				identity,
+				vm.WithRequiredGas(func(_ []byte, upstream uint64) uint64 {
+					return 2 * upstream
+				}),
+				vm.WithPreRunHook(func(_ vm.PrecompileEnvironment, input []byte) error {
+					if len(input) == 0 {
+						return errEmptyInput
+					}
+					return nil
+				}),
+			),
+		},
+	}
+	hooks.Register(t)
+
+	got, ok := vm.UpstreamPrecompile(rules, identityAddr)
+	require.True(t, ok, "vm.UpstreamPrecompile(..., [identity]) with override registered")
+	assert.Equal(t, identity, got, "vm.UpstreamPrecompile() ignores override")
+
+	input := []byte("hello")
+	required := identity.RequiredGas(input)
+	_, evm := ethtest.NewZeroEVM(t)
+	caller := vm.AccountRef{}
+
+	out, gasLeft, err := evm.Call(caller, identityAddr, input, 1e6, uint256.NewInt(0))
+	require.NoError(t, err, "evm.Call([wrapped identity])")
+	assert.Equal(t, input, out, "output")
+	assert.Equal(t, 2*required, 1e6-gasLeft, "gas used")
+
+	_, _, err = evm.Call(caller, identityAddr, input, 2*required-1, uint256.NewInt(0))
+	assert.ErrorIs(t, err, vm.ErrOutOfGas, "evm.Call([wrapped identity]) with insufficient gas for re-pricing")
+
+	_, _, err = evm.Call(caller, identityAddr, nil, 1e6, uint256.NewInt(0))
+	assert.ErrorIs(t, err, errEmptyInput, "evm.Call([wrapped identity]) with invalid input")
+}
+
+func TestBlockHeaderMode(t *testing.T) {
+	rng := ethtest.NewPseudoRand(737)
+	precompile := rng.Address()
+	stub := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				return []byte(env.BlockHeaderMode().String()), nil
+			}),
+		},
+	}
+	stub.Register(t)
+
+	for _, mode := range []vm.HeaderMode{vm.HeaderCommitted, vm.HeaderBuilding} {
+		t.Run(mode.String(), func(t *testing.T) {
+			_, evm := ethtest.NewZeroEVM(t)
+			evm.Context.HeaderMode = mode
+
+			got, _, err := evm.Call(vm.AccountRef(rng.Address()), precompile, nil, 1e6, uint256.NewInt(0))
+			require.NoError(t, err)
+			assert.Equal(t, mode.String(), string(got), "PrecompileEnvironment.BlockHeaderMode()")
+		})
+	}
+}
+
+func TestNewStandaloneEnvironment(t *testing.T) {
+	rng := ethtest.NewPseudoRand(738)
+	var (
+		precompile = rng.Address()
+		other      = rng.Address()
+		slot       = rng.Hash()
+		val        = rng.Hash()
+		call       = vm.StandaloneCall{
+			Origin:     rng.Address(),
+			Caller:     rng.Address(),
+			Precompile: precompile,
+			Gas:        1e6,
+		}
+	)
+
+	stub := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			other: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				return env.ReadOnlyState().GetState(precompile, slot).Bytes(), nil
+			}),
+		},
+	}
+	stub.Register(t)
+
+	sdb, _ := ethtest.NewZeroEVM(t)
+	sdb.SetState(precompile, slot, val)
+	header := &types.Header{
+		Number:     rng.BigUint64(),
+		Time:       rng.Uint64(),
+		Difficulty: new(big.Int),
+	}
+	block := core.NewEVMBlockContext(header, nil, rng.AddressPtr())
+
+	env := vm.NewStandaloneEnvironment(sdb, &params.ChainConfig{ChainID: big.NewInt(1)}, block, call)
+
+	assert.True(t, env.ReadOnly(), "ReadOnly()")
+	assert.Nil(t, env.StateDB(), "StateDB()")
+	assert.Equal(t, vm.StaticCall, env.IncomingCallType(), "IncomingCallType()")
+	assert.Equal(t, header.Number, env.BlockNumber(), "BlockNumber()")
+	assert.Equal(t, header.Time, env.BlockTime(), "BlockTime()")
+	assert.Equal(t, call.Gas, env.Gas(), "Gas()")
+	assert.Equal(t, val, env.ReadOnlyState().GetState(precompile, slot), "ReadOnlyState().GetState()")
+
+	wantAddrs := libevm.CallerAndSelf{Caller: call.Caller, Self: call.Precompile}
+	assert.Equal(t, &libevm.AddressContext{Origin: call.Origin, EVMSemantic: wantAddrs, Raw: &wantAddrs}, env.Addresses(), "Addresses()")
+
+	require.ErrorIs(t, env.ScratchStore(slot, val), vm.ErrWriteProtection, "ScratchStore()")
+
+	got, err := env.Call(other, nil, 1e5, uint256.NewInt(0))
+	require.NoError(t, err, "Call() to other precompile")
+	assert.Equal(t, val.Bytes(), got, "Call() result")
+}
+
+func TestPrecompileBlockContext(t *testing.T) {
+	rng := ethtest.NewPseudoRand(7622)
+	precompile := rng.Address()
+
+	type values struct {
+		Coinbase             common.Address
+		GasLimit             uint64
+		BaseFee, BlobBaseFee *big.Int
+	}
+	var got values
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				got = values{
+					Coinbase:    env.Coinbase(),
+					GasLimit:    env.BlockGasLimit(),
+					BaseFee:     env.BaseFee(),
+					BlobBaseFee: env.BlobBaseFee(),
+				}
+				// Demonstrate that the context can't be modified.
+				env.BaseFee().SetUint64(0)
+				env.BlobBaseFee().SetUint64(0)
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	tests := []struct {
+		name string
+		ctx  vm.BlockContext
+		want values
+	}{
+		{
+			name: "all_set",
+			ctx: vm.BlockContext{
+				Coinbase:    common.Address{'c', 'o', 'i', 'n'},
+				GasLimit:    30e6,
+				BaseFee:     big.NewInt(25e9),
+				BlobBaseFee: big.NewInt(1),
+			},
+			want: values{
+				Coinbase:    common.Address{'c', 'o', 'i', 'n'},
+				GasLimit:    30e6,
+				BaseFee:     big.NewInt(25e9),
+				BlobBaseFee: big.NewInt(1),
+			},
+		},
+		{
+			name: "nil_fees",
+			ctx: vm.BlockContext{
+				GasLimit: 8e6,
+			},
+			want: values{
+				GasLimit:    8e6,
+				BaseFee:     new(big.Int),
+				BlobBaseFee: new(big.Int),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			ctx.CanTransfer = core.CanTransfer
+			ctx.Transfer = core.Transfer
+			ctx.BlockNumber = big.NewInt(0)
+			_, evm := ethtest.NewZeroEVM(t, ethtest.WithBlockContext(ctx))
+
+			for i := 0; i < 2; i++ {
+				_, _, err := evm.Call(vm.AccountRef(rng.Address()), precompile, nil, 1e6, uint256.NewInt(0))
+				require.NoError(t, err, "evm.Call([precompile])")
+				assert.Equalf(t, tt.want, got, "block-context accessors (call %d)", i)
+			}
+		})
+	}
+}
+
+// blockRandomnessHooks override the block's randomness with a constant.
+type blockRandomnessHooks struct {
+	hookstest.Stub
+	value common.Hash
+}
+
+var _ vm.BlockRandomnessHooks = (*blockRandomnessHooks)(nil)
+
+func (h *blockRandomnessHooks) BlockRandomness(*types.Header) (common.Hash, bool) {
+	return h.value, true
+}
+
+func TestBlockRandomnessHooks(t *testing.T) {
+	rng := ethtest.NewPseudoRand(740)
+	precompile := rng.Address()
+	hooks := &blockRandomnessHooks{
+		Stub: hookstest.Stub{
+			PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+				precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+					return env.BlockRandomness().Bytes(), nil
+				}),
+			},
+		},
+		value: rng.Hash(),
+	}
+	hookstest.Register(t, params.Extras[*blockRandomnessHooks, *blockRandomnessHooks]{
+		NewRules: func(*params.ChainConfig, *params.Rules, *blockRandomnessHooks, *big.Int, bool, uint64) *blockRandomnessHooks {
+			return hooks
+		},
+	})
+
+	contract := rng.Address()
+	// DIFFICULTY / PREVRANDAO PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+	code := convertBytes[vm.OpCode, byte](
+		vm.DIFFICULTY,
+		vm.PUSH1, 0,
+		vm.MSTORE,
+		vm.PUSH1, 32,
+		vm.PUSH1, 0,
+		vm.RETURN,
+	)
+
+	for _, merged := range []bool{false, true} {
+		t.Run(fmt.Sprintf("merged_%t", merged), func(t *testing.T) {
+			header := &types.Header{
+				Number:     big.NewInt(1),
+				Difficulty: big.NewInt(42),
+				MixDigest:  rng.Hash(),
+			}
+			if merged {
+				header.Difficulty = big.NewInt(0)
+			}
+			mixDigest := header.MixDigest
+
+			// PREVRANDAO requires London as well as a non-nil Random.
+			sdb, evm := ethtest.NewZeroEVM(
+				t,
+				ethtest.WithChainConfig(newMergedChainConfig()),
+				ethtest.WithBlockContext(core.NewEVMBlockContext(header, nil, rng.AddressPtr())),
+			)
+			require.Equal(t, merged, evm.Context.Random != nil, "merge status")
+			sdb.SetCode(contract, code)
+			caller := vm.AccountRef(rng.Address())
+
+			for _, addr := range []common.Address{contract, precompile} {
+				got, _, err := evm.Call(caller, addr, nil, 1e6, uint256.NewInt(0))
+				require.NoErrorf(t, err, "evm.Call(%v)", addr)
+				assert.Equalf(t, hooks.value.Bytes(), got, "randomness observed by %v", addr)
+			}
+			assert.Equal(t, mixDigest, header.MixDigest, "header modified by hook")
+		})
+	}
+}
+
+// callerAliasingHooks apply the rollup alias to calls from the bridge.
+type callerAliasingHooks struct {
+	hookstest.Stub
+	bridge common.Address
+}
+
+var _ vm.CallerAliasingHooks = (*callerAliasingHooks)(nil)
+
+func (h *callerAliasingHooks) AliasCaller(caller, _ common.Address) (common.Address, bool) {
+	if caller != h.bridge {
+		return caller, false
+	}
+	return vm.ApplyRollupAlias(caller), true
+}
+
+func TestCallerAliasingHooks(t *testing.T) {
+	rng := ethtest.NewPseudoRand(742)
+	precompile := rng.Address()
+	hooks := &callerAliasingHooks{
+		Stub: hookstest.Stub{
+			PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+				precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+					return env.Addresses().Raw.Caller.Bytes(), nil
+				}),
+			},
+		},
+		bridge: rng.Address(),
+	}
+	hookstest.Register(t, params.Extras[*callerAliasingHooks, *callerAliasingHooks]{
+		NewRules: func(*params.ChainConfig, *params.Rules, *callerAliasingHooks, *big.Int, bool, uint64) *callerAliasingHooks {
+			return hooks
+		},
+	})
+
+	contract := rng.Address()
+	// CALLER PUSH1 0 MSTORE PUSH1 20 PUSH1 12 RETURN
+	code := convertBytes[vm.OpCode, byte](
+		vm.CALLER,
+		vm.PUSH1, 0,
+		vm.MSTORE,
+		vm.PUSH1, 20,
+		vm.PUSH1, 12,
+		vm.RETURN,
+	)
+	sdb, evm := ethtest.NewZeroEVM(t)
+	sdb.SetCode(contract, code)
+	sdb.SetBalance(hooks.bridge, uint256.NewInt(1))
+
+	other := rng.Address()
+	tests := []struct {
+		caller, callee, want common.Address
+	}{
+		{hooks.bridge, contract, vm.ApplyRollupAlias(hooks.bridge)},
+		{hooks.bridge, precompile, vm.ApplyRollupAlias(hooks.bridge)},
+		{other, contract, other},
+		{other, precompile, other},
+	}
+	for _, tt := range tests {
+		got, _, err := evm.Call(vm.AccountRef(tt.caller), tt.callee, nil, 1e6, uint256.NewInt(0))
+		require.NoErrorf(t, err, "evm.Call() from %v to %v", tt.caller, tt.callee)
+		assert.Equalf(t, tt.want.Bytes(), got, "caller observed by %v when called by %v", tt.callee, tt.caller)
+	}
+
+	_, _, err := evm.Call(vm.AccountRef(hooks.bridge), contract, nil, 1e6, uint256.NewInt(1))
+	require.NoError(t, err, "evm.Call() with value from aliased caller")
+	assert.True(t, sdb.GetBalance(hooks.bridge).IsZero(), "value transferred from original caller")
+	assert.Equal(t, uint256.NewInt(1), sdb.GetBalance(contract), "value received by callee")
+}
+
+func TestRollupAlias(t *testing.T) {
+	for _, addr := range []common.Address{
+		{},
+		common.HexToAddress("0xffffffffffffffffffffffffffffffffffffffff"),
+		common.HexToAddress("0x4200000000000000000000000000000000000007"),
+	} {
+		aliased := vm.ApplyRollupAlias(addr)
+		assert.NotEqualf(t, addr, aliased, "ApplyRollupAlias(%v)", addr)
+		assert.Equalf(t, addr, vm.UndoRollupAlias(aliased), "UndoRollupAlias(ApplyRollupAlias(%v))", addr)
+	}
+	assert.Equal(t, vm.RollupAliasOffset, vm.ApplyRollupAlias(common.Address{}), "ApplyRollupAlias(0)")
+}
+
+func TestPrecompileCreate(t *testing.T) {
+	rng := ethtest.NewPseudoRand(752)
+	sut := rng.Address()
+	salt := uint256.NewInt(rng.Uint64())
+
+	// Init code that deploys a single-byte contract, 0x2a.
+	initCode := []byte{
+		byte(vm.PUSH1), 0x2a, byte(vm.PUSH1), 0, byte(vm.MSTORE8),
+		byte(vm.PUSH1), 1, byte(vm.PUSH1), 0, byte(vm.RETURN),
+	}
+	wantCode := []byte{0x2a}
+
+	type result struct {
+		addr common.Address
+		err  error
+	}
+	var got []result
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			sut: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				got = nil
+				_, addr, err := env.Create(initCode, env.Gas()/2, nil)
+				got = append(got, result{addr, err})
+				_, addr, err = env.Create2(initCode, env.Gas()/2, uint256.NewInt(0), salt)
+				got = append(got, result{addr, err})
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	sdb, evm := ethtest.NewZeroEVM(t)
+	caller := vm.AccountRef(rng.Address())
+
+	const gasLimit = 1e6
+	_, _, err := evm.Call(caller, sut, nil, gasLimit, uint256.NewInt(0))
+	require.NoError(t, err, "evm.Call([precompile])")
+
+	want := []result{
+		{addr: crypto.CreateAddress(sut, 0)},
+		{addr: crypto.CreateAddress2(sut, salt.Bytes32(), crypto.Keccak256(initCode))},
+	}
+	require.Equal(t, want, got, "env.Create() and env.Create2() results")
+	for _, r := range got {
+		assert.Equalf(t, wantCode, sdb.GetCode(r.addr), "code deployed to %v", r.addr)
+	}
+	assert.Equal(t, uint64(2), sdb.GetNonce(sut), "precompile nonce after 2 creations")
+
+	t.Run("read-only", func(t *testing.T) {
+		_, _, err := evm.StaticCall(caller, sut, nil, gasLimit)
+		require.NoError(t, err, "evm.StaticCall([precompile])")
+		for i, r := range got {
+			assert.ErrorIsf(t, r.err, vm.ErrWriteProtection, "creation %d", i)
+		}
+		assert.Equal(t, uint64(2), sdb.GetNonce(sut), "precompile nonce unchanged")
+	})
+}
+
+func TestPrecompileTxContext(t *testing.T) {
+	rng := ethtest.NewPseudoRand(7542)
+	precompile := rng.Address()
+
+	var got vm.TxContext
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				got = env.TxContext()
+				// Modifications MUST NOT be reflected in the EVM.
+				env.TxContext().GasPrice.SetUint64(0)
+				env.TxContext().BlobHashes[0] = common.Hash{}
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	var (
+		origin   = rng.Address()
+		gasPrice = rng.Uint64()
+		hashes   = []common.Hash{rng.Hash(), rng.Hash()}
+		feeCap   = rng.Uint64()
+	)
+	// Each call returns new pointers so modifications can't leak into `want`.
+	newTxContext := func() vm.TxContext {
+		return vm.TxContext{
+			Origin:     origin,
+			GasPrice:   new(big.Int).SetUint64(gasPrice),
+			BlobHashes: slices.Clone(hashes),
+			BlobFeeCap: new(big.Int).SetUint64(feeCap),
+		}
+	}
+	want := newTxContext()
+
+	_, evm := ethtest.NewZeroEVM(t)
+	evm.TxContext = newTxContext()
+	_, _, err := evm.Call(vm.AccountRef(origin), precompile, nil, 1e6, uint256.NewInt(0))
+	require.NoError(t, err, "evm.Call()")
+
+	assert.Equal(t, want, got, "TxContext()")
+	assert.Equal(t, want, evm.TxContext, "EVM.TxContext after modification of copy")
+}
+
+func TestPrecompileStaticAndDelegateCall(t *testing.T) {
+	rng := ethtest.NewPseudoRand(7552)
+	var (
+		sut    = rng.Address()
+		dest   = rng.Address()
+		caller = vm.AccountRef(rng.Address())
+		slot   = common.Hash{}
+		one    = common.BytesToHash([]byte{1})
+	)
+
+	// Input to the precompile selects the type of outgoing call.
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			sut: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				switch string(input) {
+				case "static":
+					return env.StaticCall(dest, nil, env.Gas())
+				case "delegate":
+					return env.DelegateCall(dest, nil, env.Gas())
+				case "proxied":
+					return env.StaticCall(dest, nil, env.Gas(), vm.WithUNSAFECallerAddressProxying())
+				}
+				return nil, fmt.Errorf("unknown input %q", input)
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	sdb, evm := ethtest.NewZeroEVM(t)
+	// SSTORE(0, 1); return CALLER
+	sdb.SetCode(dest, []byte{
+		byte(vm.PUSH1), 1, byte(vm.PUSH1), 0, byte(vm.SSTORE),
+		byte(vm.CALLER), byte(vm.PUSH1), 0, byte(vm.MSTORE),
+		byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN),
+	})
+
+	call := func(input string) ([]byte, error) {
+		ret, _, err := evm.Call(caller, sut, []byte(input), 1e6, uint256.NewInt(0))
+		return ret, err
+	}
+
+	t.Run("static", func(t *testing.T) {
+		_, err := call("static")
+		require.ErrorIs(t, err, vm.ErrWriteProtection, "SSTORE via env.StaticCall()")
+		assert.Zero(t, sdb.GetState(dest, slot), "callee storage")
+		assert.Zero(t, sdb.GetState(sut, slot), "precompile storage")
+	})
+
+	t.Run("delegate", func(t *testing.T) {
+		got, err := call("delegate")
+		require.NoError(t, err, "env.DelegateCall()")
+		assert.Equal(t, common.BytesToHash(caller.Address().Bytes()).Bytes(), got, "CALLER inherited from precompile")
+		assert.Zero(t, sdb.GetState(dest, slot), "callee storage")
+		assert.Equal(t, one, sdb.GetState(sut, slot), "precompile storage")
+	})
+
+	t.Run("unsupported_proxying", func(t *testing.T) {
+		_, err := call("proxied")
+		require.Error(t, err, "env.StaticCall() with caller-address proxying")
+	})
+}
+
+func TestPrecompileCallGasForwarding(t *testing.T) {
+	rng := ethtest.NewPseudoRand(756)
+	var (
+		sut    = rng.Address()
+		dest   = rng.Address()
+		caller = vm.AccountRef(rng.Address())
+	)
+
+	const (
+		available = 1e6
+		// Gas consumed by `dest`: GAS, PUSH1, MSTORE, memory expansion, PUSH1,
+		// PUSH1, and RETURN (no further expansion).
+		destGas = 2 + 3 + 3 + 3 + 3 + 3 + 0
+	)
+
+	var (
+		request    uint64
+		opts       []vm.CallOption
+		calleeGas  uint64
+		precompGas uint64
+	)
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			sut: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				before := env.Gas()
+				ret, err := env.Call(dest, nil, request, uint256.NewInt(0), opts...)
+				if err != nil {
+					return nil, err
+				}
+				calleeGas = new(uint256.Int).SetBytes(ret).Uint64()
+				precompGas = before - env.Gas()
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	sdb, evm := ethtest.NewZeroEVM(t)
+	// return GAS
+	sdb.SetCode(dest, []byte{
+		byte(vm.GAS), byte(vm.PUSH1), 0, byte(vm.MSTORE),
+		byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN),
+	})
+
+	tests := []struct {
+		name          string
+		request       uint64
+		opts          []vm.CallOption
+		wantForwarded uint64
+		wantErr       error
+	}{
+		{
+			name:          "default",
+			request:       1000,
+			wantForwarded: 1000,
+		},
+		{
+			name:          "exact",
+			request:       1000,
+			opts:          []vm.CallOption{vm.WithExactGas()},
+			wantForwarded: 1000,
+		},
+		{
+			name:    "exact_insufficient",
+			request: 2 * available,
+			opts:    []vm.CallOption{vm.WithExactGas()},
+			wantErr: vm.ErrOutOfGas,
+		},
+		{
+			name:          "eip150_below_cap",
+			request:       1000,
+			opts:          []vm.CallOption{vm.WithEIP150GasRetention()},
+			wantForwarded: 1000,
+		},
+		{
+			name:          "eip150_capped",
+			request:       2 * available,
+			opts:          []vm.CallOption{vm.WithEIP150GasRetention()},
+			wantForwarded: available - available/64,
+		},
+		{
+			name:          "all_remaining",
+			opts:          []vm.CallOption{vm.WithAllRemainingGas()},
+			wantForwarded: available,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request = tt.request
+			opts = tt.opts
+			_, _, err := evm.Call(caller, sut, nil, available, uint256.NewInt(0))
+			require.ErrorIs(t, err, tt.wantErr, "evm.Call([precompile])")
+			if tt.wantErr != nil {
+				return
+			}
+			assert.Equal(t, tt.wantForwarded-2, calleeGas, "gas available to callee after GAS op code")
+			assert.Equal(t, uint64(destGas), precompGas, "gas consumed by precompile after refund of leftover")
+		})
+	}
+}
+
+func TestPrecompileCallGasSponsorship(t *testing.T) {
+	rng := ethtest.NewPseudoRand(7602)
+	var (
+		sut    = rng.Address()
+		dest   = rng.Address()
+		caller = vm.AccountRef(rng.Address())
+	)
+
+	const (
+		available = 1e6
+		request   = 1000
+		// Gas consumed by `dest`: GAS, PUSH1, MSTORE, memory expansion, PUSH1,
+		// PUSH1, and RETURN (no further expansion).
+		destGas = 2 + 3 + 3 + 3 + 3 + 3 + 0
+	)
+
+	var (
+		precharge, sponsor uint64
+		calls              int
+		calleeGas          uint64
+	)
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			sut: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				if !env.UseGas(precharge) {
+					return nil, vm.ErrOutOfGas
+				}
+				for range calls {
+					ret, err := env.Call(dest, nil, request, uint256.NewInt(0), vm.WithGasSponsorship(sponsor))
+					if err != nil {
+						return nil, err
+					}
+					calleeGas = new(uint256.Int).SetBytes(ret).Uint64()
+				}
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	sdb, evm := ethtest.NewZeroEVM(t)
+	// return GAS
+	sdb.SetCode(dest, []byte{
+		byte(vm.GAS), byte(vm.PUSH1), 0, byte(vm.MSTORE),
+		byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN),
+	})
+
+	tests := []struct {
+		name               string
+		precharge, sponsor uint64
+		calls              int
+		wantUsed           uint64
+		wantErr            error
+	}{
+		{
+			name:      "no_sponsorship",
+			precharge: 5000,
+			calls:     1,
+			wantUsed:  5000 + destGas,
+		},
+		{
+			name:      "fully_sponsored",
+			precharge: 5000,
+			sponsor:   request,
+			calls:     1,
+			wantUsed:  5000 - request + destGas,
+		},
+		{
+			name:      "partially_sponsored",
+			precharge: 5000,
+			sponsor:   400,
+			calls:     1,
+			wantUsed:  5000 - 400 + destGas,
+		},
+		{
+			name:      "sponsorship_capped_at_forwarded_gas",
+			precharge: 5000,
+			sponsor:   3 * request,
+			calls:     1,
+			wantUsed:  5000 - request + destGas,
+		},
+		{
+			name:      "exactly_exhausted_budget",
+			precharge: 2 * request,
+			sponsor:   request,
+			calls:     2,
+			wantUsed:  2 * destGas,
+		},
+		{
+			name:      "exceeds_budget",
+			precharge: request - 1,
+			sponsor:   request,
+			calls:     1,
+			wantErr:   vm.ErrSponsorshipExceedsBudget,
+		},
+		{
+			name:      "budget_spent_by_earlier_call",
+			precharge: 3 * request / 2,
+			sponsor:   request,
+			calls:     2,
+			wantErr:   vm.ErrSponsorshipExceedsBudget,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			precharge, sponsor, calls = tt.precharge, tt.sponsor, tt.calls
+			calleeGas = 0
+
+			_, gasLeft, err := evm.Call(caller, sut, nil, available, uint256.NewInt(0))
+			require.ErrorIs(t, err, tt.wantErr, "evm.Call([precompile])")
+			if tt.wantErr != nil {
+				return
+			}
+			assert.Equal(t, uint64(request-2), calleeGas, "gas available to callee after GAS op code")
+			assert.Equal(t, tt.wantUsed, available-gasLeft, "gas used by precompile, including callee")
+		})
+	}
+}
+
+func TestPrecompileCallValueFromOwnBalance(t *testing.T) {
+	rng := ethtest.NewPseudoRand(757)
+	var (
+		sut      = rng.Address()
+		dest     = rng.Address()
+		reverter = rng.Address()
+		eoa      = rng.Address()
+	)
+
+	var (
+		to   common.Address
+		opts []vm.CallOption
+	)
+	const value = 10
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			sut: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				// Proxying the caller's address results in value being debited
+				// from the EOA by default.
+				callOpts := append([]vm.CallOption{vm.WithUNSAFECallerAddressProxying()}, opts...)
+				return env.Call(to, nil, env.Gas(), uint256.NewInt(value), callOpts...)
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	tests := []struct {
+		name              string
+		to                common.Address
+		opts              []vm.CallOption
+		precompileBalance uint64
+		wantErr           error
+		wantPrecompile    uint64
+		wantDest          uint64
+	}{
+		{
+			name:              "default_debits_caller",
+			to:                dest,
+			precompileBalance: 100,
+			wantErr:           vm.ErrInsufficientBalance,
+			wantPrecompile:    100,
+		},
+		{
+			name:              "from_precompile_balance",
+			to:                dest,
+			opts:              []vm.CallOption{vm.WithValueFromPrecompileBalance()},
+			precompileBalance: 100,
+			wantPrecompile:    90,
+			wantDest:          value,
+		},
+		{
+			name:              "insufficient_precompile_balance",
+			to:                dest,
+			opts:              []vm.CallOption{vm.WithValueFromPrecompileBalance()},
+			precompileBalance: value - 1,
+			wantErr:           vm.ErrInsufficientBalance,
+			wantPrecompile:    value - 1,
+		},
+		{
+			name:              "reverted_call",
+			to:                reverter,
+			opts:              []vm.CallOption{vm.WithValueFromPrecompileBalance()},
+			precompileBalance: 100,
+			wantErr:           vm.ErrExecutionReverted,
+			wantPrecompile:    100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// REVERT requires Byzantium.
+			header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(0)}
+			sdb, evm := ethtest.NewZeroEVM(
+				t,
+				ethtest.WithChainConfig(params.TestChainConfig),
+				ethtest.WithBlockContext(core.NewEVMBlockContext(header, nil, &common.Address{})),
+			)
+			sdb.SetBalance(sut, uint256.NewInt(tt.precompileBalance))
+			// PUSH1 0 PUSH1 0 REVERT
+			sdb.SetCode(reverter, []byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.REVERT)})
+
+			to, opts = tt.to, tt.opts
+			_, _, err := evm.Call(vm.AccountRef(eoa), sut, nil, 1e6, uint256.NewInt(0))
+			require.ErrorIs(t, err, tt.wantErr, "evm.Call([precompile])")
+
+			assert.Equal(t, uint256.NewInt(tt.wantPrecompile), sdb.GetBalance(sut), "precompile balance")
+			assert.Equal(t, uint256.NewInt(tt.wantDest), sdb.GetBalance(tt.to), "destination balance")
+			assert.True(t, sdb.GetBalance(eoa).IsZero(), "caller balance")
+		})
+	}
+}
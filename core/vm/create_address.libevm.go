@@ -0,0 +1,86 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/crypto"
+)
+
+// A ContractAddressDeriver MAY be implemented by a [params.RulesHooks] to
+// override the address at which contracts are deployed by CREATE, CREATE2, and
+// transactions without a recipient; e.g. to namespace contracts per caller or
+// to implement CREATE3-like schemes natively.
+//
+// The derived address is subject to the same collision checks as the default
+// one. Receipts populated by [core.ApplyTransaction] honour the hook but those
+// re-derived by [types.Receipts.DeriveFields] don't, so chains that override
+// the address of top-level deployments MUST correct the latter themselves.
+type ContractAddressDeriver interface {
+	DeriveContractAddress(ContractAddressArgs) common.Address
+}
+
+// ContractAddressArgs are the arguments passed to
+// [ContractAddressDeriver.DeriveContractAddress].
+type ContractAddressArgs struct {
+	OpCode OpCode // CREATE or CREATE2; the former for transactions
+	Caller common.Address
+	// Nonce is that of the caller, before being incremented by the creation.
+	Nonce uint64
+	// Salt is the CREATE2 salt and is zero for CREATE.
+	Salt         common.Hash
+	InitCodeHash common.Hash
+	// Default is the address that would be used in the absence of the hook.
+	Default common.Address
+}
+
+// CreateAddress returns the address of a contract deployed by `caller`, with
+// the specified nonce and init code, via CREATE or a transaction without a
+// recipient. It is equivalent to [crypto.CreateAddress] unless a
+// [ContractAddressDeriver] is registered.
+func (evm *EVM) CreateAddress(caller common.Address, nonce uint64, initCode []byte) common.Address {
+	return evm.deriveContractAddress(CREATE, caller, nonce, common.Hash{}, &codeAndHash{code: initCode})
+}
+
+// deriveContractAddress returns the address of a contract to be deployed with
+// the `typ` opcode, honouring any [ContractAddressDeriver]. The `nonce` is
+// ignored for CREATE2, in which case the caller's nonce, like the init-code
+// hash for CREATE, is only read if a deriver is registered.
+func (evm *EVM) deriveContractAddress(typ OpCode, caller common.Address, nonce uint64, salt common.Hash, c *codeAndHash) common.Address {
+	var def common.Address
+	if typ == CREATE2 {
+		def = crypto.CreateAddress2(caller, salt, c.Hash().Bytes())
+	} else {
+		def = crypto.CreateAddress(caller, nonce)
+	}
+
+	d, ok := evm.chainRules.Hooks().(ContractAddressDeriver)
+	if !ok {
+		return def
+	}
+	if typ == CREATE2 {
+		nonce = evm.StateDB.GetNonce(caller)
+	}
+	return d.DeriveContractAddress(ContractAddressArgs{
+		OpCode:       typ,
+		Caller:       caller,
+		Nonce:        nonce,
+		Salt:         salt,
+		InitCodeHash: c.Hash(),
+		Default:      def,
+	})
+}
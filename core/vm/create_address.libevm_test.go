@@ -0,0 +1,113 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+type contractAddressHooks struct {
+	hookstest.Stub
+	got []vm.ContractAddressArgs
+}
+
+// DeriveContractAddress namespaces contracts by the caller's first byte,
+// otherwise retaining the default address.
+func (h *contractAddressHooks) DeriveContractAddress(a vm.ContractAddressArgs) common.Address {
+	h.got = append(h.got, a)
+	addr := a.Default
+	addr[0] = a.Caller[0]
+	return addr
+}
+
+func TestContractAddressDeriver(t *testing.T) {
+	hooks := &contractAddressHooks{}
+	hookstest.Register(t, params.Extras[*contractAddressHooks, *contractAddressHooks]{
+		NewRules: func(*params.ChainConfig, *params.Rules, *contractAddressHooks, *big.Int, bool, uint64) *contractAddressHooks {
+			return hooks
+		},
+	})
+
+	const (
+		gasLimit = 1e6
+		nonce    = 42
+	)
+	var (
+		caller   = common.Address{'c', 'a', 'l', 'l', 'e', 'r'}
+		initCode = []byte{byte(vm.STOP)}
+		salt     = uint256.NewInt(0xdeadbeef)
+	)
+
+	sdb, evm := ethtest.NewZeroEVM(t)
+	sdb.SetNonce(caller, nonce)
+
+	namespaced := func(a common.Address) common.Address {
+		a[0] = caller[0]
+		return a
+	}
+	defaultCreate := crypto.CreateAddress(caller, nonce)
+	defaultCreate2 := crypto.CreateAddress2(caller, salt.Bytes32(), crypto.Keccak256(initCode))
+
+	assert.Equal(t, namespaced(defaultCreate), evm.CreateAddress(caller, nonce, initCode), "%T.CreateAddress()", evm)
+
+	_, gotCreate, _, err := evm.Create(vm.AccountRef(caller), initCode, gasLimit, uint256.NewInt(0))
+	require.NoError(t, err, "%T.Create()", evm)
+	assert.Equal(t, namespaced(defaultCreate), gotCreate, "%T.Create() address", evm)
+
+	_, gotCreate2, _, err := evm.Create2(vm.AccountRef(caller), initCode, gasLimit, uint256.NewInt(0), salt)
+	require.NoError(t, err, "%T.Create2()", evm)
+	assert.Equal(t, namespaced(defaultCreate2), gotCreate2, "%T.Create2() address", evm)
+
+	codeHash := crypto.Keccak256Hash(initCode)
+	want := []vm.ContractAddressArgs{
+		{
+			OpCode:       vm.CREATE,
+			Caller:       caller,
+			Nonce:        nonce,
+			InitCodeHash: codeHash,
+			Default:      defaultCreate,
+		},
+		{
+			OpCode:       vm.CREATE,
+			Caller:       caller,
+			Nonce:        nonce,
+			InitCodeHash: codeHash,
+			Default:      defaultCreate,
+		},
+		{
+			OpCode:       vm.CREATE2,
+			Caller:       caller,
+			Nonce:        nonce + 1, // incremented by Create()
+			Salt:         salt.Bytes32(),
+			InitCodeHash: codeHash,
+			Default:      defaultCreate2,
+		},
+	}
+	assert.Equal(t, want, hooks.got, "arguments received by %T.DeriveContractAddress()", hooks)
+}
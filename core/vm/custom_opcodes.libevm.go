@@ -0,0 +1,116 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ava-labs/libevm/common/math"
+	"github.com/ava-labs/libevm/log"
+)
+
+// A CustomOpCodeRegistrar MAY be implemented by a [params.RulesHooks] to define
+// chain-specific instructions without forking the interpreter. The returned
+// opcodes are merged into the jump table of every [EVMInterpreter] constructed
+// under the respective rules.
+type CustomOpCodeRegistrar interface {
+	CustomOpCodes() []CustomOpCode
+}
+
+// A CustomOpCode defines an instruction at an otherwise undefined opcode.
+type CustomOpCode struct {
+	OpCode OpCode
+	// Execute is called with `pc` pointing at the instruction, which the
+	// interpreter increments after Execute returns; an instruction with
+	// immediate arguments MUST therefore advance `pc` past all but the last of
+	// them. A non-nil error halts the calling frame as if returned by any other
+	// instruction. Stack modifications can be made via [MutableStack].
+	Execute func(pc *uint64, evm *EVM, scope *ScopeContext) ([]byte, error)
+	// Pops and Pushes are the number of words, respectively, consumed from and
+	// added to the stack, which is validated before any gas is charged.
+	Pops, Pushes int
+	// ConstantGas is charged before DynamicGas, which MAY be nil.
+	ConstantGas uint64
+	DynamicGas  func(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error)
+	// MemorySize, if non-nil, returns the size of the memory required by the
+	// instruction, which is expanded before Execute is called. The cost of
+	// expansion is charged automatically, in addition to DynamicGas, and the
+	// latter receives the size rounded up to a whole number of words.
+	MemorySize func(*Stack) (size uint64, overflow bool)
+}
+
+func (evm *EVM) customOpCodes() []CustomOpCode {
+	r, ok := evm.chainRules.Hooks().(CustomOpCodeRegistrar)
+	if !ok {
+		return nil
+	}
+	return r.CustomOpCodes()
+}
+
+// enableCustomOpCodes modifies the table in place. Opcodes that are already
+// defined, including by [PrecompileOpCodeAlias], are logged and ignored, in
+// keeping with the treatment of unsupported EIPs.
+func (evm *EVM) enableCustomOpCodes(table *JumpTable, ops []CustomOpCode) {
+	for _, o := range ops {
+		if op := table[o.OpCode]; o.OpCode == STOP || op.HasCost() || o.Execute == nil || o.Pops < 0 || o.Pushes < 0 {
+			log.Error(
+				"Invalid custom opcode via libevm hook",
+				"opcode", o.OpCode,
+				"hooks", log.TypeOf(evm.chainRules.Hooks()),
+				"nil execute", o.Execute == nil,
+				"pops", o.Pops,
+				"pushes", o.Pushes,
+			)
+			continue
+		}
+		table[o.OpCode] = o.operation()
+	}
+}
+
+func (o CustomOpCode) operation() *operation {
+	op := &operation{
+		execute: func(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+			return o.Execute(pc, interpreter.evm, scope)
+		},
+		constantGas: o.ConstantGas,
+		minStack:    minStack(o.Pops, o.Pushes),
+		maxStack:    maxStack(o.Pops, o.Pushes),
+		memorySize:  o.MemorySize,
+	}
+	if o.DynamicGas != nil {
+		op.dynamicGas = gasFunc(o.DynamicGas)
+	}
+	if o.MemorySize == nil {
+		return op
+	}
+
+	// The interpreter only considers memory size when there is a dynamic-gas
+	// function, which is therefore always required to charge for expansion.
+	op.dynamicGas = func(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
+		gas, err := memoryGasCost(mem, memorySize)
+		if err != nil || o.DynamicGas == nil {
+			return gas, err
+		}
+		dyn, err := o.DynamicGas(evm, contract, stack, mem, memorySize)
+		if err != nil {
+			return 0, err
+		}
+		if gas, overflow := math.SafeAdd(gas, dyn); !overflow {
+			return gas, nil
+		}
+		return 0, ErrGasUintOverflow
+	}
+	return op
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+type customOpCodeHooks struct {
+	hookstest.Stub
+	ops []vm.CustomOpCode
+}
+
+var _ vm.CustomOpCodeRegistrar = (*customOpCodeHooks)(nil)
+
+func (h *customOpCodeHooks) CustomOpCodes() []vm.CustomOpCode {
+	return h.ops
+}
+
+func TestCustomOpCodes(t *testing.T) {
+	const (
+		mulAdd   = vm.OpCode(0x0c) // undefined in all forks
+		store    = vm.OpCode(0x0d) // undefined in all forks
+		mulGas   = 42
+		gasLimit = 1e6
+	)
+	contract := common.Address{'c', 'o', 'd', 'e'}
+
+	hooks := &customOpCodeHooks{}
+	hookstest.Register(t, params.Extras[*customOpCodeHooks, *customOpCodeHooks]{
+		NewRules: func(*params.ChainConfig, *params.Rules, *customOpCodeHooks, *big.Int, bool, uint64) *customOpCodeHooks {
+			return hooks
+		},
+	})
+
+	// a*2 + b
+	mulAddOp := vm.CustomOpCode{
+		OpCode: mulAdd,
+		Execute: func(_ *uint64, _ *vm.EVM, scope *vm.ScopeContext) ([]byte, error) {
+			s := vm.MutableStack{Stack: scope.Stack}
+			a, b := s.Pop(), s.Pop()
+			a.Lsh(&a, 1)
+			s.Push(a.Add(&a, &b))
+			return nil, nil
+		},
+		Pops:        2,
+		Pushes:      1,
+		ConstantGas: mulGas,
+	}
+	// mstore(offset, value), equivalent to MSTORE
+	storeOp := vm.CustomOpCode{
+		OpCode: store,
+		Execute: func(_ *uint64, _ *vm.EVM, scope *vm.ScopeContext) ([]byte, error) {
+			s := vm.MutableStack{Stack: scope.Stack}
+			off, val := s.Pop(), s.Pop()
+			scope.Memory.Set32(off.Uint64(), &val)
+			return nil, nil
+		},
+		Pops: 2,
+		MemorySize: func(s *vm.Stack) (uint64, bool) {
+			off := s.Back(0)
+			if !off.IsUint64() || off.Uint64() > 1<<32 {
+				return 0, true
+			}
+			return off.Uint64() + 32, false
+		},
+	}
+
+	returnWordAt := func(offset byte) []vm.OpCode {
+		return []vm.OpCode{vm.PUSH1, 32, vm.PUSH1, vm.OpCode(offset), vm.RETURN}
+	}
+	mulAddCode := func(op vm.OpCode) []byte {
+		code := []vm.OpCode{
+			vm.PUSH1, 3,
+			vm.PUSH1, 20,
+			op,
+			vm.PUSH1, 0,
+			vm.MSTORE,
+		}
+		return convertBytes[vm.OpCode, byte](append(code, returnWordAt(0)...)...)
+	}
+	storeCode := convertBytes[vm.OpCode, byte](append([]vm.OpCode{
+		vm.PUSH1, 99,
+		vm.PUSH1, 64,
+		store,
+	}, returnWordAt(64)...)...)
+
+	call := func(t *testing.T, code []byte) ([]byte, uint64, error) {
+		t.Helper()
+		sdb, evm := ethtest.NewZeroEVM(t)
+		sdb.SetCode(contract, code)
+		ret, gasLeft, err := evm.Call(vm.AccountRef{}, contract, nil, gasLimit, uint256.NewInt(0))
+		return ret, gasLimit - gasLeft, err
+	}
+
+	t.Run("unregistered", func(t *testing.T) {
+		hooks.ops = nil
+		_, _, err := call(t, mulAddCode(mulAdd))
+		require.IsType(t, &vm.ErrInvalidOpCode{}, err, "%T.Call() error", &vm.EVM{})
+	})
+
+	t.Run("redefinition_ignored", func(t *testing.T) {
+		redefined := mulAddOp
+		redefined.OpCode = vm.ADD
+		hooks.ops = []vm.CustomOpCode{redefined}
+
+		got, _, err := call(t, mulAddCode(vm.ADD))
+		require.NoError(t, err, "%T.Call()", &vm.EVM{})
+		assert.Equal(t, uint256.NewInt(23).PaddedBytes(32), got, "ADD unchanged")
+	})
+
+	hooks.ops = []vm.CustomOpCode{mulAddOp, storeOp}
+
+	t.Run("execution_and_gas", func(t *testing.T) {
+		got, gasUsed, err := call(t, mulAddCode(mulAdd))
+		require.NoError(t, err, "%T.Call()", &vm.EVM{})
+		assert.Equal(t, uint256.NewInt(43).PaddedBytes(32), got, "20*2 + 3")
+
+		_, addGasUsed, err := call(t, mulAddCode(vm.ADD))
+		require.NoError(t, err, "%T.Call()", &vm.EVM{})
+		assert.Equal(t, addGasUsed-vm.GasFastestStep+mulGas, gasUsed, "gas used relative to replacing with ADD")
+	})
+
+	t.Run("memory_expansion", func(t *testing.T) {
+		got, gasUsed, err := call(t, storeCode)
+		require.NoError(t, err, "%T.Call()", &vm.EVM{})
+		assert.Equal(t, uint256.NewInt(99).PaddedBytes(32), got, "stored word")
+
+		// 2*PUSH1 + 2*PUSH1 + RETURN (no further expansion), with the custom
+		// op only charging for expanding memory to 3 words.
+		const want = 4*vm.GasFastestStep + 3*params.MemoryGas
+		assert.Equal(t, uint64(want), gasUsed, "gas used")
+	})
+
+	t.Run("stack_underflow", func(t *testing.T) {
+		_, _, err := call(t, []byte{byte(mulAdd)})
+		require.IsType(t, &vm.ErrStackUnderflow{}, err, "%T.Call() error", &vm.EVM{})
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+// OnCommit schedules `fn` to be run by [EVM.RunCommitActions] iff neither the
+// precompile call nor any surrounding call reverts. See
+// [PrecompileEnvironment.OnCommit].
+func (e *environment) OnCommit(fn func()) error {
+	return e.AppendJournalEntry(&commitAction{evm: e.evm, fn: fn})
+}
+
+// OnRevert schedules `fn` to be run if the precompile call, or any surrounding
+// call, reverts. See [PrecompileEnvironment.OnRevert].
+func (e *environment) OnRevert(fn func()) error {
+	return e.AppendJournalEntry(revertAction(fn))
+}
+
+// A commitAction is a journal entry that enqueues a function for running by
+// [EVM.RunCommitActions], and dequeues it if reverted.
+type commitAction struct {
+	evm *EVM
+	fn  func()
+	idx int
+}
+
+func (a *commitAction) Apply() {
+	a.idx = len(a.evm.commitActions)
+	a.evm.commitActions = append(a.evm.commitActions, a.fn)
+}
+
+func (a *commitAction) Revert() {
+	// Actions are discarded when the transaction ends, which can happen before
+	// the journal is reverted (e.g. by execution invalidation).
+	if a.idx < len(a.evm.commitActions) {
+		a.evm.commitActions = a.evm.commitActions[:a.idx]
+	}
+}
+
+// OnTxEnd schedules `fn` to be run by [EVM.RunTxEndActions] iff neither the
+// precompile call nor any surrounding call reverts. See
+// [PrecompileEnvironment.OnTxEnd].
+func (e *environment) OnTxEnd(fn func(StateDB)) error {
+	return e.AppendJournalEntry(&txEndAction{evm: e.evm, fn: fn})
+}
+
+// A txEndAction is a journal entry that enqueues a function for running by
+// [EVM.RunTxEndActions], and dequeues it if reverted.
+type txEndAction struct {
+	evm *EVM
+	fn  func(StateDB)
+	idx int
+}
+
+func (a *txEndAction) Apply() {
+	a.idx = len(a.evm.txEndActions)
+	a.evm.txEndActions = append(a.evm.txEndActions, a.fn)
+}
+
+func (a *txEndAction) Revert() {
+	// See [commitAction.Revert] re the bounds check.
+	if a.idx < len(a.evm.txEndActions) {
+		a.evm.txEndActions = a.evm.txEndActions[:a.idx]
+	}
+}
+
+// A revertAction is a journal entry that runs the function only if reverted.
+type revertAction func()
+
+func (revertAction) Apply()    {}
+func (a revertAction) Revert() { a() }
+
+// RunCommitActions runs, in order of registration, all functions passed to
+// [PrecompileEnvironment.OnCommit] during the current transaction that weren't
+// subsequently reverted, after which it discards them. It is called by
+// core.ApplyMessage() after successful execution; other users of the [EVM]
+// MUST call it once the outcome of the transaction is final and successful,
+// otherwise calling [EVM.DiscardCommitActions].
+func (evm *EVM) RunCommitActions() {
+	actions := evm.commitActions
+	evm.commitActions = nil
+	for _, fn := range actions {
+		fn()
+	}
+}
+
+// DiscardCommitActions discards, without running, all functions pending
+// [EVM.RunCommitActions]. It is also implicitly called by [EVM.Reset].
+func (evm *EVM) DiscardCommitActions() {
+	evm.commitActions = nil
+}
+
+// RunTxEndActions runs, in order of registration, all functions passed to
+// [PrecompileEnvironment.OnTxEnd] during the current transaction that weren't
+// subsequently reverted, after which it discards them. Each function receives
+// the EVM's [StateDB], any changes to which are included in the transaction.
+// It is called by core.ApplyMessage() once all call frames have returned, iff
+// execution wasn't invalidated, and before [EVM.RunCommitActions]; other users
+// of the [EVM] MUST do the same, otherwise calling [EVM.DiscardTxEndActions].
+func (evm *EVM) RunTxEndActions() {
+	actions := evm.txEndActions
+	evm.txEndActions = nil
+	for _, fn := range actions {
+		fn(evm.StateDB)
+	}
+}
+
+// DiscardTxEndActions discards, without running, all functions pending
+// [EVM.RunTxEndActions]. It is also implicitly called by [EVM.Reset].
+func (evm *EVM) DiscardTxEndActions() {
+	evm.txEndActions = nil
+}
@@ -272,6 +272,9 @@ func opMcopy(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]by
 
 // opBlobHash implements the BLOBHASH opcode
 func opBlobHash(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	if h, ok := interpreter.evm.blobOpCodeHooks(); ok { // libevm
+		return opBlobHashHook(h, interpreter, scope)
+	}
 	index := scope.Stack.peek()
 	if index.LtUint64(uint64(len(interpreter.evm.TxContext.BlobHashes))) {
 		blobHash := interpreter.evm.TxContext.BlobHashes[index.Uint64()]
@@ -284,6 +287,9 @@ func opBlobHash(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([
 
 // opBlobBaseFee implements BLOBBASEFEE opcode
 func opBlobBaseFee(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	if h, ok := interpreter.evm.blobOpCodeHooks(); ok { // libevm
+		return opBlobBaseFeeHook(h, interpreter, scope)
+	}
 	blobBaseFee, _ := uint256.FromBig(interpreter.evm.Context.BlobBaseFee)
 	scope.Stack.push(blobBaseFee)
 	return nil, nil
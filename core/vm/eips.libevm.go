@@ -0,0 +1,37 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ava-labs/libevm/log"
+)
+
+// enableRuleEIPs enables the EIPs returned by [params.Rules.ActiveEIPs],
+// modifying the table in place. Unsupported EIPs are logged and ignored, in
+// keeping with the treatment of [Config.ExtraEips].
+func (evm *EVM) enableRuleEIPs(table *JumpTable, eips []int) {
+	for _, eip := range eips {
+		if err := EnableEIP(eip, table); err != nil {
+			log.Error(
+				"EIP activation via libevm hook failed",
+				"eip", eip,
+				"hooks", log.TypeOf(evm.chainRules.Hooks()),
+				"error", err,
+			)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestActiveEIPsHook(t *testing.T) {
+	// The zero-value chain config used by [ethtest.NewZeroEVM] results in the
+	// Frontier jump table, which doesn't include transient storage.
+	code := convertBytes[vm.OpCode, byte](
+		vm.PUSH1, 0,
+		vm.TLOAD,
+		vm.STOP,
+	)
+	contract := common.Address{'c', 'o', 'd', 'e'}
+
+	tests := []struct {
+		name    string
+		eips    []int
+		wantErr bool
+	}{
+		{
+			name:    "no_extra_eips",
+			wantErr: true,
+		},
+		{
+			name: "eip_1153",
+			eips: []int{1153},
+		},
+		{
+			name: "unsupported_eip_ignored",
+			eips: []int{1153, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks := &hookstest.Stub{
+				ActiveEIPsFn: func() []int { return tt.eips },
+			}
+			hooks.Register(t)
+
+			sdb, evm := ethtest.NewZeroEVM(t)
+			sdb.SetCode(contract, code)
+			_, _, err := evm.Call(vm.AccountRef{}, contract, nil, 1e6, uint256.NewInt(0))
+			if tt.wantErr {
+				require.IsType(t, &vm.ErrInvalidOpCode{}, err, "%T.Call() error", evm)
+				return
+			}
+			require.NoError(t, err, "%T.Call()", evm)
+		})
+	}
+}
+
+// blobOpCodeHooks return fixed values, or an error if non-nil.
+type blobOpCodeHooks struct {
+	hookstest.Stub
+	hash common.Hash
+	fee  *uint256.Int
+	err  error
+}
+
+var _ vm.BlobOpCodeHooks = (*blobOpCodeHooks)(nil)
+
+func (h *blobOpCodeHooks) BlobHash([]common.Hash, *uint256.Int) (common.Hash, error) {
+	return h.hash, h.err
+}
+
+func (h *blobOpCodeHooks) BlobBaseFee(*types.Header) (*uint256.Int, error) {
+	return h.fee, h.err
+}
+
+func TestBlobOpCodeHooks(t *testing.T) {
+	code := convertBytes[vm.OpCode, byte](
+		vm.PUSH1, 0,
+		vm.BLOBHASH,
+		vm.PUSH1, 0,
+		vm.MSTORE,
+		vm.BLOBBASEFEE,
+		vm.PUSH1, 32,
+		vm.MSTORE,
+		vm.PUSH1, 64,
+		vm.PUSH1, 0,
+		vm.RETURN,
+	)
+	contract := common.Address{'c', 'o', 'd', 'e'}
+	hash := common.Hash{'b', 'l', 'o', 'b'}
+	fee := uint256.NewInt(42)
+
+	tests := []struct {
+		name        string
+		hooks       *blobOpCodeHooks
+		want        []byte
+		wantErr     error
+		wantGasLeft bool
+	}{
+		{
+			name:        "custom_source",
+			hooks:       &blobOpCodeHooks{hash: hash, fee: fee},
+			want:        append(hash.Bytes(), fee.PaddedBytes(32)...),
+			wantGasLeft: true,
+		},
+		{
+			name:        "zero",
+			hooks:       &blobOpCodeHooks{},
+			want:        make([]byte, 64),
+			wantGasLeft: true,
+		},
+		{
+			name:    "unsupported",
+			hooks:   &blobOpCodeHooks{err: vm.ErrBlobsUnsupported},
+			wantErr: vm.ErrBlobsUnsupported,
+		},
+		{
+			name:        "revert",
+			hooks:       &blobOpCodeHooks{err: vm.ErrExecutionReverted},
+			wantErr:     vm.ErrExecutionReverted,
+			wantGasLeft: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hookstest.Register(t, params.Extras[*blobOpCodeHooks, *blobOpCodeHooks]{
+				NewRules: func(*params.ChainConfig, *params.Rules, *blobOpCodeHooks, *big.Int, bool, uint64) *blobOpCodeHooks {
+					return tt.hooks
+				},
+			})
+
+			header := &types.Header{
+				Number:     big.NewInt(1),
+				Difficulty: big.NewInt(0), // post-merge
+			}
+			sdb, evm := ethtest.NewZeroEVM(
+				t,
+				ethtest.WithChainConfig(newMergedChainConfig()),
+				ethtest.WithBlockContext(core.NewEVMBlockContext(header, nil, &common.Address{})),
+			)
+			sdb.SetCode(contract, code)
+
+			got, gasLeft, err := evm.Call(vm.AccountRef{}, contract, nil, 1e6, uint256.NewInt(0))
+			require.ErrorIs(t, err, tt.wantErr, "%T.Call()", evm)
+			assert.Equal(t, tt.wantGasLeft, gasLeft > 0, "gas remaining")
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.want, got, "BLOBHASH and BLOBBASEFEE")
+			}
+		})
+	}
+}
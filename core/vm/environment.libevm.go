@@ -1,4 +1,4 @@
-// Copyright 2024 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -19,6 +19,7 @@ package vm
 import (
 	"fmt"
 	"math/big"
+	"slices"
 
 	"github.com/holiman/uint256"
 
@@ -33,16 +34,24 @@ import (
 var _ PrecompileEnvironment = (*environment)(nil)
 
 type environment struct {
-	evm      *EVM
-	self     *Contract
+	evm *EVM
+	// self is held by value, instead of as a pointer, so the environment and
+	// the contract can be allocated together.
+	self     Contract
 	callType CallType
 
 	rawSelf, rawCaller common.Address
+	rawCallerCode      common.Address // see [CallFraming.CallerCodeAddress]
+	// snapshots are the StateDB revision IDs returned by Snapshot(), which
+	// are the only ones that RevertToSnapshot() accepts.
+	snapshots []int
+	// sponsorable is the gas consumed via UseGas() that hasn't yet been spent
+	// on sponsoring calls; see [WithGasSponsorship].
+	sponsorable uint64
 }
 
-func (e *environment) Gas() uint64            { return e.self.Gas }
-func (e *environment) UseGas(gas uint64) bool { return e.self.UseGas(gas) }
-func (e *environment) Value() *uint256.Int    { return new(uint256.Int).Set(e.self.Value()) }
+func (e *environment) Gas() uint64         { return e.self.Gas }
+func (e *environment) Value() *uint256.Int { return new(uint256.Int).Set(e.self.Value()) }
 
 func (e *environment) ChainConfig() *params.ChainConfig  { return e.evm.chainConfig }
 func (e *environment) Rules() params.Rules               { return e.evm.chainRules }
@@ -50,8 +59,89 @@ func (e *environment) ReadOnlyState() libevm.StateReader { return e.evm.StateDB
 func (e *environment) IncomingCallType() CallType        { return e.callType }
 func (e *environment) BlockNumber() *big.Int             { return new(big.Int).Set(e.evm.Context.BlockNumber) }
 func (e *environment) BlockTime() uint64                 { return e.evm.Context.Time }
+func (e *environment) Coinbase() common.Address          { return e.evm.Context.Coinbase }
+func (e *environment) BlockGasLimit() uint64             { return e.evm.Context.GasLimit }
+func (e *environment) BaseFee() *big.Int                 { return copyBigOrZero(e.evm.Context.BaseFee) }
+func (e *environment) BlobBaseFee() *big.Int             { return copyBigOrZero(e.evm.Context.BlobBaseFee) }
 
-func (e *environment) InvalidateExecution(err error) { e.evm.InvalidateExecution(err) }
+// TxContext returns a deep copy so precompiles can't modify the [EVM]'s
+// context.
+func (e *environment) TxContext() TxContext {
+	tx := e.evm.TxContext
+	tx.GasPrice = copyBig(tx.GasPrice)
+	tx.BlobFeeCap = copyBig(tx.BlobFeeCap)
+	tx.BlobHashes = slices.Clone(tx.BlobHashes)
+	return tx
+}
+
+func copyBig(x *big.Int) *big.Int {
+	if x == nil {
+		return nil
+	}
+	return new(big.Int).Set(x)
+}
+
+func copyBigOrZero(x *big.Int) *big.Int {
+	if x == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(x)
+}
+
+func (e *environment) InvalidateExecution(err error) {
+	if err != nil {
+		err = &InvalidationError{
+			Precompile: e.rawSelf,
+			Cause:      err,
+		}
+	}
+	e.evm.InvalidateExecution(err)
+}
+
+// A JournalingStateDB is a [StateDB] that supports custom journal entries, as
+// required by [PrecompileEnvironment.AppendJournalEntry].
+type JournalingStateDB interface {
+	StateDB
+	AppendJournalEntry(libevm.JournalEntry)
+}
+
+func (e *environment) AppendJournalEntry(j libevm.JournalEntry) error {
+	if e.ReadOnly() {
+		return ErrWriteProtection
+	}
+	sdb, ok := e.evm.StateDB.(JournalingStateDB)
+	if !ok {
+		return fmt.Errorf("%T does not implement vm.JournalingStateDB", e.evm.StateDB)
+	}
+	sdb.AppendJournalEntry(j)
+	return nil
+}
+
+// A ProvingStateDB is a [StateDB] that can generate Merkle proofs, as required
+// by [PrecompileEnvironment.ProofOfStorage].
+type ProvingStateDB interface {
+	StateDB
+	ProofOfStorage(common.Address, []common.Hash) (*libevm.StorageProof, error)
+}
+
+func (e *environment) ProofOfStorage(addr common.Address, slots []common.Hash) (*libevm.StorageProof, error) {
+	sdb, ok := e.evm.StateDB.(ProvingStateDB)
+	if !ok {
+		return nil, fmt.Errorf("%T does not implement vm.ProvingStateDB", e.evm.StateDB)
+	}
+	return sdb.ProofOfStorage(addr, slots)
+}
+
+// UseGas additionally records the gas as available for sponsoring calls. It
+// MUST NOT be used by other methods of the environment, which consume gas on
+// behalf of callees and MUST use e.self.UseGas() instead.
+func (e *environment) UseGas(gas uint64) bool {
+	if !e.self.UseGas(gas) {
+		return false
+	}
+	e.sponsorable += gas
+	return true
+}
 
 func (e *environment) refundGas(add uint64) error {
 	gas, overflow := math.SafeAdd(e.self.Gas, add)
@@ -103,9 +193,74 @@ func (e *environment) Call(addr common.Address, input []byte, gas uint64, value
 	return e.callContract(Call, addr, input, gas, value, opts...)
 }
 
-func (e *environment) callContract(typ CallType, addr common.Address, input []byte, gas uint64, value *uint256.Int, opts ...CallOption) (retData []byte, retErr error) {
-	var caller ContractRef = e.self
-	if options.As[callConfig](opts...).unsafeCallerAddressProxying {
+func (e *environment) StaticCall(addr common.Address, input []byte, gas uint64, opts ...CallOption) ([]byte, error) {
+	return e.callContract(StaticCall, addr, input, gas, nil, opts...)
+}
+
+func (e *environment) DelegateCall(addr common.Address, input []byte, gas uint64, opts ...CallOption) ([]byte, error) {
+	return e.callContract(DelegateCall, addr, input, gas, nil, opts...)
+}
+
+func (e *environment) Create(code []byte, gas uint64, value *uint256.Int) ([]byte, common.Address, error) {
+	return e.createContract(code, gas, value, nil)
+}
+
+func (e *environment) Create2(code []byte, gas uint64, value *uint256.Int, salt *uint256.Int) ([]byte, common.Address, error) {
+	return e.createContract(code, gas, value, salt)
+}
+
+// createContract performs CREATE2 if salt is non-nil, otherwise CREATE.
+func (e *environment) createContract(code []byte, gas uint64, value *uint256.Int, salt *uint256.Int) ([]byte, common.Address, error) {
+	op := CREATE
+	if salt != nil {
+		op = CREATE2
+	}
+	if e.ReadOnly() {
+		return nil, common.Address{}, e.traceFailedCall(op, common.Address{}, code, gas, value, ErrWriteProtection)
+	}
+	if value == nil {
+		value = new(uint256.Int)
+	}
+	if err := e.untracedFailure(&e.self, value); err != nil {
+		return nil, common.Address{}, e.traceFailedCall(op, common.Address{}, code, gas, value, err)
+	}
+	if !e.self.UseGas(gas) {
+		return nil, common.Address{}, e.traceFailedCall(op, common.Address{}, code, gas, value, ErrOutOfGas)
+	}
+
+	// As with callContract(), tracing of creations that reach the respective
+	// [EVM] method, and depth accounting, are performed by it, as is the
+	// increment of the creator's nonce.
+	var (
+		ret       []byte
+		addr      common.Address
+		returnGas uint64
+		createErr error
+	)
+	if salt == nil {
+		ret, addr, returnGas, createErr = e.evm.Create(&e.self, code, gas, value)
+	} else {
+		ret, addr, returnGas, createErr = e.evm.Create2(&e.self, code, gas, value, salt)
+	}
+	if err := e.refundGas(returnGas); err != nil {
+		return nil, common.Address{}, err
+	}
+	return ret, addr, createErr
+}
+
+func (e *environment) callContract(typ CallType, addr common.Address, input []byte, gas uint64, value *uint256.Int, opts ...CallOption) ([]byte, error) {
+	cfg := options.As[callConfig](opts...)
+	gas = cfg.gasForwarding.forwardedGas(gas, e.Gas())
+
+	var caller ContractRef = &e.self
+	if cfg.unsafeCallerAddressProxying {
+		if typ != Call {
+			// STATICCALL and DELEGATECALL are newer than the precompiles that
+			// required proxying for backwards compatibility, and the latter
+			// requires the caller to be a *Contract.
+			err := fmt.Errorf("caller-address proxying unsupported for %v", typ)
+			return nil, e.traceFailedCall(typ.OpCode(), addr, input, gas, value, err)
+		}
 		// Note that, in addition to being unsafe, this breaks an EVM
 		// assumption that the caller ContractRef is always a *Contract.
 		caller = AccountRef(e.self.CallerAddress)
@@ -117,40 +272,118 @@ func (e *environment) callContract(typ CallType, addr common.Address, input []by
 	}
 
 	if e.ReadOnly() && value != nil && !value.IsZero() {
-		return nil, ErrWriteProtection
+		return nil, e.traceFailedCall(typ.OpCode(), addr, input, gas, value, ErrWriteProtection)
 	}
-	if !e.UseGas(gas) {
-		return nil, ErrOutOfGas
+	checkValue := value
+	if typ != Call || cfg.valueFromPrecompileBalance {
+		// Either there is no value or callFromOwnBalance() checks it.
+		checkValue = nil
 	}
-
-	if t := e.evm.Config.Tracer; t != nil {
-		var bigVal *big.Int
-		if value != nil {
-			bigVal = value.ToBig()
-		}
-		t.CaptureEnter(typ.OpCode(), caller.Address(), addr, input, gas, bigVal)
-
-		startGas := gas
-		defer func() {
-			t.CaptureEnd(retData, startGas-e.Gas(), retErr)
-		}()
+	if err := e.untracedFailure(caller, checkValue); err != nil {
+		return nil, e.traceFailedCall(typ.OpCode(), addr, input, gas, value, err)
 	}
+	sponsored := min(cfg.sponsoredGas, gas)
+	if sponsored > e.sponsorable {
+		err := fmt.Errorf("%w: %d > %d", ErrSponsorshipExceedsBudget, sponsored, e.sponsorable)
+		return nil, e.traceFailedCall(typ.OpCode(), addr, input, gas, value, err)
+	}
+	charged := gas - sponsored
+	if !e.self.UseGas(charged) {
+		return nil, e.traceFailedCall(typ.OpCode(), addr, input, gas, value, ErrOutOfGas)
+	}
+	e.sponsorable -= sponsored
 
+	// Tracing is performed by the respective [EVM] method, which treats the
+	// call as entering a new scope because the precompile itself already
+	// incremented the depth. Capturing here too would result in duplicate
+	// frames, so only calls that fail before reaching said method are traced
+	// here; see [environment.traceFailedCall].
+
+	var (
+		ret       []byte
+		returnGas uint64
+		callErr   error
+	)
 	switch typ {
 	case Call:
-		ret, returnGas, callErr := e.evm.Call(caller, addr, input, gas, value)
-		if err := e.refundGas(returnGas); err != nil {
-			return nil, err
+		if cfg.valueFromPrecompileBalance {
+			ret, returnGas, callErr = e.callFromOwnBalance(caller, addr, input, gas, value)
+		} else {
+			ret, returnGas, callErr = e.evm.Call(caller, addr, input, gas, value)
 		}
-		return ret, callErr
-	case CallCode, DelegateCall, StaticCall:
-		// TODO(arr4n): these cases should be very similar to CALL, hence the
-		// early abstraction, to signal to future maintainers. If implementing
-		// them, there's likely no need to honour the
-		// [callOptUNSAFECallerAddressProxy] because it's purely for backwards
-		// compatibility.
+	case StaticCall:
+		ret, returnGas, callErr = e.evm.StaticCall(caller, addr, input, gas)
+	case DelegateCall:
+		// The callee's code is run in the context of e.self, with its caller
+		// and value inherited, exactly as for the DELEGATECALL op code.
+		ret, returnGas, callErr = e.evm.DelegateCall(caller, addr, input, gas)
+	case CallCode:
+		// TODO(arr4n): this case should be very similar to the others, hence
+		// the early abstraction, to signal to future maintainers.
 		fallthrough
 	default:
-		return nil, fmt.Errorf("unimplemented precompile call type %v", typ)
+		e.sponsorable += sponsored
+		if err := e.refundGas(charged); err != nil {
+			return nil, err
+		}
+		err := fmt.Errorf("unimplemented precompile call type %v", typ)
+		return nil, e.traceFailedCall(typ.OpCode(), addr, input, gas, value, err)
+	}
+	if err := e.refundGas(returnGas); err != nil {
+		return nil, err
+	}
+	return ret, callErr
+}
+
+// untracedFailure returns the error, if any, that the [EVM] call and creation
+// methods would return, without consuming gas, before tracing the call. A nil
+// `value` is treated as zero.
+func (e *environment) untracedFailure(caller ContractRef, value *uint256.Int) error {
+	if e.evm.depth > int(params.CallCreateDepth) {
+		return ErrDepth
+	}
+	if value != nil && !value.IsZero() && !e.evm.Context.CanTransfer(e.evm.StateDB, caller.Address(), value) {
+		return ErrInsufficientBalance
+	}
+	return nil
+}
+
+// traceFailedCall reports, to the [Config.Tracer], a call or creation that
+// failed before reaching the respective [EVM] method, which would otherwise
+// have traced it. The frame is entered and immediately exited with the error,
+// without using any gas, so the precompile's call tree is complete even if it
+// swallows the error. The error is returned unchanged.
+func (e *environment) traceFailedCall(op OpCode, to common.Address, input []byte, gas uint64, value *uint256.Int, err error) error {
+	t := e.evm.Config.Tracer
+	if t == nil {
+		return err
+	}
+	t.CaptureEnter(op, e.self.Address(), to, input, gas, value.ToBig())
+	t.CaptureExit(nil, 0, err)
+	return err
+}
+
+// callFromOwnBalance is equivalent to [EVM.Call] except that `value` is debited
+// from the precompile's own account, which might differ from that of the
+// `caller`; e.g. if invoked via DELEGATECALL or if caller-address proxying is
+// used. In such cases the value is first transferred to the caller, which is
+// reverted if the call fails.
+func (e *environment) callFromOwnBalance(caller ContractRef, addr common.Address, input []byte, gas uint64, value *uint256.Int) ([]byte, uint64, error) {
+	from, via := e.rawSelf, caller.Address()
+	if value == nil || value.IsZero() || from == via {
+		return e.evm.Call(caller, addr, input, gas, value)
+	}
+
+	sdb := e.evm.StateDB
+	if !e.evm.Context.CanTransfer(sdb, from, value) {
+		return nil, gas, e.traceFailedCall(CALL, addr, input, gas, value, ErrInsufficientBalance)
+	}
+	snapshot := sdb.Snapshot()
+	e.evm.Context.Transfer(sdb, from, via, value)
+
+	ret, gasLeft, err := e.evm.Call(caller, addr, input, gas, value)
+	if err != nil {
+		sdb.RevertToSnapshot(snapshot)
 	}
+	return ret, gasLeft, err
 }
@@ -0,0 +1,127 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/libevm/accounts/abi"
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/libevm/register"
+	"github.com/ava-labs/libevm/libevm/testonly"
+)
+
+// RegisterPrecompileEvents registers the events emitted by the precompile at
+// the address, allowing tracers and debug APIs to decode its logs; see
+// [PrecompileEventOf]. It is expected to be called in an `init()` function and
+// MUST NOT be called after [register.Freeze]. Anonymous events can't be
+// identified by their logs so result in a panic, as does registering an event
+// more than once for the same address.
+func RegisterPrecompileEvents(addr common.Address, events ...abi.Event) {
+	err := register.Guard(func() error {
+		for _, ev := range events {
+			if ev.Anonymous {
+				return fmt.Errorf("anonymous event %q of precompile %v", ev.Name, addr)
+			}
+			if _, ok := precompileEvents[addr][ev.ID]; ok {
+				return fmt.Errorf("%w of event %s for precompile %v", register.ErrReRegistration, ev.Sig, addr)
+			}
+		}
+		if precompileEvents == nil {
+			precompileEvents = make(map[common.Address]map[common.Hash]abi.Event)
+		}
+		if precompileEvents[addr] == nil {
+			precompileEvents[addr] = make(map[common.Hash]abi.Event)
+		}
+		for _, ev := range events {
+			precompileEvents[addr][ev.ID] = ev
+		}
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// precompileEvents are those passed to [RegisterPrecompileEvents]. As with all
+// other registries, it is written to only during initialisation so can be read
+// without locking.
+var precompileEvents map[common.Address]map[common.Hash]abi.Event
+
+// TestOnlyClearRegisteredPrecompileEvents clears all events previously passed
+// to [RegisterPrecompileEvents]. It panics if called from a non-testing call
+// stack.
+func TestOnlyClearRegisteredPrecompileEvents() {
+	testonly.OrPanic(func() {
+		precompileEvents = nil
+	})
+}
+
+// PrecompileEventOf returns the event registered with
+// [RegisterPrecompileEvents] for the log's address, identified by its first
+// topic. The returned boolean is false if there is no such event.
+func PrecompileEventOf(l *types.Log) (abi.Event, bool) {
+	if len(l.Topics) == 0 {
+		return abi.Event{}, false
+	}
+	ev, ok := precompileEvents[l.Address][l.Topics[0]]
+	return ev, ok
+}
+
+// A RegisteredPrecompileEvent is an event passed to [RegisterPrecompileEvents].
+type RegisteredPrecompileEvent struct {
+	Precompile common.Address
+	Event      abi.Event
+}
+
+// RegisteredPrecompileEvents returns all events passed to
+// [RegisterPrecompileEvents], sorted by precompile address and then by event
+// signature.
+func RegisteredPrecompileEvents() []RegisteredPrecompileEvent {
+	var all []RegisteredPrecompileEvent
+	for addr, events := range precompileEvents {
+		for _, ev := range events {
+			all = append(all, RegisteredPrecompileEvent{addr, ev})
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if c := bytes.Compare(all[i].Precompile[:], all[j].Precompile[:]); c != 0 {
+			return c < 0
+		}
+		return all[i].Event.Sig < all[j].Event.Sig
+	})
+	return all
+}
+
+// A PrecompileLogTracer is an [EVMLogger] that is additionally informed of
+// logs emitted via [PrecompileEnvironment.AddLog], which, unlike those emitted
+// by LOG* op codes, are otherwise invisible to tracers. The depth is that
+// which would be passed to [EVMLogger.CaptureState] by an op code in the same
+// frame.
+type PrecompileLogTracer interface {
+	EVMLogger
+	CapturePrecompileLog(_ *types.Log, depth int)
+}
+
+func (e *environment) tracePrecompileLog(l *types.Log) {
+	if t, ok := e.evm.Config.Tracer.(PrecompileLogTracer); ok {
+		t.CapturePrecompileLog(l, e.evm.depth)
+	}
+}
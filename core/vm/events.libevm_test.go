@@ -0,0 +1,194 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/accounts/abi"
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/eth/tracers"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func precompileEventsABI(t *testing.T) abi.ABI {
+	t.Helper()
+	const eventsABI = `[` +
+		`{"type":"event","name":"Transfer","inputs":[` +
+		`{"name":"from","type":"address","indexed":true},` +
+		`{"name":"to","type":"address","indexed":true},` +
+		`{"name":"value","type":"uint256","indexed":false}]},` +
+		`{"type":"event","name":"Approval","inputs":[` +
+		`{"name":"owner","type":"address","indexed":true},` +
+		`{"name":"value","type":"uint256","indexed":false}]},` +
+		`{"type":"event","name":"Anon","anonymous":true,"inputs":[]}` +
+		`]`
+	parsed, err := abi.JSON(strings.NewReader(eventsABI))
+	require.NoError(t, err, "abi.JSON()")
+	return parsed
+}
+
+func TestPrecompileEventRegistry(t *testing.T) {
+	events := precompileEventsABI(t).Events
+	transfer, approval := events["Transfer"], events["Approval"]
+
+	vm.TestOnlyClearRegisteredPrecompileEvents()
+	t.Cleanup(vm.TestOnlyClearRegisteredPrecompileEvents)
+
+	a, b := common.Address{'a'}, common.Address{'b'}
+	vm.RegisterPrecompileEvents(b, transfer)
+	vm.RegisterPrecompileEvents(a, transfer, approval)
+
+	assert.Panics(t, func() { vm.RegisterPrecompileEvents(a, transfer) }, "re-registration")
+	assert.Panics(t, func() { vm.RegisterPrecompileEvents(b, events["Anon"]) }, "anonymous event")
+
+	want := []vm.RegisteredPrecompileEvent{
+		{a, approval},
+		{a, transfer},
+		{b, transfer},
+	}
+	assert.Equal(t, want, vm.RegisteredPrecompileEvents(), "RegisteredPrecompileEvents() sorted by address then signature")
+
+	tests := []struct {
+		name   string
+		log    *types.Log
+		want   abi.Event
+		wantOK bool
+	}{
+		{
+			name:   "registered",
+			log:    &types.Log{Address: b, Topics: []common.Hash{transfer.ID}},
+			want:   transfer,
+			wantOK: true,
+		},
+		{
+			name: "registered_for_other_address",
+			log:  &types.Log{Address: b, Topics: []common.Hash{approval.ID}},
+		},
+		{
+			name: "no_topics",
+			log:  &types.Log{Address: a},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := vm.PrecompileEventOf(tt.log)
+			require.Equal(t, tt.wantOK, ok, "PrecompileEventOf() ok")
+			assert.Equal(t, tt.want, got, "PrecompileEventOf()")
+		})
+	}
+
+	t.Run("UnpackEvent", func(t *testing.T) {
+		from, to := common.Address{'f'}, common.Address{'t'}
+		value := big.NewInt(314159)
+		topics, data, err := vm.PackEvent(transfer, from, to, value)
+		require.NoError(t, err, "PackEvent()")
+
+		got, err := vm.UnpackEvent(transfer, &types.Log{Topics: topics, Data: data})
+		require.NoError(t, err, "UnpackEvent()")
+		want := map[string]any{
+			"from":  from,
+			"to":    to,
+			"value": value,
+		}
+		assert.Equal(t, want, got, "UnpackEvent(PackEvent())")
+	})
+}
+
+func TestCallTracerDecodesPrecompileEvents(t *testing.T) {
+	transfer := precompileEventsABI(t).Events["Transfer"]
+	precompile := common.Address{'p'}
+
+	vm.TestOnlyClearRegisteredPrecompileEvents()
+	t.Cleanup(vm.TestOnlyClearRegisteredPrecompileEvents)
+	vm.RegisterPrecompileEvents(precompile, transfer)
+
+	from, to := common.Address{'f'}, common.Address{'t'}
+	rawTopic := common.Hash{'r', 'a', 'w'}
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				if err := env.AddLog([]common.Hash{rawTopic}, nil); err != nil {
+					return nil, err
+				}
+				return nil, env.EmitEvent(transfer, from, to, big.NewInt(42))
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	_, evm := ethtest.NewZeroEVM(t, ethtest.WithBlockContext(vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		BlockNumber: big.NewInt(0),
+	}))
+	tracer, err := tracers.DefaultDirectory.New("callTracer", nil, json.RawMessage(`{"withLog":true}`))
+	require.NoError(t, err, `New("callTracer")`)
+	evm.Config.Tracer = tracer
+
+	const gasLimit = 1e6
+	tracer.CaptureTxStart(gasLimit)
+	_, gasLeft, err := evm.Call(vm.AccountRef{}, precompile, nil, gasLimit, new(uint256.Int))
+	require.NoError(t, err, "evm.Call([stateful precompile])")
+	tracer.CaptureTxEnd(gasLeft)
+
+	raw, err := tracer.GetResult()
+	require.NoError(t, err, "GetResult()")
+
+	type event struct {
+		Name      string         `json:"name"`
+		Signature string         `json:"signature"`
+		Args      map[string]any `json:"args"`
+	}
+	var got struct {
+		Logs []struct {
+			Address common.Address `json:"address"`
+			Topics  []common.Hash  `json:"topics"`
+			Event   *event         `json:"event"`
+		} `json:"logs"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &got), "json.Unmarshal(%s)", raw)
+	require.Len(t, got.Logs, 2, "logs")
+
+	assert.Equal(t, []common.Hash{rawTopic}, got.Logs[0].Topics, "unregistered log topics")
+	assert.Nil(t, got.Logs[0].Event, "unregistered log event")
+
+	want := &event{
+		Name:      "Transfer",
+		Signature: "Transfer(address,address,uint256)",
+		Args: map[string]any{
+			"from":  strings.ToLower(from.Hex()),
+			"to":    strings.ToLower(to.Hex()),
+			"value": float64(42),
+		},
+	}
+	assert.Equal(t, precompile, got.Logs[1].Address, "registered log address")
+	assert.Equal(t, want, got.Logs[1].Event, "registered log event")
+}
@@ -17,6 +17,7 @@
 package vm
 
 import (
+	"context"
 	"math/big"
 	"sync/atomic"
 
@@ -41,8 +42,18 @@ type (
 func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
 	if p, override := evm.chainRules.Hooks().PrecompileOverride(addr); override {
 		log.Debug("Overriding precompile", "address", addr, "implementation", log.TypeOf(p))
-		return p, p != nil
+		if p == nil {
+			return nil, false
+		}
+		return evm.applyPrecompileCodePolicy(addr, p) // libevm
 	}
+	return evm.upstreamPrecompile(addr) // libevm
+}
+
+// upstreamPrecompile is the original geth implementation of
+// [EVM.precompile], split out by libevm to allow it to be used by
+// [UpstreamPrecompile].
+func (evm *EVM) upstreamPrecompile(addr common.Address) (PrecompiledContract, bool) {
 	var precompiles map[common.Address]PrecompiledContract
 	switch {
 	case evm.chainRules.IsCancun:
@@ -81,7 +92,11 @@ type BlockContext struct {
 	BlobBaseFee *big.Int       // Provides information for BLOBBASEFEE (0 if vm runs with NoBaseFee flag and 0 blob gas price)
 	Random      *common.Hash   // Provides information for PREVRANDAO
 
-	Header *types.Header // libevm addition; not guaranteed to be set
+	Header     *types.Header // libevm addition; not guaranteed to be set
+	HeaderMode HeaderMode    // libevm addition
+	// BlockReader, if non-nil, is exposed to precompiles, bounded to the
+	// block's ancestors, via [PrecompileEnvironment.Ancestors].
+	BlockReader BlockReader // libevm addition
 }
 
 // TxContext provides the EVM with information about a transaction.
@@ -130,7 +145,16 @@ type EVM struct {
 	callGasTemp uint64
 
 	// libevm
-	executionInvalidated error // see [EVM.InvalidateExecution]
+	executionInvalidated    error                    // see [EVM.InvalidateExecution]
+	finished                bool                     // see [EVM.Finish]
+	contractCreationBlocked error                    // see [EVM.ContractCreationBlocked]
+	commitActions           []func()                 // see [EVM.RunCommitActions]
+	txEndActions            []func(StateDB)          // see [EVM.RunTxEndActions]
+	declaredConflicts       map[common.Hash]struct{} // see [EVM.DeclaredConflicts]
+	ctx                     context.Context          // see [EVM.CancelOnDone]
+	ctxDone                 atomic.Bool              // see [EVM.CancelOnDone]
+	cancellationDisabled    atomic.Bool              // see [EVM.DisableCancellation]
+	callStack               []CallFrame              // see [PrecompileEnvironment.CallStack]
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -156,20 +180,31 @@ func NewEVM(blockCtx BlockContext, txCtx TxContext, statedb StateDB, chainConfig
 		chainConfig: chainConfig,
 		chainRules:  chainConfig.Rules(blockCtx.BlockNumber, blockCtx.Random != nil, blockCtx.Time),
 	}
+	evm.overrideBlockRandomness() // libevm
+	evm.overrideTransfer()        // libevm
 	evm.interpreter = NewEVMInterpreter(evm)
+	evm.emitLifecycleEvent(EVMCreated) // libevm
 	return evm
 }
 
 // Reset resets the EVM with a new transaction context.Reset
 // This is not threadsafe and should only be done very cautiously.
 func (evm *EVM) Reset(txCtx TxContext, statedb StateDB) {
-	evm.executionInvalidated = nil // see [EVM.InvalidateExecution]
+	evm.executionInvalidated = nil    // see [EVM.InvalidateExecution]
+	evm.contractCreationBlocked = nil // see [EVM.ContractCreationBlocked]
+	evm.DiscardCommitActions()        // libevm
+	evm.DiscardTxEndActions()         // libevm
+	evm.declaredConflicts = nil       // libevm: see [EVM.DeclaredConflicts]
 	evm.TxContext, evm.StateDB = evm.overrideEVMResetArgs(txCtx, statedb)
+	evm.emitLifecycleEvent(EVMReset) // libevm
 }
 
 // Cancel cancels any running EVM operation. This may be called concurrently and
 // it's safe to be called multiple times.
 func (evm *EVM) Cancel() {
+	if evm.cancellationDisabled.Load() { // libevm
+		return
+	}
 	evm.abort.Store(true)
 }
 
@@ -217,6 +252,9 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		evm.StateDB.CreateAccount(addr)
 	}
 	evm.Context.Transfer(evm.StateDB, caller.Address(), addr, value)
+	caller = evm.aliasCaller(caller, addr) // libevm
+	evm.pushCallFrame(CALL, caller, addr)  // libevm
+	defer evm.popCallFrame()               // libevm
 
 	// Capture the tracer start/end events in debug mode
 	if debug {
@@ -288,6 +326,8 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 		return nil, gas, ErrInsufficientBalance
 	}
 	var snapshot = evm.StateDB.Snapshot()
+	evm.pushCallFrame(CALLCODE, caller, addr) // libevm
+	defer evm.popCallFrame()                  // libevm
 
 	// Invoke tracer hooks that signal entering/exiting a call frame
 	if evm.Config.Tracer != nil {
@@ -330,6 +370,8 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 		return nil, gas, ErrDepth
 	}
 	var snapshot = evm.StateDB.Snapshot()
+	evm.pushCallFrame(DELEGATECALL, caller, addr) // libevm
+	defer evm.popCallFrame()                      // libevm
 
 	// Invoke tracer hooks that signal entering/exiting a call frame
 	if evm.Config.Tracer != nil {
@@ -385,6 +427,8 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	// but is the correct thing to do and matters on other networks, in tests, and potential
 	// future scenarios
 	evm.StateDB.AddBalance(addr, new(uint256.Int))
+	evm.pushCallFrame(STATICCALL, caller, addr) // libevm
+	defer evm.popCallFrame()                    // libevm
 
 	// Invoke tracer hooks that signal entering/exiting a call frame
 	if evm.Config.Tracer != nil {
@@ -450,7 +494,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	evm.StateDB.SetNonce(caller.Address(), nonce+1)
 	// We add this to the access list _before_ taking a snapshot. Even if the creation fails,
 	// the access-list change should not be rolled back
-	if evm.chainRules.IsBerlin {
+	if evm.chainRules.IsEIP2929() { // libevm: was IsBerlin
 		evm.StateDB.AddAddressToAccessList(address)
 	}
 	// Ensure there's no existing contract already at the designated address
@@ -477,6 +521,8 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		evm.StateDB.SetNonce(address, 1)
 	}
 	evm.Context.Transfer(evm.StateDB, caller.Address(), address, value)
+	evm.pushCallFrame(typ, caller, address) // libevm
+	defer evm.popCallFrame()                // libevm
 
 	// Initialise a new contract and set the code that is to be used by the EVM.
 	// The contract is a scoped environment for this execution context only.
@@ -511,6 +557,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 		createDataGas := uint64(len(ret)) * params.CreateDataGas
 		if contract.UseGas(createDataGas) {
 			evm.StateDB.SetCode(address, ret)
+			evm.onCodeDeployed(caller, address, ret) // libevm
 		} else {
 			err = ErrCodeStoreOutOfGas
 		}
@@ -538,8 +585,12 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 
 // Create creates a new contract using code as deployment code.
 func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *uint256.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
-	contractAddr = crypto.CreateAddress(caller.Address(), evm.StateDB.GetNonce(caller.Address()))
-	return evm.create(caller, &codeAndHash{code: code}, gas, value, contractAddr, CREATE)
+	//libevm:start
+	codeAndHash := &codeAndHash{code: code}
+	nonce := evm.StateDB.GetNonce(caller.Address())
+	contractAddr = evm.deriveContractAddress(CREATE, caller.Address(), nonce, common.Hash{}, codeAndHash)
+	return evm.create(caller, codeAndHash, gas, value, contractAddr, CREATE)
+	//libevm:end
 }
 
 // Create2 creates a new contract using code as deployment code.
@@ -548,7 +599,7 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *uint2
 // instead of the usual sender-and-nonce-hash as the address where the contract is initialized at.
 func (evm *EVM) Create2(caller ContractRef, code []byte, gas uint64, endowment *uint256.Int, salt *uint256.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
 	codeAndHash := &codeAndHash{code: code}
-	contractAddr = crypto.CreateAddress2(caller.Address(), salt.Bytes32(), codeAndHash.Hash().Bytes())
+	contractAddr = evm.deriveContractAddress(CREATE2, caller.Address(), 0, salt.Bytes32(), codeAndHash) // libevm: modified
 	return evm.create(caller, codeAndHash, gas, endowment, contractAddr, CREATE2)
 }
 
@@ -1,4 +1,4 @@
-// Copyright 2025 the libevm authors.
+// Copyright 2025-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -17,11 +17,20 @@
 package vm
 
 import (
+	"time"
+
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/libevm"
 	"github.com/ava-labs/libevm/log"
 )
 
+// blockedCreationLog is called for every blocked contract creation so is rate
+// limited.
+var blockedCreationLog = log.NewLimiter(LogModule, log.LimiterConfig{
+	Burst:    10,
+	Interval: time.Second,
+})
+
 // canCreateContract is a convenience wrapper for calling the
 // [params.RulesHooks.CanCreateContract] hook.
 func (evm *EVM) canCreateContract(caller ContractRef, contractToCreate common.Address, gas uint64) (remainingGas uint64, _ error) {
@@ -39,7 +48,10 @@ func (evm *EVM) canCreateContract(caller ContractRef, contractToCreate common.Ad
 	// NOTE that this block only performs logging and that all paths propagate
 	// `(gas, err)` unmodified.
 	if err != nil {
-		log.Debug(
+		if evm.contractCreationBlocked == nil {
+			evm.contractCreationBlocked = err
+		}
+		blockedCreationLog.Debug(
 			"Contract creation blocked by libevm hook",
 			"origin", addrs.Origin,
 			"caller", addrs.EVMSemantic.Caller,
@@ -69,3 +81,19 @@ func (evm *EVM) InvalidateExecution(err error) {
 func (evm *EVM) ExecutionInvalidated() error {
 	return evm.executionInvalidated
 }
+
+// ContractCreationBlocked returns the first error returned by
+// [params.RulesAllowlistHooks.CanCreateContract] during the current
+// transaction; i.e. since [EVM.Reset] was last called. It returns nil if no
+// contract creation was blocked.
+func (evm *EVM) ContractCreationBlocked() error {
+	return evm.contractCreationBlocked
+}
+
+// IsPrecompile reports whether the address is that of a precompiled contract
+// under the EVM's current rules, honouring any
+// [params.RulesHooks.PrecompileOverride].
+func (evm *EVM) IsPrecompile(addr common.Address) bool {
+	_, ok := evm.precompile(addr)
+	return ok
+}
@@ -1,4 +1,4 @@
-// Copyright 2024-2025 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -103,3 +103,52 @@ func TestOverrideEVMResetArgs(t *testing.T) {
 	assert.Equalf(t, big.NewInt(chainID), hooks.gotResetChainID, "%T.ChainID passed to Reset() hook", params.Rules{})
 	assert.Equalf(t, big.NewInt(gasPrice), evm.GasPrice, "%T.GasPrice set by Reset() hook", evm)
 }
+
+// gasPriceOverrider sets the gas price of every new EVM.
+type gasPriceOverrider int64
+
+func (o gasPriceOverrider) OverrideNewEVMArgs(args *NewEVMArgs) *NewEVMArgs {
+	args.TxContext.GasPrice = big.NewInt(int64(o))
+	return args
+}
+
+func (gasPriceOverrider) OverrideEVMResetArgs(_ params.Rules, args *EVMResetArgs) *EVMResetArgs {
+	return args
+}
+
+func TestRegisterHooksForChain(t *testing.T) {
+	TestOnlyClearRegisteredHooks()
+	TestOnlyClearRegisteredChainHooks()
+	t.Cleanup(TestOnlyClearRegisteredHooks)
+	t.Cleanup(TestOnlyClearRegisteredChainHooks)
+
+	const (
+		global = 1
+		chainA = 42
+		chainB = 43
+	)
+	RegisterHooks(gasPriceOverrider(global))
+	RegisterHooksForChain(big.NewInt(chainA), gasPriceOverrider(chainA))
+	RegisterHooksForChain(big.NewInt(chainB), gasPriceOverrider(chainB))
+
+	assert.Panics(t, func() {
+		RegisterHooksForChain(big.NewInt(chainA), gasPriceOverrider(0))
+	}, "re-registration for same chain")
+	assert.Panics(t, func() {
+		RegisterHooksForChain(new(big.Int).Lsh(big.NewInt(1), 64), gasPriceOverrider(0))
+	}, "registration for chain ID > max uint64")
+
+	tests := []struct {
+		config *params.ChainConfig
+		want   int64
+	}{
+		{&params.ChainConfig{}, global},
+		{&params.ChainConfig{ChainID: big.NewInt(chainA)}, chainA},
+		{&params.ChainConfig{ChainID: big.NewInt(chainB)}, chainB},
+		{&params.ChainConfig{ChainID: big.NewInt(chainB + 1)}, global},
+	}
+	for _, tt := range tests {
+		evm := NewEVM(BlockContext{}, TxContext{}, nil, tt.config, Config{})
+		assert.Equalf(t, big.NewInt(tt.want), evm.GasPrice, "%T.GasPrice with chain config %+v", evm, tt.config)
+	}
+}
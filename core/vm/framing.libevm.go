@@ -0,0 +1,92 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ava-labs/libevm/common"
+)
+
+// A CallFramingKind classifies how a precompile was reached.
+//
+// NOTE: this version of the EVM doesn't implement EIP-7702 so a precompile
+// can't be reached via an account's delegation designator.
+type CallFramingKind uint8
+
+const (
+	// DirectFraming is a CALL or STATICCALL, such that the precompile runs in
+	// its own context.
+	DirectFraming CallFramingKind = iota
+	// ProxyFraming is a DELEGATECALL or CALLCODE, such that the precompile
+	// runs in the context of its caller; e.g. when used as the implementation
+	// of a proxy contract.
+	ProxyFraming
+)
+
+// String returns a human-readable name of the kind.
+func (k CallFramingKind) String() string {
+	switch k {
+	case DirectFraming:
+		return "direct"
+	case ProxyFraming:
+		return "proxy"
+	default:
+		return "unknown"
+	}
+}
+
+// A CallFraming describes how the call to a precompile was framed, as returned
+// by [PrecompileEnvironment.CallFraming].
+type CallFraming struct {
+	Kind CallFramingKind
+	// CodeAddress is the address of the precompile itself, regardless of
+	// Kind; i.e. Addresses().Raw.Self.
+	CodeAddress common.Address
+	// ContextAddress is the account whose storage, balance, and address (as
+	// seen by ADDRESS) are used by the precompile; i.e.
+	// Addresses().EVMSemantic.Self. It differs from CodeAddress iff Kind is
+	// [ProxyFraming].
+	ContextAddress common.Address
+	// CallerCodeAddress is the address of the code that made the call. If the
+	// caller was itself running in the context of another account (i.e. it
+	// was reached by DELEGATECALL or CALLCODE) then this is the address at
+	// which its code is deployed, which differs from Addresses().Raw.Caller.
+	// Otherwise it is equal to Addresses().Raw.Caller.
+	CallerCodeAddress common.Address
+}
+
+func (e *environment) CallFraming() CallFraming {
+	f := CallFraming{
+		Kind:              DirectFraming,
+		CodeAddress:       e.rawSelf,
+		ContextAddress:    e.self.Address(),
+		CallerCodeAddress: e.rawCallerCode,
+	}
+	switch e.callType {
+	case DelegateCall, CallCode:
+		f.Kind = ProxyFraming
+	}
+	return f
+}
+
+// callerCodeAddress returns the address of the code being run by `caller` if
+// it is a [Contract] with a known code address, otherwise its address.
+func callerCodeAddress(caller ContractRef) common.Address {
+	if c, ok := caller.(*Contract); ok && c.CodeAddr != nil {
+		return *c.CodeAddr
+	}
+	return caller.Address()
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestPrecompileCallFraming(t *testing.T) {
+	rng := ethtest.NewPseudoRand(7702)
+	var (
+		precompile = rng.Address()
+		proxy      = rng.Address()
+		impl       = rng.Address()
+		eoa        = rng.Address()
+	)
+
+	var got vm.CallFraming
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				got = env.CallFraming()
+				return []byte{1}, nil
+			}),
+		},
+	}
+	hookstest.Register(t, params.Extras[*hookstest.Stub, *hookstest.Stub]{
+		NewRules: func(_ *params.ChainConfig, r *params.Rules, _ *hookstest.Stub, _ *big.Int, _ bool, _ uint64) *hookstest.Stub {
+			r.IsCancun = true // enable PUSH0
+			r.IsEIP150 = true // cap gas forwarded by [forwardAllGas]
+			return hooks
+		},
+	})
+
+	tests := []struct {
+		name string
+		code map[common.Address][]vm.OpCode
+		to   common.Address
+		want vm.CallFraming
+	}{
+		{
+			name: "direct",
+			to:   precompile,
+			want: vm.CallFraming{
+				Kind:              vm.DirectFraming,
+				CodeAddress:       precompile,
+				ContextAddress:    precompile,
+				CallerCodeAddress: eoa,
+			},
+		},
+		{
+			name: "proxy_delegatecall",
+			code: map[common.Address][]vm.OpCode{
+				proxy: makeReturnProxy(t, precompile, vm.DELEGATECALL),
+			},
+			to: proxy,
+			want: vm.CallFraming{
+				Kind:              vm.ProxyFraming,
+				CodeAddress:       precompile,
+				ContextAddress:    proxy,
+				CallerCodeAddress: proxy,
+			},
+		},
+		{
+			name: "callcode",
+			code: map[common.Address][]vm.OpCode{
+				proxy: makeReturnProxy(t, precompile, vm.CALLCODE),
+			},
+			to: proxy,
+			want: vm.CallFraming{
+				Kind:              vm.ProxyFraming,
+				CodeAddress:       precompile,
+				ContextAddress:    proxy,
+				CallerCodeAddress: proxy,
+			},
+		},
+		{
+			name: "call_from_proxied_implementation",
+			code: map[common.Address][]vm.OpCode{
+				proxy: forwardAllGas(makeReturnProxy(t, impl, vm.DELEGATECALL)),
+				impl:  makeReturnProxy(t, precompile, vm.CALL),
+			},
+			to: proxy,
+			want: vm.CallFraming{
+				Kind:              vm.DirectFraming,
+				CodeAddress:       precompile,
+				ContextAddress:    precompile,
+				CallerCodeAddress: impl,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdb, evm := ethtest.NewZeroEVM(t)
+			for addr, code := range tt.code {
+				sdb.SetCode(addr, convertBytes[vm.OpCode, byte](code...))
+			}
+
+			got = vm.CallFraming{}
+			_, _, err := evm.Call(vm.AccountRef(eoa), tt.to, []byte{0}, 1e6, uint256.NewInt(0))
+			require.NoError(t, err, "evm.Call()")
+			assert.Equal(t, tt.want, got, "CallFraming()")
+		})
+	}
+}
+
+// forwardAllGas modifies the output of [makeReturnProxy] to send all available
+// gas instead of none, which is required if the destination isn't a
+// precompile.
+func forwardAllGas(proxy []vm.OpCode) []vm.OpCode {
+	// The gas argument is immediately before the CALL-type opcode, which is
+	// itself followed by 7 opcodes to return the data.
+	proxy[len(proxy)-9] = vm.GAS
+	return proxy
+}
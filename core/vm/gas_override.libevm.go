@@ -0,0 +1,103 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ava-labs/libevm/log"
+)
+
+// An OpCodeGasOverrider MAY be implemented by a [params.RulesHooks] to reprice
+// individual instructions, e.g. SSTORE or EXTCODESIZE for a custom fork,
+// without patching the upstream gas table. Overrides are applied to the jump
+// table of every [EVMInterpreter] constructed under the respective rules,
+// after all other modifications, including [CustomOpCode] registration.
+type OpCodeGasOverrider interface {
+	OpCodeGasOverrides() []OpCodeGasOverride
+}
+
+// A DynamicGasFunc computes the gas charged by an instruction in addition to
+// its constant gas. The `memorySize` is that required by the instruction,
+// rounded up to a whole number of words, or zero if the instruction doesn't
+// access memory.
+type DynamicGasFunc func(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error)
+
+// An OpCodeGasOverride modifies the gas charged by a defined opcode.
+type OpCodeGasOverride struct {
+	OpCode OpCode
+	// ConstantGas, if non-nil, replaces the instruction's constant gas.
+	ConstantGas *uint64
+	// DynamicGas, if non-nil, is called once, when the jump table is built,
+	// with the instruction's default dynamic-gas function, and returns its
+	// replacement. The default is nil if the instruction has no dynamic gas;
+	// otherwise it includes the cost of any memory expansion, which the
+	// replacement is therefore responsible for charging, typically by
+	// deferring to the default. Instructions that access memory MUST have a
+	// non-nil dynamic-gas function.
+	DynamicGas func(DynamicGasFunc) DynamicGasFunc
+}
+
+func (evm *EVM) opCodeGasOverrides() []OpCodeGasOverride {
+	o, ok := evm.chainRules.Hooks().(OpCodeGasOverrider)
+	if !ok {
+		return nil
+	}
+	return o.OpCodeGasOverrides()
+}
+
+// overrideOpCodeGas modifies the table in place. Overrides of undefined
+// opcodes are logged and ignored, in keeping with the treatment of unsupported
+// EIPs.
+func (evm *EVM) overrideOpCodeGas(table *JumpTable, overrides []OpCodeGasOverride) {
+	for _, o := range overrides {
+		op := table[o.OpCode]
+		if o.OpCode != STOP && !op.HasCost() {
+			log.Error(
+				"Gas override of undefined opcode via libevm hook",
+				"opcode", o.OpCode,
+				"hooks", log.TypeOf(evm.chainRules.Hooks()),
+			)
+			continue
+		}
+
+		if o.ConstantGas != nil {
+			op.constantGas = *o.ConstantGas
+		}
+		if o.DynamicGas == nil {
+			continue
+		}
+
+		var def DynamicGasFunc
+		if op.dynamicGas != nil {
+			def = DynamicGasFunc(op.dynamicGas)
+		}
+		repl := o.DynamicGas(def)
+		if repl == nil && op.memorySize != nil {
+			// The interpreter only expands memory when there is a dynamic-gas
+			// function.
+			log.Error(
+				"Nil dynamic-gas override of memory-accessing opcode via libevm hook",
+				"opcode", o.OpCode,
+				"hooks", log.TypeOf(evm.chainRules.Hooks()),
+			)
+			continue
+		}
+		op.dynamicGas = nil
+		if repl != nil {
+			op.dynamicGas = gasFunc(repl)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+type gasOverrideHooks struct {
+	hookstest.Stub
+	overrides []vm.OpCodeGasOverride
+}
+
+func (h *gasOverrideHooks) OpCodeGasOverrides() []vm.OpCodeGasOverride {
+	return h.overrides
+}
+
+func TestOpCodeGasOverrides(t *testing.T) {
+	const (
+		undefined       = vm.OpCode(0x0c) // undefined in all forks
+		addGas          = 100
+		mstoreSurcharge = 1000
+		gasLimit        = 1e6
+	)
+	contract := common.Address{'c', 'o', 'd', 'e'}
+
+	hooks := &gasOverrideHooks{}
+	hookstest.Register(t, params.Extras[*gasOverrideHooks, *gasOverrideHooks]{
+		NewRules: func(*params.ChainConfig, *params.Rules, *gasOverrideHooks, *big.Int, bool, uint64) *gasOverrideHooks {
+			return hooks
+		},
+	})
+
+	code := convertBytes[vm.OpCode, byte](
+		vm.PUSH1, 3,
+		vm.PUSH1, 20,
+		vm.ADD,
+		vm.PUSH1, 0,
+		vm.MSTORE,
+		vm.PUSH1, 32,
+		vm.PUSH1, 0,
+		vm.RETURN,
+	)
+	call := func(t *testing.T, code []byte) ([]byte, uint64, error) {
+		t.Helper()
+		sdb, evm := ethtest.NewZeroEVM(t)
+		sdb.SetCode(contract, code)
+		ret, gasLeft, err := evm.Call(vm.AccountRef{}, contract, nil, gasLimit, uint256.NewInt(0))
+		return ret, gasLimit - gasLeft, err
+	}
+
+	hooks.overrides = nil
+	_, defaultGas, err := call(t, code)
+	require.NoError(t, err, "%T.Call() without overrides", &vm.EVM{})
+
+	newAddGas := uint64(addGas)
+	var gotMemorySize uint64
+	hooks.overrides = []vm.OpCodeGasOverride{
+		{
+			OpCode:      vm.ADD,
+			ConstantGas: &newAddGas,
+		},
+		{
+			OpCode: vm.MSTORE,
+			DynamicGas: func(def vm.DynamicGasFunc) vm.DynamicGasFunc {
+				return func(evm *vm.EVM, contract *vm.Contract, stack *vm.Stack, mem *vm.Memory, memorySize uint64) (uint64, error) {
+					gotMemorySize = memorySize
+					gas, err := def(evm, contract, stack, mem, memorySize)
+					return gas + mstoreSurcharge, err
+				}
+			},
+		},
+		{
+			OpCode:      undefined,
+			ConstantGas: &newAddGas,
+		},
+	}
+
+	t.Run("overridden", func(t *testing.T) {
+		got, gasUsed, err := call(t, code)
+		require.NoError(t, err, "%T.Call()", &vm.EVM{})
+		assert.Equal(t, uint256.NewInt(23).PaddedBytes(32), got, "3 + 20")
+		assert.Equal(t, defaultGas-vm.GasFastestStep+addGas+mstoreSurcharge, gasUsed, "gas used")
+		assert.Equal(t, uint64(32), gotMemorySize, "memory size received by dynamic gas")
+	})
+
+	t.Run("undefined_ignored", func(t *testing.T) {
+		_, _, err := call(t, []byte{byte(undefined)})
+		require.IsType(t, &vm.ErrInvalidOpCode{}, err, "%T.Call() error", &vm.EVM{})
+	})
+
+	t.Run("default_table_unmodified", func(t *testing.T) {
+		hooks.overrides = nil
+		_, gasUsed, err := call(t, code)
+		require.NoError(t, err, "%T.Call()", &vm.EVM{})
+		assert.Equal(t, defaultGas, gasUsed, "gas used after overrides removed")
+	})
+}
@@ -0,0 +1,44 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import "github.com/ava-labs/libevm/core/types"
+
+func (e *environment) HeaderFlags() (types.HeaderFlags, error) {
+	hdr, err := e.BlockHeader()
+	if err != nil {
+		return 0, err
+	}
+	return hdr.Flags(), nil
+}
+
+// SwitchOnHeaderFlag returns a stateful precompile that runs `whenSet` if the
+// current block header carries all of the bits in `flag`, otherwise running
+// `otherwise`. An error is returned if the header is unavailable; see
+// [PrecompileEnvironment.BlockHeader].
+func SwitchOnHeaderFlag(flag types.HeaderFlags, whenSet, otherwise PrecompiledStatefulContract) PrecompiledContract {
+	return NewStatefulPrecompile(func(env PrecompileEnvironment, input []byte) ([]byte, error) {
+		flags, err := env.HeaderFlags()
+		if err != nil {
+			return nil, err
+		}
+		if flags.Has(flag) {
+			return whenSet(env, input)
+		}
+		return otherwise(env, input)
+	})
+}
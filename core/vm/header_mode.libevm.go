@@ -0,0 +1,51 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import "fmt"
+
+// A HeaderMode describes the completeness of the header available to
+// precompiles via [PrecompileEnvironment.BlockHeader].
+type HeaderMode uint8
+
+const (
+	// HeaderCommitted is the zero value, used when executing a block that has
+	// already been built; e.g. during verification, import, tracing, or calls
+	// against historical state. All header fields are final.
+	HeaderCommitted HeaderMode = iota
+	// HeaderBuilding is used while a block is under construction; e.g. by the
+	// miner. Fields dependent on the block's execution, such as GasUsed, Root,
+	// ReceiptHash, and Bloom, as well as any set by the consensus engine upon
+	// finalisation, are incomplete and MUST NOT be relied upon.
+	HeaderBuilding
+)
+
+// String returns a human-readable representation of the mode.
+func (m HeaderMode) String() string {
+	switch m {
+	case HeaderCommitted:
+		return "committed"
+	case HeaderBuilding:
+		return "building"
+	default:
+		return fmt.Sprintf("%T(%d)", m, m)
+	}
+}
+
+func (e *environment) BlockHeaderMode() HeaderMode {
+	return e.evm.Context.HeaderMode
+}
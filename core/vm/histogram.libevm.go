@@ -0,0 +1,96 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import "sync/atomic"
+
+// An OpCodeHistogram accumulates the number of executions of, and the gas
+// consumed by, each [OpCode]. It is enabled by setting
+// [Config.OpCodeHistogram] and is safe for concurrent use by multiple EVMs;
+// i.e. a single histogram MAY be shared by all EVMs of a node.
+//
+// Recording is sampling based. If SampleEvery is greater than 1 then only
+// every n-th op code executed by each [EVMInterpreter] is recorded, so counts
+// and gas SHOULD be scaled accordingly if absolute estimates are required.
+//
+// An OpCodeHistogram MUST NOT be copied after first use.
+type OpCodeHistogram struct {
+	SampleEvery uint64
+
+	count, gas [256]atomic.Uint64
+}
+
+// OpCodeStats are the values recorded for a single [OpCode] by an
+// [OpCodeHistogram].
+type OpCodeStats struct {
+	// Count is the number of sampled executions.
+	Count uint64
+	// Gas is the total gas charged for the sampled executions, as would be
+	// reported to [EVMLogger.CaptureState]; i.e. including both constant and
+	// dynamic components.
+	Gas uint64
+}
+
+// Stats returns the values recorded for the [OpCode].
+func (h *OpCodeHistogram) Stats(op OpCode) OpCodeStats {
+	return OpCodeStats{
+		Count: h.count[op].Load(),
+		Gas:   h.gas[op].Load(),
+	}
+}
+
+// Snapshot returns the values recorded for every [OpCode] that has been
+// sampled at least once. The snapshot is not atomic with respect to concurrent
+// recording; values of different op codes MAY therefore be marginally
+// inconsistent.
+func (h *OpCodeHistogram) Snapshot() map[OpCode]OpCodeStats {
+	s := make(map[OpCode]OpCodeStats)
+	for i := range h.count {
+		op := OpCode(i)
+		if st := h.Stats(op); st.Count > 0 {
+			s[op] = st
+		}
+	}
+	return s
+}
+
+// Reset zeroes all recorded values but leaves SampleEvery unchanged.
+func (h *OpCodeHistogram) Reset() {
+	for i := range h.count {
+		h.count[i].Store(0)
+		h.gas[i].Store(0)
+	}
+}
+
+func (h *OpCodeHistogram) record(op OpCode, gas uint64) {
+	h.count[op].Add(1)
+	h.gas[op].Add(gas)
+}
+
+// sampleOpCode is called by [EVMInterpreter.Run] for every op code, after all
+// gas has been charged but before execution.
+func (in *EVMInterpreter) sampleOpCode(h *OpCodeHistogram, op OpCode, gas uint64) {
+	// The counter is local to the interpreter, which is not threadsafe, so we
+	// avoid contention on a shared atomic value for op codes that won't be
+	// recorded.
+	in.opCodesSinceSample++
+	if in.opCodesSinceSample < h.SampleEvery {
+		return
+	}
+	in.opCodesSinceSample = 0
+	h.record(op, gas)
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+)
+
+func TestOpCodeHistogram(t *testing.T) {
+	code := []vm.OpCode{
+		vm.PUSH1, 1,
+		vm.PUSH1, 2,
+		vm.ADD,
+		vm.STOP,
+	}
+
+	tests := []struct {
+		sampleEvery uint64
+		want        map[vm.OpCode]vm.OpCodeStats
+	}{
+		{
+			sampleEvery: 0,
+			want: map[vm.OpCode]vm.OpCodeStats{
+				vm.PUSH1: {Count: 2, Gas: 2 * vm.GasFastestStep},
+				vm.ADD:   {Count: 1, Gas: vm.GasFastestStep},
+				vm.STOP:  {Count: 1, Gas: 0},
+			},
+		},
+		{
+			sampleEvery: 1,
+			want: map[vm.OpCode]vm.OpCodeStats{
+				vm.PUSH1: {Count: 2, Gas: 2 * vm.GasFastestStep},
+				vm.ADD:   {Count: 1, Gas: vm.GasFastestStep},
+				vm.STOP:  {Count: 1, Gas: 0},
+			},
+		},
+		{
+			sampleEvery: 2,
+			want: map[vm.OpCode]vm.OpCodeStats{
+				vm.PUSH1: {Count: 1, Gas: vm.GasFastestStep}, // second
+				vm.STOP:  {Count: 1, Gas: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("SampleEvery=%d", tt.sampleEvery), func(t *testing.T) {
+			rng := ethtest.NewPseudoRand(705)
+			contract := rng.Address()
+
+			state, evm := ethtest.NewZeroEVM(t)
+			state.CreateAccount(contract)
+			state.SetCode(contract, convertBytes[vm.OpCode, byte](code...))
+
+			h := &vm.OpCodeHistogram{SampleEvery: tt.sampleEvery}
+			evm.Config.OpCodeHistogram = h
+
+			_, _, err := evm.Call(vm.AccountRef(rng.Address()), contract, nil, 1e6, uint256.NewInt(0))
+			require.NoError(t, err, "evm.Call()")
+			assert.Equal(t, tt.want, h.Snapshot(), "Snapshot()")
+			assert.Equal(t, tt.want[vm.ADD], h.Stats(vm.ADD), "Stats(ADD)")
+
+			h.Reset()
+			assert.Empty(t, h.Snapshot(), "Snapshot() after Reset()")
+		})
+	}
+}
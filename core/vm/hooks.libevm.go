@@ -1,4 +1,4 @@
-// Copyright 2024-2025 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -17,7 +17,11 @@
 package vm
 
 import (
+	"fmt"
+	"math/big"
+
 	"github.com/ava-labs/libevm/libevm/register"
+	"github.com/ava-labs/libevm/libevm/testonly"
 	"github.com/ava-labs/libevm/params"
 )
 
@@ -46,10 +50,87 @@ func TestOnlyClearRegisteredHooks() {
 	libevmHooks.TestOnlyClear()
 }
 
+// RegisteredHooks returns the [Hooks] registered with [RegisterHooks] and
+// whether any have been registered at all.
+func RegisteredHooks() (Hooks, bool) {
+	if !libevmHooks.Registered() {
+		return nil, false
+	}
+	return libevmHooks.Get(), true
+}
+
 var libevmHooks register.AtMostOnce[Hooks]
 
+// RegisterHooksForChain registers Hooks that are used instead of those passed
+// to [RegisterHooks] by every [EVM] constructed with a [params.ChainConfig]
+// having the specified chain ID. This allows chains with different behaviour
+// to be embedded in the same process. It MUST NOT be called more than once for
+// the same chain ID, nor after [register.Freeze], and the chain ID MUST fit in
+// a uint64.
+//
+// Note that registration with [params.RegisterExtras] and
+// [types.RegisterExtras] is of types, which are necessarily process-wide, but
+// their values are carried by each ChainConfig and Header respectively so the
+// hooks they implement MAY already differ between chains.
+func RegisterHooksForChain(chainID *big.Int, h Hooks) {
+	err := register.Guard(func() error {
+		if chainID == nil || !chainID.IsUint64() {
+			return fmt.Errorf("chain ID %v not a uint64", chainID)
+		}
+		id := chainID.Uint64()
+		if _, ok := chainHooks[id]; ok {
+			return fmt.Errorf("%w of chain ID %d", register.ErrReRegistration, id)
+		}
+		if chainHooks == nil {
+			chainHooks = make(map[uint64]Hooks)
+		}
+		chainHooks[id] = h
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// chainHooks are those passed to [RegisterHooksForChain]. As with all other
+// registries, it is written to only during initialisation so can be read
+// without locking.
+var chainHooks map[uint64]Hooks
+
+// TestOnlyClearRegisteredChainHooks clears all [Hooks] previously passed to
+// [RegisterHooksForChain]. It panics if called from a non-testing call stack.
+func TestOnlyClearRegisteredChainHooks() {
+	testonly.OrPanic(func() {
+		chainHooks = nil
+	})
+}
+
+// hooksFor returns the Hooks registered for the chain, falling back on those
+// registered with [RegisterHooks].
+func hooksFor(c *params.ChainConfig) (Hooks, bool) {
+	if len(chainHooks) > 0 && c != nil && c.ChainID != nil && c.ChainID.IsUint64() {
+		if h, ok := chainHooks[c.ChainID.Uint64()]; ok {
+			return h, true
+		}
+	}
+	return RegisteredHooks()
+}
+
+// hooks returns the [Hooks] applicable to the EVM's chain; see
+// [RegisterHooksForChain].
+func (evm *EVM) hooks() (Hooks, bool) {
+	return hooksFor(evm.chainConfig)
+}
+
 // Hooks are arbitrary configuration functions to modify default VM behaviour.
 // See [RegisterHooks].
+//
+// Hooks are limited to overriding the arguments with which an [EVM] is
+// constructed or reset. Hooks that modify the behaviour of the EVM itself, such
+// as [CodeMetadataHooks], are instead optional interfaces that MAY be
+// implemented by the [params.RulesHooks] returned by [params.Rules.Hooks].
+// These are carried by each [params.ChainConfig] and are therefore already
+// scoped to both a chain and its forks.
 type Hooks interface {
 	OverrideNewEVMArgs(*NewEVMArgs) *NewEVMArgs
 	OverrideEVMResetArgs(params.Rules, *EVMResetArgs) *EVMResetArgs
@@ -79,17 +160,19 @@ func overrideNewEVMArgs(
 	chainConfig *params.ChainConfig,
 	config Config,
 ) (BlockContext, TxContext, StateDB, *params.ChainConfig, Config) {
-	if !libevmHooks.Registered() {
+	h, ok := hooksFor(chainConfig)
+	if !ok {
 		return blockCtx, txCtx, statedb, chainConfig, config
 	}
-	args := libevmHooks.Get().OverrideNewEVMArgs(&NewEVMArgs{blockCtx, txCtx, statedb, chainConfig, config})
+	args := h.OverrideNewEVMArgs(&NewEVMArgs{blockCtx, txCtx, statedb, chainConfig, config})
 	return args.BlockContext, args.TxContext, args.StateDB, args.ChainConfig, args.Config
 }
 
 func (evm *EVM) overrideEVMResetArgs(txCtx TxContext, statedb StateDB) (TxContext, StateDB) {
-	if !libevmHooks.Registered() {
+	h, ok := evm.hooks()
+	if !ok {
 		return txCtx, statedb
 	}
-	args := libevmHooks.Get().OverrideEVMResetArgs(evm.chainRules, &EVMResetArgs{txCtx, statedb})
+	args := h.OverrideEVMResetArgs(evm.chainRules, &EVMResetArgs{txCtx, statedb})
 	return args.TxContext, args.StateDB
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/ava-labs/libevm/common/math"
 	"github.com/ava-labs/libevm/crypto"
 	"github.com/ava-labs/libevm/log"
+	"github.com/ava-labs/libevm/metrics"
 )
 
 // Config are the configuration options for the Interpreter
@@ -29,6 +30,12 @@ type Config struct {
 	NoBaseFee               bool      // Forces the EIP-1559 baseFee to 0 (needed for 0 price calls)
 	EnablePreimageRecording bool      // Enables recording of SHA3/keccak preimages
 	ExtraEips               []int     // Additional EIPS that are to be enabled
+
+	OpCodeHistogram   *OpCodeHistogram      // libevm: optional, sampling op-code statistics
+	SlowPrecompiles   *SlowPrecompileConfig // libevm: optional reporting of slow precompile calls
+	PrecompileMetrics metrics.Registry      // libevm: optional per-precompile metrics; see [PrecompileMetricName]
+	OpCodeHooks       OpCodeHooks           // libevm: optional, called before and after every op code
+	Services          *Services             // libevm: optional, shared services available to precompiles
 }
 
 // ScopeContext contains the things that are per-call, such as stack and memory,
@@ -49,6 +56,8 @@ type EVMInterpreter struct {
 
 	readOnly   bool   // Whether to throw on stateful modifications
 	returnData []byte // Last CALL's return data for subsequent reuse
+
+	opCodesSinceSample uint64 // libevm: see [OpCodeHistogram]
 }
 
 // NewEVMInterpreter returns a new instance of the Interpreter.
@@ -81,8 +90,12 @@ func NewEVMInterpreter(evm *EVM) *EVMInterpreter {
 	default:
 		table = &frontierInstructionSet
 	}
+	ruleEIPs := evm.chainRules.ActiveEIPs()  // libevm
+	aliases := evm.precompileOpCodeAliases() // libevm
+	custom := evm.customOpCodes()            // libevm
+	gasOverrides := evm.opCodeGasOverrides() // libevm
 	var extraEips []int
-	if len(evm.Config.ExtraEips) > 0 {
+	if len(evm.Config.ExtraEips) > 0 || len(ruleEIPs) > 0 || len(aliases) > 0 || len(custom) > 0 || len(gasOverrides) > 0 { // libevm: modified condition
 		// Deep-copy jumptable to prevent modification of opcodes in other tables
 		table = copyJumpTable(table)
 	}
@@ -95,6 +108,10 @@ func NewEVMInterpreter(evm *EVM) *EVMInterpreter {
 		}
 	}
 	evm.Config.ExtraEips = extraEips
+	evm.enableRuleEIPs(table, ruleEIPs)               // libevm
+	evm.enablePrecompileOpCodeAliases(table, aliases) // libevm
+	evm.enableCustomOpCodes(table, custom)            // libevm
+	evm.overrideOpCodeGas(table, gasOverrides)        // libevm
 	return &EVMInterpreter{evm: evm, table: table}
 }
 
@@ -120,6 +137,10 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 	// as every returning call will return new data anyway.
 	in.returnData = nil
 
+	if in.evm.cancelledWithError() { // libevm
+		return nil, ErrCancelled
+	}
+
 	// Don't bother with the execution if there's no code.
 	if len(contract.Code) == 0 {
 		return nil, nil
@@ -226,8 +247,20 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 			in.evm.Config.Tracer.CaptureState(pc, op, gasCopy, cost, callContext, in.returnData, in.evm.depth, err)
 			logged = true
 		}
+		if h := in.evm.Config.OpCodeHistogram; h != nil { // libevm
+			in.sampleOpCode(h, op, cost)
+		}
+		opPC := pc                                    // libevm
+		if h := in.evm.Config.OpCodeHooks; h != nil { // libevm
+			if err = in.beforeOpCode(h, opPC, op, callContext); err != nil {
+				break
+			}
+		}
 		// execute the operation
 		res, err = operation.execute(&pc, in, callContext)
+		if h := in.evm.Config.OpCodeHooks; h != nil { // libevm
+			err = in.afterOpCode(h, opPC, op, callContext, err)
+		}
 		if err != nil {
 			break
 		}
@@ -238,5 +271,5 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		err = nil // clear stop token error
 	}
 
-	return res, err
+	return in.evm.limitReturnData(res, err) // libevm
 }
@@ -0,0 +1,46 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/libevm/common"
+)
+
+// An InvalidationError is recorded by [PrecompileEnvironment.InvalidateExecution]
+// and later returned by [EVM.ExecutionInvalidated]. Its treatment by the state
+// transition is determined by [params.ExecutionInvalidationHooks], which MAY
+// inspect it with [errors.As] to differentiate between precompiles and, via
+// Cause, between the reasons for invalidation.
+type InvalidationError struct {
+	// Precompile is the address of the precompile that invalidated execution.
+	// This is the raw address of the precompile, even if it was called via
+	// DELEGATECALL or CALLCODE.
+	Precompile common.Address
+	Cause      error
+}
+
+// Error returns a description of the error including the precompile address.
+func (e *InvalidationError) Error() string {
+	return fmt.Sprintf("precompile %v invalidated execution: %v", e.Precompile, e.Cause)
+}
+
+// Unwrap returns the Cause.
+func (e *InvalidationError) Unwrap() error {
+	return e.Cause
+}
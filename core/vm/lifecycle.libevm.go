@@ -0,0 +1,190 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ava-labs/libevm/libevm/register"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A LifecycleEventKind describes a point in the lifetime of an [EVM].
+type LifecycleEventKind uint8
+
+// Kinds of [LifecycleEvent].
+const (
+	// EVMCreated is emitted at the end of [NewEVM].
+	EVMCreated LifecycleEventKind = iota + 1
+	// EVMReset is emitted at the end of [EVM.Reset], typically signalling the
+	// start of a new transaction with an EVM shared by an entire block.
+	EVMReset
+	// EVMFinished is emitted by the first call to [EVM.Finish], after which the
+	// EVM MUST NOT be used.
+	EVMFinished
+)
+
+// String returns a human-readable representation of the kind.
+func (k LifecycleEventKind) String() string {
+	switch k {
+	case EVMCreated:
+		return "EVMCreated"
+	case EVMReset:
+		return "EVMReset"
+	case EVMFinished:
+		return "EVMFinished"
+	default:
+		return fmt.Sprintf("LifecycleEventKind(%d)", k)
+	}
+}
+
+// A LifecycleEvent is passed to all functions registered with
+// [SubscribeLifecycleEvents]. All fields other than Kind reflect the state of
+// the EVM at the time of the event, after any [Hooks] have been applied.
+type LifecycleEvent struct {
+	Kind         LifecycleEventKind
+	EVM          *EVM
+	ChainConfig  *params.ChainConfig
+	Rules        params.Rules
+	BlockContext BlockContext
+	TxContext    TxContext
+}
+
+type lifecycleSubscriber struct {
+	fn func(*LifecycleEvent)
+}
+
+var lifecycle struct {
+	sync.RWMutex
+	subscribers []*lifecycleSubscriber
+	// num mirrors len(subscribers) to allow a lock-free fast path, which is
+	// important as EVMs are constructed for every `eth_call` and similar.
+	num atomic.Int64
+	// frozen is an immutable snapshot of `subscribers`, taken by
+	// [register.Freeze], and read without locking.
+	frozen *[]*lifecycleSubscriber
+}
+
+func init() {
+	register.OnFreeze(
+		func() {
+			lifecycle.RLock()
+			defer lifecycle.RUnlock()
+			s := slices.Clone(lifecycle.subscribers)
+			lifecycle.frozen = &s
+		},
+		func() { lifecycle.frozen = nil },
+	)
+}
+
+// SubscribeLifecycleEvents registers `fn` to be called for every
+// [LifecycleEvent], allowing per-block and per-transaction resources to be tied
+// to the lifetime of an EVM. Subscribers are called synchronously, in order of
+// subscription, on the goroutine using the EVM; they MUST NOT block and MUST
+// NOT (un)subscribe from within `fn`.
+//
+// The returned function unsubscribes `fn` and is idempotent. As with all other
+// registration, neither function may be called after [register.Freeze], and
+// both panic with [register.ErrFrozen] if they are.
+func SubscribeLifecycleEvents(fn func(*LifecycleEvent)) (unsubscribe func()) {
+	sub := &lifecycleSubscriber{fn}
+	mustGuard(func() {
+		lifecycle.Lock()
+		defer lifecycle.Unlock()
+		lifecycle.subscribers = append(lifecycle.subscribers, sub)
+		lifecycle.num.Add(1)
+	})
+
+	var once sync.Once
+	return func() {
+		mustGuard(func() {
+			once.Do(func() {
+				lifecycle.Lock()
+				defer lifecycle.Unlock()
+				for i, s := range lifecycle.subscribers {
+					if s == sub {
+						// Copy-on-write so concurrent emitters holding the old
+						// slice are unaffected.
+						subs := make([]*lifecycleSubscriber, 0, len(lifecycle.subscribers)-1)
+						subs = append(subs, lifecycle.subscribers[:i]...)
+						lifecycle.subscribers = append(subs, lifecycle.subscribers[i+1:]...)
+						lifecycle.num.Add(-1)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+// mustGuard calls `fn` via [register.Guard], panicking on error.
+func mustGuard(fn func()) {
+	err := register.Guard(func() error {
+		fn()
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (evm *EVM) emitLifecycleEvent(kind LifecycleEventKind) {
+	var subs []*lifecycleSubscriber
+	if f := lifecycle.frozen; f != nil {
+		subs = *f
+	} else if lifecycle.num.Load() > 0 {
+		lifecycle.RLock()
+		subs = lifecycle.subscribers
+		lifecycle.RUnlock()
+	}
+	if len(subs) == 0 {
+		return
+	}
+	if _, ok := evm.StateDB.(*readOnlyStateDB); ok {
+		// Standalone environments have no end of life so would otherwise never
+		// be reported as finished.
+		return
+	}
+
+	ev := &LifecycleEvent{
+		Kind:         kind,
+		EVM:          evm,
+		ChainConfig:  evm.chainConfig,
+		Rules:        evm.chainRules,
+		BlockContext: evm.Context,
+		TxContext:    evm.TxContext,
+	}
+	for _, s := range subs {
+		s.fn(ev)
+	}
+}
+
+// Finish signals that the EVM will no longer be used, emitting an
+// [EVMFinished] event to all subscribers of [SubscribeLifecycleEvents]. Only
+// the first call has any effect. It MUST be called by the creator of every EVM
+// returned by [NewEVM], typically with `defer`, otherwise any resources that
+// subscribers tie to the EVM are leaked.
+func (evm *EVM) Finish() {
+	if evm.finished {
+		return
+	}
+	evm.finished = true
+	evm.emitLifecycleEvent(EVMFinished)
+}
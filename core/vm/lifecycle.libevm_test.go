@@ -0,0 +1,85 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/register"
+)
+
+func TestLifecycleEvents(t *testing.T) {
+	type record struct {
+		kind   vm.LifecycleEventKind
+		evm    *vm.EVM
+		origin common.Address
+	}
+	var got []record
+	unsubscribe := vm.SubscribeLifecycleEvents(func(ev *vm.LifecycleEvent) {
+		got = append(got, record{ev.Kind, ev.EVM, ev.TxContext.Origin})
+	})
+	t.Cleanup(unsubscribe)
+
+	sdb, evm := ethtest.NewZeroEVM(t)
+	origin := common.Address{'o', 'r', 'i', 'g', 'i', 'n'}
+	evm.Reset(vm.TxContext{Origin: origin}, sdb)
+	evm.Finish()
+	evm.Finish() // no-op
+
+	want := []record{
+		{vm.EVMCreated, evm, common.Address{}},
+		{vm.EVMReset, evm, origin},
+		{vm.EVMFinished, evm, origin},
+	}
+	require.Equal(t, want, got, "lifecycle events")
+
+	unsubscribe()
+	unsubscribe() // idempotent
+	got = nil
+	ethtest.NewZeroEVM(t)
+	assert.Empty(t, got, "events after unsubscribing")
+}
+
+func TestLifecycleEventsAfterFreeze(t *testing.T) {
+	var got []vm.LifecycleEventKind
+	unsubscribe := vm.SubscribeLifecycleEvents(func(ev *vm.LifecycleEvent) {
+		got = append(got, ev.Kind)
+	})
+	t.Cleanup(unsubscribe)
+
+	register.Freeze()
+	t.Cleanup(register.TestOnlyUnfreeze) // no-op unless the test fails early
+
+	_, evm := ethtest.NewZeroEVM(t)
+	evm.Finish()
+	assert.Equal(t, []vm.LifecycleEventKind{vm.EVMCreated, vm.EVMFinished}, got, "events after register.Freeze()")
+
+	assert.PanicsWithValue(t, register.ErrFrozen, func() { vm.SubscribeLifecycleEvents(func(*vm.LifecycleEvent) {}) }, "SubscribeLifecycleEvents() after register.Freeze()")
+	assert.PanicsWithValue(t, register.ErrFrozen, unsubscribe, "unsubscribing after register.Freeze()")
+
+	register.TestOnlyUnfreeze()
+	unsubscribe()
+	got = nil
+	ethtest.NewZeroEVM(t)
+	assert.Empty(t, got, "events after unfreezing and unsubscribing")
+}
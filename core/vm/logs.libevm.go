@@ -0,0 +1,141 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/libevm/accounts/abi"
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/params"
+)
+
+// ErrTooManyTopics is returned when emitting a log with more topics than
+// supported by the LOG* op codes.
+var ErrTooManyTopics = errors.New("too many log topics")
+
+// maxLogTopics is the number of topics supported by LOG4.
+const maxLogTopics = 4
+
+func (e *environment) AddLog(topics []common.Hash, data []byte) error {
+	if e.ReadOnly() {
+		return ErrWriteProtection
+	}
+	if n := len(topics); n > maxLogTopics {
+		return fmt.Errorf("%w: %d > %d", ErrTooManyTopics, n, maxLogTopics)
+	}
+	l := &types.Log{
+		// As with the LOG* op codes, the address is that of the contract in
+		// whose context the code is running; i.e. the caller's, if
+		// DELEGATECALLed.
+		Address: e.self.Address(),
+		Topics:  append([]common.Hash(nil), topics...),
+		Data:    common.CopyBytes(data),
+		// See comment in [makeLog] re this non-consensus field.
+		BlockNumber: e.evm.Context.BlockNumber.Uint64(),
+	}
+	e.evm.StateDB.AddLog(l)
+	e.tracePrecompileLog(l)
+	return nil
+}
+
+func (e *environment) EmitEvent(ev abi.Event, args ...any) error {
+	topics, data, err := PackEvent(ev, args...)
+	if err != nil {
+		return err
+	}
+	return e.AddLog(topics, data)
+}
+
+// PackEvent returns the log topics and data encoding the event with the
+// arguments, which MUST be in the order of `ev.Inputs`, irrespective of whether
+// they are indexed.
+func PackEvent(ev abi.Event, args ...any) (topics []common.Hash, data []byte, _ error) {
+	if got, want := len(args), len(ev.Inputs); got != want {
+		return nil, nil, fmt.Errorf("event %s: %d arguments; want %d", ev.Sig, got, want)
+	}
+
+	var (
+		indexed    [][]any
+		nonIndexed []any
+	)
+	for i, in := range ev.Inputs {
+		if in.Indexed {
+			indexed = append(indexed, []any{args[i]})
+		} else {
+			nonIndexed = append(nonIndexed, args[i])
+		}
+	}
+
+	if !ev.Anonymous {
+		topics = append(topics, ev.ID)
+	}
+	if len(indexed) > 0 {
+		ts, err := abi.MakeTopics(indexed...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("event %s: %v", ev.Sig, err)
+		}
+		for _, t := range ts {
+			topics = append(topics, t[0])
+		}
+	}
+
+	data, err := ev.Inputs.NonIndexed().Pack(nonIndexed...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("event %s: %v", ev.Sig, err)
+	}
+	return topics, data, nil
+}
+
+// UnpackEvent is the inverse of [PackEvent], returning the arguments of the
+// event encoded by the log, keyed by name. Indexed arguments of dynamic type
+// are the Keccak256 hash of their value. The log's first topic is assumed to
+// identify the event, and is therefore ignored, unless it is anonymous.
+func UnpackEvent(ev abi.Event, l *types.Log) (map[string]any, error) {
+	topics := l.Topics
+	if !ev.Anonymous {
+		if len(topics) == 0 {
+			return nil, fmt.Errorf("event %s: log without topics", ev.Sig)
+		}
+		topics = topics[1:]
+	}
+
+	var indexed abi.Arguments
+	for _, arg := range ev.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	args := make(map[string]any)
+	if err := abi.ParseTopicsIntoMap(args, indexed, topics); err != nil {
+		return nil, fmt.Errorf("event %s: decoding topics: %v", ev.Sig, err)
+	}
+	if err := ev.Inputs.NonIndexed().UnpackIntoMap(args, l.Data); err != nil {
+		return nil, fmt.Errorf("event %s: decoding data: %v", ev.Sig, err)
+	}
+	return args, nil
+}
+
+// LogGas returns the gas that would be charged by a LOG* op code emitting a
+// log with the number of topics and length of data, excluding memory
+// expansion. Stateful precompiles SHOULD charge equivalent gas, via
+// [PrecompileEnvironment.UseGas], when emitting logs.
+func LogGas(topics int, dataLen uint64) uint64 {
+	return params.LogGas + uint64(topics)*params.LogTopicGas + dataLen*params.LogDataGas //nolint:gosec // topics is bounded by caller
+}
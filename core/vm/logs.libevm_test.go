@@ -0,0 +1,122 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/accounts/abi"
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestPrecompileLogs(t *testing.T) {
+	const eventABI = `[{"type":"event","name":"Transfer","inputs":[` +
+		`{"name":"from","type":"address","indexed":true},` +
+		`{"name":"to","type":"address","indexed":true},` +
+		`{"name":"value","type":"uint256","indexed":false}]}]`
+	parsed, err := abi.JSON(strings.NewReader(eventABI))
+	require.NoError(t, err, "abi.JSON()")
+	transfer := parsed.Events["Transfer"]
+
+	rng := ethtest.NewPseudoRand(7512)
+	var (
+		precompile = rng.Address()
+		from       = rng.Address()
+		to         = rng.Address()
+		value      = big.NewInt(42)
+		rawTopic   = rng.Hash()
+		rawData    = []byte("raw")
+	)
+
+	var gotErrs []error
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				gotErrs = []error{
+					env.AddLog([]common.Hash{rawTopic}, rawData),
+					env.EmitEvent(transfer, from, to, value),
+					env.AddLog(make([]common.Hash, 5), nil),
+				}
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	const blockNum = 1234
+	sdb, evm := ethtest.NewZeroEVM(t, ethtest.WithBlockContext(vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		BlockNumber: big.NewInt(blockNum),
+	}))
+	txHash := rng.Hash()
+	sdb.SetTxContext(txHash, 3)
+
+	caller := vm.AccountRef(rng.Address())
+	_, _, err = evm.Call(caller, precompile, nil, 1e6, uint256.NewInt(0))
+	require.NoError(t, err, "evm.Call()")
+	require.Len(t, gotErrs, 3)
+	assert.NoError(t, gotErrs[0], "AddLog()")
+	assert.NoError(t, gotErrs[1], "EmitEvent()")
+	assert.ErrorIs(t, gotErrs[2], vm.ErrTooManyTopics, "AddLog() with 5 topics")
+
+	wantData := common.BigToHash(value).Bytes()
+	want := []*types.Log{
+		{
+			Address:     precompile,
+			Topics:      []common.Hash{rawTopic},
+			Data:        rawData,
+			BlockNumber: blockNum,
+			TxHash:      txHash,
+			TxIndex:     3,
+			Index:       0,
+		},
+		{
+			Address: precompile,
+			Topics: []common.Hash{
+				crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")),
+				common.BytesToHash(from.Bytes()),
+				common.BytesToHash(to.Bytes()),
+			},
+			Data:        wantData,
+			BlockNumber: blockNum,
+			TxHash:      txHash,
+			TxIndex:     3,
+			Index:       1,
+		},
+	}
+	assert.Equal(t, want, sdb.GetLogs(txHash, blockNum, common.Hash{}), "logs")
+
+	_, _, err = evm.StaticCall(caller, precompile, nil, 1e6)
+	require.NoError(t, err, "evm.StaticCall()")
+	for i, err := range gotErrs[:2] {
+		assert.ErrorIsf(t, err, vm.ErrWriteProtection, "error %d in read-only context", i)
+	}
+}
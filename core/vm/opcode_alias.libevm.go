@@ -0,0 +1,107 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/log"
+)
+
+// A PrecompileOpCodeAliaser MAY be implemented by a [params.RulesHooks] to
+// expose precompiles as first-class "native instructions". Each alias maps an
+// otherwise undefined opcode to an implicit call to a precompile.
+type PrecompileOpCodeAliaser interface {
+	PrecompileOpCodeAliases() []PrecompileOpCodeAlias
+}
+
+// A PrecompileOpCodeAlias maps an undefined opcode to a call to the precompile
+// at a fixed address.
+//
+// The instruction pops Inputs words from the stack, concatenating them, in
+// order of popping, as the call data. The precompile is then called, as with
+// CALL, with zero value and all remaining gas; its gas is accounted for as
+// usual and any unused gas is returned. The first Outputs words of the
+// returned data, right-padded with zeros if necessary, are pushed such that the
+// first word is at the top of the stack, and the full return data is available
+// via RETURNDATASIZE and RETURNDATACOPY. An error returned by the precompile
+// halts the calling frame as if returned by the instruction itself.
+//
+// The instruction itself costs [GasQuickStep].
+type PrecompileOpCodeAlias struct {
+	OpCode          OpCode
+	Precompile      common.Address
+	Inputs, Outputs int
+}
+
+func (evm *EVM) precompileOpCodeAliases() []PrecompileOpCodeAlias {
+	a, ok := evm.chainRules.Hooks().(PrecompileOpCodeAliaser)
+	if !ok {
+		return nil
+	}
+	return a.PrecompileOpCodeAliases()
+}
+
+// enablePrecompileOpCodeAliases modifies the table in place. Aliases of
+// defined opcodes are logged and ignored, in keeping with the treatment of
+// unsupported EIPs.
+func (evm *EVM) enablePrecompileOpCodeAliases(table *JumpTable, aliases []PrecompileOpCodeAlias) {
+	for _, a := range aliases {
+		if op := table[a.OpCode]; a.OpCode == STOP || op.HasCost() || a.Inputs < 0 || a.Outputs < 0 {
+			log.Error(
+				"Invalid precompile opcode alias via libevm hook",
+				"opcode", a.OpCode,
+				"precompile", a.Precompile,
+				"inputs", a.Inputs,
+				"outputs", a.Outputs,
+			)
+			continue
+		}
+		table[a.OpCode] = &operation{
+			execute:     makePrecompileOpCodeAlias(a),
+			constantGas: GasQuickStep,
+			minStack:    minStack(a.Inputs, a.Outputs),
+			maxStack:    maxStack(a.Inputs, a.Outputs),
+		}
+	}
+}
+
+func makePrecompileOpCodeAlias(a PrecompileOpCodeAlias) executionFunc {
+	return func(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+		input := make([]byte, 32*a.Inputs)
+		for i := 0; i < a.Inputs; i++ {
+			w := scope.Stack.pop()
+			w.WriteToSlice(input[32*i : 32*(i+1)])
+		}
+
+		gas := scope.Contract.Gas
+		scope.Contract.Gas = 0
+		ret, returnGas, err := interpreter.evm.Call(scope.Contract, a.Precompile, input, gas, new(uint256.Int))
+		scope.Contract.Gas += returnGas
+		if err != nil {
+			return nil, err
+		}
+
+		out := common.RightPadBytes(ret, 32*a.Outputs)
+		for i := a.Outputs - 1; i >= 0; i-- {
+			scope.Stack.push(new(uint256.Int).SetBytes(out[32*i : 32*(i+1)]))
+		}
+		interpreter.returnData = ret
+		return nil, nil
+	}
+}
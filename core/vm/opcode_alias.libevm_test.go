@@ -0,0 +1,126 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+type opCodeAliasHooks struct {
+	hookstest.Stub
+	aliases []vm.PrecompileOpCodeAlias
+}
+
+func (h *opCodeAliasHooks) PrecompileOpCodeAliases() []vm.PrecompileOpCodeAlias {
+	return h.aliases
+}
+
+func TestPrecompileOpCodeAlias(t *testing.T) {
+	const (
+		alias       = vm.OpCode(0x0c) // undefined in all forks
+		precompGas  = 1000
+		gasLimit    = 1e6
+		errSentinel = 0xff
+	)
+	var (
+		precompile = common.Address{'n', 'a', 't', 'i', 'v', 'e'}
+		contract   = common.Address{'c', 'o', 'd', 'e'}
+	)
+	errPrecompile := errors.New("precompile error")
+
+	// The precompile returns the difference between its two input words,
+	// demonstrating input ordering, and errors if the first is errSentinel.
+	hooks := &opCodeAliasHooks{
+		Stub: hookstest.Stub{
+			PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+				precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+					if !env.UseGas(precompGas) {
+						return nil, vm.ErrOutOfGas
+					}
+					a := new(uint256.Int).SetBytes(input[:32])
+					if a.Uint64() == errSentinel {
+						return nil, errPrecompile
+					}
+					b := new(uint256.Int).SetBytes(input[32:])
+					return a.Sub(a, b).PaddedBytes(32), nil
+				}),
+			},
+		},
+	}
+	hookstest.Register(t, params.Extras[*opCodeAliasHooks, *opCodeAliasHooks]{
+		NewRules: func(*params.ChainConfig, *params.Rules, *opCodeAliasHooks, *big.Int, bool, uint64) *opCodeAliasHooks {
+			return hooks
+		},
+	})
+
+	code := func(first byte) []byte {
+		return convertBytes[vm.OpCode, byte](
+			vm.PUSH1, 2,
+			vm.PUSH1, vm.OpCode(first),
+			alias,
+			vm.PUSH1, 0,
+			vm.MSTORE,
+			vm.PUSH1, 32,
+			vm.PUSH1, 0,
+			vm.RETURN,
+		)
+	}
+
+	t.Run("not_aliased", func(t *testing.T) {
+		hooks.aliases = nil
+		sdb, evm := ethtest.NewZeroEVM(t)
+		sdb.SetCode(contract, code(40))
+		_, _, err := evm.Call(vm.AccountRef{}, contract, nil, gasLimit, uint256.NewInt(0))
+		require.IsType(t, &vm.ErrInvalidOpCode{}, err, "%T.Call() error", evm)
+	})
+
+	hooks.aliases = []vm.PrecompileOpCodeAlias{{
+		OpCode:     alias,
+		Precompile: precompile,
+		Inputs:     2,
+		Outputs:    1,
+	}}
+
+	t.Run("aliased", func(t *testing.T) {
+		sdb, evm := ethtest.NewZeroEVM(t)
+		sdb.SetCode(contract, code(40))
+		got, gasLeft, err := evm.Call(vm.AccountRef{}, contract, nil, gasLimit, uint256.NewInt(0))
+		require.NoError(t, err, "%T.Call()", evm)
+		assert.Equal(t, uint256.NewInt(38).PaddedBytes(32), got, "returned output of aliased precompile")
+		assert.GreaterOrEqual(t, uint64(gasLimit)-gasLeft, uint64(precompGas), "gas used includes precompile")
+	})
+
+	t.Run("precompile_error", func(t *testing.T) {
+		sdb, evm := ethtest.NewZeroEVM(t)
+		sdb.SetCode(contract, code(errSentinel))
+		_, _, err := evm.Call(vm.AccountRef{}, contract, nil, gasLimit, uint256.NewInt(0))
+		require.ErrorIs(t, err, errPrecompile, "%T.Call() error", evm)
+	})
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+)
+
+// OpCodeHooks, if set as [Config.OpCodeHooks], are called for every op code
+// executed by the [EVM], allowing policies such as banning op codes in certain
+// contexts to be enforced without forking the interpreter. Unlike an
+// [EVMLogger], they can affect execution: a non-nil error halts the current
+// frame exactly as if returned by the op code itself.
+type OpCodeHooks interface {
+	// BeforeOpCode is called after all gas for the op code has been charged,
+	// and memory expanded, but before it is executed.
+	BeforeOpCode(OpCodeScope) error
+	// AfterOpCode is called after the op code is executed, with the error that
+	// it returned, if any. It is not called if the op code halted execution
+	// without error; e.g. STOP or RETURN. A non-nil error replaces that of the
+	// op code, but returning nil does not clear it.
+	AfterOpCode(_ OpCodeScope, opErr error) error
+}
+
+// An OpCodeScope is a read-only view of the execution context of an op code,
+// passed to [OpCodeHooks]. It MUST NOT be retained after the hook returns.
+type OpCodeScope struct {
+	pc       uint64
+	op       OpCode
+	depth    int
+	readOnly bool
+	scope    *ScopeContext
+}
+
+// PC returns the program counter of the op code.
+func (s OpCodeScope) PC() uint64 { return s.pc }
+
+// OpCode returns the op code being executed.
+func (s OpCodeScope) OpCode() OpCode { return s.op }
+
+// Depth returns the call depth, with the same semantics as that passed to
+// [EVMLogger.CaptureState].
+func (s OpCodeScope) Depth() int { return s.depth }
+
+// ReadOnly returns whether the op code is executing in a static context.
+func (s OpCodeScope) ReadOnly() bool { return s.readOnly }
+
+// Address returns the address of the contract in whose context the op code is
+// executing, which differs from [OpCodeScope.CodeAddress] under DELEGATECALL.
+func (s OpCodeScope) Address() common.Address { return s.scope.Contract.Address() }
+
+// CodeAddress returns the address of the code being executed, or the zero
+// address if there is none; e.g. during contract creation.
+func (s OpCodeScope) CodeAddress() common.Address {
+	if a := s.scope.Contract.CodeAddr; a != nil {
+		return *a
+	}
+	return common.Address{}
+}
+
+// Caller returns the address of the contract's caller.
+func (s OpCodeScope) Caller() common.Address { return s.scope.Contract.Caller() }
+
+// Value returns a copy of the value sent with the call.
+func (s OpCodeScope) Value() *uint256.Int {
+	if v := s.scope.Contract.Value(); v != nil {
+		return new(uint256.Int).Set(v)
+	}
+	return new(uint256.Int)
+}
+
+// Gas returns the gas remaining to the contract.
+func (s OpCodeScope) Gas() uint64 { return s.scope.Contract.Gas }
+
+// StackLen returns the number of items on the stack.
+func (s OpCodeScope) StackLen() int { return s.scope.Stack.len() }
+
+// StackBack returns a copy of the n-th item from the top of the stack, zero
+// being the top. The returned boolean is false if there is no such item.
+func (s OpCodeScope) StackBack(n int) (uint256.Int, bool) {
+	if n < 0 || n >= s.StackLen() {
+		return uint256.Int{}, false
+	}
+	return *s.scope.Stack.Back(n), true
+}
+
+// MemoryLen returns the size of the memory.
+func (s OpCodeScope) MemoryLen() uint64 { return uint64(s.scope.Memory.Len()) }
+
+// MemoryCopy returns a copy of `size` bytes of memory, starting at `offset`.
+// Bytes beyond the current size of the memory are returned as zeroes, which
+// allows the arguments to be taken from the stack without bounds checking.
+func (s OpCodeScope) MemoryCopy(offset, size uint64) []byte {
+	out := make([]byte, size)
+	if mem := s.scope.Memory.Data(); offset < uint64(len(mem)) {
+		copy(out, mem[offset:])
+	}
+	return out
+}
+
+// beforeOpCode and afterOpCode are called by [EVMInterpreter.Run] if
+// [Config.OpCodeHooks] is non-nil.
+func (in *EVMInterpreter) beforeOpCode(h OpCodeHooks, pc uint64, op OpCode, scope *ScopeContext) error {
+	return h.BeforeOpCode(in.opCodeScope(pc, op, scope))
+}
+
+func (in *EVMInterpreter) afterOpCode(h OpCodeHooks, pc uint64, op OpCode, scope *ScopeContext, opErr error) error {
+	if opErr == errStopToken {
+		return opErr
+	}
+	if err := h.AfterOpCode(in.opCodeScope(pc, op, scope), opErr); err != nil {
+		return err
+	}
+	return opErr
+}
+
+func (in *EVMInterpreter) opCodeScope(pc uint64, op OpCode, scope *ScopeContext) OpCodeScope {
+	return OpCodeScope{
+		pc:       pc,
+		op:       op,
+		depth:    in.evm.depth,
+		readOnly: in.readOnly,
+		scope:    scope,
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+)
+
+type opCodeHookRecorder struct {
+	banned, failAfter vm.OpCode
+	err               error
+
+	ops                   []vm.OpCode
+	pcs                   []uint64
+	addTopBefore          []uint256.Int
+	addTopAfter           uint256.Int
+	memAfterMStore        []byte
+	contract, codeAddress common.Address
+}
+
+func (r *opCodeHookRecorder) BeforeOpCode(s vm.OpCodeScope) error {
+	r.ops = append(r.ops, s.OpCode())
+	r.pcs = append(r.pcs, s.PC())
+	r.contract, r.codeAddress = s.Address(), s.CodeAddress()
+
+	if s.OpCode() == vm.ADD {
+		for i := 0; i < 2; i++ {
+			w, _ := s.StackBack(i)
+			r.addTopBefore = append(r.addTopBefore, w)
+		}
+	}
+	if s.OpCode() == r.banned {
+		return r.err
+	}
+	return nil
+}
+
+func (r *opCodeHookRecorder) AfterOpCode(s vm.OpCodeScope, opErr error) error {
+	switch s.OpCode() {
+	case vm.ADD:
+		r.addTopAfter, _ = s.StackBack(0)
+	case vm.MSTORE:
+		r.memAfterMStore = s.MemoryCopy(0, 40)
+	}
+	if s.OpCode() == r.failAfter {
+		return r.err
+	}
+	return opErr
+}
+
+func TestOpCodeHooks(t *testing.T) {
+	contract := common.Address{'c', 'o', 'd', 'e'}
+	code := convertBytes[vm.OpCode, byte](
+		vm.PUSH1, 2,
+		vm.PUSH1, 3,
+		vm.ADD,
+		vm.PUSH1, 0,
+		vm.MSTORE,
+		vm.CALLER,
+		vm.STOP,
+	)
+	errPolicy := errors.New("policy violation")
+	const gasLimit = 1e6
+
+	tests := []struct {
+		name              string
+		banned, failAfter vm.OpCode
+		wantOps           []vm.OpCode
+		wantErr           error
+	}{
+		{
+			name:    "no_policy",
+			banned:  vm.INVALID,
+			wantOps: []vm.OpCode{vm.PUSH1, vm.PUSH1, vm.ADD, vm.PUSH1, vm.MSTORE, vm.CALLER, vm.STOP},
+		},
+		{
+			name:    "banned_before",
+			banned:  vm.CALLER,
+			wantOps: []vm.OpCode{vm.PUSH1, vm.PUSH1, vm.ADD, vm.PUSH1, vm.MSTORE, vm.CALLER},
+			wantErr: errPolicy,
+		},
+		{
+			name:      "failed_after",
+			banned:    vm.INVALID,
+			failAfter: vm.MSTORE,
+			wantOps:   []vm.OpCode{vm.PUSH1, vm.PUSH1, vm.ADD, vm.PUSH1, vm.MSTORE},
+			wantErr:   errPolicy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &opCodeHookRecorder{
+				banned:    tt.banned,
+				failAfter: tt.failAfter,
+				err:       errPolicy,
+			}
+			sdb, evm := ethtest.NewZeroEVM(t)
+			sdb.SetCode(contract, code)
+			evm.Config.OpCodeHooks = rec
+
+			_, gasLeft, err := evm.Call(vm.AccountRef{}, contract, nil, gasLimit, uint256.NewInt(0))
+			require.ErrorIs(t, err, tt.wantErr, "%T.Call()", evm)
+			if tt.wantErr != nil {
+				assert.Zero(t, gasLeft, "gas remaining after error")
+			}
+
+			assert.Equal(t, tt.wantOps, rec.ops, "op codes passed to BeforeOpCode()")
+			assert.Equal(t, []uint64{0, 2, 4, 5, 7, 8, 9}[:len(tt.wantOps)], rec.pcs, "PCs passed to BeforeOpCode()")
+			assert.Equal(t, contract, rec.contract, "OpCodeScope.Address()")
+			assert.Equal(t, contract, rec.codeAddress, "OpCodeScope.CodeAddress()")
+
+			assert.Equal(t, []uint256.Int{*uint256.NewInt(3), *uint256.NewInt(2)}, rec.addTopBefore, "top of stack before ADD")
+			assert.Equal(t, *uint256.NewInt(5), rec.addTopAfter, "top of stack after ADD")
+
+			wantMem := make([]byte, 40)
+			wantMem[31] = 5
+			assert.Equal(t, wantMem, rec.memAfterMStore, "zero-padded memory after MSTORE")
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright 2024 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -16,10 +16,40 @@
 
 package vm
 
-import "github.com/ava-labs/libevm/libevm/options"
+import (
+	"errors"
+
+	"github.com/ava-labs/libevm/libevm/options"
+)
 
 type callConfig struct {
 	unsafeCallerAddressProxying bool
+	gasForwarding               gasForwarding
+	valueFromPrecompileBalance  bool
+	sponsoredGas                uint64
+}
+
+// gasForwarding determines how much of a precompile's gas is forwarded to a
+// contract that it calls.
+type gasForwarding uint8
+
+const (
+	exactGas gasForwarding = iota // default
+	allButOne64thGas
+	allRemainingGas
+)
+
+// forwardedGas returns the amount of gas to forward given the amount requested
+// and that available to the precompile.
+func (f gasForwarding) forwardedGas(requested, available uint64) uint64 {
+	switch f {
+	case allButOne64thGas:
+		return min(requested, available-available/64)
+	case allRemainingGas:
+		return available
+	default:
+		return requested
+	}
 }
 
 // A CallOption modifies the default behaviour of a contract call.
@@ -37,3 +67,68 @@ func WithUNSAFECallerAddressProxying() CallOption {
 		c.unsafeCallerAddressProxying = true
 	})
 }
+
+// WithExactGas results in precompiles forwarding exactly the amount of gas
+// requested when making contract calls, failing with [ErrOutOfGas] if
+// insufficient is available. This is the default behaviour, and the option
+// exists only to make it explicit. Any gas left over by the callee is credited
+// back to the precompile, regardless of the option used.
+func WithExactGas() CallOption {
+	return withGasForwarding(exactGas)
+}
+
+// WithEIP150GasRetention results in precompiles forwarding at most all but one
+// 64th of their available gas, as the CALL op codes do since EIP-150, instead
+// of failing if the amount requested is greater.
+func WithEIP150GasRetention() CallOption {
+	return withGasForwarding(allButOne64thGas)
+}
+
+// WithAllRemainingGas results in precompiles forwarding all of their
+// available gas, ignoring the amount requested.
+func WithAllRemainingGas() CallOption {
+	return withGasForwarding(allRemainingGas)
+}
+
+func withGasForwarding(f gasForwarding) CallOption {
+	return options.Func[callConfig](func(c *callConfig) {
+		c.gasForwarding = f
+	})
+}
+
+// WithValueFromPrecompileBalance results in the value sent by a precompile's
+// CALL being debited from the precompile's own account, allowing it to disburse
+// funds that it holds; e.g. collected fees or escrow. Without this option the
+// value is debited from the account of the EVM-semantic caller, which is the
+// precompile itself for a regular CALL, but not if the precompile was invoked
+// via DELEGATECALL or used [WithUNSAFECallerAddressProxying]. The call fails
+// with [ErrInsufficientBalance] if the precompile's balance is too low.
+func WithValueFromPrecompileBalance() CallOption {
+	return options.Func[callConfig](func(c *callConfig) {
+		c.valueFromPrecompileBalance = true
+	})
+}
+
+// ErrSponsorshipExceedsBudget is returned by a precompile's contract call if
+// the gas to be sponsored, as requested by [WithGasSponsorship], is greater
+// than the precompile's remaining sponsorship budget.
+var ErrSponsorshipExceedsBudget = errors.New("gas sponsorship exceeds budget")
+
+// WithGasSponsorship results in up to `gas` of that forwarded by a precompile's
+// contract call being paid for out of gas that the precompile has already
+// consumed via [PrecompileEnvironment.UseGas], instead of being deducted from
+// its available gas. This allows a precompile to pre-charge its caller and to
+// then fund calls on their behalf; e.g. for meta-transactions. The call fails
+// with [ErrSponsorshipExceedsBudget], without being made, if the sponsored
+// amount exceeds the gas so consumed less that already used for sponsorship.
+//
+// No gas is created: the callee's frame is traced with the full amount
+// forwarded, while the gas used by the precompile's frame, which includes that
+// used by the callee, is reduced by the sponsored amount because it was already
+// accounted for by UseGas(). Gas left over by the callee is
+// credited back to the precompile as usual, but not to its sponsorship budget.
+func WithGasSponsorship(gas uint64) CallOption {
+	return options.Func[callConfig](func(c *callConfig) {
+		c.sponsoredGas = gas
+	})
+}
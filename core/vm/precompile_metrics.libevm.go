@@ -0,0 +1,63 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"time"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/metrics"
+)
+
+// PrecompileMetricName returns the name under which the metric is registered
+// for the precompile at `addr` if [Config.PrecompileMetrics] is set. The
+// metric MUST be one of "calls", "gas", or "errors", all of which are
+// [metrics.Meter]s, or "time", which is a [metrics.Timer].
+//
+// The "gas" meter is marked with the gas charged by the precompile itself
+// (i.e. RequiredGas() plus any consumed by a stateful precompile), which
+// excludes gas forfeited by the caller upon error. Error rates can be derived
+// from the ratio of "errors" to "calls".
+func PrecompileMetricName(addr common.Address, metric string) string {
+	return "vm/precompile/" + addr.Hex() + "/" + metric
+}
+
+func (args *evmCallArgs) precompileMetrics() metrics.Registry {
+	if args.evm == nil { // see [RunPrecompiledContract] in tests
+		return nil
+	}
+	return args.evm.Config.PrecompileMetrics
+}
+
+// recordPrecompileMetrics records a completed call of `p`, at `args.addr`,
+// which started with `gasBefore` remaining (after deduction of RequiredGas()).
+func (args *evmCallArgs) recordPrecompileMetrics(r metrics.Registry, p PrecompiledContract, input []byte, gasBefore uint64, took time.Duration, err error) {
+	name := func(m string) string {
+		return PrecompileMetricName(args.addr, m)
+	}
+	gas := p.RequiredGas(input)
+	if gasBefore > args.gasRemaining {
+		gas += gasBefore - args.gasRemaining
+	}
+
+	metrics.GetOrRegisterMeter(name("calls"), r).Mark(1)
+	metrics.GetOrRegisterMeter(name("gas"), r).Mark(int64(gas)) //nolint:gosec // Overflow would require > 2^63 gas
+	metrics.GetOrRegisterTimer(name("time"), r).Update(took)
+	if err != nil {
+		metrics.GetOrRegisterMeter(name("errors"), r).Mark(1)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/metrics"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestPrecompileMetrics(t *testing.T) {
+	if !metrics.Enabled {
+		t.Skip("metrics disabled")
+	}
+
+	const statefulGas = 100
+	errFail := errors.New("fail")
+
+	rng := ethtest.NewPseudoRand(7357)
+	stateful := rng.Address()
+	identity := common.BytesToAddress([]byte{4})
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			stateful: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				if !env.UseGas(statefulGas) {
+					return nil, vm.ErrOutOfGas
+				}
+				if len(input) > 0 && input[0] == 1 {
+					return nil, errFail
+				}
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	reg := metrics.NewRegistry()
+	_, evm := ethtest.NewZeroEVM(t)
+	evm.Config.PrecompileMetrics = reg
+
+	calls := []struct {
+		addr  common.Address
+		input []byte
+	}{
+		{stateful, []byte{0}},
+		{stateful, []byte{1}},
+		{stateful, []byte{0}},
+		{identity, make([]byte, 64)},
+	}
+	for _, c := range calls {
+		_, _, _ = evm.Call(vm.AccountRef(rng.Address()), c.addr, c.input, 1e6, uint256.NewInt(0))
+	}
+
+	identityGas := params.IdentityBaseGas + 2*params.IdentityPerWordGas
+	tests := []struct {
+		addr                common.Address
+		wantCalls, wantErrs int64
+		wantGas             int64
+	}{
+		{
+			addr:      stateful,
+			wantCalls: 3,
+			wantErrs:  1,
+			wantGas:   3 * statefulGas,
+		},
+		{
+			addr:      identity,
+			wantCalls: 1,
+			wantErrs:  0,
+			wantGas:   int64(identityGas),
+		},
+	}
+
+	for _, tt := range tests {
+		meter := func(m string) int64 {
+			t.Helper()
+			got, ok := reg.Get(vm.PrecompileMetricName(tt.addr, m)).(metrics.Meter)
+			if !ok {
+				return 0
+			}
+			return got.Snapshot().Count()
+		}
+		assert.Equalf(t, tt.wantCalls, meter("calls"), "%v calls", tt.addr)
+		assert.Equalf(t, tt.wantErrs, meter("errors"), "%v errors", tt.addr)
+		assert.Equalf(t, tt.wantGas, meter("gas"), "%v gas", tt.addr)
+
+		timer, ok := reg.Get(vm.PrecompileMetricName(tt.addr, "time")).(metrics.Timer)
+		require.Truef(t, ok, "%v timer registered", tt.addr)
+		assert.Equalf(t, tt.wantCalls, timer.Snapshot().Count(), "%v timer updates", tt.addr)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A PrefetchHinter is a [PrecompiledContract] that declares the accounts and
+// storage slots that a call with the given input will likely access, allowing
+// them to be prefetched before execution. Hints are advisory and have no
+// effect on execution; they SHOULD be cheap to compute.
+type PrefetchHinter interface {
+	PrecompiledContract
+	PrefetchHints(input []byte) types.AccessList
+}
+
+// WithPrefetchHints returns a [PrefetchHinter] that otherwise behaves
+// identically to `p`, which MAY be a stateful precompile. Static hints can be
+// provided by a function that ignores its input.
+func WithPrefetchHints(p PrecompiledContract, hints func(input []byte) types.AccessList) PrefetchHinter {
+	return &hintedPrecompile{p, hints}
+}
+
+type hintedPrecompile struct {
+	PrecompiledContract
+	hints func([]byte) types.AccessList
+}
+
+func (p *hintedPrecompile) PrefetchHints(input []byte) types.AccessList {
+	return p.hints(input)
+}
+
+func (p *hintedPrecompile) unwrap() PrecompiledContract {
+	return p.PrecompiledContract
+}
+
+// PrefetchHints returns the hints of the precompile, if any, that would be run
+// by a call to the address under the given rules. It returns nil if there is
+// no such precompile or if it isn't a [PrefetchHinter].
+func PrefetchHints(rules params.Rules, addr common.Address, input []byte) types.AccessList {
+	p, ok := PrecompileAt(rules, addr)
+	if !ok {
+		return nil
+	}
+	h, ok := precompileAs[PrefetchHinter](p)
+	if !ok {
+		return nil
+	}
+	return h.PrefetchHints(input)
+}
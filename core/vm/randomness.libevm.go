@@ -0,0 +1,72 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+)
+
+// BlockRandomnessHooks MAY be implemented by a [params.RulesHooks] to define
+// the value returned by the DIFFICULTY opcode and its post-merge successor,
+// PREVRANDAO; e.g. a VRF output carried in header extras instead of the beacon
+// chain's RANDAO.
+type BlockRandomnessHooks interface {
+	// BlockRandomness returns the value to be used for the block, and whether
+	// it overrides the default. It is only called if the [BlockContext] passed
+	// to [NewEVM] carries a Header, and MUST be deterministic.
+	//
+	// If overridden, the value replaces BlockContext.Random after the merge,
+	// and BlockContext.Difficulty before it, as determined by
+	// [params.Rules.IsMerge]. Whether or not the merge has occurred is
+	// unchanged.
+	BlockRandomness(*types.Header) (_ common.Hash, override bool)
+}
+
+// overrideBlockRandomness applies the [BlockRandomnessHooks], if any, to
+// evm.Context.
+func (evm *EVM) overrideBlockRandomness() {
+	br, ok := evm.chainRules.Hooks().(BlockRandomnessHooks)
+	if !ok || evm.Context.Header == nil {
+		return
+	}
+	r, ok := br.BlockRandomness(evm.Context.Header)
+	if !ok {
+		return
+	}
+	// Both fields are replaced, not modified, as they MAY alias those of the
+	// Header or of another BlockContext. The one replaced is that read by the
+	// opcode, which depends on the rules and not only on the context.
+	if evm.chainRules.IsMerge {
+		evm.Context.Random = &r
+	} else {
+		evm.Context.Difficulty = r.Big()
+	}
+}
+
+// BlockRandomness returns the value that the DIFFICULTY / PREVRANDAO opcode
+// would push, after applying any [BlockRandomnessHooks].
+func (e *environment) BlockRandomness() common.Hash {
+	switch ctx := e.evm.Context; {
+	case e.evm.chainRules.IsMerge && ctx.Random != nil:
+		return *ctx.Random
+	case ctx.Difficulty != nil:
+		return common.BigToHash(ctx.Difficulty)
+	default:
+		return common.Hash{}
+	}
+}
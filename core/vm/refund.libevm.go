@@ -0,0 +1,47 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrRefundUnderflow is returned by [PrecompileEnvironment.SubRefund] if the
+// amount exceeds the current value of the refund counter.
+var ErrRefundUnderflow = errors.New("refund counter below zero")
+
+func (e *environment) AddRefund(gas uint64) error {
+	if e.ReadOnly() {
+		return ErrWriteProtection
+	}
+	e.evm.StateDB.AddRefund(gas)
+	return nil
+}
+
+func (e *environment) SubRefund(gas uint64) error {
+	if e.ReadOnly() {
+		return ErrWriteProtection
+	}
+	// The StateDB panics on underflow, which is appropriate for the
+	// interpreter's own accounting but not for arbitrary precompiles.
+	if r := e.evm.StateDB.GetRefund(); gas > r {
+		return fmt.Errorf("%w: %d > %d", ErrRefundUnderflow, gas, r)
+	}
+	e.evm.StateDB.SubRefund(gas)
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestPrecompileRefund(t *testing.T) {
+	rng := ethtest.NewPseudoRand(760)
+	var (
+		precompile = rng.Address()
+		caller     = vm.AccountRef(rng.Address())
+	)
+
+	const (
+		add = iota
+		sub
+		addThenFail
+	)
+	errFail := errors.New("uh oh")
+
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				gas := uint64(input[1])
+				switch input[0] {
+				case add:
+					return nil, env.AddRefund(gas)
+				case sub:
+					return nil, env.SubRefund(gas)
+				case addThenFail:
+					if err := env.AddRefund(gas); err != nil {
+						return nil, err
+					}
+					return nil, errFail
+				}
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	sdb, evm := ethtest.NewZeroEVM(t)
+	call := func(t *testing.T, op, gas byte) error {
+		t.Helper()
+		_, _, err := evm.Call(caller, precompile, []byte{op, gas}, 1e6, uint256.NewInt(0))
+		return err
+	}
+
+	require.NoError(t, call(t, add, 100), "AddRefund(100)")
+	assert.Equal(t, uint64(100), sdb.GetRefund(), "after AddRefund(100)")
+
+	require.NoError(t, call(t, sub, 30), "SubRefund(30)")
+	assert.Equal(t, uint64(70), sdb.GetRefund(), "after SubRefund(30)")
+
+	assert.ErrorIs(t, call(t, sub, 71), vm.ErrRefundUnderflow, "SubRefund() exceeding counter")
+	assert.Equal(t, uint64(70), sdb.GetRefund(), "after failed SubRefund()")
+
+	assert.ErrorIs(t, call(t, addThenFail, 50), errFail, "AddRefund() then failing")
+	assert.Equal(t, uint64(70), sdb.GetRefund(), "refund reverted with precompile call")
+
+	for _, op := range []byte{add, sub} {
+		_, _, err := evm.StaticCall(caller, precompile, []byte{op, 1}, 1e6)
+		assert.ErrorIsf(t, err, vm.ErrWriteProtection, "op %d via StaticCall()", op)
+	}
+	assert.Equal(t, uint64(70), sdb.GetRefund(), "after read-only calls")
+}
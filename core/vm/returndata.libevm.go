@@ -0,0 +1,55 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+)
+
+// ReturnDataLimitHooks MAY be implemented by a [params.RulesHooks] to limit the
+// size of data returned (or reverted with) by any call frame, including
+// precompiles.
+//
+// This protects nodes on chains with custom precompiles that could otherwise
+// emit unbounded output into memory. A frame exceeding the limit fails with
+// [ErrReturnDataTooLarge], consuming all of its gas, and returns no data.
+type ReturnDataLimitHooks interface {
+	// MaxReturnDataSize returns the maximum number of bytes that a call frame
+	// may return, and whether the limit applies.
+	MaxReturnDataSize() (_ uint64, limited bool)
+}
+
+// ErrReturnDataTooLarge is returned by a call frame if the data it returns
+// exceeds the limit defined by [ReturnDataLimitHooks].
+var ErrReturnDataTooLarge = errors.New("return data exceeds limit")
+
+// limitReturnData returns `ret` and `err` unchanged unless `ret` exceeds any
+// limit imposed by [ReturnDataLimitHooks], in which case it returns
+// [ErrReturnDataTooLarge].
+func (evm *EVM) limitReturnData(ret []byte, err error) ([]byte, error) {
+	if len(ret) == 0 {
+		return ret, err
+	}
+	lh, ok := evm.chainRules.Hooks().(ReturnDataLimitHooks)
+	if !ok {
+		return ret, err
+	}
+	if limit, ok := lh.MaxReturnDataSize(); ok && uint64(len(ret)) > limit {
+		return nil, ErrReturnDataTooLarge
+	}
+	return ret, err
+}
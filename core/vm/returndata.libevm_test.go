@@ -0,0 +1,121 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+type returnDataLimitHooks struct {
+	hookstest.Stub
+	limit uint64
+}
+
+var _ vm.ReturnDataLimitHooks = (*returnDataLimitHooks)(nil)
+
+func (h *returnDataLimitHooks) MaxReturnDataSize() (uint64, bool) {
+	return h.limit, true
+}
+
+func TestReturnDataLimitHooks(t *testing.T) {
+	const limit = 32
+	rng := ethtest.NewPseudoRand(746)
+	precompile := rng.Address()
+	hooks := &returnDataLimitHooks{
+		Stub: hookstest.Stub{
+			PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+				precompile: vm.NewStatefulPrecompile(func(_ vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+					return bytes.Repeat([]byte{1}, int(input[0])), nil
+				}),
+			},
+		},
+		limit: limit,
+	}
+	hookstest.Register(t, params.Extras[*returnDataLimitHooks, *returnDataLimitHooks]{
+		NewRules: func(*params.ChainConfig, *params.Rules, *returnDataLimitHooks, *big.Int, bool, uint64) *returnDataLimitHooks {
+			return hooks
+		},
+	})
+
+	sdb, evm := ethtest.NewZeroEVM(t)
+	contract := rng.Address()
+	// PUSH1 0 CALLDATALOAD PUSH1 0 RETURN; i.e. return as many (zero) bytes as
+	// the first word of input.
+	sdb.SetCode(contract, convertBytes[vm.OpCode, byte](
+		vm.PUSH1, 0,
+		vm.CALLDATALOAD,
+		vm.PUSH1, 0,
+		vm.RETURN,
+	))
+
+	tests := []struct {
+		name    string
+		to      common.Address
+		input   []byte
+		wantErr error
+		wantLen int
+	}{
+		{
+			name:    "precompile at limit",
+			to:      precompile,
+			input:   []byte{limit},
+			wantLen: limit,
+		},
+		{
+			name:    "precompile over limit",
+			to:      precompile,
+			input:   []byte{limit + 1},
+			wantErr: vm.ErrReturnDataTooLarge,
+		},
+		{
+			name:    "contract at limit",
+			to:      contract,
+			input:   common.BigToHash(common.Big32).Bytes(),
+			wantLen: limit,
+		},
+		{
+			name:    "contract over limit",
+			to:      contract,
+			input:   common.BigToHash(common.Big256).Bytes(),
+			wantErr: vm.ErrReturnDataTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gasLeft, err := evm.Call(vm.AccountRef(rng.Address()), tt.to, tt.input, 1e6, uint256.NewInt(0))
+			require.ErrorIs(t, err, tt.wantErr, "evm.Call()")
+			assert.Len(t, got, tt.wantLen, "returned data")
+			if tt.wantErr != nil {
+				assert.Zero(t, gasLeft, "gas remaining after failure")
+			}
+		})
+	}
+}
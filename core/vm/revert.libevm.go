@@ -0,0 +1,104 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/libevm/accounts/abi"
+	"github.com/ava-labs/libevm/crypto"
+)
+
+// revertReasonSelector is the function selector of Solidity's `Error(string)`,
+// used by `revert("...")` and `require(..., "...")`.
+var revertReasonSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+
+// PackRevertReason returns the revert data that Solidity produces for
+// `revert(reason)`; i.e. the ABI encoding of `Error(reason)`, which is decoded
+// by [abi.UnpackRevert] and by RPC clients.
+func PackRevertReason(reason string) []byte {
+	typ, _ := abi.NewType("string", "", nil) // known to be valid
+	data, _ := abi.Arguments{{Type: typ}}.Pack(reason)
+	return append(append([]byte{}, revertReasonSelector...), data...)
+}
+
+// PackCustomError returns the revert data that Solidity produces for a custom
+// error; i.e. the first 4 bytes of `e.ID` followed by the ABI encoding of the
+// arguments, which MUST be in the order of `e.Inputs`.
+func PackCustomError(e abi.Error, args ...any) ([]byte, error) {
+	data, err := e.Inputs.Pack(args...)
+	if err != nil {
+		return nil, fmt.Errorf("error %s: %v", e.Sig, err)
+	}
+	return append(append([]byte{}, e.ID[:4]...), data...), nil
+}
+
+// A RevertError is returned by a [PrecompiledStatefulContract] to revert the
+// call with data, exactly as the REVERT op code would: state changes are
+// reverted, unused gas is returned to the caller, and the data is available
+// as return data (e.g. to RETURNDATACOPY and `eth_call`). The EVM replaces a
+// RevertError, or any error wrapping one, with [ErrExecutionReverted], which
+// RevertError also matches via [errors.Is].
+type RevertError struct {
+	Data []byte
+}
+
+// Revert returns a [RevertError] carrying the data.
+func Revert(data []byte) error {
+	return &RevertError{Data: data}
+}
+
+// RevertWithReason is equivalent to Solidity's `revert(reason)`.
+func RevertWithReason(reason string) error {
+	return Revert(PackRevertReason(reason))
+}
+
+// RevertWithCustomError is equivalent to Solidity's `revert E(args...)`. If the
+// arguments can't be packed then the returned error is not a [RevertError],
+// and therefore consumes all gas, as this indicates a bug in the precompile.
+func RevertWithCustomError(e abi.Error, args ...any) error {
+	data, err := PackCustomError(e, args...)
+	if err != nil {
+		return err
+	}
+	return Revert(data)
+}
+
+// Error returns the decoded revert reason, if there is one, otherwise the
+// revert data as hex.
+func (e *RevertError) Error() string {
+	if reason, err := abi.UnpackRevert(e.Data); err == nil {
+		return fmt.Sprintf("%v: %s", ErrExecutionReverted, reason)
+	}
+	return fmt.Sprintf("%v: %#x", ErrExecutionReverted, e.Data)
+}
+
+// Is returns true iff `target` is [ErrExecutionReverted].
+func (e *RevertError) Is(target error) bool {
+	return target == ErrExecutionReverted
+}
+
+// convertRevertError replaces a [RevertError] with its data and
+// [ErrExecutionReverted], as expected by the [EVM].
+func convertRevertError(ret []byte, err error) ([]byte, error) {
+	var r *RevertError
+	if err == nil || !errors.As(err, &r) {
+		return ret, err
+	}
+	return r.Data, ErrExecutionReverted
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/accounts/abi"
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestPrecompileRevert(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(`[{
+		"type": "error",
+		"name": "Insufficient",
+		"inputs": [
+			{"name": "have", "type": "uint256"},
+			{"name": "want", "type": "uint256"}
+		]
+	}]`))
+	require.NoError(t, err, "abi.JSON()")
+	insufficient := parsed.Errors["Insufficient"]
+
+	rng := ethtest.NewPseudoRand(764)
+	var (
+		precompile = rng.Address()
+		proxy      = rng.Address()
+		eoa        = rng.Address()
+		slot       = rng.Hash()
+	)
+
+	var precompileErr error
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				env.StateDB().SetState(precompile, slot, common.Hash{1})
+				return []byte("ignored"), precompileErr
+			}),
+		},
+	}
+	hookstest.Register(t, params.Extras[*hookstest.Stub, *hookstest.Stub]{
+		NewRules: func(_ *params.ChainConfig, r *params.Rules, _ *hookstest.Stub, _ *big.Int, _ bool, _ uint64) *hookstest.Stub {
+			r.IsCancun = true // enable PUSH0
+			return hooks
+		},
+	})
+
+	packedCustom, err := vm.PackCustomError(insufficient, big.NewInt(1), big.NewInt(2))
+	require.NoError(t, err, "PackCustomError()")
+	assert.Equal(t, insufficient.ID[:4], packedCustom[:4], "custom-error selector")
+	gotArgs, err := insufficient.Unpack(packedCustom)
+	require.NoError(t, err, "abi.Error.Unpack(PackCustomError())")
+	assert.Equal(t, []any{big.NewInt(1), big.NewInt(2)}, gotArgs, "unpacked custom-error arguments")
+
+	_, err = vm.PackCustomError(insufficient, "wrong type")
+	require.Error(t, err, "PackCustomError() with incorrect arguments")
+
+	tests := []struct {
+		name     string
+		err      error
+		wantData []byte
+	}{
+		{
+			name:     "reason",
+			err:      vm.RevertWithReason("nope"),
+			wantData: vm.PackRevertReason("nope"),
+		},
+		{
+			name:     "custom_error",
+			err:      vm.RevertWithCustomError(insufficient, big.NewInt(1), big.NewInt(2)),
+			wantData: packedCustom,
+		},
+		{
+			name:     "raw_data",
+			err:      vm.Revert([]byte{0xde, 0xad}),
+			wantData: []byte{0xde, 0xad},
+		},
+		{
+			name:     "wrapped",
+			err:      fmt.Errorf("wrapping: %w", vm.RevertWithReason("wrapped")),
+			wantData: vm.PackRevertReason("wrapped"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			precompileErr = tt.err
+			sdb, evm := ethtest.NewZeroEVM(t)
+
+			const gas = 1e6
+			got, gasLeft, err := evm.Call(vm.AccountRef(eoa), precompile, nil, gas, uint256.NewInt(0))
+			require.Equal(t, vm.ErrExecutionReverted, err, "evm.Call([precompile]) returns sentinel error")
+			assert.Equal(t, tt.wantData, got, "revert data")
+			assert.Greater(t, gasLeft, uint64(0), "gas left after revert")
+			assert.Equal(t, common.Hash{}, sdb.GetState(precompile, slot), "state change reverted")
+
+			// The proxy returns the RETURNDATA of its call, irrespective of
+			// whether it reverted.
+			sdb.SetCode(proxy, convertBytes[vm.OpCode, byte](makeReturnProxy(t, precompile, vm.CALL)...))
+			got, _, err = evm.Call(vm.AccountRef(eoa), proxy, []byte{0}, gas, uint256.NewInt(0))
+			require.NoError(t, err, "evm.Call([proxy])")
+			assert.Equal(t, tt.wantData, got, "RETURNDATA via proxy")
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		assert.ErrorIs(t, vm.RevertWithReason("x"), vm.ErrExecutionReverted, "errors.Is(RevertWithReason(), ErrExecutionReverted)")
+		assert.EqualError(t, vm.RevertWithReason("x"), "execution reverted: x", "RevertWithReason().Error()")
+		assert.EqualError(t, vm.Revert([]byte{1, 2}), "execution reverted: 0x0102", "Revert().Error()")
+
+		var r *vm.RevertError
+		require.True(t, errors.As(vm.RevertWithReason("x"), &r), "errors.As(RevertWithReason(), %T)", r)
+		reason, err := abi.UnpackRevert(r.Data)
+		require.NoError(t, err, "abi.UnpackRevert()")
+		assert.Equal(t, "x", reason, "abi.UnpackRevert()")
+	})
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+)
+
+// header returns the header to be exposed to precompiles via
+// [vm.BlockContext.Header]. If cfg.Header is nil then one is constructed from
+// the respective Config fields, allowing [vm.PrecompileEnvironment.BlockHeader]
+// to be used without further configuration.
+func (cfg *Config) header() *types.Header {
+	if cfg.Header != nil {
+		return cfg.Header
+	}
+	hdr := &types.Header{
+		Coinbase:   cfg.Coinbase,
+		Difficulty: cfg.Difficulty,
+		Number:     cfg.BlockNumber,
+		GasLimit:   cfg.GasLimit,
+		Time:       cfg.Time,
+		BaseFee:    cfg.BaseFee,
+	}
+	if cfg.Random != nil {
+		hdr.MixDigest = *cfg.Random
+	}
+	return hdr
+}
+
+// finalise mirrors the end of [core.ApplyMessage] by running or discarding
+// the actions registered via [vm.PrecompileEnvironment.OnTxEnd] and
+// [vm.PrecompileEnvironment.OnCommit], depending on the outcome of execution,
+// before signalling that the EVM is finished.
+func finalise(evm *vm.EVM, err error) {
+	if err == nil {
+		evm.RunTxEndActions()
+		evm.RunCommitActions()
+	} else {
+		evm.DiscardTxEndActions()
+		evm.DiscardCommitActions()
+	}
+	evm.Finish()
+}
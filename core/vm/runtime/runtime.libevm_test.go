@@ -0,0 +1,98 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package runtime_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/core/vm/runtime"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestStatefulPrecompile(t *testing.T) {
+	rng := ethtest.NewPseudoRand(7522)
+	precompile := rng.Address()
+	errSentinel := errors.New("uh oh")
+
+	var committed []uint64
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				hdr, err := env.BlockHeader()
+				if err != nil {
+					return nil, err
+				}
+				num := hdr.Number.Uint64()
+				if err := env.OnCommit(func() { committed = append(committed, num) }); err != nil {
+					return nil, err
+				}
+				if len(input) > 0 {
+					return nil, errSentinel
+				}
+				return hdr.Number.Bytes(), nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	sdb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err, "state.New()")
+
+	t.Run("derived_header", func(t *testing.T) {
+		committed = nil
+		cfg := &runtime.Config{
+			State:       sdb,
+			BlockNumber: big.NewInt(42),
+		}
+		got, _, err := runtime.Call(precompile, nil, cfg)
+		require.NoError(t, err, "runtime.Call()")
+		assert.Equal(t, []byte{42}, got, "precompile output")
+		assert.Equal(t, []uint64{42}, committed, "OnCommit() actions run")
+	})
+
+	t.Run("explicit_header", func(t *testing.T) {
+		committed = nil
+		cfg := &runtime.Config{
+			State:  sdb,
+			Header: &types.Header{Number: big.NewInt(99)},
+		}
+		got, _, err := runtime.Call(precompile, nil, cfg)
+		require.NoError(t, err, "runtime.Call()")
+		assert.Equal(t, []byte{99}, got, "precompile output")
+		assert.Equal(t, []uint64{99}, committed, "OnCommit() actions run")
+	})
+
+	t.Run("error_discards_commit_actions", func(t *testing.T) {
+		committed = nil
+		cfg := &runtime.Config{State: sdb}
+		_, _, err := runtime.Call(precompile, []byte{1}, cfg)
+		require.ErrorIs(t, err, errSentinel, "runtime.Call()")
+		assert.Empty(t, committed, "OnCommit() actions")
+	})
+}
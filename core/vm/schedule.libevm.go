@@ -0,0 +1,202 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/libevm"
+)
+
+// A PrecompileFork is the point at which a [ScheduledPrecompile] is activated
+// or deactivated. At most one of Block or Timestamp MAY be non-nil; both being
+// nil signals that the fork is unscheduled.
+type PrecompileFork struct {
+	Block     *big.Int
+	Timestamp *uint64
+}
+
+func (f PrecompileFork) scheduled() bool {
+	return f.Block != nil || f.Timestamp != nil
+}
+
+// reached returns whether the fork is scheduled at or before the block number
+// and timestamp.
+func (f PrecompileFork) reached(num *big.Int, timestamp uint64) bool {
+	switch {
+	case f.Block != nil:
+		return num != nil && f.Block.Cmp(num) <= 0
+	case f.Timestamp != nil:
+		return *f.Timestamp <= timestamp
+	default:
+		return false
+	}
+}
+
+// cmp compares the forks, which MUST both be scheduled by the same criterion.
+func (f PrecompileFork) cmp(g PrecompileFork) int {
+	if f.Block != nil {
+		return f.Block.Cmp(g.Block)
+	}
+	switch {
+	case *f.Timestamp < *g.Timestamp:
+		return -1
+	case *f.Timestamp > *g.Timestamp:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// A ScheduledPrecompile is a [PrecompiledContract] that is only active between
+// its Activation (inclusive) and Deactivation (exclusive) forks. An unscheduled
+// Activation results in the precompile never being active while an unscheduled
+// Deactivation results in it remaining active indefinitely.
+type ScheduledPrecompile struct {
+	Address                  common.Address
+	Contract                 PrecompiledContract
+	Activation, Deactivation PrecompileFork
+}
+
+// A PrecompileRegistry declares all [ScheduledPrecompile]s once, from which the
+// precompiles active at any block are derived with [PrecompileRegistry.Active].
+type PrecompileRegistry struct {
+	byAddr map[common.Address][]ScheduledPrecompile
+}
+
+// NewPrecompileRegistry validates the schedule and returns a registry of the
+// precompiles. The same address MAY be scheduled more than once, e.g. to
+// upgrade an implementation, but all of its forks MUST use the same criterion
+// (block or timestamp) and its active periods MUST NOT overlap.
+func NewPrecompileRegistry(precompiles ...ScheduledPrecompile) (*PrecompileRegistry, error) {
+	r := &PrecompileRegistry{
+		byAddr: make(map[common.Address][]ScheduledPrecompile),
+	}
+	for _, p := range precompiles {
+		if p.Contract == nil {
+			return nil, fmt.Errorf("nil precompile scheduled at %v", p.Address)
+		}
+		r.byAddr[p.Address] = append(r.byAddr[p.Address], p)
+	}
+	for addr, ps := range r.byAddr {
+		if err := validatePrecompileSchedule(ps); err != nil {
+			return nil, fmt.Errorf("precompile %v: %w", addr, err)
+		}
+	}
+	return r, nil
+}
+
+var (
+	errBothForkCriteria   = errors.New("fork scheduled by both block and timestamp")
+	errMixedForkCriteria  = errors.New("forks scheduled by both blocks and timestamps")
+	errDeactivationBefore = errors.New("deactivation not after activation")
+	errOverlappingPeriods = errors.New("overlapping active periods")
+)
+
+// validatePrecompileSchedule validates all precompiles scheduled at the same
+// address, sorting them by activation.
+func validatePrecompileSchedule(ps []ScheduledPrecompile) error {
+	var byBlock, byTime bool
+	for i, p := range ps {
+		for _, f := range []PrecompileFork{p.Activation, p.Deactivation} {
+			if f.Block != nil && f.Timestamp != nil {
+				return errBothForkCriteria
+			}
+			byBlock = byBlock || f.Block != nil
+			byTime = byTime || f.Timestamp != nil
+		}
+		if byBlock && byTime {
+			return errMixedForkCriteria
+		}
+		if !p.Activation.scheduled() {
+			// Never active so can't overlap, and is moved to the end.
+			continue
+		}
+		if p.Deactivation.scheduled() && p.Deactivation.cmp(p.Activation) <= 0 {
+			return fmt.Errorf("%w; entry %d", errDeactivationBefore, i)
+		}
+	}
+
+	slices.SortStableFunc(ps, func(a, b ScheduledPrecompile) int {
+		switch aSched, bSched := a.Activation.scheduled(), b.Activation.scheduled(); {
+		case aSched && bSched:
+			return a.Activation.cmp(b.Activation)
+		case aSched:
+			return -1
+		case bSched:
+			return 1
+		default:
+			return 0
+		}
+	})
+	for i := 1; i < len(ps); i++ {
+		prev, curr := ps[i-1], ps[i]
+		if !curr.Activation.scheduled() {
+			break
+		}
+		if !prev.Deactivation.scheduled() || prev.Deactivation.cmp(curr.Activation) > 0 {
+			return errOverlappingPeriods
+		}
+	}
+	return nil
+}
+
+// Active returns the precompiles active at the block number and timestamp. It
+// is intended to be called by [params.Extras.NewRules], with the returned set
+// carried by the [params.Rules] extra payload and its methods being returned by
+// the respective [params.RulesHooks].
+func (r *PrecompileRegistry) Active(blockNum *big.Int, timestamp uint64) PrecompileSet {
+	s := make(PrecompileSet)
+	for addr, ps := range r.byAddr {
+		for _, p := range ps {
+			if p.Activation.reached(blockNum, timestamp) && !p.Deactivation.reached(blockNum, timestamp) {
+				s[addr] = p.Contract
+				break
+			}
+		}
+	}
+	return s
+}
+
+// A PrecompileSet is a set of precompiles active under a particular
+// [params.Rules], typically returned by [PrecompileRegistry.Active]. The zero
+// value is an empty set, which results in default precompile behaviour.
+type PrecompileSet map[common.Address]PrecompiledContract
+
+// PrecompileOverride implements the equivalent [params.RulesHooks] method,
+// overriding i.f.f. the address is in the set. Addresses that aren't, including
+// those of deactivated precompiles, have default behaviour.
+func (s PrecompileSet) PrecompileOverride(addr common.Address) (libevm.PrecompiledContract, bool) {
+	p, ok := s[addr]
+	if !ok {
+		return nil, false
+	}
+	return p, true
+}
+
+// ActivePrecompiles implements the equivalent [params.RulesHooks] method,
+// appending the addresses in the set to those received.
+func (s PrecompileSet) ActivePrecompiles(active []common.Address) []common.Address {
+	for addr := range s {
+		active = append(active, addr)
+	}
+	return active
+}
@@ -0,0 +1,205 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestPrecompileRegistry(t *testing.T) {
+	rng := ethtest.NewPseudoRand(769)
+	var (
+		byBlock = rng.Address()
+		byTime  = rng.Address()
+		never   = rng.Address()
+	)
+	v1 := &precompileStub{returnData: []byte("v1")}
+	v2 := &precompileStub{returnData: []byte("v2")}
+
+	block := func(n int64) vm.PrecompileFork {
+		return vm.PrecompileFork{Block: big.NewInt(n)}
+	}
+	timestamp := func(t uint64) vm.PrecompileFork {
+		return vm.PrecompileFork{Timestamp: &t}
+	}
+
+	reg, err := vm.NewPrecompileRegistry(
+		// Deliberately out of order to demonstrate sorting.
+		vm.ScheduledPrecompile{Address: byBlock, Contract: v2, Activation: block(20)},
+		vm.ScheduledPrecompile{Address: byBlock, Contract: v1, Activation: block(10), Deactivation: block(20)},
+		vm.ScheduledPrecompile{Address: byTime, Contract: v1, Activation: timestamp(100), Deactivation: timestamp(200)},
+		vm.ScheduledPrecompile{Address: never, Contract: v1},
+	)
+	require.NoError(t, err, "NewPrecompileRegistry()")
+
+	hookstest.Register(t, params.Extras[*hookstest.Stub, *hookstest.Stub]{
+		NewRules: func(_ *params.ChainConfig, _ *params.Rules, _ *hookstest.Stub, num *big.Int, _ bool, time uint64) *hookstest.Stub {
+			active := reg.Active(num, time)
+			return &hookstest.Stub{
+				PrecompileOverrides: overrides(active),
+				ActivePrecompilesFn: active.ActivePrecompiles,
+			}
+		},
+	})
+
+	tests := []struct {
+		num  int64
+		time uint64
+		want map[common.Address]vm.PrecompiledContract
+	}{
+		{num: 0, time: 0},
+		{num: 10, time: 0, want: map[common.Address]vm.PrecompiledContract{byBlock: v1}},
+		{num: 19, time: 99, want: map[common.Address]vm.PrecompiledContract{byBlock: v1}},
+		{num: 19, time: 100, want: map[common.Address]vm.PrecompiledContract{byBlock: v1, byTime: v1}},
+		{num: 20, time: 199, want: map[common.Address]vm.PrecompiledContract{byBlock: v2, byTime: v1}},
+		{num: 1e6, time: 200, want: map[common.Address]vm.PrecompiledContract{byBlock: v2}},
+	}
+
+	config := &params.ChainConfig{ChainID: big.NewInt(1)}
+	for _, tt := range tests {
+		rules := config.Rules(big.NewInt(tt.num), false, tt.time)
+		active := vm.ActivePrecompiles(rules)
+
+		for _, addr := range []common.Address{byBlock, byTime, never} {
+			want, wantOK := tt.want[addr]
+			assert.Equalf(t, wantOK, slices.Contains(active, addr), "vm.ActivePrecompiles(block %d, time %d) contains %v", tt.num, tt.time, addr)
+
+			got, gotOK := vm.PrecompileAt(rules, addr)
+			if assert.Equalf(t, wantOK, gotOK, "vm.PrecompileAt(block %d, time %d, %v) ok", tt.num, tt.time, addr) && wantOK {
+				assert.Samef(t, want, got, "vm.PrecompileAt(block %d, time %d, %v)", tt.num, tt.time, addr)
+			}
+		}
+	}
+}
+
+// overrides converts the [vm.PrecompileSet] for use as
+// [hookstest.Stub.PrecompileOverrides].
+func overrides(s vm.PrecompileSet) map[common.Address]libevm.PrecompiledContract {
+	m := make(map[common.Address]libevm.PrecompiledContract, len(s))
+	for addr, p := range s {
+		m[addr] = p
+	}
+	return m
+}
+
+func TestPrecompileRegistryValidation(t *testing.T) {
+	addr := common.Address{1}
+	p := &precompileStub{}
+	block := func(n int64) vm.PrecompileFork {
+		return vm.PrecompileFork{Block: big.NewInt(n)}
+	}
+	timestamp := func(t uint64) vm.PrecompileFork {
+		return vm.PrecompileFork{Timestamp: &t}
+	}
+
+	tests := []struct {
+		name    string
+		sched   []vm.ScheduledPrecompile
+		wantErr bool
+	}{
+		{
+			name: "sequential",
+			sched: []vm.ScheduledPrecompile{
+				{Address: addr, Contract: p, Activation: block(1), Deactivation: block(2)},
+				{Address: addr, Contract: p, Activation: block(2)},
+			},
+		},
+		{
+			name: "unscheduled_activations_never_overlap",
+			sched: []vm.ScheduledPrecompile{
+				{Address: addr, Contract: p, Activation: block(1)},
+				{Address: addr, Contract: p},
+				{Address: addr, Contract: p},
+			},
+		},
+		{
+			name: "different_criteria_at_different_addresses",
+			sched: []vm.ScheduledPrecompile{
+				{Address: addr, Contract: p, Activation: block(1)},
+				{Address: common.Address{2}, Contract: p, Activation: timestamp(1)},
+			},
+		},
+		{
+			name: "nil_contract",
+			sched: []vm.ScheduledPrecompile{
+				{Address: addr, Activation: block(1)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "both_criteria",
+			sched: []vm.ScheduledPrecompile{
+				{Address: addr, Contract: p, Activation: vm.PrecompileFork{Block: big.NewInt(1), Timestamp: new(uint64)}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "mixed_criteria",
+			sched: []vm.ScheduledPrecompile{
+				{Address: addr, Contract: p, Activation: block(1), Deactivation: block(2)},
+				{Address: addr, Contract: p, Activation: timestamp(100)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "deactivation_at_activation",
+			sched: []vm.ScheduledPrecompile{
+				{Address: addr, Contract: p, Activation: timestamp(1), Deactivation: timestamp(1)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "overlapping",
+			sched: []vm.ScheduledPrecompile{
+				{Address: addr, Contract: p, Activation: block(1), Deactivation: block(3)},
+				{Address: addr, Contract: p, Activation: block(2)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "overlapping_indefinitely",
+			sched: []vm.ScheduledPrecompile{
+				{Address: addr, Contract: p, Activation: block(5)},
+				{Address: addr, Contract: p, Activation: block(1)},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := vm.NewPrecompileRegistry(tt.sched...)
+			if tt.wantErr {
+				assert.Error(t, err, "NewPrecompileRegistry()")
+			} else {
+				assert.NoError(t, err, "NewPrecompileRegistry()")
+			}
+		})
+	}
+}
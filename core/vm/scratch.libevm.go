@@ -0,0 +1,58 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/crypto"
+)
+
+// scratchKey namespaces precompile scratch space within the transient storage
+// of the precompile's address, such that it never collides with values set
+// directly via [StateDB.SetTransientState].
+func scratchKey(key common.Hash) common.Hash {
+	return crypto.Keccak256Hash([]byte("libevm.precompile.scratch"), key[:])
+}
+
+// Scratch space is backed by the transient storage of the precompile's raw
+// address, which the StateDB maintains irrespective of EIP-1153 activation.
+func (e *environment) ScratchLoad(key common.Hash) common.Hash {
+	return e.evm.StateDB.GetTransientState(e.rawSelf, scratchKey(key))
+}
+
+func (e *environment) ScratchStore(key, value common.Hash) error {
+	if e.ReadOnly() {
+		return ErrWriteProtection
+	}
+	e.evm.StateDB.SetTransientState(e.rawSelf, scratchKey(key), value)
+	return nil
+}
+
+// Unlike scratch space, transient state is accessed at the same address, and
+// with the same keys, as the TLOAD and TSTORE op codes would use if the
+// precompile were a regular contract.
+func (e *environment) GetTransientState(key common.Hash) common.Hash {
+	return e.evm.StateDB.GetTransientState(e.self.Address(), key)
+}
+
+func (e *environment) SetTransientState(key, value common.Hash) error {
+	if e.ReadOnly() {
+		return ErrWriteProtection
+	}
+	e.evm.StateDB.SetTransientState(e.self.Address(), key, value)
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Services is a container of typed, shared services (e.g. verifier pools,
+// caches, or configuration) that precompiles retrieve via
+// [PrecompileEnvironment.Services]. It is set per EVM, with [Config.Services],
+// in place of package-level singletons, which break when multiple chains are
+// embedded in the same process or when tests are run in parallel.
+//
+// A nil *Services is valid and contains no services. As a container is shared
+// by all EVMs constructed with the same [Config], the services it contains
+// MUST be safe for concurrent use.
+type Services struct {
+	mu     sync.RWMutex
+	byType map[reflect.Type]any
+}
+
+// NewServices returns an empty container.
+func NewServices() *Services {
+	return &Services{byType: make(map[reflect.Type]any)}
+}
+
+// Provide registers the service as the `T` in the container, which MUST be
+// non-nil, replacing any already registered. If `T` is an interface type then
+// the service is only retrievable as said interface and not as its concrete
+// type.
+func Provide[T any](s *Services, svc T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byType[reflect.TypeFor[T]()] = svc
+}
+
+// Service returns the `T` registered with [Provide], and whether it exists.
+func Service[T any](s *Services) (T, bool) {
+	if s == nil {
+		var zero T
+		return zero, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	svc, ok := s.byType[reflect.TypeFor[T]()].(T)
+	return svc, ok
+}
+
+func (e *environment) Services() *Services {
+	return e.evm.Config.Services
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+type chainNamer interface {
+	ChainName() string
+}
+
+type staticChainName string
+
+func (n staticChainName) ChainName() string { return string(n) }
+
+func TestServices(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		_, ok := vm.Service[chainNamer](nil)
+		assert.False(t, ok, "vm.Service() from nil container")
+	})
+
+	t.Run("typed", func(t *testing.T) {
+		s := vm.NewServices()
+		vm.Provide[chainNamer](s, staticChainName("a"))
+		vm.Provide(s, 42)
+
+		got, ok := vm.Service[chainNamer](s)
+		require.True(t, ok, "vm.Service[chainNamer]()")
+		assert.Equal(t, "a", got.ChainName(), "vm.Service[chainNamer]()")
+
+		_, ok = vm.Service[staticChainName](s)
+		assert.False(t, ok, "vm.Service() of concrete type registered as interface")
+
+		n, ok := vm.Service[int](s)
+		require.True(t, ok, "vm.Service[int]()")
+		assert.Equal(t, 42, n, "vm.Service[int]()")
+
+		vm.Provide(s, 99)
+		n, _ = vm.Service[int](s)
+		assert.Equal(t, 99, n, "vm.Service[int]() after replacement")
+	})
+
+	t.Run("per_evm", func(t *testing.T) {
+		precompile := common.Address{'n', 'a', 'm', 'e'}
+		stub := &hookstest.Stub{
+			PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+				precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+					n, ok := vm.Service[chainNamer](env.Services())
+					if !ok {
+						return nil, fmt.Errorf("no %T service", (*chainNamer)(nil))
+					}
+					return []byte(n.ChainName()), nil
+				}),
+			},
+		}
+		stub.Register(t)
+
+		call := func(t *testing.T, s *vm.Services) ([]byte, error) {
+			t.Helper()
+			_, evm := ethtest.NewZeroEVM(t, ethtest.WithConfig(vm.Config{Services: s}))
+			ret, _, err := evm.Call(vm.AccountRef{}, precompile, nil, 1e6, uint256.NewInt(0))
+			return ret, err
+		}
+
+		for _, name := range []string{"alpha", "beta"} {
+			s := vm.NewServices()
+			vm.Provide[chainNamer](s, staticChainName(name))
+			got, err := call(t, s)
+			require.NoErrorf(t, err, "%T.Call() with %q service", &vm.EVM{}, name)
+			assert.Equal(t, name, string(got), "service retrieved by precompile")
+		}
+
+		_, err := call(t, nil)
+		require.Error(t, err, "%T.Call() without services", &vm.EVM{})
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"time"
+
+	"github.com/ava-labs/libevm/common"
+)
+
+// SlowPrecompileConfig configures reporting of precompile calls that exceed a
+// wall-clock threshold. It is enabled by setting [Config.SlowPrecompiles].
+//
+// Reporting is purely observational and MUST NOT be used to influence
+// execution as wall-clock time is non-deterministic.
+type SlowPrecompileConfig struct {
+	// Threshold is the (exclusive) minimum duration of a call for it to be
+	// reported.
+	Threshold time.Duration
+	// Report is called synchronously, on the EVM's goroutine, for every call
+	// that exceeds the Threshold. It SHOULD return quickly.
+	Report func(*SlowPrecompileCall)
+}
+
+// A SlowPrecompileCall describes a precompile call that exceeded
+// [SlowPrecompileConfig.Threshold].
+type SlowPrecompileCall struct {
+	Address   common.Address
+	CallType  CallType
+	Stateful  bool
+	InputSize int
+	Duration  time.Duration
+	Err       error
+}
+
+func (args *evmCallArgs) slowPrecompileConfig() *SlowPrecompileConfig {
+	if args.evm == nil { // see [RunPrecompiledContract] in tests
+		return nil
+	}
+	if c := args.evm.Config.SlowPrecompiles; c != nil && c.Report != nil {
+		return c
+	}
+	return nil
+}
+
+func (c *SlowPrecompileConfig) maybeReport(args *evmCallArgs, p PrecompiledContract, input []byte, took time.Duration, err error) {
+	if took <= c.Threshold {
+		return
+	}
+	_, stateful := p.(statefulPrecompile)
+	c.Report(&SlowPrecompileCall{
+		Address:   args.addr,
+		CallType:  args.callType,
+		Stateful:  stateful,
+		InputSize: len(input),
+		Duration:  took,
+		Err:       err,
+	})
+}
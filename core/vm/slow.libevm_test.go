@@ -0,0 +1,74 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestSlowPrecompileReporting(t *testing.T) {
+	const threshold = 10 * time.Millisecond
+
+	rng := ethtest.NewPseudoRand(706)
+	precompile := rng.Address()
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				if len(input) > 0 && input[0] == 1 {
+					time.Sleep(2 * threshold)
+				}
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	var got []*vm.SlowPrecompileCall
+	_, evm := ethtest.NewZeroEVM(t)
+	evm.Config.SlowPrecompiles = &vm.SlowPrecompileConfig{
+		Threshold: threshold,
+		Report: func(c *vm.SlowPrecompileCall) {
+			got = append(got, c)
+		},
+	}
+
+	fast := []byte{0}
+	slow := []byte{1, 2, 3}
+	for _, input := range [][]byte{fast, slow, fast} {
+		_, _, err := evm.Call(vm.AccountRef(rng.Address()), precompile, input, 1e6, uint256.NewInt(0))
+		require.NoError(t, err, "evm.Call()")
+	}
+
+	require.Len(t, got, 1, "slow precompile calls reported")
+	assert.Equal(t, precompile, got[0].Address, "Address")
+	assert.Equal(t, vm.Call, got[0].CallType, "CallType")
+	assert.True(t, got[0].Stateful, "Stateful")
+	assert.Equal(t, len(slow), got[0].InputSize, "InputSize")
+	assert.Greater(t, got[0].Duration, threshold, "Duration")
+	assert.NoError(t, got[0].Err, "Err")
+}
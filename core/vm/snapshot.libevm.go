@@ -0,0 +1,53 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrUnknownSnapshot is returned by [PrecompileEnvironment.RevertToSnapshot]
+// if the revision ID wasn't returned by the same environment's Snapshot().
+var ErrUnknownSnapshot = errors.New("unknown snapshot")
+
+func (e *environment) Snapshot() (int, error) {
+	if e.ReadOnly() {
+		return 0, ErrWriteProtection
+	}
+	id := e.evm.StateDB.Snapshot()
+	e.snapshots = append(e.snapshots, id)
+	return id, nil
+}
+
+func (e *environment) RevertToSnapshot(id int) error {
+	if e.ReadOnly() {
+		return ErrWriteProtection
+	}
+	// Restricting IDs to those returned by Snapshot() stops a precompile from
+	// reverting state changes made before it was called.
+	i := slices.Index(e.snapshots, id)
+	if i == -1 {
+		return fmt.Errorf("%w: %d", ErrUnknownSnapshot, id)
+	}
+	e.evm.StateDB.RevertToSnapshot(id)
+	// As with the StateDB, the revert invalidates this and all later
+	// snapshots.
+	e.snapshots = e.snapshots[:i]
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestPrecompileSnapshot(t *testing.T) {
+	rng := ethtest.NewPseudoRand(758)
+	var (
+		precompile = rng.Address()
+		caller     = vm.AccountRef(rng.Address())
+		kept       = rng.Hash()
+		reverted   = rng.Hash()
+		val        = rng.Hash()
+		outerSlot  = rng.Hash()
+	)
+
+	var errs struct {
+		unknown, afterRevert, again, readOnlySnapshot, readOnlyRevert error
+	}
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, _ []byte) ([]byte, error) {
+				if env.ReadOnly() {
+					_, errs.readOnlySnapshot = env.Snapshot()
+					errs.readOnlyRevert = env.RevertToSnapshot(0)
+					return nil, nil
+				}
+
+				sdb := env.StateDB()
+				errs.unknown = env.RevertToSnapshot(sdb.Snapshot())
+
+				sdb.SetState(precompile, kept, val)
+				snap, err := env.Snapshot()
+				if err != nil {
+					return nil, err
+				}
+				later, err := env.Snapshot()
+				if err != nil {
+					return nil, err
+				}
+				sdb.SetState(precompile, reverted, val)
+				if err := env.RevertToSnapshot(snap); err != nil {
+					return nil, err
+				}
+				errs.afterRevert = env.RevertToSnapshot(later)
+				errs.again = env.RevertToSnapshot(snap)
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	sdb, evm := ethtest.NewZeroEVM(t)
+	sdb.SetState(precompile, outerSlot, val)
+	_, _, err := evm.Call(caller, precompile, nil, 1e6, uint256.NewInt(0))
+	require.NoError(t, err, "evm.Call()")
+
+	assert.ErrorIs(t, errs.unknown, vm.ErrUnknownSnapshot, "RevertToSnapshot() with ID from StateDB")
+	assert.ErrorIs(t, errs.afterRevert, vm.ErrUnknownSnapshot, "RevertToSnapshot() with later ID invalidated by revert")
+	assert.ErrorIs(t, errs.again, vm.ErrUnknownSnapshot, "RevertToSnapshot() with same ID twice")
+	assert.Equal(t, val, sdb.GetState(precompile, kept), "state set before Snapshot()")
+	assert.Zero(t, sdb.GetState(precompile, reverted), "state set after Snapshot() and reverted")
+	assert.Equal(t, val, sdb.GetState(precompile, outerSlot), "state set before precompile call")
+
+	_, _, err = evm.StaticCall(caller, precompile, nil, 1e6)
+	require.NoError(t, err, "evm.StaticCall()")
+	assert.ErrorIs(t, errs.readOnlySnapshot, vm.ErrWriteProtection, "Snapshot() when read-only")
+	assert.ErrorIs(t, errs.readOnlyRevert, vm.ErrWriteProtection, "RevertToSnapshot() when read-only")
+}
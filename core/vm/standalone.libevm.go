@@ -0,0 +1,206 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/stateconf"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A StandaloneCall describes the precompile call for which a standalone
+// [PrecompileEnvironment] is constructed.
+type StandaloneCall struct {
+	Origin, Caller common.Address
+	// Precompile is the address of the precompile being called, reported as
+	// the Self address in the environment.
+	Precompile common.Address
+	Gas        uint64
+}
+
+// NewStandaloneEnvironment constructs a [PrecompileEnvironment] without a
+// full EVM, allowing off-chain services (e.g. indexers and fraud-proof
+// checkers) to deterministically re-execute individual precompile calls
+// against historical state.
+//
+// As the state can't be modified, the environment has the semantics of a
+// [StaticCall]: ReadOnly() is true, StateDB() is nil, and Value() is zero.
+// Calls to other contracts via the environment are supported but MUST NOT
+// modify state; any attempt to do so, other than those rejected by the EVM
+// with [ErrWriteProtection], results in a panic. Registered hooks, including
+// precompile overrides, are active.
+//
+// If the StateReader implements [ProvingStateDB]'s ProofOfStorage() method,
+// it is used to service the respective environment method.
+//
+// As the environment has no defined end of life, its EVM doesn't emit any
+// [LifecycleEvent].
+func NewStandaloneEnvironment(sr libevm.StateReader, config *params.ChainConfig, block BlockContext, call StandaloneCall) PrecompileEnvironment {
+	evm := NewEVM(block, TxContext{Origin: call.Origin}, &readOnlyStateDB{StateReader: sr}, config, Config{})
+	evm.interpreter.readOnly = true
+
+	env := &environment{
+		evm:           evm,
+		callType:      StaticCall,
+		rawCaller:     call.Caller,
+		rawCallerCode: call.Caller,
+		rawSelf:       call.Precompile,
+	}
+	env.self = *NewContract(AccountRef(call.Caller), AccountRef(call.Precompile), new(uint256.Int), call.Gas)
+	return env
+}
+
+var _ ProvingStateDB = (*readOnlyStateDB)(nil)
+
+// A readOnlyStateDB adapts a [libevm.StateReader] into a [StateDB] for use by
+// an EVM that is guaranteed to be read-only. The only modifications that such
+// an EVM makes are touches of accounts, snapshots, and access-list additions,
+// the last of which are tracked in memory so gas accounting is correct.
+type readOnlyStateDB struct {
+	libevm.StateReader
+
+	addresses map[common.Address]struct{}
+	slots     map[common.Address]map[common.Hash]struct{}
+	journal   []accessListAddition
+}
+
+type accessListAddition struct {
+	addr common.Address
+	slot *common.Hash
+}
+
+func panicOnWrite(method string) {
+	// https://google.github.io/styleguide/go/best-practices.html#when-to-panic
+	// Write protection is enforced by the EVM so this indicates a bug.
+	panic(fmt.Sprintf("BUG: %s() called on read-only StateDB of standalone PrecompileEnvironment", method))
+}
+
+func (*readOnlyStateDB) SetNonce(common.Address, uint64) { panicOnWrite("SetNonce") }
+func (*readOnlyStateDB) SetCode(common.Address, []byte)  { panicOnWrite("SetCode") }
+func (*readOnlyStateDB) AddRefund(uint64)                { panicOnWrite("AddRefund") }
+func (*readOnlyStateDB) SubRefund(uint64)                { panicOnWrite("SubRefund") }
+func (*readOnlyStateDB) SelfDestruct(common.Address)     { panicOnWrite("SelfDestruct") }
+func (*readOnlyStateDB) Selfdestruct6780(common.Address) { panicOnWrite("Selfdestruct6780") }
+func (*readOnlyStateDB) AddLog(*types.Log)               { panicOnWrite("AddLog") }
+func (*readOnlyStateDB) AddPreimage(common.Hash, []byte) {}
+
+func (*readOnlyStateDB) SetState(common.Address, common.Hash, common.Hash, ...stateconf.StateDBStateOption) {
+	panicOnWrite("SetState")
+}
+
+func (*readOnlyStateDB) SetTransientState(common.Address, common.Hash, common.Hash) {
+	panicOnWrite("SetTransientState")
+}
+
+func (*readOnlyStateDB) Prepare(params.Rules, common.Address, common.Address, *common.Address, []common.Address, types.AccessList) {
+	panicOnWrite("Prepare")
+}
+
+// CreateAccount accepts only the touching of non-existent accounts, as
+// performed by [EVM.Call] with zero value, which has no effect on a read-only
+// state.
+func (s *readOnlyStateDB) CreateAccount(addr common.Address) {
+	if s.Exist(addr) {
+		panicOnWrite("CreateAccount")
+	}
+}
+
+// AddBalance and SubBalance accept only zero-value touches, as performed by
+// [EVM.StaticCall] and zero-value transfers.
+func (*readOnlyStateDB) AddBalance(_ common.Address, amount *uint256.Int) {
+	if !amount.IsZero() {
+		panicOnWrite("AddBalance")
+	}
+}
+
+func (*readOnlyStateDB) SubBalance(_ common.Address, amount *uint256.Int) {
+	if !amount.IsZero() {
+		panicOnWrite("SubBalance")
+	}
+}
+
+func (s *readOnlyStateDB) AddressInAccessList(addr common.Address) bool {
+	if _, ok := s.addresses[addr]; ok {
+		return true
+	}
+	return s.StateReader.AddressInAccessList(addr)
+}
+
+func (s *readOnlyStateDB) SlotInAccessList(addr common.Address, slot common.Hash) (bool, bool) {
+	addrOk, slotOk := s.StateReader.SlotInAccessList(addr, slot)
+	if _, ok := s.slots[addr][slot]; ok {
+		slotOk = true
+	}
+	return addrOk || s.AddressInAccessList(addr), slotOk
+}
+
+func (s *readOnlyStateDB) AddAddressToAccessList(addr common.Address) {
+	if s.AddressInAccessList(addr) {
+		return
+	}
+	if s.addresses == nil {
+		s.addresses = make(map[common.Address]struct{})
+	}
+	s.addresses[addr] = struct{}{}
+	s.journal = append(s.journal, accessListAddition{addr: addr})
+}
+
+func (s *readOnlyStateDB) AddSlotToAccessList(addr common.Address, slot common.Hash) {
+	s.AddAddressToAccessList(addr)
+	if _, slotOk := s.SlotInAccessList(addr, slot); slotOk {
+		return
+	}
+	if s.slots == nil {
+		s.slots = make(map[common.Address]map[common.Hash]struct{})
+	}
+	if s.slots[addr] == nil {
+		s.slots[addr] = make(map[common.Hash]struct{})
+	}
+	s.slots[addr][slot] = struct{}{}
+	s.journal = append(s.journal, accessListAddition{addr: addr, slot: &slot})
+}
+
+func (s *readOnlyStateDB) Snapshot() int {
+	return len(s.journal)
+}
+
+func (s *readOnlyStateDB) RevertToSnapshot(id int) {
+	for i := len(s.journal) - 1; i >= id; i-- {
+		if a := s.journal[i]; a.slot != nil {
+			delete(s.slots[a.addr], *a.slot)
+		} else {
+			delete(s.addresses, a.addr)
+		}
+	}
+	s.journal = s.journal[:id]
+}
+
+func (s *readOnlyStateDB) ProofOfStorage(addr common.Address, slots []common.Hash) (*libevm.StorageProof, error) {
+	p, ok := s.StateReader.(interface {
+		ProofOfStorage(common.Address, []common.Hash) (*libevm.StorageProof, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("%T does not implement ProofOfStorage()", s.StateReader)
+	}
+	return p.ProofOfStorage(addr, slots)
+}
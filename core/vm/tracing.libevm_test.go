@@ -0,0 +1,162 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/eth/tracers"
+	"github.com/ava-labs/libevm/eth/tracers/logger"
+	_ "github.com/ava-labs/libevm/eth/tracers/native"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestPrecompileSubcallTracing(t *testing.T) {
+	precompile := common.Address{'p'}
+	contract := common.Address{'c'}
+
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				if _, err := env.Call(contract, []byte{0}, 10_000, new(uint256.Int)); err != nil {
+					return nil, err
+				}
+				// Neither of these reach the EVM, but their errors are
+				// deliberately swallowed so must still be traced.
+				_, _ = env.StaticCall(contract, []byte{1}, 1<<62)
+				_, _ = env.Call(contract, []byte{2}, 10_000, uint256.NewInt(1))
+				return []byte("done"), nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	state, evm := ethtest.NewZeroEVM(t)
+	state.SetCode(contract, []byte{byte(vm.STOP)})
+
+	tracer, err := tracers.DefaultDirectory.New("callTracer", nil, nil)
+	require.NoError(t, err, `New("callTracer")`)
+	evm.Config.Tracer = tracer
+
+	const gasLimit = 1e6
+	tracer.CaptureTxStart(gasLimit)
+	_, gasLeft, err := evm.Call(vm.AccountRef(common.Address{'e'}), precompile, nil, gasLimit, new(uint256.Int))
+	require.NoError(t, err, "evm.Call([stateful precompile])")
+	tracer.CaptureTxEnd(gasLeft)
+
+	type frame struct {
+		Type  string  `json:"type"`
+		To    string  `json:"to"`
+		Input string  `json:"input"`
+		Error string  `json:"error"`
+		Calls []frame `json:"calls"`
+	}
+	raw, err := tracer.GetResult()
+	require.NoError(t, err, "GetResult()")
+	var got frame
+	require.NoError(t, json.Unmarshal(raw, &got), "json.Unmarshal(%s)", raw)
+
+	to := contract.Hex()
+	want := []frame{
+		{Type: "CALL", To: to, Input: "0x00"},
+		{Type: "STATICCALL", To: to, Input: "0x01", Error: vm.ErrOutOfGas.Error()},
+		{Type: "CALL", To: to, Input: "0x02", Error: vm.ErrInsufficientBalance.Error()},
+	}
+	require.Len(t, got.Calls, len(want), "subcalls of precompile frame")
+	for i, w := range want {
+		g := got.Calls[i]
+		g.Calls = nil
+		// Compare addresses independently of hex case.
+		assert.Equalf(t, common.HexToAddress(w.To), common.HexToAddress(g.To), "subcall[%d].To", i)
+		g.To = w.To
+		assert.Equalf(t, w, g, "subcall[%d]", i)
+	}
+}
+
+// scopeRecorder records the scopes reported to an [vm.EVMLogger], deferring
+// all other methods to a [logger.StructLogger].
+type scopeRecorder struct {
+	*logger.StructLogger
+	scopes []string
+}
+
+func (r *scopeRecorder) CaptureStart(evm *vm.EVM, from, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	r.StructLogger.CaptureStart(evm, from, to, create, input, gas, value)
+	r.scopes = append(r.scopes, fmt.Sprintf("start %v", to))
+}
+
+func (r *scopeRecorder) CaptureEnd([]byte, uint64, error) {
+	r.scopes = append(r.scopes, "end")
+}
+
+func (r *scopeRecorder) CaptureEnter(typ vm.OpCode, _, to common.Address, _ []byte, _ uint64, _ *big.Int) {
+	r.scopes = append(r.scopes, fmt.Sprintf("enter %v %v", typ, to))
+}
+
+func (r *scopeRecorder) CaptureExit([]byte, uint64, error) {
+	r.scopes = append(r.scopes, "exit")
+}
+
+func TestPrecompileSubcallTracedOnce(t *testing.T) {
+	// Calls made via a [vm.PrecompileEnvironment] were previously captured by
+	// the environment as well as by the [vm.EVM] method that it calls,
+	// resulting in a duplicate frame that was closed with CaptureEnd(), which
+	// is reserved for the top-level call.
+	precompile := common.Address{'p'}
+	contract := common.Address{'c'}
+
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				if _, err := env.Call(contract, nil, 10_000, new(uint256.Int)); err != nil {
+					return nil, err
+				}
+				return env.StaticCall(contract, nil, 10_000)
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	state, evm := ethtest.NewZeroEVM(t)
+	state.SetCode(contract, []byte{byte(vm.STOP)})
+	rec := &scopeRecorder{StructLogger: logger.NewStructLogger(nil)}
+	evm.Config.Tracer = rec
+
+	_, _, err := evm.Call(vm.AccountRef(common.Address{'e'}), precompile, nil, 1e6, new(uint256.Int))
+	require.NoError(t, err, "evm.Call([stateful precompile])")
+
+	want := []string{
+		fmt.Sprintf("start %v", precompile),
+		fmt.Sprintf("enter CALL %v", contract),
+		"exit",
+		fmt.Sprintf("enter STATICCALL %v", contract),
+		"exit",
+		"end",
+	}
+	assert.Equal(t, want, rec.scopes, "scopes captured by tracer")
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+// A TransferOverrider MAY be implemented by a [params.RulesHooks] to wrap or
+// replace the [BlockContext] CanTransfer and Transfer functions set by the node;
+// e.g. to implement allowlists, a soulbound native token, or fee redirection.
+// The override is applied by [NewEVM] so the policy applies uniformly to all
+// value moved by transactions, CALL, CALLCODE, CREATE, CREATE2, and
+// precompiles, including via [PrecompileEnvironment.Call].
+//
+// A CanTransfer function returning false results in [ErrInsufficientBalance],
+// regardless of the reason.
+type TransferOverrider interface {
+	// OverrideTransfer receives the functions set by the node, which MAY be
+	// nil, and returns the ones to be used in their place.
+	OverrideTransfer(CanTransferFunc, TransferFunc) (CanTransferFunc, TransferFunc)
+}
+
+// overrideTransfer applies the registered [TransferOverrider], if any, to
+// evm.Context.
+func (evm *EVM) overrideTransfer() {
+	o, ok := evm.chainRules.Hooks().(TransferOverrider)
+	if !ok {
+		return
+	}
+	ctx := &evm.Context
+	ctx.CanTransfer, ctx.Transfer = o.OverrideTransfer(ctx.CanTransfer, ctx.Transfer)
+}
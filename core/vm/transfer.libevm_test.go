@@ -0,0 +1,100 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+// transferHooks prevent the soulbound account from sending value and redirect
+// one wei of every transfer to the treasury.
+type transferHooks struct {
+	hookstest.Stub
+	soulbound, treasury common.Address
+}
+
+func (h *transferHooks) OverrideTransfer(can vm.CanTransferFunc, transfer vm.TransferFunc) (vm.CanTransferFunc, vm.TransferFunc) {
+	canWrapped := func(sdb vm.StateDB, from common.Address, amount *uint256.Int) bool {
+		return from != h.soulbound && can(sdb, from, amount)
+	}
+	transferWrapped := func(sdb vm.StateDB, from, to common.Address, amount *uint256.Int) {
+		if amount.IsZero() {
+			transfer(sdb, from, to, amount)
+			return
+		}
+		fee := uint256.NewInt(1)
+		transfer(sdb, from, h.treasury, fee)
+		transfer(sdb, from, to, new(uint256.Int).Sub(amount, fee))
+	}
+	return canWrapped, transferWrapped
+}
+
+func TestTransferOverrider(t *testing.T) {
+	var (
+		sender    = common.Address{'s', 'e', 'n', 'd'}
+		recipient = common.Address{'r', 'e', 'c', 'v'}
+		hooks     = &transferHooks{
+			soulbound: common.Address{'s', 'o', 'u', 'l'},
+			treasury:  common.Address{'t', 'r', 'e', 'a', 's'},
+		}
+	)
+	hookstest.Register(t, params.Extras[*transferHooks, *transferHooks]{
+		NewRules: func(*params.ChainConfig, *params.Rules, *transferHooks, *big.Int, bool, uint64) *transferHooks {
+			return hooks
+		},
+	})
+
+	const (
+		gasLimit = 1e6
+		funds    = 1000
+		value    = 100
+	)
+	sdb, evm := ethtest.NewZeroEVM(t)
+	for _, addr := range []common.Address{sender, hooks.soulbound} {
+		sdb.SetBalance(addr, uint256.NewInt(funds))
+	}
+
+	_, _, err := evm.Call(vm.AccountRef(sender), recipient, nil, gasLimit, uint256.NewInt(value))
+	require.NoError(t, err, "%T.Call() with value", evm)
+
+	_, contract, _, err := evm.Create(vm.AccountRef(sender), nil, gasLimit, uint256.NewInt(value))
+	require.NoError(t, err, "%T.Create() with value", evm)
+
+	_, _, err = evm.Call(vm.AccountRef(hooks.soulbound), recipient, nil, gasLimit, uint256.NewInt(value))
+	require.ErrorIs(t, err, vm.ErrInsufficientBalance, "%T.Call() from soulbound account", evm)
+
+	for addr, want := range map[common.Address]uint64{
+		sender:          funds - 2*value,
+		recipient:       value - 1,
+		contract:        value - 1,
+		hooks.treasury:  2,
+		hooks.soulbound: funds,
+	} {
+		assert.Equalf(t, want, sdb.GetBalance(addr).Uint64(), "balance of %v", addr)
+	}
+}
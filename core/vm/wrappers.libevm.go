@@ -0,0 +1,249 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"encoding/json"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/libevm/options"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A wrappedPrecompile decorates another [PrecompiledContract] with optional
+// interfaces; e.g. as returned by [WithPrefetchHints], [WithABI], and
+// [WithDescription]. Wrappers MAY be nested.
+type wrappedPrecompile interface {
+	PrecompiledContract
+	unwrap() PrecompiledContract
+}
+
+// unwrapPrecompile returns the innermost precompile wrapped by `p`, or `p`
+// itself if it isn't wrapped.
+func unwrapPrecompile(p PrecompiledContract) PrecompiledContract {
+	for {
+		w, ok := p.(wrappedPrecompile)
+		if !ok {
+			return p
+		}
+		p = w.unwrap()
+	}
+}
+
+// precompileAs returns the outermost precompile in the chain of wrappers,
+// starting at `p` itself, that implements T.
+func precompileAs[T any](p PrecompiledContract) (T, bool) {
+	for {
+		if t, ok := p.(T); ok {
+			return t, true
+		}
+		w, ok := p.(wrappedPrecompile)
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		p = w.unwrap()
+	}
+}
+
+// An ABIDeclarer is a [PrecompiledContract] that declares its Solidity ABI,
+// allowing tooling to generate bindings.
+type ABIDeclarer interface {
+	PrecompiledContract
+	// PrecompileABI returns the name of the precompile, used to derive
+	// identifiers in generated code, and the JSON encoding of its ABI.
+	PrecompileABI() (name string, abiJSON []byte)
+}
+
+// WithABI returns an [ABIDeclarer] that otherwise behaves identically to `p`,
+// which MAY be a stateful precompile.
+func WithABI(p PrecompiledContract, name string, abiJSON []byte) ABIDeclarer {
+	return &abiPrecompile{p, name, abiJSON}
+}
+
+type abiPrecompile struct {
+	PrecompiledContract
+	name    string
+	abiJSON []byte
+}
+
+func (p *abiPrecompile) PrecompileABI() (string, []byte) {
+	return p.name, p.abiJSON
+}
+
+func (p *abiPrecompile) unwrap() PrecompiledContract {
+	return p.PrecompiledContract
+}
+
+// PrecompileABI returns the ABI declared by the precompile, if any, that would
+// be run by a call to the address under the given rules. The returned boolean
+// is false if there is no such precompile or if it isn't an [ABIDeclarer].
+func PrecompileABI(rules params.Rules, addr common.Address) (name string, abiJSON []byte, ok bool) {
+	p, ok := PrecompileAt(rules, addr)
+	if !ok {
+		return "", nil, false
+	}
+	d, ok := precompileAs[ABIDeclarer](p)
+	if !ok {
+		return "", nil, false
+	}
+	name, abiJSON = d.PrecompileABI()
+	return name, abiJSON, true
+}
+
+// A PrecompileDescription is a versioned self-description of a precompile,
+// sufficient for wallets and SDKs to generate bindings.
+type PrecompileDescription struct {
+	Name    string          `json:"name"`
+	Version string          `json:"version"`
+	ABI     json.RawMessage `json:"abi"`
+}
+
+// A Describer is a [PrecompiledContract] that describes itself. Every
+// Describer returned by [WithDescription] is also an [ABIDeclarer].
+type Describer interface {
+	PrecompiledContract
+	Describe() PrecompileDescription
+}
+
+// WithDescription returns a [Describer] that otherwise behaves identically to
+// `p`, which MAY be a stateful precompile.
+func WithDescription(p PrecompiledContract, d PrecompileDescription) Describer {
+	return &describedPrecompile{p, d}
+}
+
+type describedPrecompile struct {
+	PrecompiledContract
+	desc PrecompileDescription
+}
+
+func (p *describedPrecompile) Describe() PrecompileDescription {
+	return p.desc
+}
+
+func (p *describedPrecompile) PrecompileABI() (string, []byte) {
+	return p.desc.Name, p.desc.ABI
+}
+
+func (p *describedPrecompile) unwrap() PrecompiledContract {
+	return p.PrecompiledContract
+}
+
+// DescribePrecompile returns the description of the precompile, if any, that
+// would be run by a call to the address under the given rules. A [Describer]
+// takes precedence over an [ABIDeclarer], the latter resulting in an empty
+// Version. The returned boolean is false if there is no such precompile or if
+// it implements neither interface.
+func DescribePrecompile(rules params.Rules, addr common.Address) (PrecompileDescription, bool) {
+	p, ok := PrecompileAt(rules, addr)
+	if !ok {
+		return PrecompileDescription{}, false
+	}
+	if d, ok := precompileAs[Describer](p); ok {
+		return d.Describe(), true
+	}
+	if d, ok := precompileAs[ABIDeclarer](p); ok {
+		name, abiJSON := d.PrecompileABI()
+		return PrecompileDescription{Name: name, ABI: abiJSON}, true
+	}
+	return PrecompileDescription{}, false
+}
+
+type wrapConfig struct {
+	before      func(PrecompileEnvironment, []byte) error
+	after       func(_ PrecompileEnvironment, input, ret []byte, _ error) ([]byte, error)
+	requiredGas func(input []byte, upstream uint64) uint64
+}
+
+// A WrapOption configures the behaviour of [WrapWithEnvironment].
+type WrapOption = options.Option[wrapConfig]
+
+// WithPreRunHook results in `fn` being called before the wrapped precompile is
+// charged for and run. If `fn` returns an error then the call fails with it
+// and the wrapped precompile is not run. Gas consumed by `fn` via
+// [PrecompileEnvironment.UseGas] is in addition to that required by the
+// wrapped precompile. If multiple hooks are provided, only the last is used.
+func WithPreRunHook(fn func(_ PrecompileEnvironment, input []byte) error) WrapOption {
+	return options.Func[wrapConfig](func(c *wrapConfig) {
+		c.before = fn
+	})
+}
+
+// WithPostRunHook results in `fn` being called after the wrapped precompile is
+// run, or fails due to insufficient gas. Its return values are used as those
+// of the call, allowing it to observe or to modify the outcome. If multiple
+// hooks are provided, only the last is used.
+func WithPostRunHook(fn func(_ PrecompileEnvironment, input, ret []byte, _ error) ([]byte, error)) WrapOption {
+	return options.Func[wrapConfig](func(c *wrapConfig) {
+		c.after = fn
+	})
+}
+
+// WithRequiredGas results in `fn` determining the gas consumed to run the
+// wrapped precompile, instead of its RequiredGas() method, the return value of
+// which is passed to `fn` as `upstream`. This allows a precompile to be
+// re-priced without being reimplemented. It has no effect if the wrapped
+// precompile is stateful. If multiple functions are provided, only the last is
+// used.
+func WithRequiredGas(fn func(input []byte, upstream uint64) uint64) WrapOption {
+	return options.Func[wrapConfig](func(c *wrapConfig) {
+		c.requiredGas = fn
+	})
+}
+
+// WrapWithEnvironment adapts a stateless precompile, such as those implemented
+// by geth, into a stateful one with access to the [PrecompileEnvironment]. The
+// gas returned by `p.RequiredGas()`, or by a [WithRequiredGas] function, is
+// consumed before `p.Run()` is called, failing with [ErrOutOfGas] if
+// insufficient, exactly as if `p` were called directly. See
+// [UpstreamPrecompile] for obtaining geth implementations to wrap.
+//
+// The returned precompile is not a decorator of `p` so optional interfaces
+// implemented by `p` (e.g. [ABIDeclarer]) MUST be re-applied to it. If `p` is
+// already stateful then it is run as such, with the environment and gas being
+// passed through.
+func WrapWithEnvironment(p PrecompiledContract, opts ...WrapOption) PrecompiledContract {
+	cfg := options.As(opts...)
+
+	return NewStatefulPrecompile(func(env PrecompileEnvironment, input []byte) ([]byte, error) {
+		if cfg.before != nil {
+			if err := cfg.before(env, input); err != nil {
+				return nil, err
+			}
+		}
+
+		ret, err := cfg.runWithEnvironment(p, env, input)
+		if cfg.after != nil {
+			return cfg.after(env, input, ret, err)
+		}
+		return ret, err
+	})
+}
+
+func (cfg *wrapConfig) runWithEnvironment(p PrecompiledContract, env PrecompileEnvironment, input []byte) ([]byte, error) {
+	if sp, ok := unwrapPrecompile(p).(statefulPrecompile); ok {
+		return sp(env, input)
+	}
+	gas := p.RequiredGas(input)
+	if cfg.requiredGas != nil {
+		gas = cfg.requiredGas(input, gas)
+	}
+	if !env.UseGas(gas) {
+		return nil, ErrOutOfGas
+	}
+	return p.Run(input)
+}
@@ -48,6 +48,7 @@ import (
 	"github.com/ava-labs/libevm/event"
 	"github.com/ava-labs/libevm/internal/ethapi"
 	"github.com/ava-labs/libevm/internal/shutdowncheck"
+	"github.com/ava-labs/libevm/libevm/describe"
 	"github.com/ava-labs/libevm/log"
 	"github.com/ava-labs/libevm/miner"
 	"github.com/ava-labs/libevm/node"
@@ -213,6 +214,14 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.OverrideVerkle != nil {
 		overrides.OverrideVerkle = config.OverrideVerkle
 	}
+	//libevm:start
+	if config.ConfigOverlay != "" {
+		overrides.ConfigOverlay, err = params.LoadConfigOverlay(config.ConfigOverlay)
+		if err != nil {
+			return nil, err
+		}
+	}
+	//libevm:end
 	eth.blockchain, err = core.NewBlockChain(chainDb, cacheConfig, config.Genesis, &overrides, eth.engine, vmConfig, eth.shouldPreserve, &config.TransactionHistory)
 	if err != nil {
 		return nil, err
@@ -329,6 +338,9 @@ func (s *Ethereum) APIs() []rpc.API {
 		}, {
 			Namespace: "debug",
 			Service:   NewDebugAPI(s),
+		}, {
+			Namespace: "debug",
+			Service:   describe.NewAPI(s.blockchain), // libevm
 		}, {
 			Namespace: "net",
 			Service:   s.netRPCService,
@@ -159,6 +159,10 @@ type Config struct {
 
 	// OverrideVerkle (TODO: remove after the fork)
 	OverrideVerkle *uint64 `toml:",omitempty"`
+
+	// ConfigOverlay is the path to a chain-config overlay file, loaded with
+	// [params.LoadConfigOverlay]. (libevm)
+	ConfigOverlay string `toml:",omitempty"`
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain config.
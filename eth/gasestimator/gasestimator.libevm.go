@@ -0,0 +1,68 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gasestimator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/libevm/core"
+)
+
+// GasEstimationHooks are optional extensions of [params.RulesHooks] that allow
+// chains to provide their own gas-estimation logic for messages whose
+// requirements aren't captured by execution alone; e.g. custom transaction
+// types with extra intrinsic components, or those requiring a minimum gas
+// budget for precompiles. They are only used if the [params.RulesHooks] under
+// which the message is estimated also implement this interface.
+type GasEstimationHooks interface {
+	// EstimateGas receives the message and the lowest gas limit with which
+	// it was found to execute successfully, and returns the estimate to be
+	// used instead. It MUST NOT modify the message. A non-nil error fails the
+	// estimation.
+	EstimateGas(call *core.Message, executed uint64) (uint64, error)
+}
+
+// Estimate returns the lowest possible gas limit that allows the transaction to
+// run successfully with the provided context options, as adjusted by any
+// registered [GasEstimationHooks]. It returns an error if the transaction would
+// always revert, if the adjusted estimate exceeds a non-zero `gasCap`, or if
+// there are unexpected failures.
+func Estimate(ctx context.Context, call *core.Message, opts *Options, gasCap uint64) (uint64, []byte, error) {
+	gas, revert, err := estimate(ctx, call, opts, gasCap)
+	if err != nil {
+		return gas, revert, err
+	}
+
+	// Equivalent to the rules used by the EVM in [run], as derived from the
+	// block context returned by [core.NewEVMBlockContext].
+	hdr := opts.Header
+	rules := opts.Config.Rules(hdr.Number, hdr.Difficulty.Sign() == 0, hdr.Time)
+	h, ok := rules.Hooks().(GasEstimationHooks)
+	if !ok {
+		return gas, nil, nil
+	}
+
+	adjusted, err := h.EstimateGas(call, gas)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%T.EstimateGas(): %w", h, err)
+	}
+	if gasCap != 0 && adjusted > gasCap {
+		return 0, nil, fmt.Errorf("gas required exceeds allowance (%d)", gasCap)
+	}
+	return adjusted, nil, nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package gasestimator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/consensus"
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+type estimationHooks struct {
+	params.NOOPHooks
+	fn func(*core.Message, uint64) (uint64, error)
+}
+
+func (h estimationHooks) EstimateGas(call *core.Message, executed uint64) (uint64, error) {
+	return h.fn(call, executed)
+}
+
+type chainContext struct {
+	engine consensus.Engine
+}
+
+func (c chainContext) Engine() consensus.Engine                  { return c.engine }
+func (chainContext) GetHeader(common.Hash, uint64) *types.Header { return nil }
+
+func TestGasEstimationHooks(t *testing.T) {
+	errHook := errors.New("uh oh")
+	const extra = 5_000
+
+	tests := []struct {
+		name    string
+		hook    func(*core.Message, uint64) (uint64, error)
+		gasCap  uint64
+		want    uint64
+		wantErr error
+	}{
+		{
+			name: "no_hook",
+			want: params.TxGas,
+		},
+		{
+			name: "extra_intrinsic",
+			hook: func(_ *core.Message, executed uint64) (uint64, error) {
+				return executed + extra, nil
+			},
+			want: params.TxGas + extra,
+		},
+		{
+			name: "minimum_budget",
+			hook: func(*core.Message, uint64) (uint64, error) {
+				return 100_000, nil
+			},
+			want: 100_000,
+		},
+		{
+			name: "exceeds_cap",
+			hook: func(*core.Message, uint64) (uint64, error) {
+				return 100_000, nil
+			},
+			gasCap: 50_000,
+		},
+		{
+			name: "error",
+			hook: func(*core.Message, uint64) (uint64, error) {
+				return 0, errHook
+			},
+			wantErr: errHook,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.hook != nil {
+				hookstest.Register(t, params.Extras[params.NOOPHooks, estimationHooks]{
+					NewRules: func(*params.ChainConfig, *params.Rules, params.NOOPHooks, *big.Int, bool, uint64) estimationHooks {
+						return estimationHooks{fn: tt.hook}
+					},
+				})
+			} else {
+				params.TestOnlyClearRegisteredExtras()
+			}
+
+			sdb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+			require.NoError(t, err, "state.New()")
+			from := common.Address{'f'}
+			sdb.SetBalance(from, uint256.NewInt(1e18))
+
+			opts := &Options{
+				Config: params.TestChainConfig,
+				Chain:  chainContext{ethash.NewFaker()},
+				Header: &types.Header{
+					Number:     big.NewInt(1),
+					Difficulty: big.NewInt(1),
+					GasLimit:   30_000_000,
+					BaseFee:    big.NewInt(0),
+				},
+				State: sdb,
+			}
+			call := &core.Message{
+				From:              from,
+				To:                &common.Address{'t'},
+				Value:             big.NewInt(0),
+				GasPrice:          big.NewInt(0),
+				GasFeeCap:         big.NewInt(0),
+				GasTipCap:         big.NewInt(0),
+				SkipAccountChecks: true,
+			}
+
+			got, _, err := Estimate(context.Background(), call, opts, tt.gasCap)
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr, "Estimate()")
+			case tt.want == 0:
+				require.Error(t, err, "Estimate()")
+			default:
+				require.NoError(t, err, "Estimate()")
+				assert.Equal(t, tt.want, got, "Estimate()")
+			}
+		})
+	}
+}
@@ -536,7 +536,9 @@ func (api *API) IntermediateRoots(ctx context.Context, hash common.Hash, config
 			vmenv     = vm.NewEVM(vmctx, txContext, statedb, chainConfig, vm.Config{})
 		)
 		statedb.SetTxContext(tx.Hash(), i)
-		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit)); err != nil {
+		_, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit))
+		vmenv.Finish() // libevm
+		if err != nil {
 			log.Warn("Tracing intermediate roots did not complete", "txindex", i, "txhash", tx.Hash(), "err", err)
 			// We intentionally don't return the error here: if we do, then the RPC server will not
 			// return the roots. Most likely, the caller already knows that a certain transaction fails to
@@ -683,12 +685,14 @@ txloop:
 		statedb.SetTxContext(tx.Hash(), i)
 		vmenv := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), statedb, api.backend.ChainConfig(), vm.Config{})
 		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit)); err != nil {
+			vmenv.Finish() // libevm
 			failed = err
 			break txloop
 		}
 		// Finalize the state so any modifications are written to the trie
 		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
 		statedb.Finalise(vmenv.ChainConfig().IsEIP158(block.Number()))
+		vmenv.Finish() // libevm
 	}
 
 	close(jobs)
@@ -798,11 +802,13 @@ func (api *API) standardTraceBlockToFile(ctx context.Context, block *types.Block
 			log.Info("Wrote standard trace", "file", dump.Name())
 		}
 		if err != nil {
+			vmenv.Finish() // libevm
 			return dumps, err
 		}
 		// Finalize the state so any modifications are written to the trie
 		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
 		statedb.Finalise(vmenv.ChainConfig().IsEIP158(block.Number()))
+		vmenv.Finish() // libevm
 
 		// If we've traced the transaction we were looking for, abort
 		if tx.Hash() == txHash {
@@ -953,6 +959,7 @@ func (api *API) traceTx(ctx context.Context, message *core.Message, txctx *Conte
 		}
 	}
 	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.backend.ChainConfig(), vm.Config{Tracer: tracer, NoBaseFee: true})
+	defer vmenv.Finish() // libevm
 
 	// Define a meaningful timeout of a single transaction trace
 	if config.Timeout != nil {
@@ -0,0 +1,43 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+)
+
+var _ vm.PrecompileAccessListLogger = (*AccessListTracer)(nil)
+
+// CapturePrecompileAccessList implements [vm.PrecompileAccessListLogger],
+// merging state accessed by stateful precompiles into the access list. As with
+// opcode-level tracing, storage slots are always added but addresses without
+// slots are subject to the tracer's exclusions.
+func (a *AccessListTracer) CapturePrecompileAccessList(_ common.Address, accessed types.AccessList) {
+	for _, t := range accessed {
+		if len(t.StorageKeys) == 0 {
+			if _, ok := a.excl[t.Address]; !ok {
+				a.list.addAddress(t.Address)
+			}
+			continue
+		}
+		for _, k := range t.StorageKeys {
+			a.list.addSlot(t.Address, k)
+		}
+	}
+}
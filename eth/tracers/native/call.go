@@ -43,6 +43,8 @@ type callLog struct {
 	// Position of the log relative to subcalls within the same trace
 	// See https://github.com/ethereum/go-ethereum/pull/28389 for details
 	Position hexutil.Uint `json:"position"`
+	// libevm: see [vm.RegisterPrecompileEvents]
+	Event *callLogEvent `json:"event,omitempty"`
 }
 
 type callFrame struct {
@@ -197,6 +199,7 @@ func (t *callTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, sco
 			Data:     hexutil.Bytes(data),
 			Position: hexutil.Uint(len(t.callstack[len(t.callstack)-1].Calls)),
 		}
+		log.Event = decodePrecompileEvent(log.Address, log.Topics, log.Data) // libevm
 		t.callstack[len(t.callstack)-1].Logs = append(t.callstack[len(t.callstack)-1].Logs, log)
 	}
 }
@@ -0,0 +1,77 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package native
+
+import (
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+)
+
+var _ vm.PrecompileLogTracer = (*callTracer)(nil)
+
+// CapturePrecompileLog implements the [vm.PrecompileLogTracer] hook, recording
+// logs emitted by stateful precompiles, subject to the same configuration as
+// those recorded by [callTracer.CaptureState] for LOG* op codes.
+func (t *callTracer) CapturePrecompileLog(l *types.Log, depth int) {
+	if !t.config.WithLog {
+		return
+	}
+	if t.config.OnlyTopCall && depth > 1 {
+		return
+	}
+	if t.interrupt.Load() {
+		return
+	}
+	frame := &t.callstack[len(t.callstack)-1]
+	frame.Logs = append(frame.Logs, callLog{
+		Address:  l.Address,
+		Topics:   l.Topics,
+		Data:     hexutil.Bytes(l.Data),
+		Position: hexutil.Uint(len(frame.Calls)),
+		Event:    decodePrecompileEvent(l.Address, l.Topics, l.Data),
+	})
+}
+
+// A callLogEvent is the decoding of a [callLog] emitted by a precompile, using
+// the event registered with [vm.RegisterPrecompileEvents].
+type callLogEvent struct {
+	Name      string `json:"name"`
+	Signature string `json:"signature"`
+	// Args is nil if the log couldn't be decoded.
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// decodePrecompileEvent returns nil if no event is registered for the log.
+func decodePrecompileEvent(addr common.Address, topics []common.Hash, data []byte) *callLogEvent {
+	l := &types.Log{
+		Address: addr,
+		Topics:  topics,
+		Data:    data,
+	}
+	ev, ok := vm.PrecompileEventOf(l)
+	if !ok {
+		return nil
+	}
+	args, _ := vm.UnpackEvent(ev, l) // nil if the log is malformed
+	return &callLogEvent{
+		Name:      ev.Name,
+		Signature: ev.Sig,
+		Args:      args,
+	}
+}
@@ -1456,7 +1456,7 @@ func (r *Resolver) MaxPriorityFeePerGas(ctx context.Context) (hexutil.Big, error
 }
 
 func (r *Resolver) ChainID(ctx context.Context) (hexutil.Big, error) {
-	return hexutil.Big(*r.backend.ChainConfig().ChainID), nil
+	return hexutil.Big(*types.SigningChainID(r.backend.ChainConfig())), nil // libevm
 }
 
 // SyncState represents the synchronisation status returned from the `syncing` accessor.
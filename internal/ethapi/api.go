@@ -459,7 +459,7 @@ func (s *PersonalAccountAPI) signTransaction(ctx context.Context, args *Transact
 	// Assemble the transaction and sign with the wallet
 	tx := args.toTransaction()
 
-	return wallet.SignTxWithPassphrase(account, passwd, tx, s.b.ChainConfig().ChainID)
+	return wallet.SignTxWithPassphrase(account, passwd, tx, types.SigningChainID(s.b.ChainConfig())) // libevm
 }
 
 // SendTransaction will create a transaction from the given arguments and
@@ -634,7 +634,7 @@ func NewBlockChainAPI(b Backend) *BlockChainAPI {
 // wasn't synced up to a block where EIP-155 is enabled, but this behavior caused issues
 // in CL clients.
 func (api *BlockChainAPI) ChainId() *hexutil.Big {
-	return (*hexutil.Big)(api.b.ChainConfig().ChainID)
+	return (*hexutil.Big)(types.SigningChainID(api.b.ChainConfig())) // libevm
 }
 
 // BlockNumber returns the block number of the chain head.
@@ -1102,6 +1102,7 @@ func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.S
 		return nil, err
 	}
 	evm := b.GetEVM(ctx, msg, state, header, &vm.Config{NoBaseFee: true}, &blockCtx)
+	defer evm.Finish() // libevm
 
 	// Wait for the context to be done and cancel the evm. Even if the
 	// EVM has finished, cancelling may be done (repeatedly)
@@ -1524,6 +1525,7 @@ func AccessList(ctx context.Context, b Backend, blockNrOrHash rpc.BlockNumberOrH
 		config := vm.Config{Tracer: tracer, NoBaseFee: true}
 		vmenv := b.GetEVM(ctx, msg, statedb, header, &config, nil)
 		res, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit))
+		vmenv.Finish() // libevm
 		if err != nil {
 			return nil, 0, nil, fmt.Errorf("failed to apply transaction: %v err: %v", args.toTransaction().Hash(), err)
 		}
@@ -1734,7 +1736,7 @@ func (s *TransactionAPI) sign(addr common.Address, tx *types.Transaction) (*type
 		return nil, err
 	}
 	// Request the wallet to sign the transaction
-	return wallet.SignTx(account, tx, s.b.ChainConfig().ChainID)
+	return wallet.SignTx(account, tx, types.SigningChainID(s.b.ChainConfig())) // libevm
 }
 
 // SubmitTransaction is a helper function that submits tx to txPool and logs a message.
@@ -1796,7 +1798,7 @@ func (s *TransactionAPI) SendTransaction(ctx context.Context, args TransactionAr
 	// Assemble the transaction and sign with the wallet
 	tx := args.toTransaction()
 
-	signed, err := wallet.SignTx(account, tx, s.b.ChainConfig().ChainID)
+	signed, err := wallet.SignTx(account, tx, types.SigningChainID(s.b.ChainConfig())) // libevm
 	if err != nil {
 		return common.Hash{}, err
 	}
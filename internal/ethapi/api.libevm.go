@@ -0,0 +1,40 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/rpc"
+)
+
+// GetCodeMetadata returns the metadata of the code stored at the given address
+// in the state for the given block, as reported by any [vm.CodeMetadataHooks]
+// in effect at that block. It returns nil if there are no such hooks or if the
+// code has no metadata.
+func (api *DebugAPI) GetCodeMetadata(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (any, error) {
+	state, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	isPostMerge := header.Difficulty.Cmp(common.Big0) == 0
+	rules := api.b.ChainConfig().Rules(header.Number, isPostMerge, header.Time)
+	md := vm.CodeMetadata(rules, state, address)
+	return md, state.Error()
+}
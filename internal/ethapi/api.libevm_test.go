@@ -0,0 +1,148 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/consensus/beacon"
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/params"
+	"github.com/ava-labs/libevm/rpc"
+)
+
+type replayDomainConfig struct {
+	params.NOOPHooks
+	Domain []byte `json:"domain"`
+}
+
+func (c *replayDomainConfig) SigningChainID(chainID *big.Int) *big.Int {
+	return types.DeriveReplayDomain(chainID, c.Domain)
+}
+
+func TestReplayDomainChainID(t *testing.T) {
+	params.TestOnlyClearRegisteredExtras()
+	t.Cleanup(params.TestOnlyClearRegisteredExtras)
+	extras := params.RegisterExtras(params.Extras[*replayDomainConfig, params.NOOPHooks]{})
+
+	config := *params.MergedTestChainConfig
+	extras.ChainConfig.Set(&config, &replayDomainConfig{Domain: []byte("app")})
+	want := types.DeriveReplayDomain(config.ChainID, []byte("app"))
+	require.NotEqual(t, config.ChainID, want, "derived chain ID")
+
+	genesis := &core.Genesis{
+		Config: &config,
+		Alloc:  types.GenesisAlloc{},
+	}
+	b := newTestBackend(t, 1, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		b.SetPoS()
+	})
+	ctx := context.Background()
+	to := common.Address{1}
+
+	got := NewBlockChainAPI(b).ChainId()
+	assert.Equal(t, want, got.ToInt(), "eth_chainId")
+
+	txAPI := NewTransactionAPI(b, nil)
+	filled, err := txAPI.FillTransaction(ctx, TransactionArgs{
+		From:  &b.acc.Address,
+		To:    &to,
+		Value: (*hexutil.Big)(big.NewInt(1)),
+	})
+	require.NoError(t, err, "eth_fillTransaction")
+	assert.Equal(t, want, filled.Tx.ChainId(), "eth_fillTransaction chain ID")
+
+	args := argsFromTransaction(filled.Tx, b.acc.Address)
+	signers := map[string]func() (*SignTransactionResult, error){
+		"eth_signTransaction": func() (*SignTransactionResult, error) {
+			return txAPI.SignTransaction(ctx, args)
+		},
+		"personal_signTransaction": func() (*SignTransactionResult, error) {
+			return NewPersonalAccountAPI(b, nil).SignTransaction(ctx, args, "")
+		},
+	}
+	for name, sign := range signers {
+		t.Run(name, func(t *testing.T) {
+			res, err := sign()
+			require.NoError(t, err)
+			assert.Equal(t, want, res.Tx.ChainId(), "signed tx's chain ID")
+
+			from, err := types.Sender(types.LatestSigner(b.ChainConfig()), res.Tx)
+			require.NoError(t, err, "types.Sender() with node's signer")
+			assert.Equal(t, b.acc.Address, from, "types.Sender() with node's signer")
+		})
+	}
+
+	t.Run("unmodified_chain_ID", func(t *testing.T) {
+		args := args
+		args.ChainID = (*hexutil.Big)(config.ChainID)
+		_, err := txAPI.SignTransaction(ctx, args)
+		assert.ErrorContains(t, err, "chainId does not match")
+	})
+}
+
+func TestEVMLifecycleEventsBalanced(t *testing.T) {
+	genesis := &core.Genesis{
+		Config: params.MergedTestChainConfig,
+		Alloc: types.GenesisAlloc{
+			// PUSH0 PUSH0 REVERT
+			{'r', 'e', 'v'}: {Code: []byte{byte(vm.PUSH0), byte(vm.PUSH0), byte(vm.REVERT)}},
+		},
+	}
+	b := newTestBackend(t, 1, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		b.SetPoS()
+	})
+
+	counts := make(map[vm.LifecycleEventKind]int)
+	unsubscribe := vm.SubscribeLifecycleEvents(func(ev *vm.LifecycleEvent) {
+		if ev.ChainConfig == b.ChainConfig() {
+			counts[ev.Kind]++
+		}
+	})
+	t.Cleanup(unsubscribe)
+
+	var (
+		ctx    = context.Background()
+		latest = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		api    = NewBlockChainAPI(b)
+	)
+	for _, to := range []common.Address{{1}, {'r', 'e', 'v'}} {
+		args := TransactionArgs{
+			From: &b.acc.Address,
+			To:   &to,
+		}
+		// Errors are ignored as reverting calls are only included to exercise
+		// error paths.
+		_, _ = api.Call(ctx, args, &latest, nil, nil)
+		_, _ = api.EstimateGas(ctx, args, &latest, nil)
+		_, _ = api.CreateAccessList(ctx, args, &latest)
+		_, _ = NewTransactionAPI(b, nil).FillTransaction(ctx, args)
+	}
+
+	assert.Positive(t, counts[vm.EVMCreated], "%v events", vm.EVMCreated)
+	assert.Equal(t, counts[vm.EVMCreated], counts[vm.EVMFinished], "%v events == %v events", vm.EVMFinished, vm.EVMCreated)
+}
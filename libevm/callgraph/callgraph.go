@@ -0,0 +1,191 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package callgraph records the structured call tree of EVM execution,
+// including precompile frames and calls made by stateful precompiles via
+// [vm.PrecompileEnvironment].
+package callgraph
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/vm"
+)
+
+// A Frame is a single node in a call graph, corresponding to a call or a
+// contract creation.
+type Frame struct {
+	Type     vm.OpCode
+	From, To common.Address
+	Input    []byte
+	Output   []byte
+	Gas      uint64
+	GasUsed  uint64
+	// Value is nil if the frame type doesn't carry a value (e.g.
+	// DELEGATECALL).
+	Value *big.Int
+	Err   error
+	// Precompile is true i.f.f. `To` was a precompiled contract under the
+	// rules in effect, including any libevm overrides.
+	Precompile bool
+	Children   []*Frame
+}
+
+// Walk calls `fn` for `f` and all of its descendants, depth first and in call
+// order. The depth of `f` is 0. If `fn` returns an error then Walk returns it
+// immediately, without visiting any more frames.
+func (f *Frame) Walk(fn func(_ *Frame, depth int) error) error {
+	return f.walk(fn, 0)
+}
+
+func (f *Frame) walk(fn func(*Frame, int) error, depth int) error {
+	if err := fn(f, depth); err != nil {
+		return err
+	}
+	for _, c := range f.Children {
+		if err := c.walk(fn, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A Recorder is a [vm.EVMLogger] that records the call graph of the execution
+// it observes. The zero value is ready to use and a Recorder MAY be reused for
+// multiple executions, but not concurrently; each top-level call resets the
+// recorded graph.
+type Recorder struct {
+	evm   *vm.EVM
+	root  *Frame
+	stack []*Frame
+}
+
+var _ vm.EVMLogger = (*Recorder)(nil)
+
+// Root returns the root of the most recently recorded call graph. It returns
+// an error if no execution has been recorded or if the execution is yet to
+// complete.
+func (r *Recorder) Root() (*Frame, error) {
+	if r.root == nil {
+		return nil, errors.New("no execution recorded")
+	}
+	if n := len(r.stack); n > 0 {
+		return nil, fmt.Errorf("%d call frame(s) yet to exit", n)
+	}
+	return r.root, nil
+}
+
+func (r *Recorder) newFrame(typ vm.OpCode, from, to common.Address, input []byte, gas uint64, value *big.Int) *Frame {
+	f := &Frame{
+		Type:  typ,
+		From:  from,
+		To:    to,
+		Input: common.CopyBytes(input),
+		Gas:   gas,
+	}
+	if value != nil {
+		f.Value = new(big.Int).Set(value)
+	}
+	if typ != vm.CREATE && typ != vm.CREATE2 && r.evm != nil {
+		f.Precompile = r.evm.IsPrecompile(to)
+	}
+	return f
+}
+
+func (r *Recorder) exit(output []byte, gasUsed uint64, err error) {
+	n := len(r.stack)
+	if n == 0 {
+		return
+	}
+	f := r.stack[n-1]
+	r.stack = r.stack[:n-1]
+
+	f.Output = common.CopyBytes(output)
+	f.GasUsed = gasUsed
+	f.Err = err
+}
+
+// CaptureStart implements the [vm.EVMLogger] interface, resetting the recorded
+// call graph.
+func (r *Recorder) CaptureStart(env *vm.EVM, from, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	r.evm = env
+	typ := vm.CALL
+	if create {
+		typ = vm.CREATE
+	}
+	r.root = r.newFrame(typ, from, to, input, gas, value)
+	r.stack = []*Frame{r.root}
+}
+
+// CaptureEnd implements the [vm.EVMLogger] interface.
+func (r *Recorder) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	r.exit(output, gasUsed, err)
+}
+
+// CaptureEnter implements the [vm.EVMLogger] interface.
+func (r *Recorder) CaptureEnter(typ vm.OpCode, from, to common.Address, input []byte, gas uint64, value *big.Int) {
+	f := r.newFrame(typ, from, to, input, gas, value)
+	if n := len(r.stack); n > 0 {
+		parent := r.stack[n-1]
+		parent.Children = append(parent.Children, f)
+	}
+	r.stack = append(r.stack, f)
+}
+
+// CaptureExit implements the [vm.EVMLogger] interface.
+func (r *Recorder) CaptureExit(output []byte, gasUsed uint64, err error) {
+	r.exit(output, gasUsed, err)
+}
+
+// CaptureTxStart implements the [vm.EVMLogger] interface as a no-op.
+func (*Recorder) CaptureTxStart(gasLimit uint64) {}
+
+// CaptureTxEnd implements the [vm.EVMLogger] interface as a no-op.
+func (*Recorder) CaptureTxEnd(restGas uint64) {}
+
+// CaptureState implements the [vm.EVMLogger] interface as a no-op.
+func (*Recorder) CaptureState(uint64, vm.OpCode, uint64, uint64, *vm.ScopeContext, []byte, int, error) {
+}
+
+// CaptureFault implements the [vm.EVMLogger] interface as a no-op.
+func (*Recorder) CaptureFault(uint64, vm.OpCode, uint64, uint64, *vm.ScopeContext, int, error) {}
+
+// ApplyMessage is equivalent to [core.ApplyMessage] except that it also returns
+// the call graph of the message's execution. Any tracer already configured on
+// the EVM is replaced for the duration of the call and then restored.
+//
+// If [core.ApplyMessage] returns an error then the returned [Frame] is nil.
+func ApplyMessage(evm *vm.EVM, msg *core.Message, gp *core.GasPool) (*Frame, *core.ExecutionResult, error) {
+	rec := new(Recorder)
+
+	old := evm.Config.Tracer
+	evm.Config.Tracer = rec
+	defer func() { evm.Config.Tracer = old }()
+
+	res, err := core.ApplyMessage(evm, msg, gp)
+	if err != nil {
+		return nil, res, err
+	}
+	root, err := rec.Root()
+	if err != nil {
+		return nil, res, err
+	}
+	return root, res, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package callgraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestRecorder(t *testing.T) {
+	rng := ethtest.NewPseudoRand(704)
+	var (
+		eoa      = rng.Address()
+		sut      = rng.Address()
+		contract = rng.Address()
+		leaf     = rng.Address()
+	)
+	leafOutput := []byte("leaf")
+	errLeaf := errors.New("leaf error")
+
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			sut: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				if _, err := env.Call(contract, []byte("to contract"), env.Gas()/2, uint256.NewInt(0)); err != nil {
+					return nil, err
+				}
+				return env.Call(leaf, []byte("to leaf"), env.Gas()/2, uint256.NewInt(0))
+			}),
+			leaf: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				return leafOutput, errLeaf
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	state, evm := ethtest.NewZeroEVM(t)
+	state.CreateAccount(contract)
+	state.SetCode(contract, []byte{byte(vm.STOP)})
+
+	rec := new(Recorder)
+	evm.Config.Tracer = rec
+
+	_, _, err := evm.Call(vm.AccountRef(eoa), sut, []byte("to sut"), 1e6, uint256.NewInt(0))
+	require.ErrorIs(t, err, errLeaf, "evm.Call()")
+
+	root, err := rec.Root()
+	require.NoErrorf(t, err, "%T.Root()", rec)
+
+	type frame struct {
+		Type       vm.OpCode
+		From, To   common.Address
+		Input      string
+		Output     string
+		Err        error
+		Precompile bool
+		Depth      int
+	}
+	var got []frame
+	require.NoError(t, root.Walk(func(f *Frame, depth int) error {
+		got = append(got, frame{
+			Type:       f.Type,
+			From:       f.From,
+			To:         f.To,
+			Input:      string(f.Input),
+			Output:     string(f.Output),
+			Err:        f.Err,
+			Precompile: f.Precompile,
+			Depth:      depth,
+		})
+		return nil
+	}))
+
+	want := []frame{
+		{
+			Type:       vm.CALL,
+			From:       eoa,
+			To:         sut,
+			Input:      "to sut",
+			Output:     string(leafOutput),
+			Err:        errLeaf,
+			Precompile: true,
+			Depth:      0,
+		},
+		{
+			Type:  vm.CALL,
+			From:  sut,
+			To:    contract,
+			Input: "to contract",
+			Depth: 1,
+		},
+		{
+			Type:       vm.CALL,
+			From:       sut,
+			To:         leaf,
+			Input:      "to leaf",
+			Output:     string(leafOutput),
+			Err:        errLeaf,
+			Precompile: true,
+			Depth:      1,
+		},
+	}
+	assert.Equal(t, want, got, "call graph walked depth first")
+
+	for i, c := range root.Children {
+		assert.LessOrEqualf(t, c.GasUsed, c.Gas, "%T.Children[%d] gas used <= supplied", root, i)
+	}
+}
+
+func TestRecorderIncomplete(t *testing.T) {
+	rec := new(Recorder)
+	_, err := rec.Root()
+	require.Error(t, err, "Root() before any execution")
+
+	rec.CaptureStart(nil, common.Address{}, common.Address{}, false, nil, 0, nil)
+	_, err = rec.Root()
+	require.Error(t, err, "Root() before CaptureEnd()")
+
+	rec.CaptureEnd(nil, 0, nil)
+	_, err = rec.Root()
+	require.NoError(t, err, "Root() after CaptureEnd()")
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// The precompilegen command generates scaffolding for a stateful precompile
+// from a contract ABI. See the libevm/precompilegen package for details.
+//
+// Usage:
+//
+//	precompilegen -abi <abi.json> -pkg <package> -type <Type> -out <file.go>
+//
+// The test skeleton is written alongside the output, with the .go extension
+// replaced by _test.go. Solidity interfaces must first be compiled to ABI JSON;
+// e.g. with `solc --abi`.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ava-labs/libevm/libevm/precompilegen"
+)
+
+func main() {
+	abiFile := flag.String("abi", "", "Path to the ABI JSON file")
+	pkg := flag.String("pkg", "", "Name of the Go package to generate")
+	typ := flag.String("type", "", "Exported name of the precompile")
+	out := flag.String("out", "", "Path of the Go file to write")
+	flag.Parse()
+
+	if err := run(*abiFile, *pkg, *typ, *out); err != nil {
+		fmt.Fprint(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(abiFile, pkg, typ, out string) error {
+	if !strings.HasSuffix(out, ".go") || strings.HasSuffix(out, "_test.go") {
+		return fmt.Errorf("output %q must be a non-test .go file", out)
+	}
+	abiJSON, err := os.ReadFile(abiFile) //nolint:gosec // Variable file is under the user's direct control
+	if err != nil {
+		return err
+	}
+	gen, err := precompilegen.Generate(precompilegen.Config{
+		ABI:     abiJSON,
+		Package: pkg,
+		Type:    typ,
+	})
+	if err != nil {
+		return err
+	}
+
+	testOut := strings.TrimSuffix(out, ".go") + "_test.go"
+	for path, src := range map[string][]byte{
+		out:     gen.Source,
+		testOut: gen.Test,
+	} {
+		if err := os.WriteFile(path, src, 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
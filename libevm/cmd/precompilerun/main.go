@@ -0,0 +1,34 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// The precompilerun command executes a single precompile call against an
+// in-memory state and prints the output, gas used, logs, and state diff as
+// JSON. See the libevm/precompilerun package for details.
+//
+// Usage:
+//
+//	precompilerun -precompile <address> [-input <hex>] [-fixture <fixture.json>] [-caller <address>] [-gas <limit>] [-value <wei>] [-static]
+//
+// This binary only has access to the standard Ethereum precompiles. To debug
+// custom precompiles, build an equivalent command that imports the packages
+// registering them.
+package main
+
+import "github.com/ava-labs/libevm/libevm/precompilerun"
+
+func main() {
+	precompilerun.Main()
+}
@@ -0,0 +1,231 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package compressedinput provides an opt-in envelope format that allows
+// stateful precompiles to accept compressed input, which is useful for
+// data-heavy (e.g. proof-carrying) calls that would otherwise exceed calldata
+// budgets.
+//
+// An envelope is encoded as:
+//
+//	Magic (4 bytes) || Algorithm (1 byte) || decompressed size (uint32 big-endian) || payload
+//
+// Gas is charged on the declared decompressed size before any decompression
+// takes place, and decompression is strictly limited to that size.
+package compressedinput
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/ava-labs/libevm/core/vm"
+)
+
+// Magic is the prefix identifying an envelope.
+var Magic = [4]byte{0xef, 'c', 'm', 'p'}
+
+// headerLen is the length of an envelope before its payload.
+const headerLen = len(Magic) + 1 + 4
+
+// An Algorithm identifies the compression used for an envelope's payload.
+type Algorithm byte
+
+// Supported compression algorithms.
+const (
+	Snappy Algorithm = iota + 1
+	Zstd
+)
+
+// String returns a human-readable representation of the algorithm.
+func (a Algorithm) String() string {
+	switch a {
+	case Snappy:
+		return "snappy"
+	case Zstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("Algorithm(%d)", byte(a))
+	}
+}
+
+// Errors returned when decoding an envelope.
+var (
+	ErrMalformed        = errors.New("malformed compressed-input envelope")
+	ErrUnsupported      = errors.New("unsupported compression algorithm")
+	ErrTooLarge         = errors.New("decompressed input exceeds limit")
+	ErrSizeMismatch     = errors.New("decompressed input size mismatch")
+	errTooLargeToEncode = errors.New("input too large to encode")
+)
+
+// IsEnvelope reports whether `input` is prefixed with [Magic].
+func IsEnvelope(input []byte) bool {
+	return bytes.HasPrefix(input, Magic[:])
+}
+
+// Encode compresses `data` with the algorithm and wraps it in an envelope.
+func Encode(algo Algorithm, data []byte) ([]byte, error) {
+	if uint64(len(data)) > math.MaxUint32 {
+		return nil, errTooLargeToEncode
+	}
+
+	var payload []byte
+	switch algo {
+	case Snappy:
+		payload = snappy.Encode(nil, data)
+	case Zstd:
+		// A single segment sizes the frame's window to its content, which
+		// [Decode] requires to bound memory by the declared size.
+		enc, err := zstd.NewWriter(nil, zstd.WithSingleSegment(true))
+		if err != nil {
+			return nil, err
+		}
+		payload = enc.EncodeAll(data, nil)
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, algo)
+	}
+
+	out := make([]byte, headerLen, headerLen+len(payload))
+	copy(out, Magic[:])
+	out[len(Magic)] = byte(algo)
+	binary.BigEndian.PutUint32(out[len(Magic)+1:], uint32(len(data))) //nolint:gosec // bounds checked above
+	return append(out, payload...), nil
+}
+
+// Config limits and prices the decompression of envelopes.
+type Config struct {
+	// MaxSize is the maximum permitted decompressed size, in bytes.
+	MaxSize uint32
+	// GasPerByte is charged for every byte of declared decompressed size.
+	GasPerByte uint64
+	// Algorithms, if non-empty, restricts the accepted algorithms.
+	Algorithms []Algorithm
+}
+
+// Decode decompresses `input` if it is an envelope, charging gas against the
+// environment. Input that isn't an envelope is returned unchanged and without
+// charge. If there is insufficient gas, [vm.ErrOutOfGas] is returned.
+func (c *Config) Decode(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+	if !IsEnvelope(input) {
+		return input, nil
+	}
+	if len(input) < headerLen {
+		return nil, ErrMalformed
+	}
+
+	algo := Algorithm(input[len(Magic)])
+	if !c.accepts(algo) {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, algo)
+	}
+	size := binary.BigEndian.Uint32(input[len(Magic)+1:])
+	if size > c.MaxSize {
+		return nil, fmt.Errorf("%w: %d > %d", ErrTooLarge, size, c.MaxSize)
+	}
+
+	if c.GasPerByte > 0 {
+		hi, gas := bits.Mul64(uint64(size), c.GasPerByte)
+		if hi != 0 || !env.UseGas(gas) {
+			return nil, vm.ErrOutOfGas
+		}
+	}
+	return decompress(algo, size, input[headerLen:])
+}
+
+func (c *Config) accepts(algo Algorithm) bool {
+	if algo != Snappy && algo != Zstd {
+		return false
+	}
+	if len(c.Algorithms) == 0 {
+		return true
+	}
+	for _, a := range c.Algorithms {
+		if a == algo {
+			return true
+		}
+	}
+	return false
+}
+
+// decompress decompresses the payload, returning an error if the result isn't
+// exactly `size` bytes long. No more than `size` bytes, or the minimum zstd
+// window of 1KiB if greater, are ever allocated for the output.
+func decompress(algo Algorithm, size uint32, payload []byte) ([]byte, error) {
+	switch algo {
+	case Snappy:
+		n, err := snappy.DecodedLen(payload)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		if n != int(size) {
+			return nil, fmt.Errorf("%w: %d != %d", ErrSizeMismatch, n, size)
+		}
+		out, err := snappy.Decode(make([]byte, n), payload)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		return out, nil
+
+	case Zstd:
+		// zstd never uses a window smaller than [zstd.MinWindowSize], even
+		// for single-segment frames declaring less content.
+		limit := max(uint64(size), zstd.MinWindowSize)
+		dec, err := zstd.NewReader(
+			bytes.NewReader(payload),
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxMemory(limit),
+			zstd.WithDecoderMaxWindow(limit),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		defer dec.Close()
+
+		out, err := io.ReadAll(io.LimitReader(dec, int64(size)+1))
+		switch {
+		case errors.Is(err, zstd.ErrDecoderSizeExceeded):
+			return nil, fmt.Errorf("%w: more than %d", ErrSizeMismatch, size)
+		case err != nil:
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		if len(out) != int(size) {
+			return nil, fmt.Errorf("%w: %d != %d", ErrSizeMismatch, len(out), size)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("%w: %v", ErrUnsupported, algo)
+}
+
+// Wrap returns a precompile that decodes its input with [Config.Decode] before
+// passing it to `fn`.
+func (c *Config) Wrap(fn vm.PrecompiledStatefulContract) vm.PrecompiledStatefulContract {
+	return func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+		in, err := c.Decode(env, input)
+		if err != nil {
+			return nil, err
+		}
+		return fn(env, in)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package compressedinput
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestDecode(t *testing.T) {
+	const (
+		maxSize    = 1 << 12
+		gasPerByte = 3
+		gasLimit   = 1e6
+	)
+	cfg := &Config{
+		MaxSize:    maxSize,
+		GasPerByte: gasPerByte,
+	}
+
+	rng := ethtest.NewPseudoRand(745)
+	precompile := rng.Address()
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(cfg.Wrap(func(_ vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				return input, nil // echo
+			})),
+		},
+	}
+	hooks.Register(t)
+
+	call := func(t *testing.T, input []byte) ([]byte, uint64, error) {
+		t.Helper()
+		_, evm := ethtest.NewZeroEVM(t)
+		ret, gasLeft, err := evm.Call(vm.AccountRef(rng.Address()), precompile, input, gasLimit, uint256.NewInt(0))
+		return ret, gasLimit - gasLeft, err
+	}
+
+	data := bytes.Repeat([]byte("libevm"), 100)
+	for _, algo := range []Algorithm{Snappy, Zstd} {
+		t.Run(algo.String(), func(t *testing.T) {
+			env, err := Encode(algo, data)
+			require.NoErrorf(t, err, "Encode(%v, ...)", algo)
+			require.True(t, IsEnvelope(env), "IsEnvelope(Encode(...))")
+			assert.Less(t, len(env), len(data), "encoded length")
+
+			got, gasUsed, err := call(t, env)
+			require.NoError(t, err, "Call() with envelope")
+			assert.Equal(t, data, got, "decoded input")
+			assert.Equal(t, uint64(len(data)*gasPerByte), gasUsed, "gas used")
+		})
+	}
+
+	t.Run("passthrough", func(t *testing.T) {
+		got, gasUsed, err := call(t, data)
+		require.NoError(t, err, "Call() without envelope")
+		assert.Equal(t, data, got, "input")
+		assert.Zero(t, gasUsed, "gas used")
+	})
+
+	tooLarge, err := Encode(Snappy, make([]byte, maxSize+1))
+	require.NoError(t, err, "Encode() oversized input")
+
+	lying, err := Encode(Zstd, data)
+	require.NoError(t, err, "Encode()")
+	lying[headerLen-1]-- // declared size no longer matches
+
+	tests := []struct {
+		name  string
+		input []byte
+		want  error
+	}{
+		{
+			name:  "truncated header",
+			input: Magic[:],
+			want:  ErrMalformed,
+		},
+		{
+			name:  "unknown algorithm",
+			input: append(Magic[:], 0xff, 0, 0, 0, 0),
+			want:  ErrUnsupported,
+		},
+		{
+			name:  "too large",
+			input: tooLarge,
+			want:  ErrTooLarge,
+		},
+		{
+			name:  "size mismatch",
+			input: lying,
+			want:  ErrSizeMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := call(t, tt.input)
+			require.ErrorIs(t, err, tt.want, "Call()")
+		})
+	}
+}
+
+func TestDecodeOutOfGas(t *testing.T) {
+	rng := ethtest.NewPseudoRand(7450)
+	precompile := rng.Address()
+	cfg := &Config{
+		MaxSize:    1 << 20,
+		GasPerByte: 1 << 10,
+		Algorithms: []Algorithm{Snappy},
+	}
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(cfg.Wrap(func(vm.PrecompileEnvironment, []byte) ([]byte, error) {
+				t.Error("precompile called despite insufficient gas")
+				return nil, nil
+			})),
+		},
+	}
+	hooks.Register(t)
+
+	input, err := Encode(Snappy, make([]byte, 1<<10))
+	require.NoError(t, err, "Encode()")
+
+	_, evm := ethtest.NewZeroEVM(t)
+	_, _, err = evm.Call(vm.AccountRef(rng.Address()), precompile, input, 1e5, uint256.NewInt(0))
+	require.ErrorIs(t, err, vm.ErrOutOfGas, "Call()")
+
+	zstdInput, err := Encode(Zstd, nil)
+	require.NoError(t, err, "Encode(Zstd, nil)")
+	_, _, err = evm.Call(vm.AccountRef(rng.Address()), precompile, zstdInput, 1e5, uint256.NewInt(0))
+	require.ErrorIs(t, err, ErrUnsupported, "Call() with disallowed algorithm")
+}
@@ -0,0 +1,225 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package conformance provides assertions that libevm behaves identically to
+// upstream geth when no extras, hooks, or precompiles are registered. Forks of
+// libevm SHOULD run [Run] from a test in a binary that registers nothing, to
+// detect silent divergence introduced by their own modifications.
+package conformance
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/core/vm/runtime"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/params"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+// Run runs all of the package's assertions as sub-tests of `t`.
+func Run(t *testing.T) {
+	t.Run("HeaderRLP", HeaderRLP)
+	t.Run("StateAccountRLP", StateAccountRLP)
+	t.Run("ActivePrecompiles", ActivePrecompiles)
+	t.Run("Execution", Execution)
+}
+
+// HeaderRLP asserts that the RLP encoding of a [types.Header], and hence block
+// hashes, is unchanged.
+func HeaderRLP(t *testing.T) {
+	t.Helper()
+	// This is a deliberate change-detector test that locks in backwards
+	// compatibility of RLP encoding.
+	rng := ethtest.NewPseudoRand(42)
+
+	const numExtraBytes = 16
+	hdr := &types.Header{
+		ParentHash:  rng.Hash(),
+		UncleHash:   rng.Hash(),
+		Coinbase:    rng.Address(),
+		Root:        rng.Hash(),
+		TxHash:      rng.Hash(),
+		ReceiptHash: rng.Hash(),
+		Bloom:       rng.Bloom(),
+		Difficulty:  rng.Uint256().ToBig(),
+		Number:      rng.BigUint64(),
+		GasLimit:    rng.Uint64(),
+		GasUsed:     rng.Uint64(),
+		Time:        rng.Uint64(),
+		Extra:       rng.Bytes(numExtraBytes),
+		MixDigest:   rng.Hash(),
+		Nonce:       rng.BlockNonce(),
+
+		BaseFee:          rng.BigUint64(),
+		WithdrawalsHash:  rng.HashPtr(),
+		BlobGasUsed:      rng.Uint64Ptr(),
+		ExcessBlobGas:    rng.Uint64Ptr(),
+		ParentBeaconRoot: rng.HashPtr(),
+	}
+	t.Logf("%T:\n%+v", hdr, hdr)
+
+	// WARNING: changing this hex might break backwards compatibility of RLP
+	// encoding (i.e. block hashes might change)!
+	const wantHex = `f9029aa01a571e7e4d774caf46053201cfe0001b3c355ffcc93f510e671e8809741f0eeda0756095410506ec72a2c287fe83ebf68efb0be177e61acec1c985277e90e52087941bfc3bc193012ba58912c01fb35a3454831a8971a00bc9f064144eb5965c5e5d1020f9f90392e7e06ded9225966abc7c754b410e61a0d942eab201424f4320ec1e1ffa9390baf941629b9349977b5d48e0502dbb9386a035d9d550a9c113f78689b4c161c4605609bb57b83061914c42ad244daa7fc38eb901004b31d39ae246d689f23176d679a62ff328f530407cbafd0146f45b2ed635282e2812f2705bfffe52576a6fb31df817f29efac71fa56b8e133334079f8e2a8fd2055451571021506f27190adb52a1313f6d28c77d66ae1aa3d3d6757a762476f4c8a2b7b2a37079a4b6a15d1bc44161190c82d5e1c8b55e05c7354f1e5f6512924c941fb3d93667dc3a8c304a3c164e6525dfc99b5f474110c5059485732153e20300c3482832d07b65f97958360da414cb438ce252aec6c2718d155798390a6c6782181d1bac1dd64cd956332b008412ddc735f2994e297c8a088c6bb4c637542295ba3cbc3cd399c8127076f4d834d74d5b11a36b6d02e2fe3a583216aa4ccea0f052df9a96e7a454256bebabdfc38c429079f25913e0f1d7416b2f056c4a115f88b85f0e9fd6d25717881f03d9985060087c88a2c54269dfd07ca388eb8f974b42a412da90c757012bf5479896165caf573cf82fb3a0aa10f6ebf6b62bef8ed36b8ea3d4b1ddb80c99afafa37cb8f3393eb6d802f5bc886c8cd6bcd168a7e0886d5b1345d948b818a0061a7182ff228a4e66bade4717e6f4d318ac98fca12a053af6f98805a764fb5d8890ed9cab2c5229908891c7e2f71857c77ca0523cb6f654ef3fc7294c7768cddd9ccf4bcda3066d382675f37dd1a18507b5fb`
+	wantRLP, err := hex.DecodeString(wantHex)
+	require.NoError(t, err, "hex.DecodeString()")
+
+	t.Run("Encode", func(t *testing.T) {
+		got, err := rlp.EncodeToBytes(hdr)
+		require.NoErrorf(t, err, "rlp.EncodeToBytes(%T)", hdr)
+		assert.Equalf(t, wantRLP, got, "rlp.EncodeToBytes(%T)", hdr)
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		got := new(types.Header)
+		err := rlp.DecodeBytes(wantRLP, got)
+		require.NoErrorf(t, err, "rlp.DecodeBytes(..., %T)", hdr)
+		assert.Equal(t, hdr, got)
+	})
+}
+
+// StateAccountRLP asserts that the RLP encoding of a [types.StateAccount], and
+// hence state roots, is unchanged.
+func StateAccountRLP(t *testing.T) {
+	t.Helper()
+	// Encodings were generated on raw geth StateAccounts, before any libevm
+	// modifications.
+	tests := []struct {
+		acc     *types.StateAccount
+		wantHex string
+	}{
+		{
+			acc: &types.StateAccount{
+				Nonce:    0xcccccc,
+				Balance:  uint256.NewInt(0x555555),
+				Root:     common.MaxHash,
+				CodeHash: []byte{0x77, 0x77, 0x77},
+			},
+			wantHex: `ed83cccccc83555555a0ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff83777777`,
+		},
+		{
+			acc: &types.StateAccount{
+				Nonce:    0x444444,
+				Balance:  uint256.NewInt(0x666666),
+				Root:     common.Hash{},
+				CodeHash: []byte{0xbb, 0xbb, 0xbb},
+			},
+			wantHex: `ed8344444483666666a0000000000000000000000000000000000000000000000000000000000000000083bbbbbb`,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := rlp.EncodeToBytes(tt.acc)
+		require.NoErrorf(t, err, "rlp.EncodeToBytes(%+v)", tt.acc)
+		assert.Equalf(t, tt.wantHex, hex.EncodeToString(got), "rlp.EncodeToBytes(%+v)", tt.acc)
+	}
+}
+
+// ActivePrecompiles asserts that [vm.ActivePrecompiles] returns the upstream
+// set of addresses for each fork that changed it.
+func ActivePrecompiles(t *testing.T) {
+	t.Helper()
+	addrs := func(n int) []common.Address {
+		var out []common.Address
+		for i := 1; i <= n; i++ {
+			out = append(out, common.BytesToAddress([]byte{byte(i)}))
+		}
+		return out
+	}
+
+	tests := []struct {
+		name  string
+		rules params.Rules
+		want  []common.Address
+	}{
+		{"Homestead", params.Rules{IsHomestead: true}, addrs(4)},
+		{"Byzantium", params.Rules{IsByzantium: true}, addrs(8)},
+		{"Istanbul", params.Rules{IsIstanbul: true}, addrs(9)},
+		{"Berlin", params.Rules{IsBerlin: true}, addrs(9)},
+		{"Cancun", params.Rules{IsCancun: true}, addrs(10)},
+	}
+
+	for _, tt := range tests {
+		assert.ElementsMatchf(t, tt.want, vm.ActivePrecompiles(tt.rules), "vm.ActivePrecompiles([%s])", tt.name)
+	}
+}
+
+// Execution asserts that the output of, and gas consumed by, simple bytecode
+// and precompile calls are unchanged. It uses the [runtime] package's default
+// configuration.
+func Execution(t *testing.T) {
+	t.Helper()
+	const gasLimit = 100_000
+
+	tests := []struct {
+		name    string
+		code    []byte // if nil, the address is assumed to be a precompile
+		addr    common.Address
+		input   []byte
+		want    []byte
+		wantGas uint64
+	}{
+		{
+			name: "ADD",
+			// MSTORE(0, 2+3); RETURN(0, 32)
+			code: []byte{
+				byte(vm.PUSH1), 2, byte(vm.PUSH1), 3, byte(vm.ADD),
+				byte(vm.PUSH1), 0, byte(vm.MSTORE),
+				byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN),
+			},
+			addr:    common.Address{'c', 'o', 'd', 'e'},
+			want:    common.BigToHash(big.NewInt(5)).Bytes(),
+			wantGas: 3 + 3 + 3 + 3 + (3 + 3) + 3 + 3,
+		},
+		{
+			name:    "SHA256",
+			addr:    common.BytesToAddress([]byte{2}),
+			input:   []byte("abc"),
+			want:    common.FromHex("ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"),
+			wantGas: 60 + 12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+			require.NoError(t, err, "state.New()")
+			if tt.code != nil {
+				sdb.SetCode(tt.addr, tt.code)
+			}
+
+			cfg := &runtime.Config{
+				State:    sdb,
+				GasLimit: gasLimit,
+			}
+			got, leftOver, err := runtime.Call(tt.addr, tt.input, cfg)
+			require.NoError(t, err, "runtime.Call()")
+			assert.Equal(t, tt.want, got, "runtime.Call() output")
+			assert.Equal(t, tt.wantGas, gasLimit-leftOver, "gas consumed")
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package describe
+
+import (
+	"errors"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A ChainReader provides the chain information required by an [API]. It is
+// satisfied by a *core.BlockChain.
+type ChainReader interface {
+	Config() *params.ChainConfig
+	CurrentHeader() *types.Header
+}
+
+// An API exposes [New], [Precompiles] and [PrecompileEvents] over RPC. It is
+// intended to be registered under the "debug" namespace, making them available
+// as `debug_libevmConfiguration`, `debug_libevmPrecompiles` and
+// `debug_libevmEvents` respectively.
+type API struct {
+	chain ChainReader
+}
+
+// NewAPI constructs a new [API].
+func NewAPI(chain ChainReader) *API {
+	return &API{chain}
+}
+
+// A Result is returned by [API.LibevmConfiguration].
+type Result struct {
+	Description *Description `json:"description"`
+	Digest      common.Hash  `json:"digest"`
+}
+
+// LibevmConfiguration describes the node's libevm configuration, with
+// precompiles evaluated under the rules of the current head block.
+func (api *API) LibevmConfiguration() (*Result, error) {
+	cfg, rules, err := api.currentRules()
+	if err != nil {
+		return nil, err
+	}
+	d, err := New(cfg, rules)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := d.Digest()
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Description: d,
+		Digest:      digest,
+	}, nil
+}
+
+// LibevmPrecompiles returns the self-descriptions of precompiles active under
+// the rules of the current head block, allowing wallets and SDKs to generate
+// bindings for the connected chain.
+func (api *API) LibevmPrecompiles() ([]DescribedPrecompile, error) {
+	_, rules, err := api.currentRules()
+	if err != nil {
+		return nil, err
+	}
+	return Precompiles(rules), nil
+}
+
+// LibevmEvents returns the precompile events registered with
+// [vm.RegisterPrecompileEvents], which are decoded inline by the callTracer.
+func (api *API) LibevmEvents() []PrecompileEvent {
+	return PrecompileEvents()
+}
+
+func (api *API) currentRules() (*params.ChainConfig, params.Rules, error) {
+	hdr := api.chain.CurrentHeader()
+	if hdr == nil {
+		return nil, params.Rules{}, errors.New("no current header")
+	}
+	cfg := api.chain.Config()
+	isMerge := hdr.Difficulty != nil && hdr.Difficulty.Sign() == 0
+	return cfg, cfg.Rules(hdr.Number, isMerge, hdr.Time), nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package describe produces deterministic descriptions of everything
+// registered with libevm, allowing nodes to confirm that they are identically
+// configured before joining consensus.
+package describe
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A Description describes libevm registrations and a chain configuration.
+// Types are represented as reported by the `%T` verb of the [fmt] package.
+//
+// The JSON encoding of a Description is deterministic and is the preimage of
+// its [Description.Digest].
+type Description struct {
+	// Params is nil if no [params.Extras] have been registered.
+	Params *params.RegisteredExtrasInfo `json:"params"`
+	// Types is nil if no [types] extras have been registered.
+	Types *types.RegisteredExtrasInfo `json:"types"`
+	// StateDBHooks and VMHooks are empty if the respective hooks have not been
+	// registered.
+	StateDBHooks string `json:"stateDBHooks"`
+	VMHooks      string `json:"vmHooks"`
+
+	// ChainConfig is the JSON encoding of the [params.ChainConfig], including
+	// any extra payload and therefore all fork schedules.
+	ChainConfig json.RawMessage `json:"chainConfig"`
+	// Precompiles are those returned by [vm.ActivePrecompiles] under the
+	// [params.Rules] passed to [New], sorted by address.
+	Precompiles []Precompile `json:"precompiles"`
+}
+
+// A Precompile describes an active precompiled contract.
+type Precompile struct {
+	Address common.Address `json:"address"`
+	// Implementation is empty if [vm.ActivePrecompiles] and [vm.PrecompileAt]
+	// are inconsistent, which indicates a faulty
+	// [params.RulesHooks.ActivePrecompiles] implementation.
+	Implementation string `json:"implementation"`
+}
+
+// New describes all current libevm registrations, the chain configuration, and
+// the precompiles active under the rules.
+func New(c *params.ChainConfig, rules params.Rules) (*Description, error) {
+	cfg, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %T to JSON: %v", c, err)
+	}
+	d := &Description{
+		ChainConfig: cfg,
+	}
+
+	if info, ok := params.RegisteredExtras(); ok {
+		d.Params = &info
+	}
+	if info, ok := types.RegisteredExtras(); ok {
+		d.Types = &info
+	}
+	if h, ok := state.RegisteredExtras(); ok {
+		d.StateDBHooks = fmt.Sprintf("%T", h)
+	}
+	if h, ok := vm.RegisteredHooks(); ok {
+		d.VMHooks = fmt.Sprintf("%T", h)
+	}
+
+	for _, addr := range vm.ActivePrecompiles(rules) {
+		p := Precompile{Address: addr}
+		if impl, ok := vm.PrecompileAt(rules, addr); ok {
+			p.Implementation = fmt.Sprintf("%T", impl)
+		}
+		d.Precompiles = append(d.Precompiles, p)
+	}
+	slices.SortFunc(d.Precompiles, func(a, b Precompile) int {
+		return bytes.Compare(a.Address[:], b.Address[:])
+	})
+
+	return d, nil
+}
+
+// Digest returns the Keccak256 hash of the JSON encoding of the Description.
+func (d *Description) Digest() (common.Hash, error) {
+	buf, err := json.Marshal(d)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("encoding %T to JSON: %v", d, err)
+	}
+	return crypto.Keccak256Hash(buf), nil
+}
+
+// A DescribedPrecompile is an active precompile that describes itself via
+// [vm.DescribePrecompile].
+type DescribedPrecompile struct {
+	Address common.Address `json:"address"`
+	vm.PrecompileDescription
+}
+
+// Precompiles returns the self-descriptions of all precompiles active under the
+// rules, sorted by address. Precompiles that don't describe themselves are
+// omitted.
+func Precompiles(rules params.Rules) []DescribedPrecompile {
+	var ds []DescribedPrecompile
+	for _, addr := range vm.ActivePrecompiles(rules) {
+		if d, ok := vm.DescribePrecompile(rules, addr); ok {
+			ds = append(ds, DescribedPrecompile{addr, d})
+		}
+	}
+	slices.SortFunc(ds, func(a, b DescribedPrecompile) int {
+		return bytes.Compare(a.Address[:], b.Address[:])
+	})
+	return ds
+}
+
+// A PrecompileEvent describes an event registered with
+// [vm.RegisterPrecompileEvents].
+type PrecompileEvent struct {
+	Address   common.Address `json:"address"`
+	Name      string         `json:"name"`
+	Signature string         `json:"signature"`
+	ID        common.Hash    `json:"id"`
+}
+
+// PrecompileEvents returns all events registered with
+// [vm.RegisterPrecompileEvents], sorted by address and then by signature.
+func PrecompileEvents() []PrecompileEvent {
+	var evs []PrecompileEvent
+	for _, r := range vm.RegisteredPrecompileEvents() {
+		evs = append(evs, PrecompileEvent{
+			Address:   r.Precompile,
+			Name:      r.Event.Name,
+			Signature: r.Event.Sig,
+			ID:        r.Event.ID,
+		})
+	}
+	return evs
+}
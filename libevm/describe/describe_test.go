@@ -0,0 +1,146 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package describe
+
+import (
+	"bytes"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestNew(t *testing.T) {
+	custom := common.Address{0xff}
+	precompile := vm.NewStatefulPrecompile(func(vm.PrecompileEnvironment, []byte) ([]byte, error) {
+		return nil, nil
+	})
+
+	var includeCustom bool
+	stub := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			custom: precompile,
+		},
+		ActivePrecompilesFn: func(active []common.Address) []common.Address {
+			if includeCustom {
+				// Deliberately unsorted to demonstrate that [New] sorts.
+				return append([]common.Address{custom}, active...)
+			}
+			return active
+		},
+	}
+	stub.Register(t)
+
+	config := &params.ChainConfig{ChainID: big.NewInt(1)}
+	rules := config.Rules(big.NewInt(0), false, 0)
+
+	describe := func(t *testing.T) (*Description, common.Hash) {
+		t.Helper()
+		d, err := New(config, rules)
+		require.NoErrorf(t, err, "New(...)")
+		digest, err := d.Digest()
+		require.NoErrorf(t, err, "%T.Digest()", d)
+		return d, digest
+	}
+
+	without, withoutDigest := describe(t)
+	includeCustom = true
+	with, withDigest := describe(t)
+
+	t.Run("registrations", func(t *testing.T) {
+		require.NotNil(t, with.Params, "Description.Params")
+		assert.Equal(t, "*hookstest.Stub", with.Params.ChainConfig, "Description.Params.ChainConfig")
+		assert.Equal(t, "*hookstest.Stub", with.Params.Rules, "Description.Params.Rules")
+	})
+
+	t.Run("precompiles", func(t *testing.T) {
+		assert.Len(t, with.Precompiles, len(without.Precompiles)+1, "precompile count with custom address active")
+		assert.True(t, slices.IsSortedFunc(with.Precompiles, func(a, b Precompile) int {
+			return bytes.Compare(a.Address[:], b.Address[:])
+		}), "precompiles sorted by address")
+
+		i := slices.IndexFunc(with.Precompiles, func(p Precompile) bool {
+			return p.Address == custom
+		})
+		require.GreaterOrEqual(t, i, 0, "custom precompile present")
+		assert.NotEmpty(t, with.Precompiles[i].Implementation, "custom precompile implementation")
+	})
+
+	t.Run("digest", func(t *testing.T) {
+		_, again := describe(t)
+		assert.Equal(t, withDigest, again, "digest of unchanged configuration")
+		assert.NotEqual(t, withoutDigest, withDigest, "digest after activating precompile")
+	})
+}
+
+func TestPrecompiles(t *testing.T) {
+	var (
+		described = common.Address{0xfe}
+		declared  = common.Address{0xfd}
+		plain     = common.Address{0xfc}
+	)
+	precompile := vm.NewStatefulPrecompile(func(vm.PrecompileEnvironment, []byte) ([]byte, error) {
+		return nil, nil
+	})
+	desc := vm.PrecompileDescription{
+		Name:    "Described",
+		Version: "v1.2.3",
+		ABI:     []byte(`[{"type":"function","name":"foo"}]`),
+	}
+
+	stub := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			described: vm.WithDescription(precompile, desc),
+			declared:  vm.WithABI(precompile, "Declared", []byte(`[]`)),
+			plain:     precompile,
+		},
+		ActivePrecompilesFn: func(active []common.Address) []common.Address {
+			return append([]common.Address{described, plain, declared}, active...)
+		},
+	}
+	stub.Register(t)
+
+	rules := new(params.ChainConfig).Rules(big.NewInt(0), false, 0)
+
+	want := []DescribedPrecompile{
+		{
+			Address: declared,
+			PrecompileDescription: vm.PrecompileDescription{
+				Name: "Declared",
+				ABI:  []byte(`[]`),
+			},
+		},
+		{
+			Address:               described,
+			PrecompileDescription: desc,
+		},
+	}
+	assert.Equal(t, want, Precompiles(rules), "Precompiles()")
+
+	name, abiJSON, ok := vm.PrecompileABI(rules, described)
+	require.True(t, ok, "vm.PrecompileABI([described]) ok")
+	assert.Equal(t, desc.Name, name, "vm.PrecompileABI([described]) name")
+	assert.Equal(t, []byte(desc.ABI), abiJSON, "vm.PrecompileABI([described]) ABI")
+}
@@ -55,13 +55,15 @@ func NewZeroEVM(tb testing.TB, opts ...EVMOption) (*state.StateDB, *vm.EVM) {
 		o.apply(args)
 	}
 
-	return sdb, vm.NewEVM(
+	evm := vm.NewEVM(
 		args.blockContext,
 		args.txContext,
 		args.stateDB,
 		args.chainConfig,
 		args.config,
 	)
+	tb.Cleanup(evm.Finish)
+	return sdb, evm
 }
 
 type evmConstructorArgs struct {
@@ -96,3 +98,10 @@ func WithChainConfig(c *params.ChainConfig) EVMOption {
 		args.chainConfig = c
 	})
 }
+
+// WithConfig overrides the default config.
+func WithConfig(c vm.Config) EVMOption {
+	return funcOption(func(args *evmConstructorArgs) {
+		args.config = c
+	})
+}
@@ -0,0 +1,35 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package ethtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/core/state"
+)
+
+// StateDiffOf returns the changes made to the [state.StateDB] by `fn`.
+func StateDiffOf(tb testing.TB, sdb *state.StateDB, fn func()) state.StateDiff {
+	tb.Helper()
+	snap := sdb.Snapshot()
+	fn()
+	diff, err := sdb.DiffSinceSnapshot(snap)
+	require.NoErrorf(tb, err, "%T.DiffSinceSnapshot(%d)", sdb, snap)
+	return diff
+}
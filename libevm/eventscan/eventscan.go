@@ -0,0 +1,176 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package eventscan scans canonical receipts for events emitted by
+// precompiles, for indexers built directly on libevm rather than on RPC.
+package eventscan
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/libevm/accounts/abi"
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A Filter matches logs emitted by a single address for a set of ABI events.
+// Anonymous events have no identifying topic and are therefore never matched.
+type Filter struct {
+	address common.Address
+	events  map[common.Hash]abi.Event
+}
+
+// NewFilter returns a Filter for the named events of the ABI, emitted by the
+// address. If no names are provided then all non-anonymous events are
+// matched.
+func NewFilter(addr common.Address, contractABI abi.ABI, eventNames ...string) (*Filter, error) {
+	f := &Filter{
+		address: addr,
+		events:  make(map[common.Hash]abi.Event),
+	}
+	if len(eventNames) == 0 {
+		for _, ev := range contractABI.Events {
+			if !ev.Anonymous {
+				f.events[ev.ID] = ev
+			}
+		}
+		return f, nil
+	}
+
+	for _, name := range eventNames {
+		ev, ok := contractABI.Events[name]
+		if !ok {
+			return nil, fmt.Errorf("no event %q in ABI", name)
+		}
+		if ev.Anonymous {
+			return nil, fmt.Errorf("event %q is anonymous", name)
+		}
+		f.events[ev.ID] = ev
+	}
+	return f, nil
+}
+
+// ErrNoRegisteredABI is returned by [RegisteredFilter] if the precompile at the
+// address doesn't declare an ABI.
+var ErrNoRegisteredABI = errors.New("no ABI registered for precompile")
+
+// RegisteredFilter is equivalent to [NewFilter] with the ABI returned by
+// [vm.PrecompileABI].
+func RegisteredFilter(rules params.Rules, addr common.Address, eventNames ...string) (*Filter, error) {
+	_, abiJSON, ok := vm.PrecompileABI(rules, addr)
+	if !ok {
+		return nil, fmt.Errorf("%w at %v", ErrNoRegisteredABI, addr)
+	}
+	contractABI, err := abi.JSON(bytes.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("parsing ABI of precompile at %v: %v", addr, err)
+	}
+	return NewFilter(addr, contractABI, eventNames...)
+}
+
+// An Event is a decoded log matched by a [Filter].
+type Event struct {
+	// Name is the name of the event in the ABI, i.e. a key of [abi.ABI.Events].
+	Name string
+	Log  *types.Log
+	// Args maps argument names to their values. Indexed arguments of dynamic
+	// type are the Keccak256 hash of their value.
+	Args map[string]any
+}
+
+// Scan calls fn for every matching event in blocks [from, to], in log order.
+// Receipts are only read for blocks with header blooms that may contain a
+// matching log. If fn returns an error then scanning stops and the error is
+// propagated.
+func (f *Filter) Scan(r vm.BlockReader, from, to uint64, fn func(*Event) error) error {
+	for num := from; num <= to; num++ {
+		if err := f.scanBlock(r, num, fn); err != nil {
+			return err
+		}
+		if num == to { // avoids overflow if `to` is the maximum uint64
+			break
+		}
+	}
+	return nil
+}
+
+func (f *Filter) scanBlock(r vm.BlockReader, num uint64, fn func(*Event) error) error {
+	hdr, err := r.HeaderByNumber(num)
+	if err != nil {
+		return fmt.Errorf("header of block %d: %w", num, err)
+	}
+	if !f.mayMatch(hdr.Bloom) {
+		return nil
+	}
+
+	receipts, err := r.ReceiptsByNumber(num)
+	if err != nil {
+		return fmt.Errorf("receipts of block %d: %w", num, err)
+	}
+	for _, rcpt := range receipts {
+		for _, l := range rcpt.Logs {
+			ev, ok, err := f.match(l)
+			if err != nil {
+				return fmt.Errorf("block %d, tx %v, log %d: %v", num, l.TxHash, l.Index, err)
+			}
+			if !ok {
+				continue
+			}
+			if err := fn(ev); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mayMatch reports whether the bloom may contain a log from the Filter's
+// address with at least one of its event IDs as the first topic.
+func (f *Filter) mayMatch(b types.Bloom) bool {
+	if !types.BloomLookup(b, f.address) {
+		return false
+	}
+	for id := range f.events {
+		if types.BloomLookup(b, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter) match(l *types.Log) (*Event, bool, error) {
+	if l.Address != f.address || len(l.Topics) == 0 {
+		return nil, false, nil
+	}
+	ev, ok := f.events[l.Topics[0]]
+	if !ok {
+		return nil, false, nil
+	}
+
+	args, err := vm.UnpackEvent(ev, l)
+	if err != nil {
+		return nil, false, err
+	}
+	return &Event{
+		Name: ev.Name,
+		Log:  l,
+		Args: args,
+	}, true, nil
+}
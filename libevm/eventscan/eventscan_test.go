@@ -0,0 +1,209 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package eventscan
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/accounts/abi"
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+const testABI = `[
+	{"type":"event","name":"Minted","inputs":[
+		{"name":"to","type":"address","indexed":true},
+		{"name":"amount","type":"uint256","indexed":false}
+	]},
+	{"type":"event","name":"Paused","inputs":[]}
+]`
+
+type fakeChain struct {
+	receipts        map[uint64]types.Receipts
+	receiptsFetched []uint64
+}
+
+func (c *fakeChain) HeaderByNumber(num uint64) (*types.Header, error) {
+	return &types.Header{
+		Number: new(big.Int).SetUint64(num),
+		Bloom:  types.CreateBloom(c.receipts[num]),
+	}, nil
+}
+
+func (c *fakeChain) ReceiptsByNumber(num uint64) ([]*types.Receipt, error) {
+	c.receiptsFetched = append(c.receiptsFetched, num)
+	return c.receipts[num], nil
+}
+
+var _ vm.BlockReader = (*fakeChain)(nil)
+
+func TestScan(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(testABI))
+	require.NoError(t, err, "abi.JSON()")
+
+	precompile := common.Address{'p', 'r', 'e'}
+	other := common.Address{'o', 't', 'h'}
+
+	log := func(t *testing.T, addr common.Address, ev string, args ...any) *types.Log {
+		t.Helper()
+		topics, data, err := vm.PackEvent(contractABI.Events[ev], args...)
+		require.NoErrorf(t, err, "vm.PackEvent(%q, ...)", ev)
+		return &types.Log{Address: addr, Topics: topics, Data: data}
+	}
+	receipt := func(logs ...*types.Log) *types.Receipt {
+		r := &types.Receipt{Logs: logs}
+		r.Bloom = types.CreateBloom(types.Receipts{r})
+		return r
+	}
+
+	alice := common.Address{'a'}
+	bob := common.Address{'b'}
+	chain := &fakeChain{
+		receipts: map[uint64]types.Receipts{
+			1: {receipt(log(t, precompile, "Minted", alice, big.NewInt(1)))},
+			2: {receipt(log(t, other, "Minted", alice, big.NewInt(2)))},
+			3: {
+				receipt(log(t, precompile, "Paused")),
+				receipt(
+					log(t, other, "Paused"),
+					log(t, precompile, "Minted", bob, big.NewInt(3)),
+				),
+			},
+			// 4 is empty
+			5: {receipt(log(t, precompile, "Paused"))},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		events       []string
+		wantArgs     []map[string]any
+		wantNames    []string
+		wantReceipts []uint64
+	}{
+		{
+			name:   "single event",
+			events: []string{"Minted"},
+			wantNames: []string{
+				"Minted",
+				"Minted",
+			},
+			wantArgs: []map[string]any{
+				{"to": alice, "amount": big.NewInt(1)},
+				{"to": bob, "amount": big.NewInt(3)},
+			},
+			// Block 2's bloom lacks the precompile's address and block 5's
+			// lacks the Minted event ID.
+			wantReceipts: []uint64{1, 3},
+		},
+		{
+			name:      "all events",
+			wantNames: []string{"Minted", "Paused", "Minted", "Paused"},
+			wantArgs: []map[string]any{
+				{"to": alice, "amount": big.NewInt(1)},
+				{},
+				{"to": bob, "amount": big.NewInt(3)},
+				{},
+			},
+			wantReceipts: []uint64{1, 3, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain.receiptsFetched = nil
+			f, err := NewFilter(precompile, contractABI, tt.events...)
+			require.NoError(t, err, "NewFilter()")
+
+			var (
+				gotNames []string
+				gotArgs  []map[string]any
+			)
+			require.NoError(t, f.Scan(chain, 0, 6, func(ev *Event) error {
+				assert.Equal(t, precompile, ev.Log.Address, "Event.Log.Address")
+				gotNames = append(gotNames, ev.Name)
+				gotArgs = append(gotArgs, ev.Args)
+				return nil
+			}), "Scan()")
+
+			assert.Equal(t, tt.wantNames, gotNames, "event names")
+			assert.Equal(t, tt.wantArgs, gotArgs, "event arguments")
+			assert.Equal(t, tt.wantReceipts, chain.receiptsFetched, "blocks for which receipts were fetched")
+		})
+	}
+
+	t.Run("bloom_exclusion", func(t *testing.T) {
+		chain.receiptsFetched = nil
+		f, err := NewFilter(other, contractABI, "Minted")
+		require.NoError(t, err, "NewFilter()")
+		require.NoError(t, f.Scan(chain, 4, 5, func(*Event) error { return nil }), "Scan()")
+		assert.Empty(t, chain.receiptsFetched, "receipts fetched for blocks without matching blooms")
+	})
+
+	t.Run("callback_error", func(t *testing.T) {
+		f, err := NewFilter(precompile, contractABI)
+		require.NoError(t, err, "NewFilter()")
+		errStop := errors.New("stop")
+		var calls int
+		err = f.Scan(chain, 0, 6, func(*Event) error {
+			calls++
+			return errStop
+		})
+		assert.ErrorIs(t, err, errStop, "Scan() with erroring callback")
+		assert.Equal(t, 1, calls, "callback invocations")
+	})
+
+	t.Run("unknown_event", func(t *testing.T) {
+		_, err := NewFilter(precompile, contractABI, "Burned")
+		assert.Error(t, err, "NewFilter() with unknown event name")
+	})
+}
+
+func TestRegisteredFilter(t *testing.T) {
+	withABI := common.Address{'a', 'b', 'i'}
+	withoutABI := common.Address{'n', 'o'}
+	p := vm.NewStatefulPrecompile(func(vm.PrecompileEnvironment, []byte) ([]byte, error) {
+		return nil, nil
+	})
+
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			withABI:    vm.WithABI(p, "Test", []byte(testABI)),
+			withoutABI: p,
+		},
+	}
+	hooks.Register(t)
+	rules := params.TestChainConfig.Rules(big.NewInt(0), false, 0)
+
+	f, err := RegisteredFilter(rules, withABI, "Minted")
+	require.NoError(t, err, "RegisteredFilter() with ABI")
+	assert.Len(t, f.events, 1, "events in Filter")
+
+	_, err = RegisteredFilter(rules, withoutABI)
+	assert.ErrorIs(t, err, ErrNoRegisteredABI, fmt.Sprintf("RegisteredFilter(..., %v) without ABI", withoutABI))
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package extprecompile adapts precompiles implemented outside of Go, e.g. in
+// a WASM module or a separate process, into stateful precompiles.
+//
+// The boundary between the EVM and the implementation is a stable, versioned
+// protocol of RLP-encoded messages; see [ProtocolVersion]. Each call is started
+// by a [Request] and ended by a [Response], between which the implementation
+// MAY send any number of [HostCall] messages, each answered by a
+// [HostResult], to access the [vm.PrecompileEnvironment]. All consensus
+// concerns, including gas accounting and state access, are therefore handled
+// by libevm while the transport is abstracted by a [Backend].
+//
+// Implementations MUST be deterministic. WASM runtimes SHOULD disable
+// non-deterministic features (e.g. floating point and threads) and meter
+// execution, charging gas via the [UseGas] method.
+package extprecompile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/core/vm"
+)
+
+// A Backend transports messages to and from a precompile implementation.
+//
+// Run sends the encoded [Request] and returns the encoded [Response]. While
+// the implementation is running, every encoded [HostCall] that it sends MUST
+// be passed to `host`, with the returned, encoded [HostResult] sent back to
+// it; calls to `host` MUST NOT be concurrent and MUST NOT occur after Run
+// returns. For a WASM module, `host` would typically be exposed as an
+// imported function.
+//
+// A non-nil error signals a failure of the backend itself, not of the
+// precompile, and is treated as such; see [New].
+type Backend interface {
+	Run(request []byte, host func(hostCall []byte) (hostResult []byte)) (response []byte, _ error)
+}
+
+// ErrBackend wraps all errors returned by a [Backend] or due to a malformed
+// message received from it.
+var ErrBackend = errors.New("external precompile backend")
+
+// New returns a stateful precompile that runs every call via the [Backend].
+//
+// A backend failure, including malformed messages, doesn't have a
+// deterministic outcome and MUST NOT be treated as a precompile error.
+// Execution is therefore invalidated, via
+// [vm.PrecompileEnvironment.InvalidateExecution], and the call fails with an
+// error wrapping [ErrBackend].
+func New(b Backend) vm.PrecompiledContract {
+	return vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+		resp, err := run(b, env, input)
+		if err != nil {
+			err := fmt.Errorf("%w: %v", ErrBackend, err)
+			env.InvalidateExecution(err)
+			return nil, err
+		}
+
+		switch {
+		case resp.Reverted:
+			return nil, vm.Revert(resp.Output)
+		case resp.Error != "":
+			return nil, errors.New(resp.Error)
+		default:
+			return resp.Output, nil
+		}
+	})
+}
+
+// run returns an error i.f.f. the Backend fails.
+func run(b Backend, env vm.PrecompileEnvironment, input []byte) (*Response, error) {
+	addrs := env.Addresses()
+	req, err := encode(&Request{
+		Version:     ProtocolVersion,
+		Precompile:  addrs.EVMSemantic.Self,
+		Caller:      addrs.EVMSemantic.Caller,
+		Input:       input,
+		Gas:         env.Gas(),
+		Value:       env.Value(),
+		ReadOnly:    env.ReadOnly(),
+		BlockNumber: env.BlockNumber().Uint64(),
+		BlockTime:   env.BlockTime(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	h := &host{env: env}
+	buf, err := b.Run(req, h.serve)
+	if err != nil {
+		return nil, err
+	}
+	if h.err != nil {
+		return nil, h.err
+	}
+	return decode[Response](buf)
+}
+
+// A host bridges [HostCall] messages to a [vm.PrecompileEnvironment].
+type host struct {
+	env vm.PrecompileEnvironment
+	// err is the first error in decoding or encoding a message, which is a
+	// backend failure and therefore reported after Backend.Run returns.
+	err error
+}
+
+func (h *host) serve(buf []byte) []byte {
+	res := new(HostResult)
+	if call, err := decode[HostCall](buf); err != nil {
+		h.setErr(err)
+		res.Error = err.Error()
+	} else if err := h.dispatch(call, res); err != nil {
+		res.Error = err.Error()
+	}
+
+	out, err := encode(res)
+	if err != nil {
+		h.setErr(err)
+	}
+	return out
+}
+
+func (h *host) setErr(err error) {
+	if h.err == nil {
+		h.err = err
+	}
+}
+
+// errUnknownMethod is reported to the implementation, and not treated as a
+// backend failure, as a newer implementation MAY probe for methods.
+var errUnknownMethod = errors.New("unknown host method")
+
+func (h *host) dispatch(c *HostCall, res *HostResult) error {
+	env := h.env
+	// As with the LOG* and storage op codes, the address is that of the
+	// contract in whose context the precompile is running; i.e. the caller's,
+	// if DELEGATECALLed.
+	self := env.Addresses().EVMSemantic.Self
+
+	switch c.Method {
+	case UseGas:
+		res.OK = env.UseGas(c.Gas)
+
+	case RemainingGas:
+		res.Gas = env.Gas()
+
+	case GetState:
+		res.Hash = env.ReadOnlyState().GetState(self, c.Key)
+
+	case SetState:
+		if env.ReadOnly() {
+			return vm.ErrWriteProtection
+		}
+		env.StateDB().SetState(self, c.Key, c.Value)
+
+	case GetBalance:
+		res.Amount = env.ReadOnlyState().GetBalance(c.Address)
+
+	case AddLog:
+		return env.AddLog(c.Topics, c.Data)
+
+	case Call, StaticCall:
+		var (
+			ret []byte
+			err error
+		)
+		if c.Method == Call {
+			value := c.Amount
+			if value == nil {
+				value = new(uint256.Int)
+			}
+			ret, err = env.Call(c.Address, c.Data, c.Gas, value)
+		} else {
+			ret, err = env.StaticCall(c.Address, c.Data, c.Gas)
+		}
+		// On revert, ret is the revert data.
+		res.Data = ret
+		return err
+
+	default:
+		return fmt.Errorf("%w: %v", errUnknownMethod, c.Method)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package extprecompile_test
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/extprecompile"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+// A guest is a precompile implementation on the far side of a stream, as if in
+// another process, and therefore only uses the wire protocol.
+type guest struct {
+	t    *testing.T
+	r    io.Reader
+	w    io.Writer
+	done chan struct{}
+}
+
+func (g *guest) hostCall(c *extprecompile.HostCall) *extprecompile.HostResult {
+	g.t.Helper()
+	buf, err := rlp.EncodeToBytes(c)
+	require.NoError(g.t, err, "rlp.EncodeToBytes(%T)", c)
+	require.NoError(g.t, extprecompile.WriteFrame(g.w, extprecompile.HostCallFrame, buf))
+
+	kind, buf, err := extprecompile.ReadFrame(g.r)
+	require.NoError(g.t, err, "ReadFrame()")
+	require.Equal(g.t, extprecompile.HostResultFrame, kind, "frame kind")
+	res := new(extprecompile.HostResult)
+	require.NoError(g.t, rlp.DecodeBytes(buf, res), "rlp.DecodeBytes(..., %T)", res)
+	return res
+}
+
+func (g *guest) respond(resp *extprecompile.Response) {
+	g.t.Helper()
+	buf, err := rlp.EncodeToBytes(resp)
+	require.NoError(g.t, err, "rlp.EncodeToBytes(%T)", resp)
+	require.NoError(g.t, extprecompile.WriteFrame(g.w, extprecompile.ResponseFrame, buf))
+}
+
+// serve runs a counter precompile that increments the value in slot zero by
+// the first byte of its input, returning the new value. An input byte of 0xff
+// results in a revert and 0xfe in a malformed response.
+func (g *guest) serve() {
+	defer close(g.done)
+	for {
+		kind, buf, err := extprecompile.ReadFrame(g.r)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) {
+			return
+		}
+		require.NoError(g.t, err, "ReadFrame()")
+		require.Equal(g.t, extprecompile.RequestFrame, kind, "frame kind")
+		req := new(extprecompile.Request)
+		require.NoError(g.t, rlp.DecodeBytes(buf, req), "rlp.DecodeBytes(..., %T)", req)
+		require.Equal(g.t, uint64(extprecompile.ProtocolVersion), req.Version, "protocol version")
+
+		switch req.Input[0] {
+		case 0xff:
+			g.respond(&extprecompile.Response{Reverted: true, Output: []byte("nope")})
+			continue
+		case 0xfe:
+			require.NoError(g.t, extprecompile.WriteFrame(g.w, extprecompile.ResponseFrame, []byte{0xff}))
+			continue
+		}
+
+		if !g.hostCall(&extprecompile.HostCall{Method: extprecompile.UseGas, Gas: 1000}).OK {
+			g.respond(&extprecompile.Response{Error: vm.ErrOutOfGas.Error()})
+			continue
+		}
+		old := g.hostCall(&extprecompile.HostCall{Method: extprecompile.GetState}).Hash
+		val := new(uint256.Int).SetBytes(old[:])
+		val.AddUint64(val, uint64(req.Input[0]))
+		updated := common.Hash(val.Bytes32())
+
+		if res := g.hostCall(&extprecompile.HostCall{Method: extprecompile.SetState, Value: updated}); res.Error != "" {
+			g.respond(&extprecompile.Response{Error: res.Error})
+			continue
+		}
+		g.hostCall(&extprecompile.HostCall{
+			Method: extprecompile.AddLog,
+			Topics: []common.Hash{updated},
+		})
+		bal := g.hostCall(&extprecompile.HostCall{Method: extprecompile.GetBalance, Address: req.Caller}).Amount
+		unknown := g.hostCall(&extprecompile.HostCall{Method: 1 << 32})
+
+		g.respond(&extprecompile.Response{
+			Output: append(append(updated.Bytes(), bal.Bytes()...), unknown.Error...),
+		})
+	}
+}
+
+func TestStreamBackend(t *testing.T) {
+	hostR, guestW := io.Pipe()
+	guestR, hostW := io.Pipe()
+	g := &guest{t: t, r: guestR, w: guestW, done: make(chan struct{})}
+	go g.serve()
+	t.Cleanup(func() {
+		hostW.Close()
+		<-g.done
+	})
+
+	precompile := common.Address{'e', 'x', 't'}
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: extprecompile.New(extprecompile.NewStreamBackend(hostR, hostW)),
+		},
+	}
+	hooks.Register(t)
+
+	caller := common.Address{'c', 'a', 'l', 'l', 'e', 'r'}
+	sdb, evm := ethtest.NewZeroEVM(t, ethtest.WithBlockContext(vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		BlockNumber: big.NewInt(0),
+	}))
+	sdb.SetBalance(caller, uint256.NewInt(42))
+
+	const gasLimit = 1e6
+	call := func(t *testing.T, input byte) ([]byte, uint64, error) {
+		t.Helper()
+		ret, gasLeft, err := evm.Call(vm.AccountRef(caller), precompile, []byte{input}, gasLimit, uint256.NewInt(0))
+		return ret, gasLimit - gasLeft, err
+	}
+
+	t.Run("success", func(t *testing.T) {
+		for _, step := range []struct {
+			increment byte
+			want      uint64
+		}{{3, 3}, {4, 7}} {
+			got, gasUsed, err := call(t, step.increment)
+			require.NoErrorf(t, err, "%T.Call()", evm)
+
+			wantVal := common.Hash(uint256.NewInt(step.want).Bytes32())
+			require.GreaterOrEqual(t, len(got), 33, "output length")
+			assert.Equal(t, wantVal.Bytes(), got[:32], "returned counter")
+			assert.Equal(t, []byte{42}, got[32:33], "returned balance of caller")
+			assert.Contains(t, string(got[33:]), "unknown host method", "error of unknown method")
+			assert.Equal(t, wantVal, sdb.GetState(precompile, common.Hash{}), "stored counter")
+			assert.GreaterOrEqual(t, gasUsed, uint64(1000), "gas used")
+		}
+		assert.Len(t, sdb.Logs(), 2, "logs")
+	})
+
+	t.Run("read_only", func(t *testing.T) {
+		_, _, err := evm.StaticCall(vm.AccountRef(caller), precompile, []byte{1}, gasLimit)
+		require.EqualError(t, err, vm.ErrWriteProtection.Error(), "%T.StaticCall()", evm)
+	})
+
+	t.Run("revert", func(t *testing.T) {
+		ret, gasUsed, err := call(t, 0xff)
+		require.ErrorIs(t, err, vm.ErrExecutionReverted, "%T.Call()", evm)
+		assert.Equal(t, []byte("nope"), ret, "revert data")
+		assert.Less(t, gasUsed, uint64(gasLimit), "gas used by revert")
+	})
+
+	t.Run("malformed_response", func(t *testing.T) {
+		require.NoError(t, evm.ExecutionInvalidated(), "ExecutionInvalidated() before")
+		_, _, err := call(t, 0xfe)
+		require.ErrorIs(t, err, extprecompile.ErrBackend, "%T.Call()", evm)
+		assert.ErrorIs(t, evm.ExecutionInvalidated(), extprecompile.ErrBackend, "ExecutionInvalidated()")
+	})
+}
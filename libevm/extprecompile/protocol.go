@@ -0,0 +1,167 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package extprecompile
+
+import (
+	"fmt"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/rlp"
+)
+
+// ProtocolVersion is the version of the wire protocol, carried by every
+// [Request]. It is incremented on any change to the encoding of messages or
+// to the semantics of a [HostMethod], and implementations SHOULD reject
+// versions that they don't support.
+const ProtocolVersion = 1
+
+// All messages are RLP encoded, as lists of their fields in the order in which
+// they are declared. Fields that aren't used by a particular [HostMethod] MUST
+// still be encoded, as their zero values, which allows implementations in
+// other languages to use a single, fixed layout per message.
+
+// A Request starts a precompile call and is the first message sent to the
+// implementation.
+type Request struct {
+	Version     uint64
+	Precompile  common.Address
+	Caller      common.Address
+	Input       []byte
+	Gas         uint64
+	Value       *uint256.Int
+	ReadOnly    bool
+	BlockNumber uint64
+	BlockTime   uint64
+}
+
+// A Response ends a precompile call and is the last message sent by the
+// implementation. If Reverted is true then Output is the revert data, which
+// MAY be empty. Otherwise, a non-empty Error signals failure, consuming all
+// remaining gas, as with any other precompile error.
+type Response struct {
+	Output   []byte
+	Reverted bool
+	Error    string
+}
+
+// A HostMethod identifies the [PrecompileEnvironment] functionality requested
+// by a [HostCall].
+//
+// [PrecompileEnvironment]: https://pkg.go.dev/github.com/ava-labs/libevm/core/vm#PrecompileEnvironment
+type HostMethod uint64
+
+// Methods available to precompile implementations. The fields of [HostCall]
+// and [HostResult] used by each are noted; all others are ignored.
+const (
+	_ HostMethod = iota // reserved to detect unset methods
+
+	// UseGas consumes HostCall.Gas, setting HostResult.OK if sufficient gas
+	// was available. Implementations are responsible for their own metering
+	// and MUST charge gas deterministically, exactly as a Go precompile would.
+	UseGas
+	// RemainingGas returns the available gas in HostResult.Gas.
+	RemainingGas
+	// GetState returns, in HostResult.Hash, the value stored in the
+	// precompile's storage under HostCall.Key.
+	GetState
+	// SetState stores HostCall.Value in the precompile's storage under
+	// HostCall.Key. It fails in a read-only context.
+	SetState
+	// GetBalance returns, in HostResult.Amount, the balance of
+	// HostCall.Address.
+	GetBalance
+	// AddLog emits a log, from the precompile's address, with HostCall.Topics
+	// and HostCall.Data. It fails in a read-only context.
+	AddLog
+	// Call calls HostCall.Address with HostCall.Data as input, forwarding
+	// HostCall.Gas and sending HostCall.Amount, which MAY be nil. The returned
+	// data, or revert data, is HostResult.Data.
+	Call
+	// StaticCall is equivalent to Call, except that it is static and
+	// HostCall.Amount is ignored.
+	StaticCall
+)
+
+// String returns a human-readable representation of the method.
+func (m HostMethod) String() string {
+	switch m {
+	case UseGas:
+		return "UseGas"
+	case RemainingGas:
+		return "RemainingGas"
+	case GetState:
+		return "GetState"
+	case SetState:
+		return "SetState"
+	case GetBalance:
+		return "GetBalance"
+	case AddLog:
+		return "AddLog"
+	case Call:
+		return "Call"
+	case StaticCall:
+		return "StaticCall"
+	default:
+		return fmt.Sprintf("HostMethod(%d)", uint64(m))
+	}
+}
+
+// A HostCall is sent by the implementation, during a precompile call, to access
+// the environment in which it is running. Exactly one [HostResult] is sent in
+// reply.
+type HostCall struct {
+	Method  HostMethod
+	Address common.Address
+	Key     common.Hash
+	Value   common.Hash
+	Topics  []common.Hash
+	Data    []byte
+	Gas     uint64
+	Amount  *uint256.Int
+}
+
+// A HostResult is the reply to a [HostCall]. A non-empty Error signals failure
+// of the method, in which case all other fields are undefined, except for Data
+// in reply to a reverted Call or StaticCall.
+type HostResult struct {
+	OK     bool
+	Hash   common.Hash
+	Data   []byte
+	Gas    uint64
+	Amount *uint256.Int
+	Error  string
+}
+
+// encode and decode are thin wrappers around the rlp package, propagating
+// errors with the type being (de)serialised.
+func encode(msg any) ([]byte, error) {
+	buf, err := rlp.EncodeToBytes(msg)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %T: %v", msg, err)
+	}
+	return buf, nil
+}
+
+func decode[T any](buf []byte) (*T, error) {
+	msg := new(T)
+	if err := rlp.DecodeBytes(buf, msg); err != nil {
+		return nil, fmt.Errorf("decoding %T: %v", msg, err)
+	}
+	return msg, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package extprecompile
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// A FrameKind identifies the message carried by a frame of a stream; see
+// [NewStreamBackend].
+type FrameKind byte
+
+// Frame kinds, one per message type.
+const (
+	_ FrameKind = iota
+	RequestFrame
+	ResponseFrame
+	HostCallFrame
+	HostResultFrame
+)
+
+// MaxFrameSize is the maximum length of a frame's payload.
+const MaxFrameSize = 16 << 20
+
+// WriteFrame writes a frame carrying the payload, which MUST be an encoded
+// message of the respective kind. A frame is a single byte identifying its
+// kind, followed by the big-endian uint32 length of the payload, followed by
+// the payload itself.
+func WriteFrame(w io.Writer, kind FrameKind, payload []byte) error {
+	if n := len(payload); n > MaxFrameSize {
+		return fmt.Errorf("frame payload of %d bytes exceeds maximum of %d", n, MaxFrameSize)
+	}
+	buf := make([]byte, 5, 5+len(payload))
+	buf[0] = byte(kind)
+	binary.BigEndian.PutUint32(buf[1:], uint32(len(payload))) //nolint:gosec // bounded above
+	_, err := w.Write(append(buf, payload...))
+	return err
+}
+
+// ReadFrame reads a frame written by [WriteFrame].
+func ReadFrame(r io.Reader) (FrameKind, []byte, error) {
+	var hdr [5]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	n := binary.BigEndian.Uint32(hdr[1:])
+	if n > MaxFrameSize {
+		return 0, nil, fmt.Errorf("frame payload of %d bytes exceeds maximum of %d", n, MaxFrameSize)
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return FrameKind(hdr[0]), payload, nil
+}
+
+// NewStreamBackend returns a [Backend] that exchanges frames, written by
+// [WriteFrame], with an implementation over a pair of streams; e.g. the stdout
+// and stdin, respectively, of a child process.
+//
+// For each precompile call, the implementation reads a [RequestFrame] and MAY
+// then write any number of [HostCallFrame]s, reading a [HostResultFrame] after
+// each, before writing a single [ResponseFrame]. Calls are serialised, so the
+// implementation only ever handles one at a time.
+func NewStreamBackend(r io.Reader, w io.Writer) Backend {
+	return &streamBackend{r: r, w: w}
+}
+
+type streamBackend struct {
+	mu sync.Mutex
+	r  io.Reader
+	w  io.Writer
+}
+
+func (s *streamBackend) Run(req []byte, host func([]byte) []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := WriteFrame(s.w, RequestFrame, req); err != nil {
+		return nil, err
+	}
+	for {
+		kind, payload, err := ReadFrame(s.r)
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case ResponseFrame:
+			return payload, nil
+		case HostCallFrame:
+			if err := WriteFrame(s.w, HostResultFrame, host(payload)); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unexpected frame kind %d", kind)
+		}
+	}
+}
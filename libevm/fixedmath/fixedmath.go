@@ -0,0 +1,298 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package fixedmath provides deterministic, floating-point-free, fixed-point
+// arithmetic for use by precompiles; e.g. to implement pricing curves.
+//
+// The [UD60x18] type is equivalent to that of the PRBMath Solidity library: an
+// unsigned, 256-bit integer interpreted as having 18 decimal places. All
+// operations are implemented with integer arithmetic only and round down
+// (towards zero), so results are identical on all platforms and are therefore
+// safe to use in consensus code. Transcendental functions are accurate to
+// within a small number of units in the last place; see the respective
+// function documentation.
+package fixedmath
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/holiman/uint256"
+)
+
+// A UD60x18 is an unsigned fixed-point number with 18 decimal places. The zero
+// value is zero and is ready to use. UD60x18 values are immutable.
+type UD60x18 struct {
+	raw uint256.Int
+}
+
+// Decimals is the number of decimal places of a [UD60x18].
+const Decimals = 18
+
+var (
+	// unit is the raw value of One.
+	unit = uint256.NewInt(1e18)
+	// log2E is log2(e), rounded down to 18 decimal places.
+	log2E = uint256.NewInt(1_442695040888963407)
+	// log2EUp is log2(e), rounded up to 18 decimal places, such that dividing
+	// by it rounds down.
+	log2EUp = uint256.NewInt(1_442695040888963408)
+)
+
+// Well-known values.
+var (
+	Zero = UD60x18{}
+	One  = UD60x18{*unit}
+	Max  = UD60x18{*new(uint256.Int).SetAllOne()}
+)
+
+// Errors returned by operations on [UD60x18] values.
+var (
+	ErrOverflow       = errors.New("fixedmath: overflow")
+	ErrUnderflow      = errors.New("fixedmath: underflow")
+	ErrDivisionByZero = errors.New("fixedmath: division by zero")
+	// ErrDomain is returned by logarithms of values less than [One], which
+	// would be negative.
+	ErrDomain = errors.New("fixedmath: input outside of domain")
+)
+
+// FromUint64 returns the integer as a UD60x18.
+func FromUint64(n uint64) UD60x18 {
+	var x UD60x18
+	x.raw.Mul(uint256.NewInt(n), unit) // can't overflow: 2^64 * 10^18 < 2^128
+	return x
+}
+
+// FromRaw returns the UD60x18 with the raw value, i.e. `raw` / 10^18.
+func FromRaw(raw *uint256.Int) UD60x18 {
+	return UD60x18{*raw}
+}
+
+// Raw returns the raw value of `x`, i.e. `x` * 10^18.
+func (x UD60x18) Raw() *uint256.Int {
+	return new(uint256.Int).Set(&x.raw)
+}
+
+// Parse parses a non-negative decimal number, with at most [Decimals] digits
+// after an optional decimal point.
+func Parse(s string) (UD60x18, error) {
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || len(frac) > Decimals || strings.ContainsAny(whole+frac, "+-_") {
+		return Zero, fmt.Errorf("fixedmath: invalid number %q", s)
+	}
+	var raw uint256.Int
+	if err := raw.SetFromDecimal(whole + frac + strings.Repeat("0", Decimals-len(frac))); err != nil {
+		return Zero, fmt.Errorf("fixedmath: parsing %q: %v", s, err)
+	}
+	return UD60x18{raw}, nil
+}
+
+// String returns the decimal representation of `x`, without trailing zeros
+// after the decimal point.
+func (x UD60x18) String() string {
+	var whole, frac uint256.Int
+	whole.DivMod(&x.raw, unit, &frac)
+	if frac.IsZero() {
+		return whole.Dec()
+	}
+	f := frac.Dec()
+	f = strings.Repeat("0", Decimals-len(f)) + f
+	return whole.Dec() + "." + strings.TrimRight(f, "0")
+}
+
+// Cmp returns -1, 0 or +1 if `x` is, respectively, less than, equal to, or
+// greater than `y`.
+func (x UD60x18) Cmp(y UD60x18) int {
+	return x.raw.Cmp(&y.raw)
+}
+
+// IsZero reports whether `x` is zero.
+func (x UD60x18) IsZero() bool {
+	return x.raw.IsZero()
+}
+
+// Floor returns `x` rounded down to an integer.
+func (x UD60x18) Floor() UD60x18 {
+	var frac uint256.Int
+	frac.Mod(&x.raw, unit)
+	x.raw.Sub(&x.raw, &frac)
+	return x
+}
+
+// Add returns `x` + `y`.
+func (x UD60x18) Add(y UD60x18) (UD60x18, error) {
+	if _, overflow := x.raw.AddOverflow(&x.raw, &y.raw); overflow {
+		return Zero, ErrOverflow
+	}
+	return x, nil
+}
+
+// Sub returns `x` - `y`.
+func (x UD60x18) Sub(y UD60x18) (UD60x18, error) {
+	if _, underflow := x.raw.SubOverflow(&x.raw, &y.raw); underflow {
+		return Zero, ErrUnderflow
+	}
+	return x, nil
+}
+
+// Mul returns `x` * `y`. The intermediate product is computed with 512 bits so
+// only the result can overflow.
+func (x UD60x18) Mul(y UD60x18) (UD60x18, error) {
+	if _, overflow := x.raw.MulDivOverflow(&x.raw, &y.raw, unit); overflow {
+		return Zero, ErrOverflow
+	}
+	return x, nil
+}
+
+// Div returns `x` / `y`. The intermediate product is computed with 512 bits so
+// only the result can overflow.
+func (x UD60x18) Div(y UD60x18) (UD60x18, error) {
+	if y.IsZero() {
+		return Zero, ErrDivisionByZero
+	}
+	if _, overflow := x.raw.MulDivOverflow(&x.raw, unit, &y.raw); overflow {
+		return Zero, ErrOverflow
+	}
+	return x, nil
+}
+
+// Sqrt returns the square root of `x`, which is exact to 18 decimal places
+// before rounding down. It returns [ErrOverflow] if `x` is greater than
+// [Max] / 10^18, as the raw value must be scaled before its root is taken.
+func (x UD60x18) Sqrt() (UD60x18, error) {
+	if _, overflow := x.raw.MulOverflow(&x.raw, unit); overflow {
+		return Zero, ErrOverflow
+	}
+	x.raw.Sqrt(&x.raw)
+	return x, nil
+}
+
+// Log2 returns the binary logarithm of `x`, which MUST be at least [One]. The
+// result is accurate to within 2 units in the last place.
+func (x UD60x18) Log2() (UD60x18, error) {
+	if x.Cmp(One) < 0 {
+		return Zero, ErrDomain
+	}
+
+	// The integer part is n = floor(log2(x)), leaving y = x / 2^n in [1, 2)
+	// from which 64 bits of the fractional part are computed, one at a time,
+	// by repeated squaring. Using a binary fixed-point representation of y
+	// avoids accumulating decimal rounding errors.
+	var quo uint256.Int
+	quo.Div(&x.raw, unit)
+	n := uint(quo.BitLen() - 1) //nolint:gosec // x >= One so BitLen() >= 1
+
+	var y uint256.Int
+	y.MulDivOverflow(&x.raw, new(uint256.Int).Lsh(uint256.NewInt(1), exp2Q-n), unit) // < 2^(exp2Q+1)
+	two := new(uint256.Int).Lsh(uint256.NewInt(2), exp2Q)
+
+	var frac uint64
+	for i := 63; i >= 0; i-- {
+		y.Mul(&y, &y) // y < 2^(exp2Q+1) so y^2 can't overflow
+		y.Rsh(&y, exp2Q)
+		if y.Cmp(two) >= 0 {
+			frac |= 1 << i
+			y.Rsh(&y, 1)
+		}
+	}
+
+	var result, f uint256.Int
+	result.Mul(uint256.NewInt(uint64(n)), unit)
+	f.Mul(uint256.NewInt(frac), unit)
+	f.Rsh(&f, 64)
+	result.Add(&result, &f)
+	return UD60x18{result}, nil
+}
+
+// Ln returns the natural logarithm of `x`, which MUST be at least [One]. It is
+// computed as [UD60x18.Log2] divided by log2(e), and has the same accuracy.
+func (x UD60x18) Ln() (UD60x18, error) {
+	l, err := x.Log2()
+	if err != nil {
+		return Zero, err
+	}
+	l.raw.MulDivOverflow(&l.raw, unit, log2EUp) // can't overflow as log2EUp > unit
+	return l, nil
+}
+
+// exp2Q is the number of fractional bits of the binary fixed-point values
+// used to compute [UD60x18.Exp2], chosen such that the product of two values
+// less than 2 can't overflow.
+const exp2Q = 126
+
+// exp2Factors[i] is 2^(2^-(i+1)) as a binary fixed-point value with exp2Q
+// fractional bits, rounded down. They are computed by repeated integer square
+// roots, and are therefore deterministic.
+var exp2Factors = func() [64]uint256.Int {
+	var fs [64]uint256.Int
+	x := new(uint256.Int).Lsh(uint256.NewInt(2), exp2Q)
+	for i := range fs {
+		x.Lsh(x, exp2Q)
+		x.Sqrt(x)
+		fs[i].Set(x)
+	}
+	return fs
+}()
+
+// Exp2 returns 2^`x`. The result has a relative error of less than 10^-17 and
+// is exact for integer `x`.
+func (x UD60x18) Exp2() (UD60x18, error) {
+	var whole, frac uint256.Int
+	whole.DivMod(&x.raw, unit, &frac)
+	if !whole.IsUint64() || whole.Uint64() > 256 {
+		return Zero, ErrOverflow
+	}
+	n := uint(whole.Uint64())
+
+	// The fractional part is converted to a 64-bit binary fraction, each set
+	// bit of which contributes a factor to the result.
+	var bits uint256.Int
+	bits.Lsh(&frac, 64)
+	bits.Div(&bits, unit)
+	f := bits.Uint64()
+
+	result := new(uint256.Int).Lsh(uint256.NewInt(1), exp2Q)
+	for i := 0; i < 64; i++ {
+		if f&(1<<(63-i)) != 0 {
+			result.Mul(result, &exp2Factors[i])
+			result.Rsh(result, exp2Q)
+		}
+	}
+
+	// result < 2^(exp2Q+1) so multiplying by 10^18 < 2^60 can't overflow.
+	result.Mul(result, unit)
+	if n <= exp2Q {
+		result.Rsh(result, exp2Q-n)
+		return UD60x18{*result}, nil
+	}
+	shift := n - exp2Q
+	if uint(result.BitLen())+shift > 256 { //nolint:gosec // BitLen() is non-negative
+		return Zero, ErrOverflow
+	}
+	result.Lsh(result, shift)
+	return UD60x18{*result}, nil
+}
+
+// Exp returns e^`x`, computed as 2^(`x` * log2(e)). The error therefore grows
+// with `x`, but is less than 10^-15 relative to the result for all `x` that
+// don't overflow.
+func (x UD60x18) Exp() (UD60x18, error) {
+	if _, overflow := x.raw.MulDivOverflow(&x.raw, log2E, unit); overflow {
+		return Zero, ErrOverflow
+	}
+	return x.Exp2()
+}
@@ -0,0 +1,288 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package fixedmath
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParse(t *testing.T, s string) UD60x18 {
+	t.Helper()
+	x, err := Parse(s)
+	require.NoErrorf(t, err, "Parse(%q)", s)
+	return x
+}
+
+func TestParseAndString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"0", "0"},
+		{"1", "1"},
+		{"1.5", "1.5"},
+		{"1.500", "1.5"},
+		{"0.000000000000000001", "0.000000000000000001"},
+		{"123456789.987654321", "123456789.987654321"},
+		{"115792089237316195423570985008687907853269984665640564039457.584007913129639935", "115792089237316195423570985008687907853269984665640564039457.584007913129639935"},
+	}
+	for _, tt := range tests {
+		assert.Equalf(t, tt.want, mustParse(t, tt.in).String(), "Parse(%q).String()", tt.in)
+	}
+	assert.Equal(t, Max, mustParse(t, tests[len(tests)-1].in), "Parse(<max>)")
+
+	for _, in := range []string{"", ".5", "-1", "+1", "1.0000000000000000001", "1e18", "abc", "115792089237316195423570985008687907853269984665640564039457.584007913129639936"} {
+		_, err := Parse(in)
+		assert.Errorf(t, err, "Parse(%q)", in)
+	}
+}
+
+// randUD60x18 returns a value with a random number of bits, up to `maxBits`,
+// such that all magnitudes are well represented.
+func randUD60x18(rng *rand.Rand, maxBits int) UD60x18 {
+	var b [32]byte
+	rng.Read(b[:])
+	var x uint256.Int
+	x.SetBytes(b[:])
+	x.Rsh(&x, uint(256-1-rng.Intn(maxBits)))
+	return UD60x18{x}
+}
+
+func TestArithmeticAgainstBigInt(t *testing.T) {
+	rng := rand.New(rand.NewSource(42)) //nolint:gosec // reproducible testing
+	u := unit.ToBig()
+	maxUint := Max.raw.ToBig()
+
+	check := func(t *testing.T, name string, x, y UD60x18, got UD60x18, err error, want *big.Int, wantErr error) {
+		t.Helper()
+		if want != nil && (want.Sign() < 0 || want.Cmp(maxUint) > 0) {
+			assert.ErrorIsf(t, err, wantErr, "%s(%v, %v)", name, x, y)
+			return
+		}
+		require.NoErrorf(t, err, "%s(%v, %v)", name, x, y)
+		assert.Equalf(t, want.String(), got.raw.Dec(), "%s(%v, %v)", name, x, y)
+	}
+
+	for i := 0; i < 10_000; i++ {
+		x, y := randUD60x18(rng, 256), randUD60x18(rng, 256)
+		bx, by := x.raw.ToBig(), y.raw.ToBig()
+
+		got, err := x.Add(y)
+		check(t, "Add", x, y, got, err, new(big.Int).Add(bx, by), ErrOverflow)
+
+		got, err = x.Sub(y)
+		check(t, "Sub", x, y, got, err, new(big.Int).Sub(bx, by), ErrUnderflow)
+
+		got, err = x.Mul(y)
+		want := new(big.Int).Mul(bx, by)
+		check(t, "Mul", x, y, got, err, want.Quo(want, u), ErrOverflow)
+
+		got, err = x.Div(y)
+		if y.IsZero() {
+			assert.ErrorIs(t, err, ErrDivisionByZero, "Div(x, 0)")
+			continue
+		}
+		want = new(big.Int).Mul(bx, u)
+		check(t, "Div", x, y, got, err, want.Quo(want, by), ErrOverflow)
+	}
+}
+
+func TestSqrt(t *testing.T) {
+	rng := rand.New(rand.NewSource(42)) //nolint:gosec // reproducible testing
+	u := unit.ToBig()
+
+	for i := 0; i < 10_000; i++ {
+		x := randUD60x18(rng, 196)
+		got, err := x.Sqrt()
+		require.NoErrorf(t, err, "%v.Sqrt()", x)
+
+		// got^2 <= x*10^18 < (got+1)^2
+		scaled := new(big.Int).Mul(x.raw.ToBig(), u)
+		g := got.raw.ToBig()
+		require.LessOrEqualf(t, new(big.Int).Mul(g, g).Cmp(scaled), 0, "%v.Sqrt()^2 <= x", x)
+		g.Add(g, big.NewInt(1))
+		require.Equalf(t, 1, new(big.Int).Mul(g, g).Cmp(scaled), "(%v.Sqrt()+1e-18)^2 > x", x)
+	}
+
+	_, err := Max.Sqrt()
+	assert.ErrorIs(t, err, ErrOverflow, "Max.Sqrt()")
+}
+
+func TestExactResults(t *testing.T) {
+	for n := uint64(0); n <= 196; n++ {
+		x := FromUint64(n)
+
+		got, err := x.Exp2()
+		require.NoErrorf(t, err, "%d.Exp2()", n)
+		want := new(uint256.Int).Lsh(unit, uint(n))
+		assert.Equalf(t, want, got.Raw(), "%d.Exp2()", n)
+
+		if n >= 64 {
+			continue
+		}
+		got, err = FromUint64(1 << n).Log2()
+		require.NoErrorf(t, err, "(2^%d).Log2()", n)
+		assert.Equalf(t, x, got, "(2^%d).Log2()", n)
+	}
+
+	for _, x := range []UD60x18{FromUint64(197), FromUint64(1000), Max} {
+		_, err := x.Exp2()
+		assert.ErrorIsf(t, err, ErrOverflow, "%v.Exp2()", x)
+	}
+
+	for _, x := range []UD60x18{Zero, mustParse(t, "0.999999999999999999")} {
+		_, err := x.Log2()
+		assert.ErrorIsf(t, err, ErrDomain, "%v.Log2()", x)
+		_, err = x.Ln()
+		assert.ErrorIsf(t, err, ErrDomain, "%v.Ln()", x)
+	}
+}
+
+func TestGoldenValues(t *testing.T) {
+	// Reference values are those of the respective functions rounded down to
+	// 18 decimal places; results MAY be lower by the stated tolerance.
+	tests := []struct {
+		name      string
+		fn        func(UD60x18) (UD60x18, error)
+		in, want  string
+		tolerance uint64 // in units of 10^-18
+	}{
+		{"Sqrt", UD60x18.Sqrt, "2", "1.414213562373095048", 0},
+		{"Sqrt", UD60x18.Sqrt, "0.25", "0.5", 0},
+		{"Log2", UD60x18.Log2, "3", "1.584962500721156181", 10},
+		{"Log2", UD60x18.Log2, "10", "3.321928094887362347", 10},
+		{"Ln", UD60x18.Ln, "2.718281828459045235", "0.999999999999999999", 10},
+		{"Ln", UD60x18.Ln, "10", "2.302585092994045684", 10},
+		{"Exp2", UD60x18.Exp2, "0.5", "1.414213562373095048", 1},
+		{"Exp2", UD60x18.Exp2, "10.25", "1217.748085762786372318", 1_000},
+		{"Exp", UD60x18.Exp, "1", "2.718281828459045235", 10},
+		{"Exp", UD60x18.Exp, "10", "22026.465794806716516957", 100_000},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.fn(mustParse(t, tt.in))
+		require.NoErrorf(t, err, "%s(%s)", tt.name, tt.in)
+		want := mustParse(t, tt.want)
+		require.LessOrEqualf(t, got.Cmp(want), 0, "%s(%s) = %v rounds down from %v", tt.name, tt.in, got, want)
+
+		diff, err := want.Sub(got)
+		require.NoError(t, err)
+		assert.LessOrEqualf(t, diff.raw.Uint64(), tt.tolerance, "%s(%s) = %v; error from %v", tt.name, tt.in, got, want)
+	}
+}
+
+func TestTranscendentalAgainstFloat(t *testing.T) {
+	rng := rand.New(rand.NewSource(42)) //nolint:gosec // reproducible testing
+
+	// float64 has a relative precision of ~1.1e-16, which bounds the
+	// tolerance that can be checked.
+	const tolerance = 1e-14
+	toFloat := func(x UD60x18) float64 {
+		f, _ := new(big.Float).SetInt(x.raw.ToBig()).Float64()
+		return f / 1e18
+	}
+	fromFloat := func(f float64) UD60x18 {
+		b, _ := new(big.Float).Mul(big.NewFloat(f), big.NewFloat(1e18)).Int(nil)
+		x, _ := uint256.FromBig(b)
+		return UD60x18{*x}
+	}
+	assertClose := func(t *testing.T, name string, in UD60x18, got UD60x18, want float64) {
+		t.Helper()
+		// Converting inputs close to 1 to float64 introduces an absolute
+		// error of ~1e-16 that the relative tolerance can't absorb when the
+		// logarithm is itself tiny.
+		delta := math.Max(tolerance*math.Abs(want), 1e-15)
+		assert.InDeltaf(t, want, toFloat(got), delta, "%s(%v)", name, in)
+	}
+
+	for i := 0; i < 10_000; i++ {
+		// Logarithms
+		x := fromFloat(1 + rng.ExpFloat64()*math.Pow(10, float64(rng.Intn(30))))
+		if x.Cmp(One) > 0 {
+			got, err := x.Log2()
+			require.NoErrorf(t, err, "%v.Log2()", x)
+			assertClose(t, "Log2", x, got, math.Log2(toFloat(x)))
+
+			got, err = x.Ln()
+			require.NoErrorf(t, err, "%v.Ln()", x)
+			assertClose(t, "Ln", x, got, math.Log(toFloat(x)))
+		}
+
+		// Exponentials, up to ~2^130 to remain within float64 precision of
+		// the input.
+		x = fromFloat(rng.Float64() * 90)
+		got, err := x.Exp2()
+		require.NoErrorf(t, err, "%v.Exp2()", x)
+		assertClose(t, "Exp2", x, got, math.Exp2(toFloat(x)))
+
+		got, err = x.Exp()
+		require.NoErrorf(t, err, "%v.Exp()", x)
+		assertClose(t, "Exp", x, got, math.Exp(toFloat(x)))
+	}
+}
+
+func TestMonotonicity(t *testing.T) {
+	// Pricing curves rely on monotonicity, which rounding errors could
+	// otherwise break at the smallest scale.
+	fns := []struct {
+		name  string
+		fn    func(UD60x18) (UD60x18, error)
+		start string
+	}{
+		{"Sqrt", UD60x18.Sqrt, "0.5"},
+		{"Log2", UD60x18.Log2, "1"},
+		{"Ln", UD60x18.Ln, "1"},
+		{"Exp2", UD60x18.Exp2, "0.5"},
+		{"Exp", UD60x18.Exp, "0.5"},
+	}
+	step := FromRaw(uint256.NewInt(1))
+
+	for _, f := range fns {
+		t.Run(f.name, func(t *testing.T) {
+			for _, start := range []string{f.start, "3.14159", "42"} {
+				x := mustParse(t, start)
+				prev, err := f.fn(x)
+				require.NoError(t, err)
+				for i := 0; i < 2_000; i++ {
+					x, err = x.Add(step)
+					require.NoError(t, err)
+					got, err := f.fn(x)
+					require.NoError(t, err)
+					require.GreaterOrEqualf(t, got.Cmp(prev), 0, "%s(%v) >= %s(%v - 1e-18)", f.name, x, f.name, x)
+					prev = got
+				}
+			}
+		})
+	}
+}
+
+func TestFloor(t *testing.T) {
+	for in, want := range map[string]string{
+		"0":        "0",
+		"0.9":      "0",
+		"1":        "1",
+		"12.34567": "12",
+	} {
+		assert.Equalf(t, mustParse(t, want), mustParse(t, in).Floor(), "%s.Floor()", in)
+	}
+}
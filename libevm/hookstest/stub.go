@@ -1,4 +1,4 @@
-// Copyright 2024-2025 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -41,14 +41,17 @@ func Register[C params.ChainConfigHooks, R params.RulesHooks](tb testing.TB, ext
 // [params.RulesHooks]. Each of the fields, if non-nil, back their respective
 // hook methods, which otherwise fall back to the default behaviour.
 type Stub struct {
-	CheckConfigForkOrderFn  func() error
-	CheckConfigCompatibleFn func(*params.ChainConfig, *big.Int, uint64) *params.ConfigCompatError
-	DescriptionSuffix       string
-	PrecompileOverrides     map[common.Address]libevm.PrecompiledContract
-	ActivePrecompilesFn     func([]common.Address) []common.Address
-	CanExecuteTransactionFn func(common.Address, *common.Address, libevm.StateReader) error
-	CanCreateContractFn     func(*libevm.AddressContext, uint64, libevm.StateReader) (uint64, error)
-	MinimumGasConsumptionFn func(txGasLimit uint64) uint64
+	CheckConfigForkOrderFn    func() error
+	CheckConfigCompatibleFn   func(*params.ChainConfig, *big.Int, uint64) *params.ConfigCompatError
+	DescriptionSuffix         string
+	ExtraForksFn              func() []params.ExtraFork
+	PrecompileOverrides       map[common.Address]libevm.PrecompiledContract
+	ActivePrecompilesFn       func([]common.Address) []common.Address
+	CanExecuteTransactionFn   func(common.Address, *common.Address, libevm.StateReader) error
+	CanCreateContractFn       func(*libevm.AddressContext, uint64, libevm.StateReader) (uint64, error)
+	MinimumGasConsumptionFn   func(txGasLimit uint64) uint64
+	ActiveEIPsFn              func() []int
+	InvalidationDispositionFn func(error) params.InvalidationDisposition
 }
 
 // Register is a convenience wrapper for registering s as both the
@@ -105,6 +108,15 @@ func (s Stub) Description() string {
 	return s.DescriptionSuffix
 }
 
+// ExtraForks proxies to the s.ExtraForksFn function if non-nil, otherwise it
+// acts as a noop.
+func (s Stub) ExtraForks() []params.ExtraFork {
+	if f := s.ExtraForksFn; f != nil {
+		return f()
+	}
+	return nil
+}
+
 // CanExecuteTransaction proxies arguments to the s.CanExecuteTransactionFn
 // function if non-nil, otherwise it acts as a noop.
 func (s Stub) CanExecuteTransaction(from common.Address, to *common.Address, sr libevm.StateReader) error {
@@ -132,7 +144,26 @@ func (s Stub) MinimumGasConsumption(limit uint64) uint64 {
 	return 0
 }
 
+// ActiveEIPs proxies to the s.ActiveEIPsFn function if non-nil, otherwise it
+// acts as a noop.
+func (s Stub) ActiveEIPs() []int {
+	if f := s.ActiveEIPsFn; f != nil {
+		return f()
+	}
+	return nil
+}
+
+// InvalidationDisposition proxies arguments to the s.InvalidationDispositionFn
+// function if non-nil, otherwise it returns [params.RejectInvalidatedTx].
+func (s Stub) InvalidationDisposition(err error) params.InvalidationDisposition {
+	if f := s.InvalidationDispositionFn; f != nil {
+		return f(err)
+	}
+	return params.RejectInvalidatedTx
+}
+
 var _ interface {
 	params.ChainConfigHooks
 	params.RulesHooks
+	params.ExecutionInvalidationHooks
 } = Stub{}
@@ -0,0 +1,121 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package lazy provides safe, lazy initialisation of values such as those
+// required by precompiles that need expensive, one-time setup (e.g. verifier
+// keys or lookup tables).
+//
+// Unlike ad-hoc [sync.Once] globals, initialisation is scoped to a specific
+// [params.ChainConfig], which allows multiple chains to be embedded in the
+// same process; errors are returned instead of panicking; and values can be
+// reset in tests.
+package lazy
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/libevm/libevm/testonly"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A PerChain lazily initialises a `T` at most once per [params.ChainConfig],
+// identified by pointer. Precompiles SHOULD use the config returned by
+// [vm.PrecompileEnvironment.ChainConfig], which is stable for a given chain.
+//
+// The zero value is ready to use once `Init` is set; a PerChain MUST NOT be
+// copied after first use.
+//
+// [vm.PrecompileEnvironment.ChainConfig]: https://pkg.go.dev/github.com/ava-labs/libevm/core/vm#PrecompileEnvironment
+type PerChain[T any] struct {
+	// Init is called to initialise the value for a specific chain. If it
+	// returns an error (or panics) then the error is returned by the
+	// respective call to [PerChain.Get] and initialisation will be retried by
+	// the next call.
+	Init func(context.Context, *params.ChainConfig) (T, error)
+
+	mu      sync.Mutex
+	entries map[*params.ChainConfig]*entry[T]
+}
+
+type entry[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// Get returns the value for the chain, calling `Init` if it hasn't already
+// been successfully called with the same config. Concurrent calls for the
+// same config will block until a single call to `Init` returns, and will
+// receive its result. A call that is blocked waiting for another's
+// initialisation will return early if `ctx` is cancelled; `ctx` is otherwise
+// propagated to `Init`.
+func (p *PerChain[T]) Get(ctx context.Context, c *params.ChainConfig) (T, error) {
+	p.mu.Lock()
+	if p.entries == nil {
+		p.entries = make(map[*params.ChainConfig]*entry[T])
+	}
+	e, ok := p.entries[c]
+	if !ok {
+		e = &entry[T]{done: make(chan struct{})}
+		p.entries[c] = e
+	}
+	p.mu.Unlock()
+
+	if ok {
+		select {
+		case <-e.done:
+			return e.val, e.err
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+
+	e.val, e.err = p.init(ctx, c)
+	if e.err != nil {
+		p.mu.Lock()
+		delete(p.entries, c)
+		p.mu.Unlock()
+	}
+	close(e.done)
+	return e.val, e.err
+}
+
+func (p *PerChain[T]) init(ctx context.Context, c *params.ChainConfig) (_ T, retErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			retErr = fmt.Errorf("lazy initialisation panicked: %v", r)
+		}
+	}()
+	if p.Init == nil {
+		var zero T
+		return zero, fmt.Errorf("%T.Init is nil", p)
+	}
+	return p.Init(ctx, c)
+}
+
+// TestOnlyReset discards all initialised values such that the next call to
+// [PerChain.Get] will call `Init` again. It panics if called from a non-testing
+// call stack.
+func (p *PerChain[T]) TestOnlyReset() {
+	testonly.OrPanic(func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.entries = nil
+	})
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package lazy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestPerChain(t *testing.T) {
+	ctx := context.Background()
+	errFail := errors.New("failed")
+
+	var (
+		calls int
+		fail  bool
+	)
+	sut := &PerChain[uint64]{
+		Init: func(_ context.Context, c *params.ChainConfig) (uint64, error) {
+			calls++
+			if fail {
+				return 0, errFail
+			}
+			return c.ChainID.Uint64(), nil
+		},
+	}
+
+	a := &params.ChainConfig{ChainID: params.MainnetChainConfig.ChainID}
+	b := &params.ChainConfig{ChainID: params.SepoliaChainConfig.ChainID}
+
+	for range 3 {
+		got, err := sut.Get(ctx, a)
+		require.NoError(t, err, "Get(a)")
+		assert.Equal(t, a.ChainID.Uint64(), got, "Get(a)")
+	}
+	assert.Equal(t, 1, calls, "Init() calls after repeated Get() with same config")
+
+	got, err := sut.Get(ctx, b)
+	require.NoError(t, err, "Get(b)")
+	assert.Equal(t, b.ChainID.Uint64(), got, "Get(b)")
+	assert.Equal(t, 2, calls, "Init() calls after Get() with different config")
+
+	sut.TestOnlyReset()
+	fail = true
+	_, err = sut.Get(ctx, a)
+	assert.ErrorIs(t, err, errFail, "Get(a) after reset with failing Init()")
+
+	fail = false
+	got, err = sut.Get(ctx, a)
+	require.NoError(t, err, "Get(a) retry after error")
+	assert.Equal(t, a.ChainID.Uint64(), got, "Get(a) retry after error")
+	assert.Equal(t, 4, calls, "Init() calls after reset and retry")
+}
+
+func TestPerChainPanic(t *testing.T) {
+	sut := &PerChain[int]{
+		Init: func(context.Context, *params.ChainConfig) (int, error) {
+			panic("boom")
+		},
+	}
+	_, err := sut.Get(context.Background(), params.TestChainConfig)
+	assert.ErrorContains(t, err, "boom", "Get() with panicking Init()")
+}
+
+func TestPerChainConcurrent(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls int
+	sut := &PerChain[int]{
+		Init: func(context.Context, *params.ChainConfig) (int, error) {
+			calls++
+			close(started)
+			<-release
+			return 42, nil
+		},
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	got := make([]int, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got[i], _ = sut.Get(context.Background(), params.TestChainConfig)
+		}()
+	}
+
+	<-started
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := sut.Get(ctx, params.TestChainConfig)
+	assert.ErrorIs(t, err, context.Canceled, "Get() with cancelled context while Init() in flight")
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, 1, calls, "Init() calls")
+	for i, v := range got {
+		assert.Equalf(t, 42, v, "Get() in goroutine %d", i)
+	}
+}
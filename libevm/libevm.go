@@ -1,4 +1,4 @@
-// Copyright 2024 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -58,6 +58,54 @@ type StateReader interface {
 	SlotInAccessList(addr common.Address, slot common.Hash) (addressOk bool, slotOk bool)
 }
 
+// A MultiStateReader is a [StateReader] that can read multiple storage slots at
+// once. It is an optional extension, so as not to break existing
+// implementations of [StateReader] and vm.StateDB; see [GetStates].
+type MultiStateReader interface {
+	StateReader
+	// GetStates is equivalent to calling GetState() for each key, returning the
+	// values in the same order, but implementations SHOULD resolve all slots in
+	// a single pass over the underlying storage.
+	GetStates(common.Address, []common.Hash, ...stateconf.StateDBStateOption) []common.Hash
+}
+
+// GetStates returns the values of the storage slots of the account, in the
+// same order as the keys. It uses the GetStates() method if `sr` is a
+// [MultiStateReader], otherwise it calls GetState() for each key.
+func GetStates(sr StateReader, addr common.Address, keys []common.Hash, opts ...stateconf.StateDBStateOption) []common.Hash {
+	if m, ok := sr.(MultiStateReader); ok {
+		return m.GetStates(addr, keys, opts...)
+	}
+	vals := make([]common.Hash, len(keys))
+	for i, k := range keys {
+		vals[i] = sr.GetState(addr, k, opts...)
+	}
+	return vals
+}
+
+// A StorageRanger is a [StateReader] that can page through an account's
+// storage. Like [MultiStateReader], it is an optional extension.
+type StorageRanger interface {
+	StateReader
+	StorageRange(addr common.Address, start common.Hash, limit int) (*StorageRange, error)
+}
+
+// A StorageRange is a page of an account's storage, in order of hashed slot
+// key, as returned by [StorageRanger.StorageRange].
+type StorageRange struct {
+	Slots []StorageSlot
+	// NextKey is the hashed key of the first slot after those in Slots, for
+	// use as the start of the next page, or nil if there are no more slots.
+	NextKey *common.Hash
+}
+
+// A StorageSlot is a single, non-zero entry in a [StorageRange].
+type StorageSlot struct {
+	HashedKey common.Hash
+	Key       *common.Hash // nil if the preimage of HashedKey is unknown
+	Value     common.Hash
+}
+
 // AddressContext carries addresses available to contexts such as calls and
 // contract creation.
 //
@@ -88,3 +136,46 @@ type CallerAndSelf struct {
 	Caller common.Address
 	Self   common.Address
 }
+
+// A JournalEntry is a side effect kept outside of the state (e.g. in a cache or
+// an external index) that is journalled alongside regular state changes, such
+// that it is undone if the surrounding call reverts.
+type JournalEntry interface {
+	// Apply performs the side effect. It is called exactly once, when the
+	// entry is added to the journal.
+	Apply()
+	// Revert undoes the effects of Apply(). It is called at most once, after
+	// reverting all entries that were added later.
+	Revert()
+}
+
+// A StorageProof is a Merkle proof of an account, and some of its storage
+// slots, against a state root. Proofs are lists of RLP-encoded trie nodes,
+// ordered from the root.
+type StorageProof struct {
+	StateRoot    common.Hash
+	Address      common.Address
+	AccountProof [][]byte
+	StorageRoot  common.Hash
+	Slots        []SlotProof
+}
+
+// A SlotProof is a Merkle proof of a single storage slot against the
+// StorageRoot of a [StorageProof].
+type SlotProof struct {
+	Key   common.Hash
+	Value common.Hash
+	Proof [][]byte
+}
+
+// A BlockReader provides read-only access to canonical blocks, by number, for
+// use by precompiles. H and R are the header and receipt types, respectively,
+// which are type parameters only to avoid a circular dependency; see
+// [vm.BlockReader] for the instantiated type. Implementations MUST return an
+// error if the block is unknown.
+//
+// [vm.BlockReader]: https://pkg.go.dev/github.com/ava-labs/libevm/core/vm#BlockReader
+type BlockReader[H, R any] interface {
+	HeaderByNumber(number uint64) (H, error)
+	ReceiptsByNumber(number uint64) ([]R, error)
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package migration runs one-off state migrations, such as storage-layout
+// upgrades or parameter seeding, at the block in which a fork activates.
+//
+// Migrations are registered with [Register] and run by the block processor,
+// miner, and chain generator via [Run], before any transactions. Each
+// migration records its completion in state so is run exactly once per chain,
+// irrespective of restarts or reorgs.
+package migration
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm/register"
+	"github.com/ava-labs/libevm/libevm/stateconf"
+	"github.com/ava-labs/libevm/libevm/testonly"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A Migration is a state transition run at the first block at which its fork
+// is active.
+type Migration struct {
+	// Name uniquely identifies the migration. It is used to derive the
+	// completion marker so MUST NOT change once run on a live chain.
+	Name string
+	// Address is the account under which the completion marker is stored;
+	// typically that of the precompile being migrated.
+	Address common.Address
+	// IsActive reports whether the fork triggering the migration is active at
+	// the block. It MUST be monotonic; i.e. once true for a block, it MUST be
+	// true for all of its descendants. See [AtTimestamp] and [AtBlock].
+	IsActive func(c *params.ChainConfig, num *big.Int, time uint64) bool
+	// Migrate performs the migration. An error is treated as an invalid block.
+	Migrate func(vm.StateDB, *types.Header) error
+}
+
+// AtTimestamp returns a [Migration.IsActive] function for a fork activated at
+// the timestamp.
+func AtTimestamp(t uint64) func(*params.ChainConfig, *big.Int, uint64) bool {
+	return func(_ *params.ChainConfig, _ *big.Int, time uint64) bool {
+		return time >= t
+	}
+}
+
+// AtBlock returns a [Migration.IsActive] function for a fork activated at the
+// block number.
+func AtBlock(n uint64) func(*params.ChainConfig, *big.Int, uint64) bool {
+	return func(_ *params.ChainConfig, num *big.Int, _ uint64) bool {
+		return num.Cmp(new(big.Int).SetUint64(n)) >= 0
+	}
+}
+
+var (
+	mu         sync.RWMutex
+	registered []Migration
+	// frozen is an immutable snapshot of `registered`, taken by
+	// [register.Freeze], and read by [Run] without locking.
+	frozen *[]Migration
+)
+
+func init() {
+	register.OnFreeze(
+		func() {
+			mu.RLock()
+			defer mu.RUnlock()
+			s := slices.Clone(registered)
+			frozen = &s
+		},
+		func() { frozen = nil },
+	)
+}
+
+// Register registers the migration, to be run by [Run]. Migrations activated
+// at the same block are run in order of registration. It is expected to be
+// called in an `init()` function and panics if the migration is incomplete, if
+// its name is already registered, or if called after [register.Freeze].
+func Register(m Migration) {
+	if m.Name == "" || m.IsActive == nil || m.Migrate == nil {
+		panic(fmt.Sprintf("incomplete migration %+v", m))
+	}
+
+	err := register.Guard(func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, r := range registered {
+			if r.Name == m.Name {
+				return fmt.Errorf("migration %q already registered", m.Name)
+			}
+		}
+		registered = append(registered, m)
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// all returns all registered migrations.
+func all() []Migration {
+	if frozen != nil {
+		return *frozen
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	return registered
+}
+
+// TestOnlyClearRegistered clears all registered migrations. It panics if
+// called from a non-testing call stack.
+func TestOnlyClearRegistered() {
+	testonly.OrPanic(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		registered = nil
+		frozen = nil
+	})
+}
+
+// MarkerSlot returns the storage slot, under the [Migration.Address], in which
+// completion of the named migration is recorded.
+func MarkerSlot(name string) common.Hash {
+	return crypto.Keccak256Hash([]byte("libevm.migration"), []byte(name))
+}
+
+var completed = common.Hash{31: 1}
+
+// Done reports whether the migration has already been run against the state.
+func Done(sdb vm.StateDB, m Migration) bool {
+	return sdb.GetState(m.Address, MarkerSlot(m.Name), stateconf.SkipStateKeyTransformation()) == completed
+}
+
+// Run runs every registered migration that is active at the header and hasn't
+// already been run, recording completion of each. If the marker's account
+// would otherwise be empty, as defined by EIP-161, its nonce is set to 1 so
+// the marker isn't removed.
+func Run(config *params.ChainConfig, header *types.Header, sdb vm.StateDB) error {
+	for _, m := range all() {
+		if !m.IsActive(config, header.Number, header.Time) || Done(sdb, m) {
+			continue
+		}
+		if err := m.Migrate(sdb, header); err != nil {
+			return fmt.Errorf("state migration %q: %w", m.Name, err)
+		}
+		if sdb.Empty(m.Address) {
+			sdb.SetNonce(m.Address, 1)
+		}
+		sdb.SetState(m.Address, MarkerSlot(m.Name), completed, stateconf.SkipStateKeyTransformation())
+	}
+	return nil
+}
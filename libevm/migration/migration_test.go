@@ -0,0 +1,122 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package migration
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/register"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestRun(t *testing.T) {
+	TestOnlyClearRegistered()
+	t.Cleanup(TestOnlyClearRegistered)
+
+	var (
+		addr  = common.Address{'m', 'i', 'g'}
+		key   = common.Hash{'k', 'e', 'y'}
+		val   = common.Hash{'v', 'a', 'l'}
+		calls int
+	)
+	m := Migration{
+		Name:     "test",
+		Address:  addr,
+		IsActive: AtBlock(2),
+		Migrate: func(sdb vm.StateDB, _ *types.Header) error {
+			calls++
+			sdb.SetState(addr, key, val)
+			return nil
+		},
+	}
+	Register(m)
+	assert.Panics(t, func() { Register(m) }, "duplicate registration")
+
+	sdb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err, "state.New()")
+	config := params.TestChainConfig
+
+	for num, wantCalls := range []int{0, 0, 1, 1, 1} {
+		hdr := &types.Header{Number: big.NewInt(int64(num))}
+		require.NoErrorf(t, Run(config, hdr, sdb), "Run() at block %d", num)
+		assert.Equalf(t, wantCalls, calls, "Migrate() calls after Run() at block %d", num)
+	}
+
+	assert.True(t, Done(sdb, m), "Done()")
+	assert.Equal(t, val, sdb.GetState(addr, key), "migrated state")
+	assert.False(t, sdb.Empty(addr), "marker account Empty()")
+}
+
+func TestRunError(t *testing.T) {
+	TestOnlyClearRegistered()
+	t.Cleanup(TestOnlyClearRegistered)
+
+	errMigration := errors.New("uh oh")
+	m := Migration{
+		Name:     "failing",
+		IsActive: AtTimestamp(0),
+		Migrate: func(vm.StateDB, *types.Header) error {
+			return errMigration
+		},
+	}
+	Register(m)
+
+	sdb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err, "state.New()")
+	hdr := &types.Header{Number: big.NewInt(0)}
+	require.ErrorIs(t, Run(params.TestChainConfig, hdr, sdb), errMigration, "Run()")
+	assert.False(t, Done(sdb, m), "Done() after failed migration")
+}
+
+func TestFrozenRegistration(t *testing.T) {
+	TestOnlyClearRegistered()
+	t.Cleanup(TestOnlyClearRegistered)
+	t.Cleanup(register.TestOnlyUnfreeze)
+
+	newMigration := func(name string, calls *int) Migration {
+		return Migration{
+			Name:     name,
+			Address:  common.Address{'m', 'i', 'g'},
+			IsActive: AtBlock(0),
+			Migrate: func(vm.StateDB, *types.Header) error {
+				*calls++
+				return nil
+			},
+		}
+	}
+
+	var before, after int
+	Register(newMigration("before", &before))
+	register.Freeze()
+	assert.PanicsWithValue(t, register.ErrFrozen, func() { Register(newMigration("after", &after)) }, "Register() after register.Freeze()")
+
+	sdb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err, "state.New()")
+	require.NoError(t, Run(params.TestChainConfig, &types.Header{Number: big.NewInt(0)}, sdb), "Run()")
+	assert.Equal(t, 1, before, "calls to migration registered before freezing")
+	assert.Zero(t, after, "calls to migration rejected after freezing")
+}
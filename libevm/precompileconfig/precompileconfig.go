@@ -0,0 +1,232 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package precompileconfig provides a pattern for upgradable precompile
+// configuration, carried as [params.ChainConfig] extras and exposed via
+// [params.Rules] extras.
+//
+// Each precompile has a typed [Config], registered under a unique key with
+// [Register]. A chain's [Upgrades] schedule any number of configs, each taking
+// effect at its timestamp and replacing any earlier config for the same key,
+// until the precompile is disabled. Typical usage is to include an [Upgrades]
+// field in the chain-config extras, returning [Upgrades.ActiveAt] from the
+// `NewRules` function of [params.Extras] for storage in the rules extras.
+package precompileconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ava-labs/libevm/libevm/register"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A Config configures a single precompile from its activation timestamp.
+type Config interface {
+	// Key MUST return the key under which the Config type was registered.
+	Key() string
+	// Timestamp returns the time at which the Config takes effect.
+	Timestamp() uint64
+	// IsDisabled reports whether the Config disables, rather than configures,
+	// the precompile.
+	IsDisabled() bool
+	// Verify returns an error if the Config is invalid.
+	Verify() error
+}
+
+// registry maps keys to the constructors passed to [Register]. As with all
+// other registries, it is written to only during initialisation so can be read
+// without locking.
+var registry = make(map[string]func() Config)
+
+// Register registers a constructor of zero-value Configs, used for JSON
+// decoding of the key's Configs. It is expected to be called in an `init()`
+// function and panics if the key is already registered or if called after
+// [register.Freeze].
+func Register(key string, newConfig func() Config) {
+	err := register.Guard(func() error {
+		if _, dup := registry[key]; dup {
+			return fmt.Errorf("precompile config %q already registered", key)
+		}
+		registry[key] = newConfig
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func newConfig(key string) (Config, error) {
+	ctor, ok := registry[key]
+	if !ok {
+		return nil, fmt.Errorf("unregistered precompile config %q", key)
+	}
+	return ctor(), nil
+}
+
+// Upgrades schedule precompile configuration, in non-decreasing order of
+// timestamp. They are JSON encoded as an array of single-key objects, mapping
+// from each [Config.Key] to the JSON encoding of the respective Config.
+type Upgrades []Config
+
+var _ interface {
+	json.Marshaler
+	json.Unmarshaler
+} = (*Upgrades)(nil)
+
+// MarshalJSON implements the [json.Marshaler] interface.
+func (u Upgrades) MarshalJSON() ([]byte, error) {
+	out := make([]map[string]Config, len(u))
+	for i, c := range u {
+		out[i] = map[string]Config{c.Key(): c}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON implements the [json.Unmarshaler] interface.
+func (u *Upgrades) UnmarshalJSON(b []byte) error {
+	var raw []map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	out := make(Upgrades, len(raw))
+	for i, r := range raw {
+		if n := len(r); n != 1 {
+			return fmt.Errorf("precompile upgrade %d has %d keys; MUST have exactly 1", i, n)
+		}
+		for key, buf := range r {
+			c, err := newConfig(key)
+			if err != nil {
+				return fmt.Errorf("precompile upgrade %d: %w", i, err)
+			}
+			if err := json.Unmarshal(buf, c); err != nil {
+				return fmt.Errorf("precompile upgrade %d (%q): %v", i, key, err)
+			}
+			out[i] = c
+		}
+	}
+	*u = out
+	return nil
+}
+
+// Verify verifies every [Config] and that the Upgrades are correctly ordered.
+// For each key, the first Config MUST NOT disable the precompile and no two
+// consecutive Configs may both disable it, nor share a timestamp.
+func (u Upgrades) Verify() error {
+	last := make(map[string]Config)
+	for i, c := range u {
+		key := c.Key()
+		if err := c.Verify(); err != nil {
+			return fmt.Errorf("precompile upgrade %d (%q): %w", i, key, err)
+		}
+		if i > 0 && c.Timestamp() < u[i-1].Timestamp() {
+			return fmt.Errorf("precompile upgrade %d (%q) at time %d before previous upgrade at %d", i, key, c.Timestamp(), u[i-1].Timestamp())
+		}
+
+		prev, ok := last[key]
+		switch {
+		case !ok && c.IsDisabled():
+			return fmt.Errorf("precompile upgrade %d (%q) disables precompile that was never enabled", i, key)
+		case ok && prev.IsDisabled() && c.IsDisabled():
+			return fmt.Errorf("precompile upgrade %d (%q) disables precompile that is already disabled", i, key)
+		case ok && prev.Timestamp() == c.Timestamp():
+			return fmt.Errorf("precompile upgrade %d (%q) at same time as previous upgrade of same precompile", i, key)
+		}
+		last[key] = c
+	}
+	return nil
+}
+
+// ActiveAt returns the Configs in effect at the timestamp, keyed by
+// [Config.Key]. Disabled precompiles are absent.
+func (u Upgrades) ActiveAt(timestamp uint64) Active {
+	active := make(Active)
+	for _, c := range u {
+		if c.Timestamp() > timestamp {
+			break
+		}
+		if c.IsDisabled() {
+			delete(active, c.Key())
+		} else {
+			active[c.Key()] = c
+		}
+	}
+	return active
+}
+
+// CheckCompatible returns a non-nil error if changing from `u` to `newUpgrades`
+// would alter any upgrade at or before the head timestamp. Upgrades are
+// compared by their JSON encodings.
+func (u Upgrades) CheckCompatible(newUpgrades Upgrades, headTimestamp uint64) *params.ConfigCompatError {
+	stored, updated := u.upTo(headTimestamp), newUpgrades.upTo(headTimestamp)
+
+	for i := 0; i < len(stored) || i < len(updated); i++ {
+		var s, n Config
+		if i < len(stored) {
+			s = stored[i]
+		}
+		if i < len(updated) {
+			n = updated[i]
+		}
+		if equal(s, n) {
+			continue
+		}
+
+		var sTime, nTime *uint64
+		if s != nil {
+			t := s.Timestamp()
+			sTime = &t
+		}
+		if n != nil {
+			t := n.Timestamp()
+			nTime = &t
+		}
+		return params.NewTimestampCompatError(fmt.Sprintf("precompile upgrade %d", i), sTime, nTime)
+	}
+	return nil
+}
+
+func (u Upgrades) upTo(timestamp uint64) Upgrades {
+	n := sort.Search(len(u), func(i int) bool {
+		return u[i].Timestamp() > timestamp
+	})
+	return u[:n]
+}
+
+func equal(a, b Config) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.Key() != b.Key() {
+		return false
+	}
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(aJSON) == string(bJSON)
+}
+
+// Active precompile configs, keyed by [Config.Key], as returned by
+// [Upgrades.ActiveAt].
+type Active map[string]Config
+
+// Get returns the active [Config] for the key, asserted as type `C`. It returns
+// false if the key isn't active or if its Config isn't a `C`.
+func Get[C Config](a Active, key string) (C, bool) {
+	c, ok := a[key].(C)
+	return c, ok
+}
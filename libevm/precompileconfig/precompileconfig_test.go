@@ -0,0 +1,224 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package precompileconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	key     string
+	Time    uint64 `json:"timestamp"`
+	Disable bool   `json:"disable,omitempty"`
+	Value   int    `json:"value,omitempty"`
+}
+
+func (c *testConfig) Key() string       { return c.key }
+func (c *testConfig) Timestamp() uint64 { return c.Time }
+func (c *testConfig) IsDisabled() bool  { return c.Disable }
+
+func (c *testConfig) Verify() error {
+	if c.Value < 0 {
+		return errors.New("negative value")
+	}
+	return nil
+}
+
+const (
+	keyA = "testA"
+	keyB = "testB"
+)
+
+func init() {
+	Register(keyA, func() Config { return &testConfig{key: keyA} })
+	Register(keyB, func() Config { return &testConfig{key: keyB} })
+}
+
+func enable(key string, time uint64, val int) *testConfig {
+	return &testConfig{key: key, Time: time, Value: val}
+}
+
+func disable(key string, time uint64) *testConfig {
+	return &testConfig{key: key, Time: time, Disable: true}
+}
+
+func TestUpgradesJSON(t *testing.T) {
+	u := Upgrades{
+		enable(keyA, 10, 1),
+		enable(keyB, 20, 2),
+		disable(keyA, 30),
+	}
+
+	buf, err := json.Marshal(u)
+	require.NoError(t, err, "json.Marshal(Upgrades)")
+	assert.JSONEq(t, `[
+		{"testA": {"timestamp": 10, "value": 1}},
+		{"testB": {"timestamp": 20, "value": 2}},
+		{"testA": {"timestamp": 30, "disable": true}}
+	]`, string(buf))
+
+	var got Upgrades
+	require.NoError(t, json.Unmarshal(buf, &got), "json.Unmarshal(..., &Upgrades)")
+	assert.Equal(t, u, got, "JSON round trip")
+
+	for _, in := range []string{
+		`[{"unregistered": {}}]`,
+		`[{"testA": {}, "testB": {}}]`,
+	} {
+		assert.Errorf(t, json.Unmarshal([]byte(in), &got), "json.Unmarshal(%s)", in)
+	}
+}
+
+func TestUpgradesVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		u       Upgrades
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			u: Upgrades{
+				enable(keyA, 10, 1),
+				enable(keyB, 10, 1),
+				enable(keyA, 20, 2),
+				disable(keyA, 30),
+				enable(keyA, 40, 3),
+			},
+		},
+		{
+			name:    "invalid_config",
+			u:       Upgrades{enable(keyA, 10, -1)},
+			wantErr: true,
+		},
+		{
+			name:    "decreasing_timestamps",
+			u:       Upgrades{enable(keyA, 20, 1), enable(keyB, 10, 1)},
+			wantErr: true,
+		},
+		{
+			name:    "disable_before_enable",
+			u:       Upgrades{disable(keyA, 10)},
+			wantErr: true,
+		},
+		{
+			name:    "double_disable",
+			u:       Upgrades{enable(keyA, 10, 1), disable(keyA, 20), disable(keyA, 30)},
+			wantErr: true,
+		},
+		{
+			name:    "same_key_same_time",
+			u:       Upgrades{enable(keyA, 10, 1), enable(keyA, 10, 2)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.u.Verify()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUpgradesActiveAt(t *testing.T) {
+	u := Upgrades{
+		enable(keyA, 10, 1),
+		enable(keyB, 20, 2),
+		enable(keyA, 30, 3),
+		disable(keyA, 40),
+	}
+
+	tests := map[uint64]map[string]int{ // timestamp -> key -> value
+		0:  {},
+		10: {keyA: 1},
+		25: {keyA: 1, keyB: 2},
+		30: {keyA: 3, keyB: 2},
+		40: {keyB: 2},
+	}
+
+	for timestamp, want := range tests {
+		active := u.ActiveAt(timestamp)
+		got := make(map[string]int)
+		for key := range active {
+			c, ok := Get[*testConfig](active, key)
+			require.Truef(t, ok, "Get[*testConfig](ActiveAt(%d), %q)", timestamp, key)
+			got[key] = c.Value
+		}
+		assert.Equalf(t, want, got, "ActiveAt(%d)", timestamp)
+	}
+}
+
+func TestUpgradesCheckCompatible(t *testing.T) {
+	stored := Upgrades{
+		enable(keyA, 10, 1),
+		enable(keyB, 20, 2),
+	}
+
+	tests := []struct {
+		name     string
+		updated  Upgrades
+		head     uint64
+		wantErr  bool
+		rewindTo uint64
+	}{
+		{
+			name:    "unchanged",
+			updated: stored,
+			head:    100,
+		},
+		{
+			name:    "future_change",
+			updated: Upgrades{enable(keyA, 10, 1), enable(keyB, 30, 2)},
+			head:    15,
+		},
+		{
+			name:     "past_change",
+			updated:  Upgrades{enable(keyA, 10, 1), enable(keyB, 20, 3)},
+			head:     25,
+			wantErr:  true,
+			rewindTo: 19,
+		},
+		{
+			name:     "past_removal",
+			updated:  Upgrades{enable(keyA, 10, 1)},
+			head:     25,
+			wantErr:  true,
+			rewindTo: 19,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := stored.CheckCompatible(tt.updated, tt.head)
+			if !tt.wantErr {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, tt.rewindTo, err.RewindToTime, "RewindToTime")
+		})
+	}
+}
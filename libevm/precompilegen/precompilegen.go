@@ -0,0 +1,227 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package precompilegen generates scaffolding for stateful precompiles from a
+// contract ABI, including selector dispatch, argument decoding, event emitters,
+// gas constants, and test stubs. It can also generate Solidity interfaces and
+// mocks from the ABIs declared by registered precompiles; see [Solidity].
+//
+// Solidity interfaces are supported by first compiling them to ABI JSON; e.g.
+// with `solc --abi`.
+package precompilegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/ava-labs/libevm/accounts/abi"
+)
+
+// Config configures [Generate].
+type Config struct {
+	// ABI is the JSON encoding of the contract ABI.
+	ABI []byte
+	// Package is the name of the Go package in which the code is generated.
+	Package string
+	// Type is the exported name of the precompile, used as a prefix for all
+	// generated identifiers.
+	Type string
+}
+
+// Generated is the output of [Generate], as gofmt-ed Go source.
+type Generated struct {
+	// Source contains the precompile itself, to be placed in a non-test file.
+	Source []byte
+	// Test contains a stub implementation and test skeleton, to be placed in a
+	// _test.go file in the same package.
+	Test []byte
+}
+
+// Generate generates a precompile skeleton from the ABI in the [Config].
+//
+// The generated code defines an interface with one method per ABI method,
+// which the user implements, a constructor of a [vm.PrecompiledContract] that
+// dispatches calls to said interface, a gas constant per method, and a
+// function per ABI event that emits it as a log. The gas constants are all
+// zero and MUST be changed.
+//
+// [vm.PrecompiledContract]: https://pkg.go.dev/github.com/ava-labs/libevm/core/vm#PrecompiledContract
+func Generate(cfg Config) (*Generated, error) {
+	if !token.IsIdentifier(cfg.Package) {
+		return nil, fmt.Errorf("invalid package name %q", cfg.Package)
+	}
+	if !token.IsExported(cfg.Type) {
+		return nil, fmt.Errorf("type name %q is not an exported identifier", cfg.Type)
+	}
+	parsed, err := abi.JSON(bytes.NewReader(cfg.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("abi.JSON(): %v", err)
+	}
+
+	data, err := newTemplateData(cfg, parsed)
+	if err != nil {
+		return nil, err
+	}
+	src, err := execute(sourceTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+	test, err := execute(testTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+	return &Generated{
+		Source: src,
+		Test:   test,
+	}, nil
+}
+
+func execute(tmpl *template.Template, data *templateData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("executing %q template: %v", tmpl.Name(), err)
+	}
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format.Source([%s template output]): %v", tmpl.Name(), err)
+	}
+	return out, nil
+}
+
+type templateData struct {
+	Package string
+	Type    string
+	ABI     string
+	Methods []method
+	Events  []event
+	// Imports required by the types of method and event arguments.
+	Imports, TestImports imports
+}
+
+type imports struct {
+	BigInt, Common bool
+}
+
+type method struct {
+	GoName  string
+	ABIName string
+	Sig     string
+	Inputs  []argument
+	Outputs []argument
+	View    bool
+	Payable bool
+}
+
+type event struct {
+	GoName  string
+	ABIName string
+	Sig     string
+	Inputs  []argument
+}
+
+type argument struct {
+	Name string
+	Type string
+}
+
+func newTemplateData(cfg Config, parsed abi.ABI) (*templateData, error) {
+	if strings.Contains(string(cfg.ABI), "`") {
+		return nil, fmt.Errorf("ABI JSON contains backtick")
+	}
+	d := &templateData{
+		Package: cfg.Package,
+		Type:    cfg.Type,
+		ABI:     string(cfg.ABI),
+	}
+
+	// args converts ABI arguments, ensuring that their names don't collide
+	// with any in `taken`.
+	args := func(in abi.Arguments, prefix string, taken []argument, imps ...*imports) []argument {
+		out := make([]argument, len(in))
+		for i, a := range in {
+			typ := a.Type.GetType().String()
+			for _, imp := range imps {
+				imp.BigInt = imp.BigInt || strings.Contains(typ, "big.")
+				imp.Common = imp.Common || strings.Contains(typ, "common.")
+			}
+			name := argName(a.Name, prefix, i)
+			for slices.ContainsFunc(taken, func(t argument) bool { return t.Name == name }) {
+				name += "_"
+			}
+			out[i] = argument{
+				Name: name,
+				Type: typ,
+			}
+		}
+		return out
+	}
+
+	for _, m := range parsed.Methods {
+		in := args(m.Inputs, "arg", nil, &d.Imports, &d.TestImports)
+		d.Methods = append(d.Methods, method{
+			GoName:  abi.ToCamelCase(m.Name),
+			ABIName: m.Name,
+			Sig:     m.Sig,
+			Inputs:  in,
+			Outputs: args(m.Outputs, "out", in, &d.Imports, &d.TestImports),
+			View:    m.IsConstant(),
+			Payable: m.IsPayable(),
+		})
+	}
+	for _, e := range parsed.Events {
+		ev := event{
+			GoName:  abi.ToCamelCase(e.Name),
+			ABIName: e.Name,
+			Sig:     e.Sig,
+			Inputs:  args(e.Inputs, "arg", nil, &d.Imports),
+		}
+		d.Events = append(d.Events, ev)
+	}
+	// Map iteration above is random but generated code MUST be deterministic.
+	slices.SortFunc(d.Methods, func(a, b method) int { return strings.Compare(a.ABIName, b.ABIName) })
+	slices.SortFunc(d.Events, func(a, b event) int { return strings.Compare(a.ABIName, b.ABIName) })
+	return d, nil
+}
+
+// reserved identifiers are those of imported packages and local variables in
+// generated code.
+var reserved = map[string]bool{
+	"abi": true, "big": true, "common": true, "errors": true, "fmt": true,
+	"strings": true, "testing": true, "types": true, "vm": true,
+	"args": true, "data": true, "env": true, "err": true, "ev": true,
+	"impl": true, "indexed": true, "input": true, "method": true, "s": true,
+	"sdb": true, "t": true, "topics": true,
+}
+
+// argName converts an ABI argument name into a Go identifier that won't shadow
+// any of those used by generated code.
+func argName(name, prefix string, i int) string {
+	n := []rune(abi.ToCamelCase(name))
+	if len(n) == 0 {
+		return fmt.Sprintf("%s%d", prefix, i)
+	}
+	n[0] = []rune(strings.ToLower(string(n[0])))[0]
+	name = string(n)
+	if reserved[name] || token.Lookup(name).IsKeyword() {
+		return name + "_"
+	}
+	return name
+}
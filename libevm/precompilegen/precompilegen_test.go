@@ -0,0 +1,167 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package precompilegen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `[
+	{"type":"function","name":"place","stateMutability":"payable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"cancel","stateMutability":"nonpayable","inputs":[{"name":"chan","type":"uint8"}],"outputs":[]},
+	{"type":"function","name":"count","stateMutability":"view","inputs":[],"outputs":[{"name":"count","type":"uint256"}]},
+	{"type":"function","name":"orders","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"tuple[]","internalType":"struct IOrderBook.Order[]","components":[{"name":"who","type":"address"},{"name":"amount","type":"uint256"}]}]},
+	{"type":"event","name":"Placed","anonymous":false,"inputs":[{"name":"who","type":"address","indexed":true},{"name":"amount","type":"uint256","indexed":false}]}
+]`
+
+// topLevelDecls returns the names of all top-level declarations in the Go
+// source, including methods, which are prefixed with their receiver type.
+func topLevelDecls(t *testing.T, src []byte) map[string]bool {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.SkipObjectResolution)
+	require.NoErrorf(t, err, "parser.ParseFile(...)\n%s", src)
+
+	decls := make(map[string]bool)
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			name := d.Name.Name
+			if d.Recv != nil {
+				star, ok := d.Recv.List[0].Type.(*ast.StarExpr)
+				require.Truef(t, ok, "receiver of %s is not a pointer", name)
+				name = star.X.(*ast.Ident).Name + "." + name //nolint:forcetypeassert // test will panic if invalid
+			}
+			decls[name] = true
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				switch s := s.(type) {
+				case *ast.TypeSpec:
+					decls[s.Name.Name] = true
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						decls[n.Name] = true
+					}
+				}
+			}
+		}
+	}
+	return decls
+}
+
+func TestGenerate(t *testing.T) {
+	cfg := Config{
+		ABI:     []byte(testABI),
+		Package: "orders",
+		Type:    "OrderBook",
+	}
+	gen, err := Generate(cfg)
+	require.NoError(t, err, "Generate()")
+
+	t.Run("source", func(t *testing.T) {
+		got := topLevelDecls(t, gen.Source)
+		for _, want := range []string{
+			"OrderBookABIJSON",
+			"OrderBookABI",
+			"OrderBookPlaceGas",
+			"OrderBookCancelGas",
+			"OrderBookCountGas",
+			"OrderBookOrdersGas",
+			"ErrOrderBookUnknownSelector",
+			"ErrOrderBookNonPayable",
+			"OrderBookImpl",
+			"NewOrderBook",
+			"runOrderBook",
+			"EmitOrderBookPlaced",
+		} {
+			assert.Truef(t, got[want], "%q declared", want)
+		}
+		assert.Contains(t, string(gen.Source), "chan_ uint8", "keyword argument renamed")
+	})
+
+	t.Run("test", func(t *testing.T) {
+		got := topLevelDecls(t, gen.Test)
+		for _, want := range []string{
+			"stubOrderBook",
+			"stubOrderBook.Place",
+			"stubOrderBook.Cancel",
+			"stubOrderBook.Count",
+			"stubOrderBook.Orders",
+			"TestOrderBook",
+		} {
+			assert.Truef(t, got[want], "%q declared", want)
+		}
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		for range 5 {
+			again, err := Generate(cfg)
+			require.NoError(t, err, "Generate()")
+			require.Equal(t, gen, again, "Generate() with identical Config")
+		}
+	})
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{
+			name: "invalid package",
+			cfg:  Config{ABI: []byte(`[]`), Package: "not-ident", Type: "X"},
+		},
+		{
+			name: "unexported type",
+			cfg:  Config{ABI: []byte(`[]`), Package: "p", Type: "x"},
+		},
+		{
+			name: "invalid ABI",
+			cfg:  Config{ABI: []byte(`{`), Package: "p", Type: "X"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(tt.cfg)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestArgName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"", "arg3"},
+		{"_", "arg3"},
+		{"amount", "amount"},
+		{"_to", "to"},
+		{"my_value", "myValue"},
+		{"type", "type_"},
+		{"input", "input_"},
+		{"common", "common_"},
+	}
+	for _, tt := range tests {
+		assert.Equalf(t, tt.want, argName(tt.name, "arg", 3), "argName(%q)", tt.name)
+	}
+}
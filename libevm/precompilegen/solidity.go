@@ -0,0 +1,266 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package precompilegen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/ava-labs/libevm/accounts/abi"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/params"
+)
+
+// SolidityConfig configures [Solidity].
+type SolidityConfig struct {
+	// License is the SPDX license identifier of generated files, defaulting
+	// to UNLICENSED.
+	License string
+	// Pragma is the Solidity version pragma of generated files, defaulting to
+	// ^0.8.0.
+	Pragma string
+}
+
+// A SolidityFile is a generated Solidity source file.
+type SolidityFile struct {
+	Name   string
+	Source []byte
+}
+
+// Solidity generates Solidity bindings for every precompile that is active
+// under the rules and is a [vm.ABIDeclarer]; e.g. as returned by the
+// constructors generated by [Generate]. For each precompile named N it
+// generates:
+//
+//   - IN.sol, with an interface of the ABI and the precompile's address as a
+//     file-level constant; and
+//   - NMock.sol, with a contract that implements the interface with virtual
+//     no-op functions, for example to be deployed at the precompile address
+//     with Foundry's vm.etch().
+//
+// Files are sorted by name. An error is returned if two precompiles declare the
+// same name.
+func Solidity(rules params.Rules, cfg SolidityConfig) ([]SolidityFile, error) {
+	if cfg.License == "" {
+		cfg.License = "UNLICENSED"
+	}
+	if cfg.Pragma == "" {
+		cfg.Pragma = "^0.8.0"
+	}
+
+	var files []SolidityFile
+	seen := make(map[string]bool)
+	for _, addr := range vm.ActivePrecompiles(rules) {
+		name, abiJSON, ok := vm.PrecompileABI(rules, addr)
+		if !ok {
+			continue
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("multiple precompiles declare ABI name %q", name)
+		}
+		seen[name] = true
+
+		parsed, err := abi.JSON(bytes.NewReader(abiJSON))
+		if err != nil {
+			return nil, fmt.Errorf("abi.JSON([ABI of precompile %q at %v]): %v", name, addr, err)
+		}
+		data := newSolContract(cfg, name, addr.Hex(), parsed)
+
+		for tmplName, tmpl := range map[string]*template.Template{
+			"I" + name + ".sol": solInterfaceTemplate,
+			name + "Mock.sol":   solMockTemplate,
+		} {
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, data); err != nil {
+				return nil, fmt.Errorf("executing %q template for %q: %v", tmpl.Name(), name, err)
+			}
+			files = append(files, SolidityFile{
+				Name:   tmplName,
+				Source: buf.Bytes(),
+			})
+		}
+	}
+
+	slices.SortFunc(files, func(a, b SolidityFile) int { return strings.Compare(a.Name, b.Name) })
+	return files, nil
+}
+
+// WriteSolidity writes the files returned by [Solidity] to the directory, which
+// MUST already exist.
+func WriteSolidity(dir string, rules params.Rules, cfg SolidityConfig) error {
+	files, err := Solidity(rules, cfg)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f.Name), f.Source, 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type solContract struct {
+	SolidityConfig
+	Name, Address string
+	Structs       []solStruct
+	Events        []solEvent
+	Functions     []solFunction
+}
+
+type solStruct struct {
+	Name   string
+	Fields []solParam
+}
+
+type solEvent struct {
+	Name      string
+	Params    []solParam
+	Anonymous bool
+}
+
+type solFunction struct {
+	Name       string
+	Params     []solParam
+	Returns    []solParam
+	Mutability string
+}
+
+type solParam struct {
+	Type, Location, Name string
+	Indexed              bool
+}
+
+func newSolContract(cfg SolidityConfig, name, addr string, parsed abi.ABI) *solContract {
+	c := &solContract{
+		SolidityConfig: cfg,
+		Name:           name,
+		Address:        addr,
+	}
+
+	for _, m := range parsed.Methods {
+		c.Functions = append(c.Functions, solFunction{
+			Name:       m.RawName,
+			Params:     c.params(m.Inputs, "calldata"),
+			Returns:    c.params(m.Outputs, "memory"),
+			Mutability: mutability(m),
+		})
+	}
+	for _, e := range parsed.Events {
+		c.Events = append(c.Events, solEvent{
+			Name:      e.RawName,
+			Params:    c.params(e.Inputs, ""),
+			Anonymous: e.Anonymous,
+		})
+	}
+
+	// Map iteration above is random but generated code MUST be deterministic.
+	slices.SortFunc(c.Functions, func(a, b solFunction) int {
+		return strings.Compare(a.Name+signature(a.Params), b.Name+signature(b.Params))
+	})
+	slices.SortFunc(c.Events, func(a, b solEvent) int {
+		return strings.Compare(a.Name+signature(a.Params), b.Name+signature(b.Params))
+	})
+	slices.SortFunc(c.Structs, func(a, b solStruct) int { return strings.Compare(a.Name, b.Name) })
+	return c
+}
+
+func signature(ps []solParam) string {
+	types := make([]string, len(ps))
+	for i, p := range ps {
+		types[i] = p.Type
+	}
+	return "(" + strings.Join(types, ",") + ")"
+}
+
+func mutability(m abi.Method) string {
+	switch m.StateMutability {
+	case "view", "pure", "payable":
+		return m.StateMutability
+	}
+	switch {
+	case m.Constant:
+		return "view"
+	case m.Payable:
+		return "payable"
+	}
+	return ""
+}
+
+// params converts ABI arguments into Solidity parameters, using `loc` as the
+// data location of reference types.
+func (c *solContract) params(args abi.Arguments, loc string) []solParam {
+	ps := make([]solParam, len(args))
+	for i, a := range args {
+		ps[i] = solParam{
+			Type:    c.solType(a.Type),
+			Name:    a.Name,
+			Indexed: a.Indexed,
+		}
+		switch a.Type.T {
+		case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+			ps[i].Location = loc
+		}
+	}
+	return ps
+}
+
+// solType returns the Solidity type name of `t`, declaring structs as
+// necessary.
+func (c *solContract) solType(t abi.Type) string {
+	switch t.T {
+	case abi.SliceTy:
+		return c.solType(*t.Elem) + "[]"
+	case abi.ArrayTy:
+		return fmt.Sprintf("%s[%d]", c.solType(*t.Elem), t.Size)
+	case abi.TupleTy:
+		return c.declareStruct(t)
+	default:
+		return t.String()
+	}
+}
+
+func (c *solContract) declareStruct(t abi.Type) string {
+	// Structs declared in a Solidity interface are named by the compiler as
+	// the concatenation of the interface and struct names, in which case the
+	// interface name is redundant.
+	s := solStruct{Name: t.TupleRawName}
+	if n := strings.TrimPrefix(s.Name, "I"+c.Name); n != "" {
+		s.Name = n
+	}
+	for i, elem := range t.TupleElems {
+		s.Fields = append(s.Fields, solParam{
+			Type: c.solType(*elem),
+			Name: t.TupleRawNames[i],
+		})
+	}
+	for _, d := range c.Structs {
+		if d.Name == s.Name || (s.Name == "" && slices.Equal(d.Fields, s.Fields)) {
+			return d.Name
+		}
+	}
+	if s.Name == "" {
+		s.Name = fmt.Sprintf("Tuple%d", len(c.Structs))
+	}
+	c.Structs = append(c.Structs, s)
+	return s.Name
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package precompilegen
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestSolidity(t *testing.T) {
+	addr := common.HexToAddress("0x0100000000000000000000000000000000000abc")
+	stub := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			addr: vm.WithABI(vm.NewStatefulPrecompile(nil), "OrderBook", []byte(testABI)),
+		},
+		ActivePrecompilesFn: func(active []common.Address) []common.Address {
+			return append(active, addr)
+		},
+	}
+	stub.Register(t)
+	rules := new(params.ChainConfig).Rules(big.NewInt(0), false, 0)
+
+	files, err := Solidity(rules, SolidityConfig{})
+	require.NoError(t, err, "Solidity()")
+
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"IOrderBook.sol", "OrderBookMock.sol"}, names, "file names")
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{
+			file: "IOrderBook.sol",
+			want: []string{
+				"// SPDX-License-Identifier: UNLICENSED",
+				"pragma solidity ^0.8.0;",
+				"address constant OrderBookAddress = " + addr.Hex() + ";",
+				"interface IOrderBook {",
+				"struct Order {\n        address who;\n        uint256 amount;\n    }",
+				"event Placed(address indexed who, uint256 amount);",
+				"function cancel(uint8 chan) external;",
+				"function count() external view returns (uint256 count);",
+				"function orders() external view returns (Order[] memory);",
+				"function place(address to, uint256 amount) external payable returns (bool);",
+			},
+		},
+		{
+			file: "OrderBookMock.sol",
+			want: []string{
+				`import "./IOrderBook.sol";`,
+				"contract OrderBookMock is IOrderBook {",
+				"function cancel(uint8) external virtual override {}",
+				"function orders() external view virtual override returns (Order[] memory) {}",
+				"function place(address, uint256) external payable virtual override returns (bool) {}",
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			src := string(files[i].Source)
+			for _, w := range tt.want {
+				assert.Containsf(t, src, w, "generated %s", tt.file)
+			}
+		})
+	}
+
+	t.Run("duplicate name", func(t *testing.T) {
+		other := common.HexToAddress("0x0100000000000000000000000000000000000def")
+		stub.PrecompileOverrides[other] = stub.PrecompileOverrides[addr]
+		stub.ActivePrecompilesFn = func(active []common.Address) []common.Address {
+			return append(active, addr, other)
+		}
+		_, err := Solidity(rules, SolidityConfig{})
+		require.Error(t, err, "Solidity() with duplicate precompile names")
+	})
+}
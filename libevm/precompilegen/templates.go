@@ -0,0 +1,252 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package precompilegen
+
+import "text/template"
+
+var (
+	sourceTemplate = template.Must(template.New("source").Parse(sourceTmpl))
+	testTemplate   = template.Must(template.New("test").Parse(testTmpl))
+)
+
+const sourceTmpl = `// Code generated by precompilegen. This is a skeleton that is expected to be
+// edited, in particular the gas constants.
+
+package {{.Package}}
+
+import (
+	"errors"
+	"fmt"
+	{{- if .Imports.BigInt}}
+	"math/big"
+	{{- end}}
+	"strings"
+
+	"github.com/ava-labs/libevm/accounts/abi"
+	{{- if .Imports.Common}}
+	"github.com/ava-labs/libevm/common"
+	{{- end}}
+	"github.com/ava-labs/libevm/core/vm"
+)
+
+// {{.Type}}ABIJSON is the ABI from which the {{.Type}} precompile was generated.
+const {{.Type}}ABIJSON = ` + "`{{.ABI}}`" + `
+
+// {{.Type}}ABI is the parsed equivalent of [{{.Type}}ABIJSON].
+var {{.Type}}ABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader({{.Type}}ABIJSON))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+{{if .Methods -}}
+// Gas charged by the {{.Type}} precompile before dispatching to the respective
+// method of its [{{.Type}}Impl].
+//
+// TODO: set gas costs; zero is never appropriate.
+const (
+{{- range .Methods}}
+	{{$.Type}}{{.GoName}}Gas uint64 = 0 // {{.Sig}}
+{{- end}}
+)
+{{- end}}
+
+// Errors returned by the {{.Type}} precompile for invalid calls.
+var (
+	Err{{.Type}}UnknownSelector = errors.New("unknown function selector")
+	Err{{.Type}}NonPayable      = errors.New("non-payable method called with value")
+)
+
+// {{.Type}}Impl implements the methods of the {{.Type}} precompile, with
+// arguments already decoded and gas already charged. Returning an error
+// reverts the call.
+type {{.Type}}Impl interface {
+{{- range .Methods}}
+	// {{.GoName}} implements {{.Sig}}.
+	{{.GoName}}(env vm.PrecompileEnvironment{{range .Inputs}}, {{.Name}} {{.Type}}{{end}}) ({{range .Outputs}}{{.Name}} {{.Type}}, {{end}}err error)
+{{- end}}
+}
+
+// New{{.Type}} returns a stateful precompile that decodes calls according to
+// [{{.Type}}ABI] and dispatches them to the respective method of ` + "`impl`" + `.
+// The precompile declares its ABI, allowing generation of Solidity bindings.
+func New{{.Type}}(impl {{.Type}}Impl) vm.PrecompiledContract {
+	p := vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+		return run{{.Type}}(impl, env, input)
+	})
+	return vm.WithABI(p, {{printf "%q" .Type}}, []byte({{.Type}}ABIJSON))
+}
+
+func run{{.Type}}(impl {{.Type}}Impl, env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+	if len(input) < 4 {
+		return nil, fmt.Errorf("%w: input too short", Err{{.Type}}UnknownSelector)
+	}
+	method, err := {{.Type}}ABI.MethodById(input[:4])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", Err{{.Type}}UnknownSelector, err)
+	}
+
+	switch method.Name {
+{{- range .Methods}}
+	case {{printf "%q" .ABIName}}:
+		if !env.UseGas({{$.Type}}{{.GoName}}Gas) {
+			return nil, vm.ErrOutOfGas
+		}
+		{{- if not .View}}
+		if env.ReadOnly() {
+			return nil, vm.ErrWriteProtection
+		}
+		{{- end}}
+		{{- if not .Payable}}
+		if !env.Value().IsZero() {
+			return nil, Err{{$.Type}}NonPayable
+		}
+		{{- end}}
+		{{- if .Inputs}}
+		args, err := method.Inputs.Unpack(input[4:])
+		if err != nil {
+			return nil, fmt.Errorf("unpacking arguments of %s: %v", method.Sig, err)
+		}
+		{{- range $i, $in := .Inputs}}
+		{{$in.Name}} := *abi.ConvertType(args[{{$i}}], new({{$in.Type}})).(*{{$in.Type}})
+		{{- end}}
+		{{- end}}
+		{{if .Outputs}}{{range .Outputs}}{{.Name}}, {{end}}err := {{else}}err = {{end}}impl.{{.GoName}}(env{{range .Inputs}}, {{.Name}}{{end}})
+		if err != nil {
+			return nil, err
+		}
+		return method.Outputs.Pack({{range $i, $o := .Outputs}}{{if $i}}, {{end}}{{$o.Name}}{{end}})
+{{- end}}
+	}
+	return nil, fmt.Errorf("%w: %s", Err{{.Type}}UnknownSelector, method.Sig)
+}
+{{range .Events}}
+// Emit{{$.Type}}{{.GoName}} emits the {{.Sig}} event as a log of the
+// precompile's address. It returns [vm.ErrWriteProtection] if the precompile
+// was called in a read-only context.
+func Emit{{$.Type}}{{.GoName}}(env vm.PrecompileEnvironment{{range .Inputs}}, {{.Name}} {{.Type}}{{end}}) error {
+	return env.EmitEvent({{$.Type}}ABI.Events[{{printf "%q" .ABIName}}]{{range .Inputs}}, {{.Name}}{{end}})
+}
+{{end}}`
+
+const testTmpl = `// Code generated by precompilegen. This is a skeleton that is expected to be
+// edited.
+
+package {{.Package}}
+
+import (
+	{{- if .Methods}}
+	"errors"
+	{{- end}}
+	{{- if .TestImports.BigInt}}
+	"math/big"
+	{{- end}}
+	"testing"
+{{- if .Methods}}
+{{if .TestImports.Common}}
+	"github.com/ava-labs/libevm/common"
+{{- end}}
+	"github.com/ava-labs/libevm/core/vm"
+{{- end}}
+)
+
+// stub{{.Type}} is a [{{.Type}}Impl] that delegates each method to the
+// respective function field, which MAY be nil, in which case the method returns
+// an error.
+type stub{{.Type}} struct {
+{{- range .Methods}}
+	{{.GoName}}Fn func(vm.PrecompileEnvironment{{range .Inputs}}, {{.Type}}{{end}}) ({{range .Outputs}}{{.Type}}, {{end}}error)
+{{- end}}
+}
+
+var _ {{.Type}}Impl = (*stub{{.Type}})(nil)
+{{range .Methods}}
+func (s *stub{{$.Type}}) {{.GoName}}(env vm.PrecompileEnvironment{{range .Inputs}}, {{.Name}} {{.Type}}{{end}}) ({{range .Outputs}}{{.Name}} {{.Type}}, {{end}}err error) {
+	if s.{{.GoName}}Fn == nil {
+		err = errors.New("{{.GoName}}Fn not set")
+		return
+	}
+	return s.{{.GoName}}Fn(env{{range .Inputs}}, {{.Name}}{{end}})
+}
+{{end}}
+func Test{{.Type}}(t *testing.T) {
+{{- range .Methods}}
+	t.Run({{printf "%q" .Sig}}, func(t *testing.T) {
+		t.Skip("TODO")
+	})
+{{- end}}
+}
+`
+
+var (
+	solInterfaceTemplate = template.Must(template.New("interface").Parse(solInterfaceTmpl))
+	solMockTemplate      = template.Must(template.New("mock").Parse(solMockTmpl))
+)
+
+const solInterfaceTmpl = `// SPDX-License-Identifier: {{.License}}
+// Code generated by precompilegen from the ABI declared by the Go
+// implementation of the {{.Name}} precompile. DO NOT EDIT.
+pragma solidity {{.Pragma}};
+
+address constant {{.Name}}Address = {{.Address}};
+
+interface I{{.Name}} {
+{{- range .Structs}}
+    struct {{.Name}} {
+    {{- range .Fields}}
+        {{.Type}} {{.Name}};
+    {{- end}}
+    }
+{{end}}
+{{- range .Events}}
+    event {{.Name}}(
+    {{- range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Type}}{{if $p.Indexed}} indexed{{end}}{{with $p.Name}} {{.}}{{end}}{{end -}}
+    ){{if .Anonymous}} anonymous{{end}};
+{{- end}}
+{{range .Functions}}
+    function {{.Name}}(
+    {{- range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Type}}{{with $p.Location}} {{.}}{{end}}{{with $p.Name}} {{.}}{{end}}{{end -}}
+    ) external{{with .Mutability}} {{.}}{{end}}
+    {{- if .Returns}} returns (
+    {{- range $i, $p := .Returns}}{{if $i}}, {{end}}{{$p.Type}}{{with $p.Location}} {{.}}{{end}}{{with $p.Name}} {{.}}{{end}}{{end -}}
+    ){{end}};
+{{- end}}
+}
+`
+
+const solMockTmpl = `// SPDX-License-Identifier: {{.License}}
+// Code generated by precompilegen from the ABI declared by the Go
+// implementation of the {{.Name}} precompile. DO NOT EDIT.
+pragma solidity {{.Pragma}};
+
+import "./I{{.Name}}.sol";
+
+// {{.Name}}Mock implements I{{.Name}} with no-op functions that return zero
+// values. Functions are virtual so tests can override specific behaviour.
+contract {{.Name}}Mock is I{{.Name}} {
+{{- range .Functions}}
+    function {{.Name}}(
+    {{- range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Type}}{{with $p.Location}} {{.}}{{end}}{{end -}}
+    ) external{{with .Mutability}} {{.}}{{end}} virtual override
+    {{- if .Returns}} returns (
+    {{- range $i, $p := .Returns}}{{if $i}}, {{end}}{{$p.Type}}{{with $p.Location}} {{.}}{{end}}{{end -}}
+    ){{end}} {}
+{{- end}}
+}
+`
@@ -0,0 +1,243 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package precompilerun executes a single precompile call against an in-memory
+// state, for debugging without running a node.
+//
+// Only precompiles registered in the running binary are available. Users of
+// libevm SHOULD therefore build their own command that imports the packages
+// registering their precompiles (and any other extras) before calling [Main].
+package precompilerun
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/common/hexutil"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A Fixture is the pre-state and environment against which a precompile is
+// run. All fields are optional.
+type Fixture struct {
+	Alloc types.GenesisAlloc `json:"alloc"`
+	// Config defaults to [params.MergedTestChainConfig].
+	Config      *params.ChainConfig `json:"config"`
+	BlockNumber uint64              `json:"number"`
+	Time        uint64              `json:"timestamp"`
+	Coinbase    common.Address      `json:"coinbase"`
+}
+
+// A Call describes the precompile invocation.
+type Call struct {
+	Caller  common.Address
+	Address common.Address
+	Input   []byte
+	Gas     uint64
+	// Value is ignored if Static is true, otherwise nil is treated as zero.
+	Value  *uint256.Int
+	Static bool
+}
+
+// A Result is the outcome of [Run]. An error returned by the precompile is
+// recorded in Result.Error, not returned by [Run].
+type Result struct {
+	Output  hexutil.Bytes   `json:"output"`
+	GasUsed uint64          `json:"gasUsed"`
+	Error   string          `json:"error,omitempty"`
+	Logs    []*types.Log    `json:"logs"`
+	Diff    state.StateDiff `json:"stateDiff"`
+}
+
+// ErrNotPrecompile is returned by [Run] if the called address isn't an active
+// precompile under the [Fixture] configuration.
+var ErrNotPrecompile = errors.New("not an active precompile")
+
+// Run executes the [Call] against a fresh, in-memory state populated from the
+// [Fixture].
+func Run(f *Fixture, c *Call) (*Result, error) {
+	if f == nil {
+		f = new(Fixture)
+	}
+	cfg := f.Config
+	if cfg == nil {
+		cfg = params.MergedTestChainConfig
+	}
+	num := new(big.Int).SetUint64(f.BlockNumber)
+	rules := cfg.Rules(num, true /*isMerge*/, f.Time)
+	if _, ok := vm.PrecompileAt(rules, c.Address); !ok {
+		return nil, fmt.Errorf("%w: %v", ErrNotPrecompile, c.Address)
+	}
+
+	sdb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	if err != nil {
+		return nil, err
+	}
+	for addr, acc := range f.Alloc {
+		if acc.Balance != nil {
+			sdb.SetBalance(addr, uint256.MustFromBig(acc.Balance))
+		}
+		sdb.SetNonce(addr, acc.Nonce)
+		sdb.SetCode(addr, acc.Code)
+		for k, v := range acc.Storage {
+			sdb.SetState(addr, k, v)
+		}
+	}
+	// Committing ensures that the diff only reflects the call and that reads
+	// of original values, e.g. for gas accounting, behave as on a live chain.
+	// Empty accounts aren't deleted as precompiles typically have storage but
+	// no nonce, balance, nor code.
+	root, err := sdb.Commit(f.BlockNumber, false)
+	if err != nil {
+		return nil, err
+	}
+	sdb, err = state.New(root, sdb.Database(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sdb.Prepare(rules, c.Caller, f.Coinbase, &c.Address, vm.ActivePrecompiles(rules), nil)
+	snap := sdb.Snapshot()
+
+	evm := vm.NewEVM(
+		vm.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			GetHash:     func(uint64) common.Hash { return common.Hash{} },
+			Coinbase:    f.Coinbase,
+			BlockNumber: num,
+			Time:        f.Time,
+			Difficulty:  new(big.Int),
+			BaseFee:     new(big.Int),
+			Random:      &common.Hash{},
+		},
+		vm.TxContext{Origin: c.Caller},
+		sdb, cfg, vm.Config{},
+	)
+	defer evm.Finish()
+
+	var (
+		ret     []byte
+		gasLeft uint64
+		errCall error
+		caller  = vm.AccountRef(c.Caller)
+	)
+	if c.Static {
+		ret, gasLeft, errCall = evm.StaticCall(caller, c.Address, c.Input, c.Gas)
+	} else {
+		value := c.Value
+		if value == nil {
+			value = new(uint256.Int)
+		}
+		ret, gasLeft, errCall = evm.Call(caller, c.Address, c.Input, c.Gas, value)
+	}
+
+	diff, err := sdb.DiffSinceSnapshot(snap)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{
+		Output:  ret,
+		GasUsed: c.Gas - gasLeft,
+		Logs:    sdb.Logs(),
+		Diff:    diff,
+	}
+	if errCall != nil {
+		res.Error = errCall.Error()
+	}
+	return res, nil
+}
+
+// Main parses command-line flags, calls [Run], and prints the [Result] as JSON
+// to stdout. It exits the process with a non-zero status on error.
+func Main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("precompilerun", flag.ContinueOnError)
+	var (
+		fixture = fs.String("fixture", "", "Path to JSON Fixture; optional")
+		addr    = fs.String("precompile", "", "Address of the precompile to call")
+		input   = fs.String("input", "0x", "Hex-encoded call data")
+		caller  = fs.String("caller", "0x0000000000000000000000000000000000000000", "Address of the caller")
+		gas     = fs.Uint64("gas", 10_000_000, "Gas limit of the call")
+		value   = fs.String("value", "0", "Value, in wei, sent with the call; decimal or 0x-prefixed hex")
+		static  = fs.Bool("static", false, "Use STATICCALL semantics")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	f := new(Fixture)
+	if *fixture != "" {
+		buf, err := os.ReadFile(*fixture)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(buf, f); err != nil {
+			return fmt.Errorf("json.Unmarshal(%q, %T): %v", *fixture, f, err)
+		}
+	}
+
+	if !common.IsHexAddress(*addr) {
+		return fmt.Errorf("invalid -precompile address %q", *addr)
+	}
+	if !common.IsHexAddress(*caller) {
+		return fmt.Errorf("invalid -caller address %q", *caller)
+	}
+	in, err := hexutil.Decode(*input)
+	if err != nil {
+		return fmt.Errorf("decoding -input: %v", err)
+	}
+	val, err := uint256.FromDecimal(*value)
+	if err != nil {
+		if val, err = uint256.FromHex(*value); err != nil {
+			return fmt.Errorf("parsing -value %q: %v", *value, err)
+		}
+	}
+
+	res, err := Run(f, &Call{
+		Caller:  common.HexToAddress(*caller),
+		Address: common.HexToAddress(*addr),
+		Input:   in,
+		Gas:     *gas,
+		Value:   val,
+		Static:  *static,
+	})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
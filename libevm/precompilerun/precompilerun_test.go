@@ -0,0 +1,158 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package precompilerun
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestRun(t *testing.T) {
+	rng := ethtest.NewPseudoRand(731)
+	var (
+		precompile = rng.Address()
+		caller     = rng.Address()
+		slot       = rng.Hash()
+		before     = rng.Hash()
+		after      = rng.Hash()
+		topic      = rng.Hash()
+	)
+	const gasCost = 1000
+
+	stub := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			precompile: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				if !env.UseGas(gasCost) {
+					return nil, vm.ErrOutOfGas
+				}
+				self := env.Addresses().EVMSemantic.Self
+				prev := env.ReadOnlyState().GetState(self, slot)
+				sdb := env.StateDB()
+				if sdb == nil {
+					return prev.Bytes(), nil
+				}
+				sdb.SetState(self, slot, after)
+				sdb.AddLog(&types.Log{
+					Address: self,
+					Topics:  []common.Hash{topic},
+					Data:    input,
+				})
+				return prev.Bytes(), nil
+			}),
+		},
+	}
+	stub.Register(t)
+
+	fixture := &Fixture{
+		Alloc: types.GenesisAlloc{
+			precompile: {
+				Balance: big.NewInt(0),
+				Storage: map[common.Hash]common.Hash{slot: before},
+			},
+		},
+	}
+	input := []byte("hello")
+
+	t.Run("call", func(t *testing.T) {
+		got, err := Run(fixture, &Call{
+			Caller:  caller,
+			Address: precompile,
+			Input:   input,
+			Gas:     1e6,
+		})
+		require.NoError(t, err, "Run()")
+
+		assert.Equal(t, before.Bytes(), []byte(got.Output), "output")
+		assert.Equal(t, uint64(gasCost), got.GasUsed, "gas used")
+		assert.Empty(t, got.Error, "error")
+		require.Len(t, got.Logs, 1, "logs")
+		assert.Equal(t, []common.Hash{topic}, got.Logs[0].Topics, "log topics")
+		assert.Equal(t, input, got.Logs[0].Data, "log data")
+		assert.Equal(t, state.StateDiff{
+			precompile: {
+				Storage: map[common.Hash]state.Change[common.Hash]{
+					slot: {Before: before, After: after},
+				},
+			},
+		}, got.Diff, "state diff")
+	})
+
+	t.Run("static", func(t *testing.T) {
+		got, err := Run(fixture, &Call{
+			Caller:  caller,
+			Address: precompile,
+			Gas:     1e6,
+			Static:  true,
+		})
+		require.NoError(t, err, "Run()")
+		assert.Equal(t, before.Bytes(), []byte(got.Output), "output")
+		assert.Empty(t, got.Logs, "logs")
+		assert.Empty(t, got.Diff, "state diff")
+	})
+
+	t.Run("out of gas", func(t *testing.T) {
+		got, err := Run(fixture, &Call{
+			Caller:  caller,
+			Address: precompile,
+			Gas:     gasCost - 1,
+			Value:   uint256.NewInt(0),
+		})
+		require.NoError(t, err, "Run()")
+		assert.Equal(t, vm.ErrOutOfGas.Error(), got.Error, "error")
+		assert.Empty(t, got.Diff, "state diff")
+	})
+
+	t.Run("not precompile", func(t *testing.T) {
+		_, err := Run(fixture, &Call{Address: rng.Address()})
+		require.ErrorIs(t, err, ErrNotPrecompile)
+	})
+}
+
+func TestFlags(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+	require.NoError(t, os.WriteFile(fixture, []byte(`{"alloc":{}}`), 0o600))
+
+	var out bytes.Buffer
+	err := run([]string{
+		"-fixture", fixture,
+		"-precompile", common.BytesToAddress([]byte{4}).Hex(), // identity
+		"-input", "0xdecafbad",
+		"-value", "0x0",
+	}, &out)
+	require.NoError(t, err, "run()")
+
+	var got Result
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got), "json.Unmarshal([output], %T)", &got)
+	assert.Equal(t, []byte{0xde, 0xca, 0xfb, 0xad}, []byte(got.Output), "identity precompile output")
+	assert.NotZero(t, got.GasUsed, "gas used")
+}
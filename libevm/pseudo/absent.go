@@ -0,0 +1,67 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package pseudo
+
+// IsAbsent reports whether t carries no payload at all, which is distinct
+// from carrying a nil or otherwise zero value. This is the case for a nil
+// *Type and for the zero value of a Type; e.g. if the object holding it was
+// created by a code path that doesn't set extras.
+//
+// Absent payloads are treated as zero by [Type.IsZero], [Type.Interface], and
+// [GetOrZero], but [NewValue] will return an error as the payload's type is
+// unknown.
+func (t *Type) IsAbsent() bool {
+	return t == nil || t.val == nil
+}
+
+// Default returns a Pseudo[T] carrying the default value of type `T`. This is
+// the same as [Zero] unless `T` is a pointer type, in which case the payload
+// is a non-nil pointer to the zero value of the pointed-to type.
+func Default[T any]() *Pseudo[T] {
+	return From(defaultValue[T]())
+}
+
+// GetOrZero returns the payload carried by `t`, or the zero value of `T` if
+// the payload is absent. An error is returned i.f.f. a payload is present but
+// not of type `T`.
+func GetOrZero[T any](t *Type) (T, error) {
+	var zero T
+	if t.IsAbsent() {
+		return zero, nil
+	}
+	v, err := NewValue[T](t)
+	if err != nil {
+		return zero, err
+	}
+	return v.Get(), nil
+}
+
+// MustGetOrZero is equivalent to [GetOrZero] except that it panics instead of
+// returning an error.
+func MustGetOrZero[T any](t *Type) T {
+	v, err := GetOrZero[T](t)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// MustGetOrZero is equivalent to [Accessor.Get] except that it returns the zero
+// value of `T` if the Container's payload is absent, instead of panicking.
+func (a Accessor[C, T]) MustGetOrZero(from C) T {
+	return MustGetOrZero[T](a.get(from))
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package pseudo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAbsent(t *testing.T) {
+	for name, typ := range map[string]*Type{
+		"nil":        nil,
+		"zero value": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, typ.IsAbsent(), "IsAbsent()")
+			assert.True(t, typ.IsZero(), "IsZero()")
+			assert.Nil(t, typ.Interface(), "Interface()")
+			assert.True(t, typ.Equal(nil), "Equal(nil)")
+			assert.True(t, typ.Equal(&Type{}), "Equal(&Type{})")
+			assert.False(t, typ.Equal(Zero[int]().Type), "Equal([present])")
+			assert.False(t, Zero[int]().Type.Equal(typ), "[present].Equal()")
+
+			buf, err := json.Marshal(typ)
+			require.NoError(t, err, "json.Marshal()")
+			assert.Equal(t, "null", string(buf), "json.Marshal()")
+
+			_, err = NewValue[int](typ)
+			assert.Error(t, err, "NewValue()")
+
+			got, err := GetOrZero[*int](typ)
+			require.NoError(t, err, "GetOrZero()")
+			assert.Nil(t, got, "GetOrZero()")
+			assert.Equal(t, "", MustGetOrZero[string](typ), "MustGetOrZero()")
+		})
+	}
+
+	present := From(42).Type
+	assert.False(t, present.IsAbsent(), "IsAbsent() with payload")
+	assert.Equal(t, 42, MustGetOrZero[int](present), "MustGetOrZero() with payload")
+	_, err := GetOrZero[string](present)
+	assert.Error(t, err, "GetOrZero() with incorrect type")
+	assert.Panics(t, func() { MustGetOrZero[string](present) }, "MustGetOrZero() with incorrect type")
+}
+
+func TestAccessorMustGetOrZero(t *testing.T) {
+	type container struct{ t *Type }
+	acc := NewAccessor[*container, *float64](
+		func(c *container) *Type { return c.t },
+		func(c *container, t *Type) { c.t = t },
+	)
+
+	c := new(container)
+	assert.Nil(t, acc.MustGetOrZero(c), "MustGetOrZero() with absent payload")
+	assert.Panics(t, func() { acc.Get(c) }, "Get() with absent payload")
+
+	x := 3.14
+	acc.Set(c, &x)
+	assert.Equal(t, &x, acc.MustGetOrZero(c), "MustGetOrZero() after Set()")
+}
+
+func TestDefault(t *testing.T) {
+	assert.Equal(t, 0, Default[int]().Value.Get(), "Default[int]()")
+	assert.Equal(t, "", Default[string]().Value.Get(), "Default[string]()")
+
+	ptr := Default[*struct{ X int }]().Value.Get()
+	require.NotNil(t, ptr, "Default[*struct{...}]()")
+	assert.Zero(t, ptr.X, "Default[*struct{...}]() payload")
+
+	assert.Nil(t, Default[[]byte]().Value.Get(), "Default[[]byte]()")
+}
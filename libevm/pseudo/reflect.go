@@ -1,4 +1,4 @@
-// Copyright 2024 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -58,3 +58,13 @@ func (c *concrete[T]) DecodeRLP(s *rlp.Stream) error {
 		return s.Decode(&c.val)
 	}
 }
+
+// defaultValue returns the zero value of `T` unless it is a pointer, in which
+// case it returns a pointer to a new zero value of the pointed-to type.
+func defaultValue[T any]() T {
+	var x T
+	if typ := reflect.TypeOf(&x).Elem(); typ.Kind() == reflect.Pointer {
+		return reflect.New(typ.Elem()).Interface().(T) //nolint:forcetypeassert // invariant of reflect.New
+	}
+	return x
+}
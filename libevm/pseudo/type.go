@@ -1,4 +1,4 @@
-// Copyright 2024 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -102,12 +102,20 @@ func MustPointerTo[T any](t *Type) *Pseudo[*T] {
 
 // Interface returns the wrapped value as an `any`, equivalent to
 // [reflect.Value.Interface]. Prefer [Value.Get].
-func (t *Type) Interface() any { return t.val.get() }
+func (t *Type) Interface() any {
+	if t.IsAbsent() {
+		return nil
+	}
+	return t.val.get()
+}
 
 // NewValue constructs a [Value] from a [Type], first confirming that `t` wraps
 // a payload of type `T`.
 func NewValue[T any](t *Type) (*Value[T], error) {
 	var x T
+	if t.IsAbsent() {
+		return nil, fmt.Errorf("cannot create *Value[%T] with absent *Type", x)
+	}
 	if !t.val.canSetTo(x) {
 		return nil, fmt.Errorf("cannot create *Value[%T] with *Type carrying %T", x, t.val.get())
 	}
@@ -124,8 +132,9 @@ func MustNewValue[T any](t *Type) *Value[T] {
 	return v
 }
 
-// IsZero reports whether t carries the the zero value for its type.
-func (t *Type) IsZero() bool { return t.val.isZero() }
+// IsZero reports whether t carries the the zero value for its type. An absent
+// payload is considered to be zero.
+func (t *Type) IsZero() bool { return t.IsAbsent() || t.val.isZero() }
 
 // An EqualityChecker reports if it is equal to another value of the same type.
 type EqualityChecker[T any] interface {
@@ -136,8 +145,14 @@ type EqualityChecker[T any] interface {
 // u carry different types then Equal returns false. If t and u carry the same
 // type and said type implements [EqualityChecker] then Equal propagates the
 // value returned by the checker. In all other cases, Equal returns
-// [reflect.DeepEqual] performed on the payloads carried by t and u.
-func (t *Type) Equal(u *Type) bool { return t.val.equal(u) }
+// [reflect.DeepEqual] performed on the payloads carried by t and u. Absent
+// payloads are only equal to each other.
+func (t *Type) Equal(u *Type) bool {
+	if t.IsAbsent() || u.IsAbsent() {
+		return t.IsAbsent() && u.IsAbsent()
+	}
+	return t.val.equal(u)
+}
 
 // Get returns the value.
 func (v *Value[T]) Get() T { return v.t.val.get().(T) } //nolint:forcetypeassert // invariant
@@ -145,8 +160,14 @@ func (v *Value[T]) Get() T { return v.t.val.get().(T) } //nolint:forcetypeassert
 // Set sets the value.
 func (v *Value[T]) Set(val T) { v.t.val.mustSet(val) }
 
-// MarshalJSON implements the [json.Marshaler] interface.
-func (t *Type) MarshalJSON() ([]byte, error) { return t.val.MarshalJSON() }
+// MarshalJSON implements the [json.Marshaler] interface. An absent payload is
+// encoded as JSON null.
+func (t *Type) MarshalJSON() ([]byte, error) {
+	if t.IsAbsent() {
+		return []byte("null"), nil
+	}
+	return t.val.MarshalJSON()
+}
 
 // UnmarshalJSON implements the [json.Unmarshaler] interface.
 func (t *Type) UnmarshalJSON(b []byte) error { return t.val.UnmarshalJSON(b) }
@@ -0,0 +1,126 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package ratelimit provides a storage-backed, token-bucket rate limiter for
+// stateful precompiles; e.g. to cap minting or bridge throughput.
+//
+// A bucket holds up to a fixed capacity of tokens and is refilled by a fixed
+// number of tokens per block. Its state is stored in the persistent storage of
+// the precompile and all writes are made via the [vm.StateDB] so are reverted
+// along with the precompile call or any surrounding call.
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+)
+
+var slotPreimagePrefix = []byte("libevm-rate-limit-")
+
+// ErrRateLimited is returned by [Limiter.Consume] if there are insufficient
+// tokens available.
+var ErrRateLimited = errors.New("rate limited")
+
+// A Limiter is a token-bucket rate limiter. A bucket that has never been
+// consumed from is full.
+//
+// The bucket's state is stored in two slots of the persistent storage of the
+// precompile's Addresses().EVMSemantic.Self, at locations derived from the
+// Key. Neither method consumes gas; callers SHOULD charge for two storage reads
+// and, in the case of [Limiter.Consume], two writes.
+type Limiter struct {
+	// Capacity is the maximum number of tokens held by the bucket.
+	Capacity *uint256.Int
+	// PerBlock is the number of tokens added to the bucket for every block
+	// since it was last consumed from, up to the Capacity.
+	PerBlock *uint256.Int
+	// Key differentiates between multiple limiters used by the same contract.
+	// It MAY be nil.
+	Key []byte
+}
+
+// slots returns the storage slots holding the number of tokens in the bucket
+// when it was last consumed from, and the block number at which that occurred
+// plus one. The latter therefore being zero denotes a full bucket.
+func (l *Limiter) slots() (tokens, block common.Hash) {
+	tokens = crypto.Keccak256Hash(slotPreimagePrefix, l.Key)
+	var b uint256.Int
+	b.SetBytes(tokens[:])
+	b.AddUint64(&b, 1)
+	return tokens, b.Bytes32()
+}
+
+// Available returns the number of tokens currently available for consumption.
+// It is safe to call if the environment is read-only.
+func (l *Limiter) Available(env vm.PrecompileEnvironment) *uint256.Int {
+	tokensSlot, blockSlot := l.slots()
+	self := env.Addresses().EVMSemantic.Self
+	sr := env.ReadOnlyState()
+
+	last := new(uint256.Int).SetBytes32(sr.GetState(self, blockSlot).Bytes())
+	if last.IsZero() {
+		return new(uint256.Int).Set(l.Capacity)
+	}
+	last.SubUint64(last, 1)
+
+	tokens := new(uint256.Int).SetBytes32(sr.GetState(self, tokensSlot).Bytes())
+	now, overflow := uint256.FromBig(env.BlockNumber())
+	if overflow || now.Lt(last) {
+		return tokens
+	}
+
+	elapsed := new(uint256.Int).Sub(now, last)
+	refill, overflow := new(uint256.Int).MulOverflow(elapsed, l.PerBlock)
+	if overflow {
+		return new(uint256.Int).Set(l.Capacity)
+	}
+	if _, overflow := tokens.AddOverflow(tokens, refill); overflow || tokens.Gt(l.Capacity) {
+		return new(uint256.Int).Set(l.Capacity)
+	}
+	return tokens
+}
+
+// Consume removes `amount` tokens from the bucket, returning [ErrRateLimited]
+// if fewer are [Limiter.Available]. It returns [vm.ErrWriteProtection] if the
+// environment is read-only.
+func (l *Limiter) Consume(env vm.PrecompileEnvironment, amount *uint256.Int) error {
+	if env.ReadOnly() {
+		return vm.ErrWriteProtection
+	}
+	avail := l.Available(env)
+	if amount.Gt(avail) {
+		return fmt.Errorf("%w: %v requested; %v available", ErrRateLimited, amount, avail)
+	}
+
+	now, overflow := uint256.FromBig(env.BlockNumber())
+	if overflow {
+		return fmt.Errorf("block number %v overflows 256 bits", env.BlockNumber())
+	}
+	now.AddUint64(now, 1) // see [Limiter.slots]
+
+	tokensSlot, blockSlot := l.slots()
+	self := env.Addresses().EVMSemantic.Self
+	sdb := env.StateDB()
+	sdb.SetState(self, tokensSlot, avail.Sub(avail, amount).Bytes32())
+	sdb.SetState(self, blockSlot, now.Bytes32())
+	return nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package ratelimit
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/ethtest"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+)
+
+func TestLimiter(t *testing.T) {
+	limiters := map[byte]*Limiter{
+		0: {
+			Capacity: uint256.NewInt(10),
+			PerBlock: uint256.NewInt(2),
+		},
+		1: {
+			Capacity: uint256.NewInt(10),
+			PerBlock: uint256.NewInt(2),
+			Key:      []byte{1},
+		},
+	}
+
+	// Input: [limiter key, revert after consumption?, amount...]
+	const revert = 1
+	sut := common.HexToAddress("1171")
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			sut: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				l := limiters[input[0]]
+				if err := l.Consume(env, new(uint256.Int).SetBytes(input[2:])); err != nil {
+					return nil, err
+				}
+				if input[1] == revert {
+					return nil, vm.ErrExecutionReverted
+				}
+				return l.Available(env).Bytes(), nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	_, evm := ethtest.NewZeroEVM(t)
+
+	// All tests run on the same [vm.EVM] so are dependent on the effects of
+	// the one(s) before.
+	tests := []struct {
+		block   int64
+		key     byte
+		revert  bool
+		amount  uint64
+		wantErr error
+		// Only checked if no error
+		wantAvailable uint64
+	}{
+		{block: 1, amount: 4, wantAvailable: 6},
+		{block: 1, amount: 7, wantErr: ErrRateLimited},
+		{block: 1, amount: 3, revert: true, wantErr: vm.ErrExecutionReverted},
+		{block: 1, key: 1, amount: 10, wantAvailable: 0},
+		{block: 2, amount: 8, wantAvailable: 0},
+		{block: 3, amount: 3, wantErr: ErrRateLimited},
+		{block: 3, key: 1, amount: 4, wantAvailable: 0},
+		{block: 100, amount: 10, wantAvailable: 0},
+		{block: 101, amount: 0, wantAvailable: 2},
+	}
+
+	for _, tt := range tests {
+		evm.Context.BlockNumber = big.NewInt(tt.block)
+
+		input := []byte{tt.key, 0}
+		if tt.revert {
+			input[1] = revert
+		}
+		input = append(input, uint256.NewInt(tt.amount).Bytes()...)
+
+		desc := fmt.Sprintf("Consume(%d) at block %d with key %d and revert = %t", tt.amount, tt.block, tt.key, tt.revert)
+		got, _, err := evm.Call(vm.AccountRef{}, sut, input, 1e6, uint256.NewInt(0))
+		// Tests are dependent so we use require instead of assert.
+		require.ErrorIsf(t, err, tt.wantErr, "%s", desc)
+		if tt.wantErr != nil {
+			continue
+		}
+		require.Equalf(t, tt.wantAvailable, new(uint256.Int).SetBytes(got).Uint64(), "%s; Available()", desc)
+	}
+}
+
+func TestConsumeReadOnly(t *testing.T) {
+	l := &Limiter{
+		Capacity: uint256.NewInt(1),
+		PerBlock: uint256.NewInt(1),
+	}
+
+	sut := common.HexToAddress("1171")
+	hooks := &hookstest.Stub{
+		PrecompileOverrides: map[common.Address]libevm.PrecompiledContract{
+			sut: vm.NewStatefulPrecompile(func(env vm.PrecompileEnvironment, input []byte) ([]byte, error) {
+				if err := l.Consume(env, uint256.NewInt(1)); err != nil {
+					return l.Available(env).Bytes(), err
+				}
+				return nil, nil
+			}),
+		},
+	}
+	hooks.Register(t)
+
+	_, evm := ethtest.NewZeroEVM(t)
+	got, _, err := evm.StaticCall(vm.AccountRef{}, sut, nil, 1e6)
+	assert.ErrorIs(t, err, vm.ErrWriteProtection, "Consume() via StaticCall()")
+	assert.Equal(t, uint64(1), new(uint256.Int).SetBytes(got).Uint64(), "Available() via StaticCall()")
+}
@@ -1,4 +1,4 @@
-// Copyright 2024-2025 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -15,14 +15,105 @@
 // <http://www.gnu.org/licenses/>.
 
 // Package register provides functionality for optional registration of types.
+//
+// All registration is expected to occur during program initialisation, after
+// which [Freeze] SHOULD be called; node.New() does so. Registered values are
+// then immutable, which allows hot paths to access them without atomic loads
+// or locks, and attempts at further registration fail with [ErrFrozen].
+// Registries that are otherwise guarded by a lock take an immutable snapshot
+// via [OnFreeze] for use by said hot paths.
 package register
 
 import (
 	"errors"
+	"sync"
+	"sync/atomic"
 
 	"github.com/ava-labs/libevm/libevm/testonly"
 )
 
+var (
+	mu       sync.Mutex
+	frozen   atomic.Bool
+	onFreeze []freezeFns
+)
+
+type freezeFns struct {
+	freeze, thaw func()
+}
+
+// ErrFrozen is returned (or panicked with) by all registration functions
+// called after [Freeze].
+var ErrFrozen = errors.New("registration after register.Freeze()")
+
+// Freeze ends the registration phase. It MUST be called before any goroutine
+// that accesses registered values is started, as said accesses are not
+// synchronised with Freeze. Functions passed to [OnFreeze] are called, in
+// order, before Freeze returns. Calls after the first are no-ops.
+func Freeze() {
+	mu.Lock()
+	defer mu.Unlock()
+	if frozen.Load() {
+		return
+	}
+	frozen.Store(true)
+	for _, fns := range onFreeze {
+		fns.freeze()
+	}
+}
+
+// Frozen reports whether [Freeze] has been called. Unlike registered values,
+// it is safe to call concurrently with Freeze.
+func Frozen() bool {
+	return frozen.Load()
+}
+
+// OnFreeze registers `freeze` to be called by [Freeze]; e.g. to take an
+// immutable snapshot of a registry for use by hot paths. If already frozen,
+// `freeze` is called immediately. The optional `thaw` function is called by
+// [TestOnlyUnfreeze] and SHOULD discard anything set up by `freeze`.
+func OnFreeze(freeze, thaw func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	if frozen.Load() {
+		freeze()
+	}
+	onFreeze = append(onFreeze, freezeFns{freeze, thaw})
+}
+
+// Guard returns [ErrFrozen] if [Freeze] has been called, otherwise it calls
+// `fn` and propagates its error. Calls to Guard are mutually exclusive with
+// each other and with Freeze, making it suitable for implementing other
+// registries.
+func Guard(fn func() error) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if frozen.Load() {
+		return ErrFrozen
+	}
+	return fn()
+}
+
+// TestOnlyUnfreeze reverses [Freeze] such that registration is possible again,
+// calling, in reverse order, all `thaw` functions passed to [OnFreeze]. The
+// functions remain registered for the next call to Freeze. It panics if called
+// from a non-testing call stack.
+func TestOnlyUnfreeze() {
+	testonly.OrPanic(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if !frozen.Load() {
+			return
+		}
+		frozen.Store(false)
+		for i := len(onFreeze) - 1; i >= 0; i-- {
+			if thaw := onFreeze[i].thaw; thaw != nil {
+				thaw()
+			}
+		}
+	})
+}
+
 // An AtMostOnce allows zero or one registration of a T.
 type AtMostOnce[T any] struct {
 	v *T
@@ -32,13 +123,16 @@ type AtMostOnce[T any] struct {
 // [AtMostOnce.Register].
 var ErrReRegistration = errors.New("re-registration")
 
-// Register registers `v` or returns [ErrReRegistration] if already called.
+// Register registers `v` or returns [ErrReRegistration] if already called. It
+// returns [ErrFrozen] if called after [Freeze].
 func (o *AtMostOnce[T]) Register(v T) error {
-	if o.Registered() {
-		return ErrReRegistration
-	}
-	o.v = &v
-	return nil
+	return Guard(func() error {
+		if o.Registered() {
+			return ErrReRegistration
+		}
+		o.v = &v
+		return nil
+	})
 }
 
 // MustRegister is equivalent to [AtMostOnce.Register], panicking on error.
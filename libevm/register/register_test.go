@@ -1,4 +1,4 @@
-// Copyright 2025 the libevm authors.
+// Copyright 2025-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -76,3 +76,42 @@ func TestAtMostOnce(t *testing.T) {
 		})
 	})
 }
+
+func TestFreeze(t *testing.T) {
+	t.Cleanup(TestOnlyUnfreeze)
+
+	var (
+		before, after AtMostOnce[int]
+		snapshots     []bool
+		thawed        int
+	)
+	require.NoError(t, before.Register(1), "Register() before Freeze()")
+	OnFreeze(func() { snapshots = append(snapshots, Frozen()) }, func() { thawed++ })
+
+	require.False(t, Frozen(), "Frozen() before Freeze()")
+	Freeze()
+	Freeze() // idempotent
+	require.True(t, Frozen(), "Frozen() after Freeze()")
+	assert.Equal(t, []bool{true}, snapshots, "OnFreeze() functions called exactly once, after freezing")
+
+	OnFreeze(func() { snapshots = append(snapshots, true) }, nil)
+	assert.Len(t, snapshots, 2, "OnFreeze() after Freeze() calls immediately")
+
+	assert.Equal(t, 1, before.Get(), "Get() after Freeze()")
+	assert.ErrorIs(t, after.Register(2), ErrFrozen, "Register() after Freeze()")
+	assert.False(t, after.Registered(), "Registered() after rejected registration")
+	assert.PanicsWithValue(t, ErrFrozen, func() { after.MustRegister(2) }, "MustRegister() after Freeze()")
+	assert.ErrorIs(t, Guard(func() error { return nil }), ErrFrozen, "Guard() after Freeze()")
+
+	var overridden int
+	before.TempOverride(3, func() { overridden = before.Get() })
+	assert.Equal(t, 3, overridden, "Get() within TempOverride() after Freeze()")
+	assert.Equal(t, 1, before.Get(), "Get() after TempOverride()")
+
+	TestOnlyUnfreeze()
+	assert.Equal(t, 1, thawed, "OnFreeze() thaw functions called by TestOnlyUnfreeze()")
+	assert.NoError(t, after.Register(2), "Register() after TestOnlyUnfreeze()")
+
+	Freeze()
+	assert.Len(t, snapshots, 4, "OnFreeze() functions called again by re-freezing")
+}
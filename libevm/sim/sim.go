@@ -0,0 +1,212 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package sim runs sequences of messages against a sandboxed copy of chain
+// state, with all registered libevm hooks and precompiles active. It is
+// intended as the building block for custom RPC simulation endpoints.
+package sim
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/crypto"
+	"github.com/ava-labs/libevm/libevm/migration"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A Backend provides the chain against which simulations are run. It is
+// implemented by [core.BlockChain].
+type Backend interface {
+	core.ChainContext
+	Config() *params.ChainConfig
+	// StateAt MUST return a StateDB that isn't shared with any other user, as
+	// it is modified by the simulation.
+	StateAt(root common.Hash) (*state.StateDB, error)
+}
+
+// A Tracer is a [vm.EVMLogger] that reports a result; e.g. those in the
+// eth/tracers packages.
+type Tracer interface {
+	vm.EVMLogger
+	GetResult() (json.RawMessage, error)
+}
+
+// A Request describes a simulation.
+type Request struct {
+	// Parent is the header of the block whose post-state is simulated upon.
+	Parent *types.Header
+	// Header, if non-nil, is called to modify the header of the simulated
+	// block before any messages are run. The header is otherwise a copy of
+	// Parent, with updated ParentHash and Number.
+	Header func(*types.Header)
+	// Overrides are applied to the copied state before anything else.
+	Overrides StateOverride
+	Messages  []*core.Message
+	// VMConfig is used for all messages. If NewTracer is non-nil then it
+	// replaces VMConfig.Tracer for each message.
+	VMConfig  vm.Config
+	NewTracer func(msgIndex int) (Tracer, error)
+}
+
+// A Result is the outcome of a simulation. Each slice has one element per
+// message; Traces is nil if no tracer was requested.
+type Result struct {
+	Header   *types.Header
+	Receipts types.Receipts
+	Results  []*core.ExecutionResult
+	Traces   []json.RawMessage
+	// Diff is the aggregate state change of the simulated block, excluding the
+	// Request Overrides.
+	Diff state.StateDiff
+}
+
+// Run runs the [Request] against a copy of the state at the Parent's root.
+// Neither the Backend's state nor any other persistent data are modified.
+//
+// The messages are run in order, as if they were included in a block built on
+// Parent, with any due [migration] run first. An error is returned if any
+// message is invalid (e.g. has an incorrect nonce), but not if it fails during
+// execution, which is instead reported by its receipt and result.
+//
+// As messages have no transaction hashes, those in receipts and logs are
+// placeholders derived from the block number and message index.
+func Run(b Backend, req *Request) (*Result, error) {
+	if req.Parent == nil {
+		return nil, errors.New("nil parent header")
+	}
+	config := b.Config()
+	sdb, err := b.StateAt(req.Parent.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	header := types.CopyHeader(req.Parent)
+	header.ParentHash = req.Parent.Hash()
+	header.Number.Add(header.Number, common.Big1)
+	header.Root = common.Hash{}
+	header.GasUsed = 0
+	if req.Header != nil {
+		req.Header(header)
+	}
+
+	if err := req.Overrides.Apply(sdb); err != nil {
+		return nil, err
+	}
+	sdb.Finalise(config.IsEIP158(header.Number))
+
+	diff := make(state.StateDiff)
+	if err := migration.Run(config, header, sdb); err != nil {
+		return nil, err
+	}
+	mergeDiff(diff, sdb.TxDiff())
+	sdb.Finalise(config.IsEIP158(header.Number))
+
+	res := &Result{
+		Header: header,
+		Diff:   diff,
+	}
+	var (
+		blockCtx = core.NewEVMBlockContext(header, b, nil)
+		gp       = new(core.GasPool).AddGas(header.GasLimit)
+		usedGas  uint64
+	)
+	blockCtx.HeaderMode = vm.HeaderBuilding
+	for i, msg := range req.Messages {
+		vmConfig := req.VMConfig
+		var tracer Tracer
+		if req.NewTracer != nil {
+			tracer, err = req.NewTracer(i)
+			if err != nil {
+				return nil, fmt.Errorf("message %d: new tracer: %v", i, err)
+			}
+			vmConfig.Tracer = tracer
+		}
+
+		txHash := messageHash(header.Number.Uint64(), i)
+		sdb.SetTxContext(txHash, i)
+		evm := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), sdb, config, vmConfig)
+
+		result, err := core.ApplyMessage(evm, msg, gp)
+		evm.Finish()
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		mergeDiff(diff, sdb.TxDiff())
+
+		var root []byte
+		if config.IsByzantium(header.Number) {
+			sdb.Finalise(true)
+		} else {
+			root = sdb.IntermediateRoot(config.IsEIP158(header.Number)).Bytes()
+		}
+		usedGas += result.UsedGas
+
+		r := &types.Receipt{
+			PostState:         root,
+			CumulativeGasUsed: usedGas,
+			TxHash:            txHash,
+			GasUsed:           result.UsedGas,
+			Failure:           core.ReceiptFailure(result, evm),
+			Logs:              sdb.GetLogs(txHash, header.Number.Uint64(), common.Hash{}),
+			BlockNumber:       header.Number,
+			TransactionIndex:  uint(i),
+		}
+		if result.Failed() {
+			r.Status = types.ReceiptStatusFailed
+		} else {
+			r.Status = types.ReceiptStatusSuccessful
+		}
+		if msg.To == nil {
+			r.ContractAddress = crypto.CreateAddress(msg.From, msg.Nonce)
+		}
+		r.Bloom = types.CreateBloom(types.Receipts{r})
+
+		res.Receipts = append(res.Receipts, r)
+		res.Results = append(res.Results, result)
+		if tracer != nil {
+			trace, err := tracer.GetResult()
+			if err != nil {
+				return nil, fmt.Errorf("message %d: trace result: %v", i, err)
+			}
+			res.Traces = append(res.Traces, trace)
+		}
+	}
+
+	header.GasUsed = usedGas
+	hash := header.Hash()
+	for _, r := range res.Receipts {
+		r.BlockHash = hash
+		for _, l := range r.Logs {
+			l.BlockHash = hash
+		}
+	}
+	return res, nil
+}
+
+// messageHash returns the placeholder transaction hash for the message.
+func messageHash(blockNum uint64, msgIndex int) common.Hash {
+	buf := binary.BigEndian.AppendUint64([]byte("libevm.sim"), blockNum)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(msgIndex))
+	return crypto.Keccak256Hash(buf)
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package sim
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/eth/tracers/logger"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestRun(t *testing.T) {
+	var (
+		sender    = common.Address{'s', 'e', 'n', 'd'}
+		recipient = common.Address{'r', 'e', 'c', 'v'}
+	)
+	genesis := &core.Genesis{
+		Config:   params.TestChainConfig,
+		GasLimit: 30_000_000,
+		BaseFee:  big.NewInt(params.InitialBaseFee),
+		Alloc: types.GenesisAlloc{
+			recipient: {Balance: big.NewInt(1)},
+		},
+	}
+	bc, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	require.NoError(t, err, "core.NewBlockChain()")
+	defer bc.Stop()
+	parent := bc.CurrentBlock()
+
+	transfer := func(nonce uint64, value int64) *core.Message {
+		return &core.Message{
+			From:      sender,
+			To:        &recipient,
+			Nonce:     nonce,
+			Value:     big.NewInt(value),
+			GasLimit:  params.TxGas,
+			GasPrice:  new(big.Int),
+			GasFeeCap: new(big.Int),
+			GasTipCap: new(big.Int),
+		}
+	}
+	funds := uint256.NewInt(1_000)
+	req := &Request{
+		Parent: parent,
+		Overrides: StateOverride{
+			sender: {Balance: funds},
+		},
+		Messages: []*core.Message{
+			transfer(0, 100),
+			transfer(1, 50),
+		},
+		VMConfig: vm.Config{NoBaseFee: true},
+		NewTracer: func(int) (Tracer, error) {
+			return logger.NewStructLogger(nil), nil
+		},
+	}
+
+	got, err := Run(bc, req)
+	require.NoError(t, err, "Run()")
+
+	assert.Equal(t, parent.Hash(), got.Header.ParentHash, "Header.ParentHash")
+	assert.Equal(t, parent.Number.Uint64()+1, got.Header.Number.Uint64(), "Header.Number")
+	assert.Equal(t, 2*params.TxGas, got.Header.GasUsed, "Header.GasUsed")
+	require.Len(t, got.Receipts, 2, "Receipts")
+	for i, r := range got.Receipts {
+		assert.Equalf(t, types.ReceiptStatusSuccessful, r.Status, "Receipts[%d].Status", i)
+		assert.Equalf(t, uint64(i+1)*params.TxGas, r.CumulativeGasUsed, "Receipts[%d].CumulativeGasUsed", i)
+		assert.Equalf(t, got.Header.Hash(), r.BlockHash, "Receipts[%d].BlockHash", i)
+	}
+	assert.Len(t, got.Results, 2, "Results")
+	assert.Len(t, got.Traces, 2, "Traces")
+
+	want := state.StateDiff{
+		sender: {
+			Balance: &state.Change[*uint256.Int]{Before: funds, After: uint256.NewInt(850)},
+			Nonce:   &state.Change[uint64]{Before: 0, After: 2},
+		},
+		recipient: {
+			Balance: &state.Change[*uint256.Int]{Before: uint256.NewInt(1), After: uint256.NewInt(151)},
+		},
+	}
+	assert.Equal(t, want, got.Diff, "Diff")
+
+	sdb, err := bc.StateAt(parent.Root)
+	require.NoError(t, err, "StateAt(parent root)")
+	assert.True(t, sdb.GetBalance(sender).IsZero(), "chain state modified by simulation")
+
+	t.Run("invalid_message", func(t *testing.T) {
+		req.Messages = []*core.Message{transfer(1, 0)}
+		_, err := Run(bc, req)
+		require.ErrorIs(t, err, core.ErrNonceTooHigh, "Run() with message nonce too high")
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package sim
+
+import (
+	"fmt"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/state"
+)
+
+// An AccountOverride modifies an account before a simulation. Nil fields
+// leave the respective value unchanged.
+type AccountOverride struct {
+	Nonce   *uint64
+	Code    []byte
+	Balance *uint256.Int
+	// State replaces all of the account's storage whereas StateDiff only
+	// modifies the specified slots. At most one of them may be non-nil.
+	State     map[common.Hash]common.Hash
+	StateDiff map[common.Hash]common.Hash
+}
+
+// A StateOverride maps accounts to their respective overrides.
+type StateOverride map[common.Address]AccountOverride
+
+// Apply applies the overrides to the StateDB.
+func (o StateOverride) Apply(sdb *state.StateDB) error {
+	for addr, acc := range o {
+		if acc.State != nil && acc.StateDiff != nil {
+			return fmt.Errorf("account %v has both State and StateDiff overrides", addr)
+		}
+		if acc.Nonce != nil {
+			sdb.SetNonce(addr, *acc.Nonce)
+		}
+		if acc.Code != nil {
+			sdb.SetCode(addr, acc.Code)
+		}
+		if acc.Balance != nil {
+			sdb.SetBalance(addr, acc.Balance)
+		}
+		if acc.State != nil {
+			sdb.SetStorage(addr, acc.State)
+		}
+		for k, v := range acc.StateDiff {
+			sdb.SetState(addr, k, v)
+		}
+	}
+	return nil
+}
+
+// mergeDiff merges `next`, which MUST describe changes made after those in
+// `into`, into the latter. Values changed back to their original are removed.
+func mergeDiff(into, next state.StateDiff) {
+	for addr, n := range next {
+		d, ok := into[addr]
+		if !ok {
+			into[addr] = n
+			continue
+		}
+		if n.Balance != nil {
+			d.Balance = mergeChange(d.Balance, n.Balance, func(a, b *uint256.Int) bool { return a.Eq(b) })
+		}
+		if n.Nonce != nil {
+			d.Nonce = mergeChange(d.Nonce, n.Nonce, func(a, b uint64) bool { return a == b })
+		}
+		if n.CodeHash != nil {
+			d.CodeHash = mergeChange(d.CodeHash, n.CodeHash, func(a, b common.Hash) bool { return a == b })
+		}
+		for k, c := range n.Storage {
+			if d.Storage == nil {
+				d.Storage = make(map[common.Hash]state.Change[common.Hash])
+			}
+			if prev, ok := d.Storage[k]; ok {
+				c.Before = prev.Before
+			}
+			if c.Before == c.After {
+				delete(d.Storage, k)
+			} else {
+				d.Storage[k] = c
+			}
+		}
+		if d.Balance == nil && d.Nonce == nil && d.CodeHash == nil && len(d.Storage) == 0 {
+			delete(into, addr)
+		}
+	}
+}
+
+func mergeChange[T any](prev, next *state.Change[T], eq func(T, T) bool) *state.Change[T] {
+	if prev == nil {
+		return next
+	}
+	if eq(prev.Before, next.After) {
+		return nil
+	}
+	return &state.Change[T]{Before: prev.Before, After: next.After}
+}
@@ -0,0 +1,193 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+// Package validate cross-checks a chain configuration against libevm
+// registrations, allowing misconfiguration to be detected at startup instead
+// of at the block in which it would otherwise take effect.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/precompileconfig"
+	"github.com/ava-labs/libevm/params"
+)
+
+// A PrecompileUpgrader is a [params.ChainConfigHooks] that schedules
+// precompile configuration, which [Configuration] verifies.
+type PrecompileUpgrader interface {
+	PrecompileUpgrades() precompileconfig.Upgrades
+}
+
+// An EIPScheduler is a [params.ChainConfigHooks] that schedules individual
+// EIPs, which [Configuration] checks are supported.
+type EIPScheduler interface {
+	EIPSchedule() params.EIPSchedule
+}
+
+// Configuration validates the chain configuration and its registered hooks,
+// returning all errors found, joined. It is intended to be called before the
+// chain is used, and checks that:
+//
+//   - geth-defined and hook-defined forks are correctly ordered;
+//   - forks returned by [params.ChainConfig.ExtraForks] are named
+//     uniquely and scheduled by at most one of block and timestamp;
+//   - [PrecompileUpgrader] schedules pass [precompileconfig.Upgrades.Verify];
+//   - EIPs scheduled by an [EIPScheduler], or returned by a
+//     [params.EIPActivator], are supported by [vm.EnableEIP]; and
+//   - every address returned by [vm.ActivePrecompiles] is unique and has a
+//     precompile returned by [vm.PrecompileAt].
+//
+// Rules-dependent checks are performed at genesis and at every scheduled
+// activation known to libevm; i.e. of extra forks, precompile upgrades, and
+// EIPs. Timestamp-scheduled activations are evaluated at the latest
+// block-scheduled extra fork, if any.
+func Configuration(c *params.ChainConfig) error {
+	if c == nil {
+		return errors.New("nil chain config")
+	}
+
+	var errs []error
+	if err := c.CheckConfigForkOrder(); err != nil {
+		errs = append(errs, fmt.Errorf("fork order: %w", err))
+	}
+
+	hooks := c.Hooks()
+	forks := c.ExtraForks()
+	errs = append(errs, extraForks(forks)...)
+
+	var times []uint64
+	if u, ok := hooks.(PrecompileUpgrader); ok {
+		upgrades := u.PrecompileUpgrades()
+		if err := upgrades.Verify(); err != nil {
+			errs = append(errs, err)
+		}
+		for _, cfg := range upgrades {
+			times = append(times, cfg.Timestamp())
+		}
+	}
+	if s, ok := hooks.(EIPScheduler); ok {
+		schedule := s.EIPSchedule()
+		eips := maps.Keys(schedule)
+		slices.Sort(eips)
+		for _, eip := range eips {
+			if !vm.ValidEip(eip) {
+				errs = append(errs, fmt.Errorf("EIP-%d scheduled at time %d is not supported by vm.EnableEIP()", eip, schedule[eip]))
+			}
+		}
+		times = append(times, maps.Values(schedule)...)
+	}
+
+	for _, a := range activations(forks, times) {
+		errs = append(errs, rulesAt(c, a)...)
+	}
+	return errors.Join(errs...)
+}
+
+func extraForks(forks []params.ExtraFork) []error {
+	var errs []error
+	seen := make(map[string]bool)
+	for i, f := range forks {
+		switch {
+		case f.Name == "":
+			errs = append(errs, fmt.Errorf("extra fork %d has empty name", i))
+		case seen[f.Name]:
+			errs = append(errs, fmt.Errorf("extra fork %d (%q) has duplicate name", i, f.Name))
+		}
+		seen[f.Name] = true
+
+		if f.Block != nil && f.Timestamp != nil {
+			errs = append(errs, fmt.Errorf("extra fork %d (%q) scheduled by both block (%v) and timestamp (%d)", i, f.Name, f.Block, *f.Timestamp))
+		}
+	}
+	return errs
+}
+
+// An activation is a point at which the [params.Rules] might change.
+type activation struct {
+	block *big.Int
+	time  uint64
+}
+
+func (a activation) String() string {
+	return fmt.Sprintf("block %v, time %d", a.block, a.time)
+}
+
+// activations returns genesis followed by the distinct block- and
+// timestamp-scheduled activations, in ascending order.
+func activations(forks []params.ExtraFork, times []uint64) []activation {
+	var blocks []uint64
+	for _, f := range forks {
+		switch {
+		case f.Block != nil && f.Block.IsUint64():
+			blocks = append(blocks, f.Block.Uint64())
+		case f.Timestamp != nil:
+			times = append(times, *f.Timestamp)
+		}
+	}
+	slices.Sort(blocks)
+	blocks = slices.Compact(blocks)
+	times = slices.Clone(times)
+	slices.Sort(times)
+	times = slices.Compact(times)
+
+	as := []activation{{block: new(big.Int)}}
+	var last uint64
+	for _, b := range blocks {
+		if b > 0 {
+			as = append(as, activation{block: new(big.Int).SetUint64(b)})
+		}
+		last = b
+	}
+	for _, t := range times {
+		if t > 0 || last > 0 {
+			as = append(as, activation{block: new(big.Int).SetUint64(last), time: t})
+		}
+	}
+	return as
+}
+
+func rulesAt(c *params.ChainConfig, a activation) []error {
+	isMerge := c.TerminalTotalDifficulty != nil && c.TerminalTotalDifficulty.Sign() == 0
+	rules := c.Rules(a.block, isMerge, a.time)
+
+	var errs []error
+	for _, eip := range rules.ActiveEIPs() {
+		if !vm.ValidEip(eip) {
+			errs = append(errs, fmt.Errorf("at %v: EIP-%d returned by ActiveEIPs hook is not supported by vm.EnableEIP()", a, eip))
+		}
+	}
+
+	seen := make(map[common.Address]bool)
+	for _, addr := range vm.ActivePrecompiles(rules) {
+		if seen[addr] {
+			errs = append(errs, fmt.Errorf("at %v: precompile %v returned more than once by vm.ActivePrecompiles()", a, addr))
+			continue
+		}
+		seen[addr] = true
+		if _, ok := vm.PrecompileAt(rules, addr); !ok {
+			errs = append(errs, fmt.Errorf("at %v: active precompile %v has no implementation; ActivePrecompiles and PrecompileOverride hooks are inconsistent", a, addr))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package validate
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/libevm/hookstest"
+	"github.com/ava-labs/libevm/libevm/precompileconfig"
+	"github.com/ava-labs/libevm/params"
+)
+
+type upgrade struct {
+	time    uint64
+	disable bool
+}
+
+func (u *upgrade) Key() string       { return "test" }
+func (u *upgrade) Timestamp() uint64 { return u.time }
+func (u *upgrade) IsDisabled() bool  { return u.disable }
+func (u *upgrade) Verify() error     { return nil }
+
+type chainExtras struct {
+	params.NOOPHooks
+	forks    []params.ExtraFork
+	upgrades precompileconfig.Upgrades
+	eips     params.EIPSchedule
+
+	// Rules-dependent behaviour, from the timestamp of the respective field.
+	inconsistentPrecompileFrom, badEIPFrom *uint64
+}
+
+func (c *chainExtras) ExtraForks() []params.ExtraFork                { return c.forks }
+func (c *chainExtras) PrecompileUpgrades() precompileconfig.Upgrades { return c.upgrades }
+func (c *chainExtras) EIPSchedule() params.EIPSchedule               { return c.eips }
+
+type rulesExtras struct {
+	params.NOOPHooks
+	chain *chainExtras
+	time  uint64
+}
+
+var phantom = common.Address{'p', 'h', 'a', 'n', 't', 'o', 'm'}
+
+func (r *rulesExtras) active(from *uint64) bool {
+	return from != nil && r.time >= *from
+}
+
+func (r *rulesExtras) ActivePrecompiles(active []common.Address) []common.Address {
+	if r.active(r.chain.inconsistentPrecompileFrom) {
+		// Not returned by the embedded PrecompileOverride().
+		return append(active, phantom)
+	}
+	return active
+}
+
+func (r *rulesExtras) ActiveEIPs() []int {
+	if r.active(r.chain.badEIPFrom) {
+		return []int{-1}
+	}
+	return nil
+}
+
+func ptr[T any](x T) *T { return &x }
+
+func TestConfiguration(t *testing.T) {
+	extras := hookstest.Register(t, params.Extras[*chainExtras, *rulesExtras]{
+		NewRules: func(_ *params.ChainConfig, _ *params.Rules, c *chainExtras, _ *big.Int, _ bool, time uint64) *rulesExtras {
+			return &rulesExtras{chain: c, time: time}
+		},
+	})
+
+	tests := []struct {
+		name        string
+		extras      *chainExtras
+		wantErrsHas []string
+	}{
+		{
+			name: "valid",
+			extras: &chainExtras{
+				forks: []params.ExtraFork{
+					{Name: "Foo", Block: big.NewInt(10)},
+					{Name: "Bar", Timestamp: ptr[uint64](20)},
+					{Name: "Unscheduled"},
+				},
+				upgrades: precompileconfig.Upgrades{
+					&upgrade{time: 30},
+					&upgrade{time: 40, disable: true},
+				},
+				eips: params.EIPSchedule{3855: 50},
+			},
+		},
+		{
+			name: "bad_extra_forks",
+			extras: &chainExtras{
+				forks: []params.ExtraFork{
+					{Name: "Foo", Block: big.NewInt(10)},
+					{Name: "Foo", Timestamp: ptr[uint64](20)},
+					{Name: "", Block: big.NewInt(30)},
+					{Name: "Both", Block: big.NewInt(1), Timestamp: ptr[uint64](1)},
+				},
+			},
+			wantErrsHas: []string{
+				`extra fork 1 ("Foo") has duplicate name`,
+				`extra fork 2 has empty name`,
+				`extra fork 3 ("Both") scheduled by both block`,
+			},
+		},
+		{
+			name: "bad_precompile_upgrades",
+			extras: &chainExtras{
+				upgrades: precompileconfig.Upgrades{
+					&upgrade{time: 30, disable: true},
+				},
+			},
+			wantErrsHas: []string{"disables precompile that was never enabled"},
+		},
+		{
+			name: "unsupported_scheduled_eip",
+			extras: &chainExtras{
+				eips: params.EIPSchedule{-1: 50},
+			},
+			wantErrsHas: []string{"EIP--1 scheduled at time 50 is not supported"},
+		},
+		{
+			name: "unsupported_active_eip_after_fork",
+			extras: &chainExtras{
+				forks:      []params.ExtraFork{{Name: "Foo", Timestamp: ptr[uint64](100)}},
+				badEIPFrom: ptr[uint64](100),
+			},
+			wantErrsHas: []string{"at block 0, time 100: EIP--1 returned by ActiveEIPs hook"},
+		},
+		{
+			name: "inconsistent_precompile_after_upgrade",
+			extras: &chainExtras{
+				forks: []params.ExtraFork{{Name: "Foo", Block: big.NewInt(5)}},
+				upgrades: precompileconfig.Upgrades{
+					&upgrade{time: 200},
+				},
+				inconsistentPrecompileFrom: ptr[uint64](200),
+			},
+			wantErrsHas: []string{"at block 5, time 200: active precompile " + phantom.String() + " has no implementation"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &params.ChainConfig{ChainID: big.NewInt(1)}
+			extras.ChainConfig.Set(c, tt.extras)
+
+			err := Configuration(c)
+			if len(tt.wantErrsHas) == 0 {
+				require.NoError(t, err, "Configuration()")
+				return
+			}
+			require.Error(t, err, "Configuration()")
+			for _, want := range tt.wantErrsHas {
+				assert.ErrorContains(t, err, want, "Configuration()")
+			}
+		})
+	}
+
+	assert.Error(t, Configuration(nil), "Configuration(nil)")
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+// A Limiter gates and rate-limits high-frequency log sites; e.g. those executed
+// for every EVM call. Records are only considered if the [Root] logger and the
+// Limiter's module (see [SetModuleLevel]) are both enabled at the respective
+// level, after which sampling and then rate limiting are applied.
+//
+// Emitted records carry a "suppressed" attribute with the number of records
+// that were dropped by sampling or rate limiting since the last emission, if
+// non-zero.
+//
+// The zero value is a valid Limiter that only performs level gating of the
+// empty module. A Limiter MUST NOT be copied after first use.
+type Limiter struct {
+	module string
+	cfg    LimiterConfig
+	now    func() time.Time // for testing; defaults to [time.Now]
+
+	mu          sync.Mutex
+	calls       uint64
+	windowStart time.Time
+	inWindow    uint64
+	suppressed  uint64
+}
+
+// LimiterConfig configures a [Limiter]. Zero values disable the respective
+// limits.
+type LimiterConfig struct {
+	// SampleEvery, if greater than 1, results in only every nth record being
+	// considered for rate limiting, the first always being considered.
+	SampleEvery uint64
+	// At most Burst records are emitted per Interval. Both MUST be non-zero
+	// for rate limiting to be applied.
+	Burst    uint64
+	Interval time.Duration
+}
+
+// NewLimiter constructs a new [Limiter] for the module.
+func NewLimiter(module string, cfg LimiterConfig) *Limiter {
+	return &Limiter{
+		module: module,
+		cfg:    cfg,
+	}
+}
+
+// The following methods call Root().Write() directly, without any intermediate
+// functions, to keep the call depth the same as for the package-level logging
+// functions.
+
+// Trace is equivalent to the package-level [Trace] function, subject to the
+// Limiter's gating.
+func (l *Limiter) Trace(msg string, ctx ...any) {
+	if s, ok := l.allow(LevelTrace); ok {
+		Root().Write(LevelTrace, msg, withSuppressed(ctx, s)...)
+	}
+}
+
+// Debug is equivalent to the package-level [Debug] function, subject to the
+// Limiter's gating.
+func (l *Limiter) Debug(msg string, ctx ...any) {
+	if s, ok := l.allow(slog.LevelDebug); ok {
+		Root().Write(slog.LevelDebug, msg, withSuppressed(ctx, s)...)
+	}
+}
+
+func withSuppressed(ctx []any, suppressed uint64) []any {
+	if suppressed == 0 {
+		return ctx
+	}
+	// Full slice expression to guarantee a copy and avoid modifying the
+	// caller's backing array.
+	return append(ctx[:len(ctx):len(ctx)], "suppressed", suppressed)
+}
+
+// allow reports whether a record at the specified level should be emitted and,
+// if so, how many records were suppressed since the last emission.
+func (l *Limiter) allow(level slog.Level) (suppressed uint64, _ bool) {
+	if !moduleEnabled(l.module, level) || !Root().Enabled(context.Background(), level) {
+		return 0, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if n := l.cfg.SampleEvery; n > 1 && (l.calls-1)%n != 0 {
+		l.suppressed++
+		return 0, false
+	}
+
+	if l.cfg.Burst > 0 && l.cfg.Interval > 0 {
+		now := time.Now
+		if l.now != nil {
+			now = l.now
+		}
+		if t := now(); t.Sub(l.windowStart) >= l.cfg.Interval {
+			l.windowStart = t
+			l.inWindow = 0
+		}
+		if l.inWindow >= l.cfg.Burst {
+			l.suppressed++
+			return 0, false
+		}
+		l.inWindow++
+	}
+
+	suppressed = l.suppressed
+	l.suppressed = 0
+	return suppressed, true
+}
+
+var moduleLevels sync.Map // string -> slog.Level
+
+// SetModuleLevel sets the minimum level at which all [Limiter]s of the module
+// emit records. Module levels can only further restrict logging; records MUST
+// still be enabled by the [Root] logger to be emitted.
+func SetModuleLevel(module string, level slog.Level) {
+	moduleLevels.Store(module, level)
+}
+
+// ClearModuleLevel reverts the effect of [SetModuleLevel].
+func ClearModuleLevel(module string) {
+	moduleLevels.Delete(module)
+}
+
+func moduleEnabled(module string, level slog.Level) bool {
+	lvl, ok := moduleLevels.Load(module)
+	return !ok || level >= lvl.(slog.Level)
+}
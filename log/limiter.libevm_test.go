@@ -0,0 +1,132 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slog"
+)
+
+// captureRoot replaces the [Root] logger, for the duration of the test, with
+// one that records all messages at or above the specified level.
+func captureRoot(t *testing.T, level slog.Level) *[]slog.Record {
+	t.Helper()
+	old := Root()
+	t.Cleanup(func() { SetDefault(old) })
+
+	var got []slog.Record
+	SetDefault(NewLogger(&recorder{level: level, records: &got}))
+	return &got
+}
+
+type recorder struct {
+	slog.Handler
+	level   slog.Level
+	records *[]slog.Record
+}
+
+func (r *recorder) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= r.level
+}
+
+func (r *recorder) Handle(_ context.Context, rec slog.Record) error {
+	*r.records = append(*r.records, rec)
+	return nil
+}
+
+func suppressedAttr(t *testing.T, r slog.Record) uint64 {
+	t.Helper()
+	var n uint64
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == "suppressed" {
+			n = a.Value.Uint64()
+			return false
+		}
+		return true
+	})
+	return n
+}
+
+func TestLimiter(t *testing.T) {
+	t.Run("level_gating", func(t *testing.T) {
+		got := captureRoot(t, slog.LevelInfo)
+		var l Limiter
+		l.Debug("dropped")
+		assert.Empty(t, *got, "records when root logger not enabled")
+	})
+
+	t.Run("module_level", func(t *testing.T) {
+		got := captureRoot(t, LevelTrace)
+		const module = "test-module"
+		l := NewLimiter(module, LimiterConfig{})
+
+		SetModuleLevel(module, slog.LevelDebug)
+		t.Cleanup(func() { ClearModuleLevel(module) })
+		l.Trace("dropped")
+		l.Debug("kept")
+
+		ClearModuleLevel(module)
+		l.Trace("kept")
+
+		require.Len(t, *got, 2, "records")
+		for _, r := range *got {
+			assert.Equal(t, "kept", r.Message)
+		}
+	})
+
+	t.Run("sampling", func(t *testing.T) {
+		got := captureRoot(t, slog.LevelDebug)
+		l := NewLimiter("", LimiterConfig{SampleEvery: 3})
+		for i := 0; i < 7; i++ {
+			l.Debug("")
+		}
+		require.Len(t, *got, 3, "records from 7 calls sampling every 3")
+		var suppressed []uint64
+		for _, r := range *got {
+			suppressed = append(suppressed, suppressedAttr(t, r))
+		}
+		assert.Equal(t, []uint64{0, 2, 2}, suppressed, "suppressed counts")
+	})
+
+	t.Run("rate_limiting", func(t *testing.T) {
+		got := captureRoot(t, slog.LevelDebug)
+		l := NewLimiter("", LimiterConfig{Burst: 2, Interval: time.Second})
+		now := time.Unix(0, 0)
+		l.now = func() time.Time { return now }
+
+		for i := 0; i < 5; i++ {
+			l.Debug("first")
+		}
+		now = now.Add(time.Second)
+		l.Debug("second")
+
+		var msgs []string
+		for _, r := range *got {
+			msgs = append(msgs, r.Message)
+		}
+		assert.Equal(t, "first first second", strings.Join(msgs, " "), "emitted messages")
+		if assert.Len(t, *got, 3) {
+			assert.Equal(t, uint64(3), suppressedAttr(t, (*got)[2]), "suppressed count after new interval")
+		}
+	})
+}
@@ -53,6 +53,9 @@ type Config struct {
 	Recommit  time.Duration  // The time interval for miner to re-create mining work.
 
 	NewPayloadTimeout time.Duration // The maximum time allowance for creating a new payload
+
+	UpgradeDryRun *UpgradeDryRun    `toml:"-"` // libevm: see [UpgradeDryRun]
+	TxHooks       *TransactionHooks `toml:"-"` // libevm: see [TransactionHooks]
 }
 
 // DefaultConfig contains default settings for miner.
@@ -0,0 +1,122 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/txpool"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/log"
+)
+
+// TransactionHooks allow the embedder to control which transactions the block
+// builder includes, and in which order, without forking the miner. All fields
+// are optional. Every block is filled, in order, with:
+//
+//  1. The transactions returned by Bundles;
+//  2. Pool transactions from the senders returned by Prioritized;
+//  3. Pool transactions from local accounts; then
+//  4. All other pool transactions.
+//
+// Pool transactions within each of the last three groups are ordered by
+// price and nonce, as usual, and are subject to Veto.
+type TransactionHooks struct {
+	// Bundles returns groups of transactions, e.g. system transactions, to be
+	// included at the start of the block, in order. Each bundle is atomic: if
+	// any of its transactions can't be included then none of them are.
+	Bundles func(*types.Header, libevm.StateReader) [][]*types.Transaction
+	// Prioritized returns senders whose pool transactions are included before
+	// those of all other senders, including local accounts.
+	Prioritized func(*types.Header) []common.Address
+	// Veto is called before every pool transaction is applied and returns a
+	// non-nil error to exclude it; e.g. if it would call a paused precompile.
+	// As a sender's nonces can't be skipped, all of their subsequent
+	// transactions are also excluded from the block.
+	Veto func(_ *types.Header, _ *types.Transaction, from common.Address, _ libevm.StateReader) error
+}
+
+func (w *worker) txHooks() *TransactionHooks {
+	if h := w.config.TxHooks; h != nil {
+		return h
+	}
+	return &TransactionHooks{}
+}
+
+// commitBundles commits the transactions returned by [TransactionHooks.Bundles],
+// reverting `env` to its state before a bundle if any of its transactions
+// fails.
+func (w *worker) commitBundles(env *environment) {
+	h := w.txHooks()
+	if h.Bundles == nil {
+		return
+	}
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+
+	for i, bundle := range h.Bundles(env.header, env.state) {
+		saved := env.copy()
+		if err := w.commitBundle(env, bundle); err != nil {
+			log.Debug("Transaction bundle failed, bundle skipped", "index", i, "err", err)
+			env.discard()
+			*env = *saved
+			continue
+		}
+		saved.discard()
+	}
+}
+
+func (w *worker) commitBundle(env *environment, bundle []*types.Transaction) error {
+	for _, tx := range bundle {
+		env.state.SetTxContext(tx.Hash(), env.tcount)
+		if _, err := w.commitTransaction(env, tx); err != nil {
+			return err
+		}
+		env.tcount++
+	}
+	return nil
+}
+
+// takePrioritizedTxs removes the transactions of all senders returned by
+// [TransactionHooks.Prioritized] from the maps, returning them in a new one.
+func (w *worker) takePrioritizedTxs(env *environment, from ...map[common.Address][]*txpool.LazyTransaction) map[common.Address][]*txpool.LazyTransaction {
+	h := w.txHooks()
+	prio := make(map[common.Address][]*txpool.LazyTransaction)
+	if h.Prioritized == nil {
+		return prio
+	}
+	for _, account := range h.Prioritized(env.header) {
+		for _, txsBySender := range from {
+			if txs := txsBySender[account]; len(txs) > 0 {
+				delete(txsBySender, account)
+				prio[account] = txs
+			}
+		}
+	}
+	return prio
+}
+
+// vetoTx returns the error returned by [TransactionHooks.Veto], if any.
+func (w *worker) vetoTx(env *environment, tx *types.Transaction, from common.Address) error {
+	h := w.txHooks()
+	if h.Veto == nil {
+		return nil
+	}
+	return h.Veto(env.header, tx, from, env.state)
+}
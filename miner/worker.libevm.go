@@ -0,0 +1,193 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/core"
+	"github.com/ava-labs/libevm/core/state"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/libevm/migration"
+	"github.com/ava-labs/libevm/log"
+	"github.com/ava-labs/libevm/params"
+)
+
+// An UpgradeDryRun configures the block builder to re-execute the transactions
+// of every block that it builds under a pending chain configuration, typically
+// one that activates an upcoming network upgrade (i.e. new forks and/or
+// precompiles), reporting any divergence from the block actually built. The
+// dry run is performed in the background, on a copy of the parent state, and
+// has no effect on the block.
+type UpgradeDryRun struct {
+	// ChainConfig is the pending configuration, which MUST NOT be modified
+	// once passed to the miner.
+	ChainConfig *params.ChainConfig
+	// Report, if non-nil, is called once for every dry run, with all
+	// divergences found, which MAY be empty. If nil, divergences are logged
+	// as warnings.
+	Report func(*types.Header, []*UpgradeDivergence)
+}
+
+// An UpgradeDivergence describes a difference in the outcome of a block
+// executed under the pending [UpgradeDryRun] configuration compared to the
+// current one.
+type UpgradeDivergence struct {
+	// TxIndex and TxHash identify the transaction with differing outcomes. The
+	// index is -1 if the divergence is in the block as a whole.
+	TxIndex int
+	TxHash  common.Hash
+	// Field names the differing value; e.g. "status", "gas used", "logs",
+	// "error", or "state root".
+	Field string
+	// Current and Pending are the respective values under each configuration.
+	Current, Pending any
+}
+
+// dryRunUpgrade starts a background dry run of the transactions in `env`
+// under the configured [UpgradeDryRun], if any. It MUST be called after the
+// environment is filled but before it is finalised.
+func (w *worker) dryRunUpgrade(env *environment) {
+	dry := w.config.UpgradeDryRun
+	if dry == nil || dry.ChainConfig == nil || len(env.txs) == 0 {
+		return
+	}
+	parent := w.chain.GetHeader(env.header.ParentHash, env.header.Number.Uint64()-1)
+	if parent == nil {
+		log.Error("Upgrade dry run missing parent", "number", env.header.Number, "parent", env.header.ParentHash)
+		return
+	}
+	statedb, err := w.chain.StateAt(parent.Root)
+	if err != nil {
+		log.Error("Upgrade dry run failed to open parent state", "number", env.header.Number, "err", err)
+		return
+	}
+
+	// Everything used in the background MUST be copied as `env` continues to
+	// be modified by the worker.
+	var (
+		header   = types.CopyHeader(env.header)
+		coinbase = env.coinbase
+		txs      = append([]*types.Transaction(nil), env.txs...)
+		receipts = copyReceipts(env.receipts)
+		current  = env.state.Copy()
+	)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		divs := w.upgradeDivergences(dry.ChainConfig, statedb, current, header, coinbase, txs, receipts)
+		if dry.Report != nil {
+			dry.Report(header, divs)
+			return
+		}
+		for _, d := range divs {
+			log.Warn(
+				"Upgrade dry run diverged",
+				"number", header.Number,
+				"txIndex", d.TxIndex,
+				"txHash", d.TxHash,
+				"field", d.Field,
+				"current", d.Current,
+				"pending", d.Pending,
+			)
+		}
+	}()
+}
+
+// upgradeDivergences applies the transactions to `statedb` under the pending
+// configuration, after the same pre-transaction steps as [worker.prepareWork],
+// and compares the results to the receipts and `current` state produced under
+// the worker's configuration. The final state roots are only compared if all
+// receipts match, as any other divergence would almost certainly also result
+// in different roots.
+func (w *worker) upgradeDivergences(pending *params.ChainConfig, statedb, current *state.StateDB, header *types.Header, coinbase common.Address, txs []*types.Transaction, receipts []*types.Receipt) []*UpgradeDivergence {
+	if err := w.preTransactionSteps(pending, header, statedb); err != nil {
+		return []*UpgradeDivergence{{
+			TxIndex: -1,
+			Field:   "error",
+			Pending: err,
+		}}
+	}
+
+	var (
+		divs    []*UpgradeDivergence
+		gp      = new(core.GasPool).AddGas(header.GasLimit)
+		usedGas uint64
+	)
+	for i, tx := range txs {
+		want := receipts[i]
+		diverge := func(field string, curr, pend any) {
+			divs = append(divs, &UpgradeDivergence{
+				TxIndex: i,
+				TxHash:  tx.Hash(),
+				Field:   field,
+				Current: curr,
+				Pending: pend,
+			})
+		}
+
+		statedb.SetTxContext(tx.Hash(), i)
+		got, err := core.ApplyTransactionToBuildingBlock(pending, w.chain, &coinbase, gp, statedb, header, tx, &usedGas, *w.chain.GetVMConfig())
+		if err != nil {
+			// The transaction can't be included so there's no point in
+			// continuing as all subsequent nonces will be invalid.
+			diverge("error", nil, err)
+			return divs
+		}
+		if got.Status != want.Status {
+			diverge("status", want.Status, got.Status)
+		}
+		if got.GasUsed != want.GasUsed {
+			diverge("gas used", want.GasUsed, got.GasUsed)
+		}
+		if len(got.Logs) != len(want.Logs) || got.Bloom != want.Bloom {
+			diverge("logs", len(want.Logs), len(got.Logs))
+		}
+	}
+	if len(divs) > 0 {
+		return divs
+	}
+
+	num := header.Number
+	currRoot := current.IntermediateRoot(w.chainConfig.IsEIP158(num))
+	if pendRoot := statedb.IntermediateRoot(pending.IsEIP158(num)); pendRoot != currRoot {
+		divs = append(divs, &UpgradeDivergence{
+			TxIndex: -1,
+			Field:   "state root",
+			Current: currRoot,
+			Pending: pendRoot,
+		})
+	}
+	return divs
+}
+
+// preTransactionSteps applies the state changes that [worker.prepareWork]
+// makes before any transactions, under the specified configuration.
+func (w *worker) preTransactionSteps(config *params.ChainConfig, header *types.Header, statedb *state.StateDB) error {
+	if err := migration.Run(config, header, statedb); err != nil {
+		return err
+	}
+	if header.ParentBeaconRoot != nil {
+		context := core.NewEVMBlockContext(header, w.chain, nil)
+		context.HeaderMode = vm.HeaderBuilding
+		vmenv := vm.NewEVM(context, vm.TxContext{}, statedb, config, vm.Config{})
+		core.ProcessBeaconBlockRoot(*header.ParentBeaconRoot, vmenv, statedb)
+		vmenv.Finish()
+	}
+	return nil
+}
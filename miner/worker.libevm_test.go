@@ -0,0 +1,244 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/common"
+	"github.com/ava-labs/libevm/consensus/ethash"
+	"github.com/ava-labs/libevm/core/rawdb"
+	"github.com/ava-labs/libevm/core/txpool"
+	"github.com/ava-labs/libevm/core/types"
+	"github.com/ava-labs/libevm/core/vm"
+	"github.com/ava-labs/libevm/event"
+	"github.com/ava-labs/libevm/libevm"
+	"github.com/ava-labs/libevm/libevm/migration"
+	"github.com/ava-labs/libevm/params"
+)
+
+func TestUpgradeDryRun(t *testing.T) {
+	otherChainID := *ethashChainConfig
+	otherChainID.ChainID = new(big.Int).Add(ethashChainConfig.ChainID, big.NewInt(1))
+
+	sameConfig := *ethashChainConfig
+
+	// The migration is run by the block builder, so MUST also be run by the
+	// dry run to avoid a spurious state-root divergence.
+	migrateAll := func(*params.ChainConfig, *big.Int, uint64) bool { return true }
+	migratePendingOnly := func(c *params.ChainConfig, _ *big.Int, _ uint64) bool { return c == &sameConfig }
+
+	tests := []struct {
+		name      string
+		pending   *params.ChainConfig
+		migration func(*params.ChainConfig, *big.Int, uint64) bool
+		want      []*UpgradeDivergence
+	}{
+		{
+			name:    "no_divergence",
+			pending: &sameConfig,
+			want:    nil,
+		},
+		{
+			name:      "migration_under_both",
+			pending:   &sameConfig,
+			migration: migrateAll,
+			want:      nil,
+		},
+		{
+			name:      "migration_under_pending_only",
+			pending:   &sameConfig,
+			migration: migratePendingOnly,
+			want: []*UpgradeDivergence{{
+				TxIndex: -1,
+				Field:   "state root",
+			}},
+		},
+		{
+			name:    "tx_invalidated",
+			pending: &otherChainID,
+			want: []*UpgradeDivergence{{
+				TxIndex: 0,
+				TxHash:  pendingTxs[0].Hash(),
+				Field:   "error",
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.migration != nil {
+				migration.TestOnlyClearRegistered()
+				t.Cleanup(migration.TestOnlyClearRegistered)
+				migration.Register(migration.Migration{
+					Name:     tt.name,
+					Address:  common.Address{'m', 'i', 'g'},
+					IsActive: tt.migration,
+					Migrate: func(sdb vm.StateDB, _ *types.Header) error {
+						sdb.SetState(common.Address{'m', 'i', 'g'}, common.Hash{}, common.Hash{1})
+						return nil
+					},
+				})
+			}
+
+			type report struct {
+				header *types.Header
+				divs   []*UpgradeDivergence
+			}
+			reports := make(chan report, 1)
+
+			config := *testConfig
+			config.UpgradeDryRun = &UpgradeDryRun{
+				ChainConfig: tt.pending,
+				Report: func(h *types.Header, divs []*UpgradeDivergence) {
+					reports <- report{h, divs}
+				},
+			}
+
+			engine := ethash.NewFaker()
+			defer engine.Close()
+			b := newTestWorkerBackend(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+			for _, err := range b.txPool.Add(pendingTxs, true, true) {
+				require.NoError(t, err, "%T.Add()", b.txPool)
+			}
+			w := newWorker(&config, ethashChainConfig, engine, b, new(event.TypeMux), nil, false)
+			defer w.close()
+
+			res := w.getSealingBlock(&generateParams{
+				parentHash: b.chain.Genesis().Hash(),
+				timestamp:  uint64(time.Now().Unix()),
+				coinbase:   common.HexToAddress("0xdeadbeef"),
+			})
+			require.NoError(t, res.err, "getSealingBlock()")
+			require.Len(t, res.block.Transactions(), len(pendingTxs), "transactions in built block")
+
+			select {
+			case got := <-reports:
+				assert.Equal(t, res.block.NumberU64(), got.header.Number.Uint64(), "reported block number")
+				require.Len(t, got.divs, len(tt.want), "divergences")
+				for i, want := range tt.want {
+					d := got.divs[i]
+					assert.Equal(t, want.TxIndex, d.TxIndex, "TxIndex")
+					assert.Equal(t, want.TxHash, d.TxHash, "TxHash")
+					assert.Equal(t, want.Field, d.Field, "Field")
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("dry run not reported")
+			}
+		})
+	}
+}
+
+func TestTransactionHooks(t *testing.T) {
+	var (
+		signer  = types.LatestSigner(ethashChainConfig)
+		vetoed  = common.Address{'v', 'e', 't', 'o'}
+		errVeto = errors.New("vetoed")
+	)
+	transfer := func(t *testing.T, key *ecdsa.PrivateKey, nonce uint64, to common.Address, value int64) *types.Transaction {
+		t.Helper()
+		return types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &to,
+			Value:    big.NewInt(value),
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(params.InitialBaseFee),
+		})
+	}
+
+	// The first bundle funds the user, who is then the sender of the other
+	// bundles. The second bundle fails because of a repeated nonce.
+	var (
+		fundUser    = transfer(t, testBankKey, 0, testUserAddress, 1e16)
+		fromUser    = transfer(t, testUserKey, 0, testBankAddress, 1)
+		toUser      = transfer(t, testBankKey, 1, testUserAddress, 1)
+		toVetoed    = transfer(t, testBankKey, 2, vetoed, 1)
+		bundles     = [][]*types.Transaction{{fundUser}, {fromUser, fromUser}, {fromUser}}
+		pool        = []*types.Transaction{fundUser, toUser, toVetoed}
+		wantInBlock = []*types.Transaction{fundUser, fromUser, toUser}
+	)
+
+	config := *testConfig
+	config.TxHooks = &TransactionHooks{
+		Bundles: func(*types.Header, libevm.StateReader) [][]*types.Transaction {
+			return bundles
+		},
+		Veto: func(_ *types.Header, tx *types.Transaction, from common.Address, _ libevm.StateReader) error {
+			if *tx.To() == vetoed {
+				return errVeto
+			}
+			return nil
+		},
+	}
+
+	engine := ethash.NewFaker()
+	defer engine.Close()
+	b := newTestWorkerBackend(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	for _, err := range b.txPool.Add(pool, true, true) {
+		require.NoError(t, err, "%T.Add()", b.txPool)
+	}
+	w := newWorker(&config, ethashChainConfig, engine, b, new(event.TypeMux), nil, false)
+	defer w.close()
+
+	res := w.getSealingBlock(&generateParams{
+		parentHash: b.chain.Genesis().Hash(),
+		timestamp:  uint64(time.Now().Unix()),
+		coinbase:   common.HexToAddress("0xdeadbeef"),
+	})
+	require.NoError(t, res.err, "getSealingBlock()")
+
+	var got, want []common.Hash
+	for _, tx := range res.block.Transactions() {
+		got = append(got, tx.Hash())
+	}
+	for _, tx := range wantInBlock {
+		want = append(want, tx.Hash())
+	}
+	assert.Equal(t, want, got, "transactions in built block")
+}
+
+func TestTakePrioritizedTxs(t *testing.T) {
+	var (
+		prio  = common.Address{'p', 'r', 'i', 'o'}
+		local = common.Address{'l', 'o', 'c', 'a', 'l'}
+		other = common.Address{'o', 't', 'h', 'e', 'r'}
+	)
+	w := &worker{
+		config: &Config{
+			TxHooks: &TransactionHooks{
+				Prioritized: func(*types.Header) []common.Address {
+					return []common.Address{prio, local}
+				},
+			},
+		},
+	}
+	lazy := []*txpool.LazyTransaction{{}}
+	locals := map[common.Address][]*txpool.LazyTransaction{local: lazy}
+	remotes := map[common.Address][]*txpool.LazyTransaction{prio: lazy, other: lazy}
+
+	got := w.takePrioritizedTxs(&environment{header: &types.Header{}}, locals, remotes)
+	assert.Equal(t, map[common.Address][]*txpool.LazyTransaction{prio: lazy, local: lazy}, got, "prioritized")
+	assert.Empty(t, locals, "locals after removing prioritized")
+	assert.Equal(t, map[common.Address][]*txpool.LazyTransaction{other: lazy}, remotes, "remotes after removing prioritized")
+}
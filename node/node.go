@@ -34,6 +34,7 @@ import (
 	"github.com/ava-labs/libevm/core/rawdb"
 	"github.com/ava-labs/libevm/ethdb"
 	"github.com/ava-labs/libevm/event"
+	"github.com/ava-labs/libevm/libevm/register"
 	"github.com/ava-labs/libevm/log"
 	"github.com/ava-labs/libevm/p2p"
 	"github.com/ava-labs/libevm/rpc"
@@ -75,6 +76,11 @@ const (
 
 // New creates a new P2P node, ready for protocol registration.
 func New(conf *Config) (*Node, error) {
+	// libevm: registration of extras, hooks, etc. MUST be complete before a
+	// node is constructed, after which registered values are read without
+	// synchronisation.
+	register.Freeze()
+
 	// Copy config and resolve the datadir so future changes to the current
 	// working directory don't affect the node.
 	confCopy := *conf
@@ -0,0 +1,38 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/libevm/libevm/register"
+)
+
+func TestNewFreezesRegistration(t *testing.T) {
+	t.Cleanup(register.TestOnlyUnfreeze)
+
+	stack, err := New(testNodeConfig())
+	require.NoError(t, err, "New()")
+	t.Cleanup(func() { stack.Close() })
+
+	assert.True(t, register.Frozen(), "register.Frozen() after New()")
+	var late register.AtMostOnce[int]
+	assert.ErrorIs(t, late.Register(0), register.ErrFrozen, "registration after New()")
+}
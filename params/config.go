@@ -367,7 +367,7 @@ type ChainConfig struct {
 	Ethash *EthashConfig `json:"ethash,omitempty"`
 	Clique *CliqueConfig `json:"clique,omitempty"`
 
-	extra *pseudo.Type // See RegisterExtras()
+	extra atomicExtra // See RegisterExtras()
 }
 
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
@@ -742,6 +742,11 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.VerkleTime, newcfg.VerkleTime, headTimestamp) {
 		return newTimestampCompatError("Verkle fork timestamp", c.VerkleTime, newcfg.VerkleTime)
 	}
+	//libevm:start
+	if err := c.checkExtraForksCompatible(newcfg, headNumber, headTimestamp); err != nil {
+		return err
+	}
+	//libevm:end
 	return c.Hooks().CheckConfigCompatible(newcfg, headNumber, headTimestamp)
 }
 
@@ -914,7 +919,8 @@ type Rules struct {
 	IsMerge, IsShanghai, IsCancun, IsPrague                 bool
 	IsVerkle                                                bool
 
-	extra *pseudo.Type // See RegisterExtras()
+	extra      *pseudo.Type // See RegisterExtras()
+	activeEIPs []int        // libevm: see [Rules.ActiveEIPs]
 }
 
 // Rules ensures c's ChainID is not nil.
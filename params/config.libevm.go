@@ -1,4 +1,4 @@
-// Copyright 2024-2025 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -17,9 +17,11 @@
 package params
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"reflect"
+	"sync/atomic"
 
 	"github.com/ava-labs/libevm/libevm/pseudo"
 	"github.com/ava-labs/libevm/libevm/register"
@@ -123,6 +125,29 @@ func TestOnlyClearRegisteredExtras() {
 	registeredExtras.TestOnlyClear()
 }
 
+// RegisteredExtrasInfo describes the types registered with [RegisterExtras].
+// Types are represented as reported by the `%T` verb of the [fmt] package.
+type RegisteredExtrasInfo struct {
+	ChainConfig   string `json:"chainConfig"`
+	Rules         string `json:"rules"`
+	ReuseJSONRoot bool   `json:"reuseJSONRoot"`
+}
+
+// RegisteredExtras returns information about the types registered with
+// [RegisterExtras] and whether any have been registered at all.
+func RegisteredExtras() (RegisteredExtrasInfo, bool) {
+	r := registeredExtras
+	if !r.Registered() {
+		return RegisteredExtrasInfo{}, false
+	}
+	e := r.Get()
+	return RegisteredExtrasInfo{
+		ChainConfig:   fmt.Sprintf("%T", e.newChainConfig().Interface()),
+		Rules:         fmt.Sprintf("%T", e.newRules().Interface()),
+		ReuseJSONRoot: e.reuseJSONRoot,
+	}, true
+}
+
 // registeredExtras holds non-generic constructors for the [Extras] types
 // registered via [RegisterExtras].
 var registeredExtras register.AtMostOnce[*extraConstructors]
@@ -151,7 +176,7 @@ func (*Extras[C, R]) payloads() ExtraPayloads[C, R] {
 	return ExtraPayloads[C, R]{
 		ChainConfig: pseudo.NewAccessor[*ChainConfig, C](
 			(*ChainConfig).extraPayload,
-			func(c *ChainConfig, t *pseudo.Type) { c.extra = t },
+			(*ChainConfig).storeExtra,
 		),
 		Rules: pseudo.NewAccessor[*Rules, R](
 			(*Rules).extraPayload,
@@ -190,6 +215,41 @@ type ExtraPayloads[C ChainConfigHooks, R RulesHooks] struct {
 	Rules       pseudo.Accessor[*Rules, R]
 }
 
+// ErrExtrasNotRegistered is returned by [ChainConfigExtra] and [RulesExtra] if
+// [RegisterExtras] hasn't been called.
+var ErrExtrasNotRegistered = errors.New("params extras not registered")
+
+// ChainConfigExtra returns the extra payload carried by the [ChainConfig] as a
+// `C`, which MAY be an interface implemented by the registered type. Unlike
+// [ExtraPayloads.ChainConfig], it doesn't require access to the value returned
+// by [RegisterExtras], returning an error instead of panicking if the
+// registered type doesn't match `C`.
+func ChainConfigExtra[C any](c *ChainConfig) (C, error) {
+	if !registeredExtras.Registered() {
+		var zero C
+		return zero, ErrExtrasNotRegistered
+	}
+	return extraAs[C](c.extraPayload(), "ChainConfig")
+}
+
+// RulesExtra is the [Rules] equivalent of [ChainConfigExtra].
+func RulesExtra[R any](r Rules) (R, error) {
+	if !registeredExtras.Registered() {
+		var zero R
+		return zero, ErrExtrasNotRegistered
+	}
+	return extraAs[R](r.extraPayload(), "Rules")
+}
+
+func extraAs[T any](t *pseudo.Type, carrier string) (T, error) {
+	v, ok := t.Interface().(T)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%s extra of type %T requested as %s", carrier, t.Interface(), reflect.TypeOf(&zero).Elem())
+	}
+	return v, nil
+}
+
 // hooksFromChainConfig is equivalent to FromChainConfig(), but returns an
 // interface instead of the concrete type implementing it; this allows it to be
 // used in non-generic code.
@@ -206,8 +266,10 @@ func (e ExtraPayloads[C, R]) hooksFromRules(r *Rules) RulesHooks {
 // abstract the libevm-specific behaviour outside of original geth code.
 func (c *ChainConfig) addRulesExtra(r *Rules, blockNum *big.Int, isMerge bool, timestamp uint64) {
 	r.extra = nil
+	r.activeEIPs = nil
 	if registeredExtras.Registered() {
 		r.extra = registeredExtras.Get().newForRules(c, r, blockNum, isMerge, timestamp)
+		r.activeEIPs = activeEIPs(r.Hooks())
 	}
 }
 
@@ -223,10 +285,72 @@ func (c *ChainConfig) extraPayload() *pseudo.Type {
 		// See https://google.github.io/styleguide/go/best-practices#when-to-panic
 		panic(fmt.Sprintf("%T.ExtraPayload() called before RegisterExtras()", c))
 	}
-	if c.extra == nil {
-		c.extra = registeredExtras.Get().newChainConfig()
+	if t := c.extra.load(); t != nil {
+		return t
+	}
+	// Another goroutine may have constructed a payload since the load above,
+	// in which case it wins.
+	c.extra.compareAndSwap(nil, registeredExtras.Get().newChainConfig())
+	return c.extra.load()
+}
+
+// An atomicExtra holds a [ChainConfig]'s extra payload, which is treated as
+// copy-on-write: the [pseudo.Type] that it points to is never modified once
+// stored, only ever replaced. This allows ChainConfigs to be shared across
+// goroutines, without locking, while (rare) updaters install new payloads.
+//
+// It is equivalent to an [atomic.Pointer], which can't be used because its
+// embedded noCopy marker would cause `go vet` to reject the copying of
+// ChainConfig values that is common throughout geth. As with any copy of a
+// value that holds an atomic, a ChainConfig MUST NOT be copied while its
+// payload is being updated.
+type atomicExtra struct {
+	v atomic.Value // always a *pseudo.Type, if set
+}
+
+func (a *atomicExtra) load() *pseudo.Type {
+	t, _ := a.v.Load().(*pseudo.Type)
+	return t
+}
+
+func (a *atomicExtra) store(t *pseudo.Type) {
+	a.v.Store(t)
+}
+
+// compareAndSwap stores `updated` iff the current payload is `old`, reporting
+// whether it did so.
+func (a *atomicExtra) compareAndSwap(old, updated *pseudo.Type) bool {
+	// An [atomic.Value] that has never been stored to only compares equal to
+	// an untyped nil, not to a nil *pseudo.Type.
+	if old == nil && a.v.CompareAndSwap(nil, updated) {
+		return true
+	}
+	return a.v.CompareAndSwap(old, updated)
+}
+
+func (c *ChainConfig) storeExtra(t *pseudo.Type) {
+	c.extra.store(t)
+}
+
+// UpdateChainConfig atomically replaces the extra payload carried by the
+// [ChainConfig] with the value returned by `fn`, which receives the current
+// payload. If another goroutine replaces the payload while `fn` is running
+// then `fn` is called again with the newer payload, so it SHOULD be free of
+// side effects.
+//
+// Payloads are copy-on-write: concurrent readers retain the value that they
+// already received, so `fn` MUST NOT modify its argument, and MUST return a
+// modified copy instead if `C` is a pointer type. Similarly, values returned
+// by [pseudo.Accessor.GetPointer] MUST NOT be modified if the ChainConfig is
+// shared across goroutines; use [pseudo.Accessor.Set] or this method instead.
+func (e ExtraPayloads[C, R]) UpdateChainConfig(c *ChainConfig, fn func(C) C) {
+	for {
+		old := c.extraPayload()
+		updated := pseudo.From(fn(pseudo.MustNewValue[C](old).Get())).Type
+		if c.extra.compareAndSwap(old, updated) {
+			return
+		}
 	}
-	return c.extra
 }
 
 // extraPayload is equivalent to [ChainConfig.extraPayload].
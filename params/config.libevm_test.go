@@ -1,4 +1,4 @@
-// Copyright 2024 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -18,6 +18,7 @@ package params
 import (
 	"encoding/json"
 	"math/big"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -37,6 +38,13 @@ var _ interface {
 	json.Unmarshaler
 } = (*rawJSON)(nil)
 
+// extraOf returns an [atomicExtra] holding `t`, for use in [ChainConfig]
+// literals.
+func extraOf(t *pseudo.Type) (e atomicExtra) {
+	e.store(t)
+	return e
+}
+
 func TestRegisterExtras(t *testing.T) {
 	type (
 		ccExtraA struct {
@@ -121,7 +129,7 @@ func TestRegisterExtras(t *testing.T) {
 
 			input := &ChainConfig{
 				ChainID: big.NewInt(142857),
-				extra:   tt.ccExtra,
+				extra:   extraOf(tt.ccExtra),
 			}
 
 			buf, err := json.Marshal(input)
@@ -362,3 +370,122 @@ func assertRulesCopiedFromChainConfig[C ChainConfigHooks, R RulesHooks, Payload
 
 	assert.Equalf(t, val, getX(&rulesExtra), "%T.X copied from %T.X", rulesExtra, ccExtra)
 }
+
+func TestTypedExtraAccessors(t *testing.T) {
+	type (
+		ccExtra struct {
+			X int
+			NOOPHooks
+		}
+		rulesExtra struct {
+			Y int
+			NOOPHooks
+		}
+	)
+
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+
+	_, err := RulesExtra[*rulesExtra](Rules{})
+	require.ErrorIs(t, err, ErrExtrasNotRegistered, "RulesExtra() before registration")
+
+	extras := RegisterExtras(Extras[*ccExtra, *rulesExtra]{
+		NewRules: func(_ *ChainConfig, _ *Rules, c *ccExtra, _ *big.Int, _ bool, _ uint64) *rulesExtra {
+			return &rulesExtra{Y: c.X + 1}
+		},
+	})
+
+	config := new(ChainConfig)
+	extras.ChainConfig.Set(config, &ccExtra{X: 42})
+	rules := config.Rules(big.NewInt(0), false, 0)
+
+	gotC, err := ChainConfigExtra[*ccExtra](config)
+	require.NoError(t, err, "ChainConfigExtra[*ccExtra]()")
+	assert.Equal(t, 42, gotC.X, "ChainConfigExtra[*ccExtra]().X")
+
+	gotR, err := RulesExtra[*rulesExtra](rules)
+	require.NoError(t, err, "RulesExtra[*rulesExtra]()")
+	assert.Equal(t, 43, gotR.Y, "RulesExtra[*rulesExtra]().Y")
+
+	_, err = RulesExtra[RulesHooks](rules)
+	assert.NoError(t, err, "RulesExtra[RulesHooks]() with interface type")
+
+	_, err = RulesExtra[*ccExtra](rules)
+	assert.ErrorContains(t, err, "requested as *params.ccExtra", "RulesExtra() with mismatched type")
+}
+
+func TestChainConfigExtrasCopyOnWrite(t *testing.T) {
+	type ccExtra struct {
+		X int
+		NOOPHooks
+	}
+
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+	extras := RegisterExtras(Extras[*ccExtra, NOOPHooks]{})
+
+	config := new(ChainConfig)
+	extras.ChainConfig.Set(config, &ccExtra{X: 1})
+
+	before := extras.ChainConfig.Get(config)
+	extras.UpdateChainConfig(config, func(c *ccExtra) *ccExtra {
+		return &ccExtra{X: c.X + 1}
+	})
+	assert.Equal(t, 1, before.X, "payload received before UpdateChainConfig() is unchanged")
+	assert.Equal(t, 2, extras.ChainConfig.Get(config).X, "payload after UpdateChainConfig()")
+}
+
+// TestChainConfigExtrasConcurrency is only meaningful when run with the race
+// detector enabled.
+func TestChainConfigExtrasConcurrency(t *testing.T) {
+	type ccExtra struct {
+		X int `json:"x"`
+		NOOPHooks
+	}
+
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+	extras := RegisterExtras(Extras[*ccExtra, NOOPHooks]{})
+
+	// Deliberately leave the payload unset so lazy construction is also
+	// exercised concurrently.
+	config := new(ChainConfig)
+
+	const (
+		readers  = 8
+		updaters = 4
+		iters    = 100
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iters; j++ {
+				_ = extras.ChainConfig.Get(config)
+				_ = config.Rules(big.NewInt(0), false, 0)
+				_ = config.Hooks()
+				_, _ = ChainConfigExtra[*ccExtra](config)
+				_, _ = json.Marshal(config)
+			}
+		}()
+	}
+	for i := 0; i < updaters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iters; j++ {
+				extras.UpdateChainConfig(config, func(c *ccExtra) *ccExtra {
+					if c == nil {
+						return &ccExtra{X: 1}
+					}
+					return &ccExtra{X: c.X + 1}
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, updaters*iters, extras.ChainConfig.Get(config).X, "no updates lost")
+}
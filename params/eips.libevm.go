@@ -0,0 +1,109 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"fmt"
+	"slices"
+
+	"golang.org/x/exp/maps"
+)
+
+// An EIPActivator MAY be implemented by [RulesHooks] to enable individual EIPs
+// in addition to those of the named forks. Activated EIPs are applied, in
+// order, to the EVM's jump table after any vm.Config.ExtraEips, and those with
+// effects beyond the jump table are also reflected by the respective [Rules]
+// methods; e.g. [Rules.IsEIP2929].
+type EIPActivator interface {
+	ActiveEIPs() []int
+}
+
+// An EIPSchedule maps EIP numbers to the timestamps at which they are
+// activated, allowing individual EIPs to be enabled independently of the named
+// forks. It is intended to be carried by [ChainConfig] extras, with the result
+// of [EIPSchedule.Active] being returned by [EIPActivator.ActiveEIPs].
+//
+// Only EIPs supported by vm.EnableEIP() can be activated in this manner.
+type EIPSchedule map[int]uint64
+
+// Active returns the EIPs active at the timestamp, in ascending order.
+func (s EIPSchedule) Active(timestamp uint64) []int {
+	var active []int
+	for eip, at := range s {
+		if at <= timestamp {
+			active = append(active, eip)
+		}
+	}
+	slices.Sort(active)
+	return active
+}
+
+// CheckCompatible returns a non-nil error if changing from `s` to `newSchedule`
+// would alter the activation of any EIP at or before the head timestamp.
+func (s EIPSchedule) CheckCompatible(newSchedule EIPSchedule, headTimestamp uint64) *ConfigCompatError {
+	eips := append(maps.Keys(s), maps.Keys(newSchedule)...)
+	slices.Sort(eips)
+
+	for _, eip := range slices.Compact(eips) {
+		stored, updated := s.at(eip), newSchedule.at(eip)
+		if isForkTimestampIncompatible(stored, updated, headTimestamp) {
+			return newTimestampCompatError(fmt.Sprintf("EIP-%d activation timestamp", eip), stored, updated)
+		}
+	}
+	return nil
+}
+
+func (s EIPSchedule) at(eip int) *uint64 {
+	t, ok := s[eip]
+	if !ok {
+		return nil
+	}
+	return &t
+}
+
+// ActiveEIPs returns the EIPs enabled by an [EIPActivator], which MUST NOT be
+// modified. They are determined when the Rules are derived.
+func (r *Rules) ActiveEIPs() []int {
+	return r.activeEIPs
+}
+
+// IsEIP2929 reports whether access lists and the respective gas costs are in
+// effect, either because of the Berlin fork or via an [EIPActivator].
+func (r *Rules) IsEIP2929() bool {
+	return r.IsBerlin || r.eipActive(2929)
+}
+
+// IsEIP3860 reports whether the init-code size limit and the respective gas
+// costs are in effect, either because of the Shanghai fork or via an
+// [EIPActivator].
+func (r *Rules) IsEIP3860() bool {
+	return r.IsShanghai || r.eipActive(3860)
+}
+
+func (r *Rules) eipActive(eip int) bool {
+	return slices.Contains(r.activeEIPs, eip)
+}
+
+// activeEIPs returns the EIPs enabled by the hooks, if they implement
+// [EIPActivator].
+func activeEIPs(h RulesHooks) []int {
+	a, ok := h.(EIPActivator)
+	if !ok || isNilPointer(a) {
+		return nil
+	}
+	return a.ActiveEIPs()
+}
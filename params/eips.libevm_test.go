@@ -0,0 +1,91 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEIPSchedule(t *testing.T) {
+	s := EIPSchedule{
+		1153: 10,
+		5656: 20,
+		3855: 10,
+	}
+
+	t.Run("Active", func(t *testing.T) {
+		tests := map[uint64][]int{
+			0:  nil,
+			10: {1153, 3855},
+			19: {1153, 3855},
+			20: {1153, 3855, 5656},
+		}
+		for timestamp, want := range tests {
+			assert.Equalf(t, want, s.Active(timestamp), "%T.Active(%d)", s, timestamp)
+		}
+	})
+
+	t.Run("CheckCompatible", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			new     EIPSchedule
+			head    uint64
+			wantErr bool
+		}{
+			{
+				name: "unchanged",
+				new:  s,
+				head: 100,
+			},
+			{
+				name: "future_change",
+				new:  EIPSchedule{1153: 10, 5656: 30, 3855: 10},
+				head: 15,
+			},
+			{
+				name:    "past_change",
+				new:     EIPSchedule{1153: 10, 5656: 30, 3855: 10},
+				head:    25,
+				wantErr: true,
+			},
+			{
+				name:    "removal_of_active",
+				new:     EIPSchedule{5656: 20, 3855: 10},
+				head:    15,
+				wantErr: true,
+			},
+			{
+				name: "addition_in_future",
+				new:  EIPSchedule{1153: 10, 5656: 20, 3855: 10, 6780: 50},
+				head: 25,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := s.CheckCompatible(tt.new, tt.head)
+				if tt.wantErr {
+					assert.NotNil(t, err)
+				} else {
+					assert.Nil(t, err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"math/big"
+)
+
+// An ExtraForker MAY be implemented by [ChainConfigHooks] to define forks in
+// addition to those of geth, which are included in [ChainConfig.CheckCompatible]
+// before [ChainConfigHooks.CheckConfigCompatible] is called.
+type ExtraForker interface {
+	// ExtraForks returns the forks, which are matched between configs by
+	// their names.
+	ExtraForks() []ExtraFork
+}
+
+// An ExtraFork is a fork defined by [ChainConfig] extras, returned by
+// [ExtraForker.ExtraForks]. Exactly one of Block or Timestamp SHOULD be
+// non-nil for scheduled forks; both being nil signals that the fork is
+// unscheduled.
+type ExtraFork struct {
+	Name      string
+	Block     *big.Int
+	Timestamp *uint64
+}
+
+// ExtraForks returns the forks defined by the config's extras, which is nil if
+// they don't implement [ExtraForker] or if the config carries no extras.
+func (c *ChainConfig) ExtraForks() []ExtraFork {
+	f, ok := c.Hooks().(ExtraForker)
+	if !ok || isNilPointer(f) {
+		return nil
+	}
+	return f.ExtraForks()
+}
+
+// checkExtraForksCompatible is the [ExtraForker] equivalent of
+// the geth-defined fork checks in [ChainConfig.checkCompatible]. Forks are
+// matched by name, with those absent from either config being treated as
+// unscheduled; a conflict is reported for the first incompatible fork in the
+// order returned by the stored config, followed by any only in the new one.
+func (c *ChainConfig) checkExtraForksCompatible(newcfg *ChainConfig, headNumber *big.Int, headTimestamp uint64) *ConfigCompatError {
+	stored := c.ExtraForks()
+	fresh := newcfg.ExtraForks()
+	updated := make(map[string]ExtraFork)
+	for _, f := range fresh {
+		updated[f.Name] = f
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range stored {
+		seen[s.Name] = true
+		u, ok := updated[s.Name]
+		if !ok {
+			u = ExtraFork{Name: s.Name}
+		}
+		if err := checkExtraForkCompatible(s, u, headNumber, headTimestamp); err != nil {
+			return err
+		}
+	}
+	for _, u := range fresh {
+		if seen[u.Name] {
+			continue
+		}
+		if err := checkExtraForkCompatible(ExtraFork{Name: u.Name}, u, headNumber, headTimestamp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkExtraForkCompatible returns a non-nil error if changing the stored fork
+// `s` to `u` would alter its activation at or before the head.
+func checkExtraForkCompatible(s, u ExtraFork, headNumber *big.Int, headTimestamp uint64) *ConfigCompatError {
+	if isForkBlockIncompatible(s.Block, u.Block, headNumber) {
+		return newBlockCompatError(s.Name+" fork block", s.Block, u.Block)
+	}
+	if isForkTimestampIncompatible(s.Timestamp, u.Timestamp, headTimestamp) {
+		return newTimestampCompatError(s.Name+" fork timestamp", s.Timestamp, u.Timestamp)
+	}
+	return nil
+}
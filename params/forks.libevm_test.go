@@ -0,0 +1,97 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type extraForksConfig struct {
+	NOOPHooks
+	FooTime *uint64
+}
+
+func (c *extraForksConfig) ExtraForks() []ExtraFork {
+	return []ExtraFork{{Name: "Foo", Timestamp: c.FooTime}}
+}
+
+func TestExtraForksCompatible(t *testing.T) {
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+	extras := RegisterExtras(Extras[*extraForksConfig, NOOPHooks]{})
+
+	config := func(fooTime *uint64) *ChainConfig {
+		c := new(ChainConfig)
+		extras.ChainConfig.Set(c, &extraForksConfig{FooTime: fooTime})
+		return c
+	}
+
+	tests := []struct {
+		name            string
+		stored, updated *uint64
+		headTime        uint64
+		wantRewindTo    uint64 // 0 means no error expected
+	}{
+		{
+			name:     "unchanged",
+			stored:   newUint64(10),
+			updated:  newUint64(10),
+			headTime: 100,
+		},
+		{
+			name:     "changed_before_activation",
+			stored:   newUint64(10),
+			updated:  newUint64(20),
+			headTime: 5,
+		},
+		{
+			name:         "changed_after_activation",
+			stored:       newUint64(10),
+			updated:      newUint64(20),
+			headTime:     15,
+			wantRewindTo: 9,
+		},
+		{
+			name:         "newly_scheduled_in_past",
+			updated:      newUint64(10),
+			headTime:     15,
+			wantRewindTo: 9,
+		},
+		{
+			name:         "unscheduled_after_activation",
+			stored:       newUint64(10),
+			headTime:     15,
+			wantRewindTo: 9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := config(tt.stored).CheckCompatible(config(tt.updated), 0, tt.headTime)
+			if tt.wantRewindTo == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, "Foo fork timestamp", err.What, "ConfigCompatError.What")
+			assert.Equal(t, tt.wantRewindTo, err.RewindToTime, "ConfigCompatError.RewindToTime")
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright 2024-2025 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -18,6 +18,7 @@ package params
 
 import (
 	"math/big"
+	"reflect"
 
 	"github.com/ava-labs/libevm/common"
 	"github.com/ava-labs/libevm/libevm"
@@ -50,10 +51,13 @@ type RulesHooks interface {
 	// precompile behaviour is honoured.
 	PrecompileOverride(common.Address) (_ libevm.PrecompiledContract, override bool)
 	// ActivePrecompiles receives the addresses that would usually be returned
-	// by a call to [vm.ActivePrecompiles] and MUST return the value to be
-	// returned by said function, which will be propagated. It MAY alter the
-	// received slice. The value it returns MUST be consistent with the
-	// behaviour of the PrecompileOverride hook.
+	// by a call to [vm.ActivePrecompiles], in ascending order, and MUST return
+	// the value to be returned by said function, which will be propagated. It
+	// MAY alter the received slice. The returned addresses MAY be in any order
+	// and MAY contain duplicates as [vm.ActivePrecompiles] sorts and
+	// deduplicates them, without modifying the returned slice. The value it
+	// returns MUST be consistent with the behaviour of the PrecompileOverride
+	// hook.
 	ActivePrecompiles([]common.Address) []common.Address
 	// MinimumGasConsumption receives a transaction's gas limit and returns the
 	// minimum quantity of gas units to be charged for said transaction. If the
@@ -144,3 +148,12 @@ func (NOOPHooks) ActivePrecompiles(active []common.Address) []common.Address {
 func (NOOPHooks) MinimumGasConsumption(uint64) uint64 {
 	return 0
 }
+
+// isNilPointer reports whether `x` is a nil pointer, which is the case for
+// hooks carried by a [ChainConfig] or [Rules] without a payload when the
+// registered type is a pointer. Such hooks can't be used as receivers of
+// optional interfaces, which have no [NOOPHooks] equivalent.
+func isNilPointer(x any) bool {
+	v := reflect.ValueOf(x)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package params
+
+// An InvalidationDisposition determines how the state transition treats a
+// transaction whose execution was invalidated by a call to
+// [vm.EVM.InvalidateExecution].
+//
+// [vm.EVM.InvalidateExecution]: https://pkg.go.dev/github.com/ava-labs/libevm/core/vm#EVM.InvalidateExecution
+type InvalidationDisposition uint8
+
+const (
+	// RejectInvalidatedTx voids the transaction, reverting all of its state
+	// changes, including nonce incrementing and gas purchase, such that it
+	// MUST NOT be included in a block. This is the default disposition.
+	RejectInvalidatedTx InvalidationDisposition = iota
+	// RevertInvalidatedTx reverts the transaction's execution, consuming all
+	// of its gas, but otherwise treats it as included; i.e. the nonce is
+	// incremented and fees are paid.
+	RevertInvalidatedTx
+	// FailInvalidatedBlock treats the invalidation as a consensus error, which
+	// renders the entire block invalid.
+	FailInvalidatedBlock
+)
+
+// String returns a human-readable name of the disposition.
+func (d InvalidationDisposition) String() string {
+	switch d {
+	case RejectInvalidatedTx:
+		return "reject tx"
+	case RevertInvalidatedTx:
+		return "revert tx"
+	case FailInvalidatedBlock:
+		return "fail block"
+	default:
+		return "unknown"
+	}
+}
+
+// ExecutionInvalidationHooks are optional extensions of [RulesHooks] that
+// determine the semantics of invalidated execution. They are only used if the
+// [RulesHooks] returned by the registered [Extras] also implement this
+// interface, otherwise [RejectInvalidatedTx] is used.
+type ExecutionInvalidationHooks interface {
+	// InvalidationDisposition receives the error passed to
+	// [vm.EVM.InvalidateExecution], which is typically a
+	// [vm.InvalidationError], and returns how the transaction is to be
+	// treated. It MUST be deterministic as it MAY be called more than once for
+	// the same transaction.
+	//
+	// [vm.EVM.InvalidateExecution]: https://pkg.go.dev/github.com/ava-labs/libevm/core/vm#EVM.InvalidateExecution
+	// [vm.InvalidationError]: https://pkg.go.dev/github.com/ava-labs/libevm/core/vm#InvalidationError
+	InvalidationDisposition(error) InvalidationDisposition
+}
+
+// InvalidationDisposition returns the value returned by the
+// [ExecutionInvalidationHooks] implemented by the [RulesHooks], if any, or
+// [RejectInvalidatedTx].
+func (r *Rules) InvalidationDisposition(err error) InvalidationDisposition {
+	h, ok := r.Hooks().(ExecutionInvalidationHooks)
+	if !ok || isNilPointer(h) {
+		return RejectInvalidatedTx
+	}
+	return h.InvalidationDisposition(err)
+}
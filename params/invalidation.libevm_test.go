@@ -0,0 +1,46 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type invalidationRules struct {
+	NOOPHooks
+	disposition InvalidationDisposition
+}
+
+func (r *invalidationRules) InvalidationDisposition(error) InvalidationDisposition {
+	return r.disposition
+}
+
+func TestInvalidationDispositionNilHooks(t *testing.T) {
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+	// Without a NewRules() function, the hooks are a nil pointer.
+	RegisterExtras(Extras[NOOPHooks, *invalidationRules]{})
+
+	rules := (&ChainConfig{ChainID: big.NewInt(1)}).Rules(big.NewInt(0), false, 0)
+	var got InvalidationDisposition
+	assert.NotPanics(t, func() { got = rules.InvalidationDisposition(errors.New("")) })
+	assert.Equal(t, RejectInvalidatedTx, got)
+}
@@ -1,4 +1,4 @@
-// Copyright 2024-2025 the libevm authors.
+// Copyright 2024-2026 the libevm authors.
 //
 // The libevm additions to go-ethereum are free software: you can redistribute
 // them and/or modify them under the terms of the GNU Lesser General Public License
@@ -39,8 +39,12 @@ func (c *ChainConfig) UnmarshalJSON(data []byte) (err error) {
 		return json.Unmarshal(data, (*chainConfigWithoutMethods)(c))
 	}
 	ec := registeredExtras.Get()
-	c.extra = ec.newChainConfig()
-	return UnmarshalChainConfigJSON(data, c, c.extra, ec.reuseJSONRoot)
+	extra := ec.newChainConfig()
+	if err := UnmarshalChainConfigJSON(data, c, extra, ec.reuseJSONRoot); err != nil {
+		return err
+	}
+	c.storeExtra(extra)
+	return nil
 }
 
 // UnmarshalChainConfigJSON is equivalent to [ChainConfig.UnmarshalJSON]
@@ -83,16 +87,22 @@ func (c *ChainConfig) MarshalJSON() ([]byte, error) {
 		return json.Marshal((*chainConfigWithoutMethods)(c))
 	}
 	ec := registeredExtras.Get()
-	return MarshalChainConfigJSON(*c, c.extra, ec.reuseJSONRoot)
+	// Unlike MarshalChainConfigJSON(), this doesn't copy `c`, which would race
+	// with concurrent updates of its extra payload.
+	return marshalChainConfigJSON((*chainConfigWithoutMethods)(c), c.extra.load(), ec.reuseJSONRoot)
 }
 
 // MarshalChainConfigJSON is equivalent to [ChainConfig.MarshalJSON]
 // had [Extras] with `C` been registered, but without the need to
 // call [RegisterExtras].
 func MarshalChainConfigJSON[C any](config ChainConfig, extra C, reuseJSONRoot bool) (data []byte, err error) {
+	return marshalChainConfigJSON((*chainConfigWithoutMethods)(&config), extra, reuseJSONRoot)
+}
+
+func marshalChainConfigJSON[C any](config *chainConfigWithoutMethods, extra C, reuseJSONRoot bool) (data []byte, err error) {
 	if !reuseJSONRoot {
 		jsonExtra := struct {
-			ChainConfig
+			*chainConfigWithoutMethods
 			Extra C `json:"extra,omitempty"`
 		}{
 			config,
@@ -100,7 +110,7 @@ func MarshalChainConfigJSON[C any](config ChainConfig, extra C, reuseJSONRoot bo
 		}
 		data, err = json.Marshal(jsonExtra)
 		if err != nil {
-			return nil, fmt.Errorf(`encoding combination of %T and %T (as "extra" key) to JSON: %s`, config, extra, err)
+			return nil, fmt.Errorf(`encoding combination of %T and %T (as "extra" key) to JSON: %s`, (*ChainConfig)(config), extra, err)
 		}
 		return data, nil
 	}
@@ -110,7 +120,7 @@ func MarshalChainConfigJSON[C any](config ChainConfig, extra C, reuseJSONRoot bo
 	// map[string]json.RawMessage intermediates.
 	// Note we cannot encode a combined struct directly because of the extra
 	// type generic nature which cannot be embedded in such a combined struct.
-	configJSONRaw, err := toJSONRawMessages((chainConfigWithoutMethods)(config))
+	configJSONRaw, err := toJSONRawMessages(config)
 	if err != nil {
 		return nil, fmt.Errorf("converting config to JSON raw messages: %s", err)
 	}
@@ -69,7 +69,7 @@ func TestChainConfigJSONRoundTrip(t *testing.T) {
 			}`,
 			want: &ChainConfig{
 				ChainID: big.NewInt(5678),
-				extra:   pseudo.From(rootJSONChainConfigExtra{TopLevelFoo: "hello"}).Type,
+				extra:   extraOf(pseudo.From(rootJSONChainConfigExtra{TopLevelFoo: "hello"}).Type),
 			},
 		},
 		{
@@ -85,7 +85,7 @@ func TestChainConfigJSONRoundTrip(t *testing.T) {
 			}`,
 			want: &ChainConfig{
 				ChainID: big.NewInt(5678),
-				extra:   pseudo.From(&rootJSONChainConfigExtra{TopLevelFoo: "hello"}).Type,
+				extra:   extraOf(pseudo.From(&rootJSONChainConfigExtra{TopLevelFoo: "hello"}).Type),
 			},
 		},
 		{
@@ -101,7 +101,7 @@ func TestChainConfigJSONRoundTrip(t *testing.T) {
 			}`,
 			want: &ChainConfig{
 				ChainID: big.NewInt(42),
-				extra:   pseudo.From(nestedChainConfigExtra{NestedFoo: "world"}).Type,
+				extra:   extraOf(pseudo.From(nestedChainConfigExtra{NestedFoo: "world"}).Type),
 			},
 		},
 		{
@@ -117,7 +117,7 @@ func TestChainConfigJSONRoundTrip(t *testing.T) {
 			}`,
 			want: &ChainConfig{
 				ChainID: big.NewInt(42),
-				extra:   pseudo.From(&nestedChainConfigExtra{NestedFoo: "world"}).Type,
+				extra:   extraOf(pseudo.From(&nestedChainConfigExtra{NestedFoo: "world"}).Type),
 			},
 		},
 	}
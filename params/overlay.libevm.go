@@ -0,0 +1,130 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/naoina/toml"
+)
+
+// A ConfigOverlay is a partial [ChainConfig], applied as a JSON merge patch
+// (RFC 7396) to the JSON encoding of a full config, including any registered
+// extras. This allows operators to adjust parameters, such as upcoming fork
+// timestamps, without rebuilding binaries. As with all merge patches, a null
+// value removes the respective field.
+//
+// Compatibility of the resulting config with that stored in a database is
+// checked by core.SetupGenesisBlockWithOverride(), just as for other overrides.
+type ConfigOverlay struct {
+	patch map[string]any
+}
+
+// ParseConfigOverlay parses a JSON-encoded [ConfigOverlay], which MUST be an
+// object.
+func ParseConfigOverlay(buf []byte) (*ConfigOverlay, error) {
+	patch, err := decodeJSONObject(buf)
+	if err != nil {
+		return nil, fmt.Errorf("parsing config overlay: %v", err)
+	}
+	return &ConfigOverlay{patch}, nil
+}
+
+// LoadConfigOverlay reads a [ConfigOverlay] from a file. Files with a `.toml`
+// extension are parsed as TOML, using the same keys as the JSON encoding of a
+// [ChainConfig], and all others as JSON.
+func LoadConfigOverlay(path string) (*ConfigOverlay, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(filepath.Ext(path), ".toml") {
+		return ParseConfigOverlay(buf)
+	}
+
+	var patch map[string]any
+	if err := toml.Unmarshal(buf, &patch); err != nil {
+		return nil, fmt.Errorf("parsing TOML config overlay %q: %v", path, err)
+	}
+	return &ConfigOverlay{patch}, nil
+}
+
+// Apply modifies `c` in place, returning an error if the overlay can't be
+// applied or if the resulting config fails [ChainConfig.CheckConfigForkOrder].
+// `c` is only modified if no error is returned.
+func (o *ConfigOverlay) Apply(c *ChainConfig) error {
+	orig, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("encoding %T to JSON: %v", c, err)
+	}
+	doc, err := decodeJSONObject(orig)
+	if err != nil {
+		return fmt.Errorf("decoding %T JSON: %v", c, err)
+	}
+
+	patched, err := json.Marshal(mergePatch(doc, o.patch))
+	if err != nil {
+		return fmt.Errorf("encoding overlaid %T: %v", c, err)
+	}
+	updated := new(ChainConfig)
+	if err := json.Unmarshal(patched, updated); err != nil {
+		return fmt.Errorf("decoding overlaid %T: %v", c, err)
+	}
+	if err := updated.CheckConfigForkOrder(); err != nil {
+		return fmt.Errorf("overlaid %T: %w", c, err)
+	}
+
+	*c = *updated
+	return nil
+}
+
+// decodeJSONObject decodes the buffer, retaining full numerical precision.
+func decodeJSONObject(buf []byte) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// mergePatch implements the MergePatch function of RFC 7396. It may modify
+// `target`.
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any)
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+	return t
+}
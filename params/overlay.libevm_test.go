@@ -0,0 +1,133 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// overlayTestConfig returns a new config with valid fork ordering.
+func overlayTestConfig() *ChainConfig {
+	return &ChainConfig{
+		ChainID:                       big.NewInt(42),
+		HomesteadBlock:                big.NewInt(0),
+		EIP150Block:                   big.NewInt(0),
+		EIP155Block:                   big.NewInt(0),
+		EIP158Block:                   big.NewInt(0),
+		ByzantiumBlock:                big.NewInt(0),
+		ConstantinopleBlock:           big.NewInt(0),
+		PetersburgBlock:               big.NewInt(0),
+		IstanbulBlock:                 big.NewInt(0),
+		BerlinBlock:                   big.NewInt(0),
+		LondonBlock:                   big.NewInt(0),
+		TerminalTotalDifficulty:       big.NewInt(0),
+		TerminalTotalDifficultyPassed: true,
+		ShanghaiTime:                  newUint64(0),
+		CancunTime:                    newUint64(100),
+	}
+}
+
+func TestConfigOverlay(t *testing.T) {
+	tests := []struct {
+		name    string
+		overlay string
+		want    func(*ChainConfig)
+		wantErr bool
+	}{
+		{
+			name:    "change_fork_time",
+			overlay: `{"cancunTime": 200}`,
+			want: func(c *ChainConfig) {
+				c.CancunTime = newUint64(200)
+			},
+		},
+		{
+			name:    "remove_fork",
+			overlay: `{"cancunTime": null}`,
+			want: func(c *ChainConfig) {
+				c.CancunTime = nil
+			},
+		},
+		{
+			name:    "invalid_fork_order",
+			overlay: `{"shanghaiTime": null}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid_type",
+			overlay: `{"cancunTime": "soon"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := ParseConfigOverlay([]byte(tt.overlay))
+			require.NoError(t, err, "ParseConfigOverlay()")
+
+			got := overlayTestConfig()
+			err = o.Apply(got)
+			want := overlayTestConfig()
+			if tt.wantErr {
+				require.Error(t, err, "%T.Apply()", o)
+			} else {
+				require.NoError(t, err, "%T.Apply()", o)
+				tt.want(want)
+			}
+			// Comparison via JSON avoids false negatives due to [big.Int]
+			// internals.
+			assert.JSONEq(t, toJSON(t, want), toJSON(t, got), "%T after %T.Apply()", got, o)
+		})
+	}
+}
+
+func TestLoadConfigOverlay(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"overlay.json": `{"cancunTime": 200}`,
+		"overlay.toml": `cancunTime = 200`,
+	}
+
+	for name, contents := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(path, []byte(contents), 0o600), "os.WriteFile()")
+
+			o, err := LoadConfigOverlay(path)
+			require.NoErrorf(t, err, "LoadConfigOverlay(%q)", path)
+
+			c := overlayTestConfig()
+			require.NoError(t, o.Apply(c), "%T.Apply()", o)
+			require.NotNil(t, c.CancunTime, "CancunTime")
+			assert.Equal(t, uint64(200), *c.CancunTime, "CancunTime")
+		})
+	}
+}
+
+func toJSON(t *testing.T, v any) string {
+	t.Helper()
+	buf, err := json.Marshal(v)
+	require.NoErrorf(t, err, "json.Marshal(%T)", v)
+	return string(buf)
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// An ExtraForkScheduler is an optional extension of [ChainConfigHooks],
+// allowing forks returned by [ExtraForker] to be rescheduled with
+// [ChainConfig.PostponeExtraFork] and [ChainConfig.UnscheduleExtraFork]. As the
+// hooks are the [ChainConfig] extra payload, they MUST be modified in place.
+type ExtraForkScheduler interface {
+	// SetExtraFork reschedules the fork with the same name to that of the
+	// argument, which MAY be unscheduled. It is only called by libevm after
+	// validating the change.
+	SetExtraFork(ExtraFork) error
+}
+
+// Errors returned when rescheduling extras-defined forks.
+var (
+	ErrExtraForksNotReschedulable = errors.New("chain config hooks don't implement params.ExtraForkScheduler")
+	ErrUnknownExtraFork           = errors.New("unknown extra fork")
+	ErrNotPostponement            = errors.New("not a postponement")
+)
+
+// PostponeExtraFork reschedules the extras-defined fork with the same name as
+// `to`, as returned by [ChainConfig.ExtraForks], to the later block or
+// timestamp of `to`. The fork MUST already be scheduled, by the same criterion
+// (i.e. block or timestamp), otherwise [ErrNotPostponement] is returned. See
+// [ChainConfig.UnscheduleExtraFork] for other requirements and the treatment of
+// forks that are already active.
+func (c *ChainConfig) PostponeExtraFork(to ExtraFork, headNumber *big.Int, headTimestamp uint64) error {
+	from, err := c.extraFork(to.Name)
+	if err != nil {
+		return err
+	}
+
+	var later bool
+	switch {
+	case to.Block != nil && to.Timestamp != nil:
+		return fmt.Errorf("%w: %q rescheduled by both block and timestamp", ErrNotPostponement, to.Name)
+	case to.Block != nil && from.Block != nil:
+		later = to.Block.Cmp(from.Block) > 0
+	case to.Timestamp != nil && from.Timestamp != nil:
+		later = *to.Timestamp > *from.Timestamp
+	}
+	if !later {
+		return fmt.Errorf("%w: %q from %s to %s", ErrNotPostponement, to.Name, from.schedule(), to.schedule())
+	}
+	return c.rescheduleExtraFork(from, to, headNumber, headTimestamp)
+}
+
+// UnscheduleExtraFork unschedules the named extras-defined fork, as returned by
+// [ChainConfig.ExtraForks], which MUST implement [ExtraForkScheduler]. The
+// config MUST still satisfy [ChainConfig.CheckConfigForkOrder] after the change,
+// otherwise it is reverted and the error returned.
+//
+// A fork that is active at the head, given by the block number and timestamp
+// of the chain's current block, can't be rescheduled without rewinding the
+// chain. In this case the config is unchanged and the returned error is the
+// [ConfigCompatError] that [ChainConfig.CheckCompatible] would report, its
+// RewindTo fields denoting the point to which the chain MUST be rewound before
+// rescheduling is retried with the new head.
+func (c *ChainConfig) UnscheduleExtraFork(name string, headNumber *big.Int, headTimestamp uint64) error {
+	from, err := c.extraFork(name)
+	if err != nil {
+		return err
+	}
+	return c.rescheduleExtraFork(from, ExtraFork{Name: name}, headNumber, headTimestamp)
+}
+
+func (c *ChainConfig) extraFork(name string) (ExtraFork, error) {
+	for _, f := range c.ExtraForks() {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	return ExtraFork{}, fmt.Errorf("%w: %q", ErrUnknownExtraFork, name)
+}
+
+func (c *ChainConfig) rescheduleExtraFork(from, to ExtraFork, headNumber *big.Int, headTimestamp uint64) error {
+	s, ok := c.Hooks().(ExtraForkScheduler)
+	if !ok {
+		return ErrExtraForksNotReschedulable
+	}
+	if err := checkExtraForkCompatible(from, to, headNumber, headTimestamp); err != nil {
+		return err
+	}
+
+	if err := s.SetExtraFork(to); err != nil {
+		return fmt.Errorf("%T.SetExtraFork(%q): %w", s, to.Name, err)
+	}
+	var err error
+	if got, _ := c.extraFork(to.Name); got.schedule() != to.schedule() {
+		err = fmt.Errorf("%T.SetExtraFork(%q) had no effect; MUST modify hooks in place", s, to.Name)
+	} else if err = c.CheckConfigForkOrder(); err == nil {
+		return nil
+	}
+	if rErr := s.SetExtraFork(from); rErr != nil {
+		return fmt.Errorf("%w; restoring original %q schedule: %v", err, from.Name, rErr)
+	}
+	return err
+}
+
+// schedule returns a human-readable description of when the fork is
+// scheduled.
+func (f ExtraFork) schedule() string {
+	switch {
+	case f.Block != nil && f.Timestamp != nil:
+		return fmt.Sprintf("block %v and timestamp %d", f.Block, *f.Timestamp)
+	case f.Block != nil:
+		return fmt.Sprintf("block %v", f.Block)
+	case f.Timestamp != nil:
+		return fmt.Sprintf("timestamp %d", *f.Timestamp)
+	default:
+		return "unscheduled"
+	}
+}
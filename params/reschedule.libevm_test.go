@@ -0,0 +1,179 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package params
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schedulableForksConfig has two timestamp-scheduled forks, Foo and Bar, the
+// latter of which MUST NOT be scheduled before the former.
+type schedulableForksConfig struct {
+	NOOPHooks
+	FooTime, BarTime *uint64
+}
+
+var errBarBeforeFoo = errors.New("Bar before Foo")
+
+func (c *schedulableForksConfig) ExtraForks() []ExtraFork {
+	return []ExtraFork{
+		{Name: "Foo", Timestamp: c.FooTime},
+		{Name: "Bar", Timestamp: c.BarTime},
+	}
+}
+
+func (c *schedulableForksConfig) CheckConfigForkOrder() error {
+	if c.BarTime != nil && (c.FooTime == nil || *c.BarTime < *c.FooTime) {
+		return errBarBeforeFoo
+	}
+	return nil
+}
+
+func (c *schedulableForksConfig) SetExtraFork(f ExtraFork) error {
+	switch f.Name {
+	case "Foo":
+		c.FooTime = f.Timestamp
+	case "Bar":
+		c.BarTime = f.Timestamp
+	}
+	return nil
+}
+
+func TestRescheduleExtraFork(t *testing.T) {
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+	extras := RegisterExtras(Extras[*schedulableForksConfig, NOOPHooks]{})
+
+	tests := []struct {
+		name           string
+		reschedule     func(*ChainConfig) error
+		headTime       uint64
+		wantErr        error
+		wantRewindTo   uint64
+		wantFoo        *uint64
+		wantBarUnsched bool
+	}{
+		{
+			name: "postpone",
+			reschedule: func(c *ChainConfig) error {
+				return c.PostponeExtraFork(ExtraFork{Name: "Foo", Timestamp: newUint64(15)}, nil, 5)
+			},
+			headTime: 5,
+			wantFoo:  newUint64(15),
+		},
+		{
+			name: "postpone_earlier",
+			reschedule: func(c *ChainConfig) error {
+				return c.PostponeExtraFork(ExtraFork{Name: "Foo", Timestamp: newUint64(8)}, nil, 5)
+			},
+			wantErr: ErrNotPostponement,
+			wantFoo: newUint64(10),
+		},
+		{
+			name: "postpone_by_block",
+			reschedule: func(c *ChainConfig) error {
+				return c.PostponeExtraFork(ExtraFork{Name: "Foo", Block: big.NewInt(100)}, nil, 5)
+			},
+			wantErr: ErrNotPostponement,
+			wantFoo: newUint64(10),
+		},
+		{
+			name: "postpone_past_dependent_fork",
+			reschedule: func(c *ChainConfig) error {
+				return c.PostponeExtraFork(ExtraFork{Name: "Foo", Timestamp: newUint64(25)}, nil, 5)
+			},
+			wantErr: errBarBeforeFoo,
+			wantFoo: newUint64(10),
+		},
+		{
+			name: "postpone_after_activation",
+			reschedule: func(c *ChainConfig) error {
+				return c.PostponeExtraFork(ExtraFork{Name: "Foo", Timestamp: newUint64(15)}, nil, 12)
+			},
+			wantRewindTo: 9,
+			wantFoo:      newUint64(10),
+		},
+		{
+			name: "unschedule",
+			reschedule: func(c *ChainConfig) error {
+				return c.UnscheduleExtraFork("Bar", nil, 5)
+			},
+			wantFoo:        newUint64(10),
+			wantBarUnsched: true,
+		},
+		{
+			name: "unschedule_with_dependent_fork",
+			reschedule: func(c *ChainConfig) error {
+				return c.UnscheduleExtraFork("Foo", nil, 5)
+			},
+			wantErr: errBarBeforeFoo,
+			wantFoo: newUint64(10),
+		},
+		{
+			name: "unknown",
+			reschedule: func(c *ChainConfig) error {
+				return c.UnscheduleExtraFork("Baz", nil, 5)
+			},
+			wantErr: ErrUnknownExtraFork,
+			wantFoo: newUint64(10),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks := &schedulableForksConfig{
+				FooTime: newUint64(10),
+				BarTime: newUint64(20),
+			}
+			c := new(ChainConfig)
+			extras.ChainConfig.Set(c, hooks)
+
+			err := tt.reschedule(c)
+			switch {
+			case tt.wantRewindTo != 0:
+				var compat *ConfigCompatError
+				require.ErrorAs(t, err, &compat)
+				assert.Equal(t, tt.wantRewindTo, compat.RewindToTime, "ConfigCompatError.RewindToTime")
+			default:
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+
+			assert.Equal(t, tt.wantFoo, hooks.FooTime, "Foo fork timestamp")
+			if tt.wantBarUnsched {
+				assert.Nil(t, hooks.BarTime, "Bar fork timestamp")
+			} else {
+				assert.Equal(t, newUint64(20), hooks.BarTime, "Bar fork timestamp")
+			}
+		})
+	}
+}
+
+func TestRescheduleExtraForkNotSupported(t *testing.T) {
+	TestOnlyClearRegisteredExtras()
+	t.Cleanup(TestOnlyClearRegisteredExtras)
+	extras := RegisterExtras(Extras[*extraForksConfig, NOOPHooks]{})
+
+	c := new(ChainConfig)
+	extras.ChainConfig.Set(c, &extraForksConfig{FooTime: newUint64(10)})
+	err := c.UnscheduleExtraFork("Foo", nil, 0)
+	assert.ErrorIs(t, err, ErrExtraForksNotReschedulable, "UnscheduleExtraFork()")
+}
@@ -299,6 +299,7 @@ func (t *StateTest) RunNoVerify(subtest StateSubtest, vmconfig vm.Config, snapsh
 	gaspool := new(core.GasPool)
 	gaspool.AddGas(block.GasLimit())
 	_, err = core.ApplyMessage(evm, msg, gaspool)
+	evm.Finish() // libevm
 	if err != nil {
 		state.StateDB.RevertToSnapshot(snapshot)
 	}
@@ -164,6 +164,8 @@ func (set *NodeSet) Summary() string {
 // MergedNodeSet represents a merged node set for a group of tries.
 type MergedNodeSet struct {
 	Sets map[common.Hash]*NodeSet
+
+	externalRefs map[common.Hash]common.Hash // libevm: see [MergedNodeSet.AddExternalReference]
 }
 
 // NewMergedNodeSet initializes an empty merged set.
@@ -0,0 +1,36 @@
+// Copyright 2026 the libevm authors.
+//
+// The libevm additions to go-ethereum are free software: you can redistribute
+// them and/or modify them under the terms of the GNU Lesser General Public License
+// as published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The libevm additions are distributed in the hope that they will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package trienode
+
+import "github.com/ava-labs/libevm/common"
+
+// AddExternalReference records that the trie with root `child` is referenced
+// by the node with hash `parent` in another trie, in the same way that storage
+// tries are referenced by account leaves. This allows hash-based databases to
+// retain the child trie for as long as the parent node.
+func (set *MergedNodeSet) AddExternalReference(child, parent common.Hash) {
+	if set.externalRefs == nil {
+		set.externalRefs = make(map[common.Hash]common.Hash)
+	}
+	set.externalRefs[child] = parent
+}
+
+// ExternalReferences returns all references recorded by
+// [MergedNodeSet.AddExternalReference], keyed by child root.
+func (set *MergedNodeSet) ExternalReferences() map[common.Hash]common.Hash {
+	return set.externalRefs
+}
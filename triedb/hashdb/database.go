@@ -597,6 +597,13 @@ func (db *Database) Update(root common.Hash, parent common.Hash, block uint64, n
 			}
 		}
 	}
+	//libevm:start
+	for child, parent := range nodes.ExternalReferences() {
+		if _, ok := db.dirties[parent]; ok {
+			db.reference(child, parent)
+		}
+	}
+	//libevm:end
 	return nil
 }
 